@@ -176,6 +176,91 @@ func (m *RegisterRequest) validate(all bool) error {
 
 	}
 
+	// no validation rules for Region
+
+	for idx, item := range m.GetWebhooks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, RegisterRequestValidationError{
+						field:  fmt.Sprintf("Webhooks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, RegisterRequestValidationError{
+						field:  fmt.Sprintf("Webhooks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return RegisterRequestValidationError{
+					field:  fmt.Sprintf("Webhooks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if m.GetEmail() != "" {
+
+		if err := m._validateEmail(m.GetEmail()); err != nil {
+			err = RegisterRequestValidationError{
+				field:  "Email",
+				reason: "value must be a valid email address",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for OidcTicket
+
+	// no validation rules for Plan
+
+	if all {
+		switch v := interface{}(m.GetExternalInfluxdb()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RegisterRequestValidationError{
+					field:  "ExternalInfluxdb",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RegisterRequestValidationError{
+					field:  "ExternalInfluxdb",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExternalInfluxdb()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RegisterRequestValidationError{
+				field:  "ExternalInfluxdb",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		return RegisterRequestMultiError(errors)
 	}
@@ -183,6 +268,56 @@ func (m *RegisterRequest) validate(all bool) error {
 	return nil
 }
 
+func (m *RegisterRequest) _validateHostname(host string) error {
+	s := strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if len(host) > 253 {
+		return errors.New("hostname cannot exceed 253 characters")
+	}
+
+	for _, part := range strings.Split(s, ".") {
+		if l := len(part); l == 0 || l > 63 {
+			return errors.New("hostname part must be non-empty and cannot exceed 63 characters")
+		}
+
+		if part[0] == '-' {
+			return errors.New("hostname parts cannot begin with hyphens")
+		}
+
+		if part[len(part)-1] == '-' {
+			return errors.New("hostname parts cannot end with hyphens")
+		}
+
+		for _, r := range part {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return fmt.Errorf("hostname parts can only contain alphanumeric characters or hyphens, got %q", string(r))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *RegisterRequest) _validateEmail(addr string) error {
+	a, err := mail.ParseAddress(addr)
+	if err != nil {
+		return err
+	}
+	addr = a.Address
+
+	if len(addr) > 254 {
+		return errors.New("email addresses cannot exceed 254 characters")
+	}
+
+	parts := strings.SplitN(addr, "@", 2)
+
+	if len(parts[0]) > 64 {
+		return errors.New("email address local phrase cannot exceed 64 characters")
+	}
+
+	return m._validateHostname(parts[1])
+}
+
 // RegisterRequestMultiError is an error wrapping multiple validation errors
 // returned by RegisterRequest.ValidateAll() if the designated constraints
 // aren't met.
@@ -254,46 +389,5514 @@ var _ interface {
 	ErrorName() string
 } = RegisterRequestValidationError{}
 
-// Validate checks the field values on RegisterReply with the rules defined in
-// the proto definition for this message. If any rules are violated, the first
+// Validate checks the field values on ExternalInfluxdb with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ExternalInfluxdb) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ExternalInfluxdb with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ExternalInfluxdbMultiError, or nil if none found.
+func (m *ExternalInfluxdb) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ExternalInfluxdb) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if uri, err := url.Parse(m.GetUrl()); err != nil {
+		err = ExternalInfluxdbValidationError{
+			field:  "Url",
+			reason: "value must be a valid URI",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	} else if !uri.IsAbs() {
+		err := ExternalInfluxdbValidationError{
+			field:  "Url",
+			reason: "value must be absolute",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetOrg()) < 1 {
+		err := ExternalInfluxdbValidationError{
+			field:  "Org",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := ExternalInfluxdbValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ExternalInfluxdbMultiError(errors)
+	}
+
+	return nil
+}
+
+// ExternalInfluxdbMultiError is an error wrapping multiple validation errors
+// returned by ExternalInfluxdb.ValidateAll() if the designated constraints
+// aren't met.
+type ExternalInfluxdbMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ExternalInfluxdbMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ExternalInfluxdbMultiError) AllErrors() []error { return m }
+
+// ExternalInfluxdbValidationError is the validation error returned by
+// ExternalInfluxdb.Validate if the designated constraints aren't met.
+type ExternalInfluxdbValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ExternalInfluxdbValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ExternalInfluxdbValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ExternalInfluxdbValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ExternalInfluxdbValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ExternalInfluxdbValidationError) ErrorName() string { return "ExternalInfluxdbValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ExternalInfluxdbValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sExternalInfluxdb.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ExternalInfluxdbValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ExternalInfluxdbValidationError{}
+
+// Validate checks the field values on Webhook with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
 // error encountered is returned, or nil if there are no violations.
-func (m *RegisterReply) Validate() error {
+func (m *Webhook) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on RegisterReply with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// result is a list of violation errors wrapped in RegisterReplyMultiError, or
-// nil if none found.
-func (m *RegisterReply) ValidateAll() error {
+// ValidateAll checks the field values on Webhook with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in WebhookMultiError, or nil if none found.
+func (m *Webhook) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *RegisterReply) validate(all bool) error {
+func (m *Webhook) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
-	var errors []error
-
-	// no validation rules for Success
-
-	// no validation rules for Token
+	var errors []error
+
+	if uri, err := url.Parse(m.GetUrl()); err != nil {
+		err = WebhookValidationError{
+			field:  "Url",
+			reason: "value must be a valid URI",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	} else if !uri.IsAbs() {
+		err := WebhookValidationError{
+			field:  "Url",
+			reason: "value must be absolute",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetSecret()) < 16 {
+		err := WebhookValidationError{
+			field:  "Secret",
+			reason: "value length must be at least 16 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return WebhookMultiError(errors)
+	}
+
+	return nil
+}
+
+// WebhookMultiError is an error wrapping multiple validation errors returned
+// by Webhook.ValidateAll() if the designated constraints aren't met.
+type WebhookMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WebhookMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WebhookMultiError) AllErrors() []error { return m }
+
+// WebhookValidationError is the validation error returned by Webhook.Validate
+// if the designated constraints aren't met.
+type WebhookValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WebhookValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WebhookValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WebhookValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WebhookValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WebhookValidationError) ErrorName() string { return "WebhookValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WebhookValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWebhook.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WebhookValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WebhookValidationError{}
+
+// Validate checks the field values on RegisterReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RegisterReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RegisterReplyMultiError, or
+// nil if none found.
+func (m *RegisterReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	// no validation rules for Token
+
+	// no validation rules for Pending
+
+	if len(errors) > 0 {
+		return RegisterReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// RegisterReplyMultiError is an error wrapping multiple validation errors
+// returned by RegisterReply.ValidateAll() if the designated constraints
+// aren't met.
+type RegisterReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterReplyMultiError) AllErrors() []error { return m }
+
+// RegisterReplyValidationError is the validation error returned by
+// RegisterReply.Validate if the designated constraints aren't met.
+type RegisterReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterReplyValidationError) ErrorName() string { return "RegisterReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RegisterReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterReplyValidationError{}
+
+// Validate checks the field values on PasswordResetRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PasswordResetRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PasswordResetRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PasswordResetRequestMultiError, or nil if none found.
+func (m *PasswordResetRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PasswordResetRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetUsername()) < 1 {
+		err := PasswordResetRequestValidationError{
+			field:  "Username",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return PasswordResetRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// PasswordResetRequestMultiError is an error wrapping multiple validation
+// errors returned by PasswordResetRequest.ValidateAll() if the designated
+// constraints aren't met.
+type PasswordResetRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PasswordResetRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PasswordResetRequestMultiError) AllErrors() []error { return m }
+
+// PasswordResetRequestValidationError is the validation error returned by
+// PasswordResetRequest.Validate if the designated constraints aren't met.
+type PasswordResetRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PasswordResetRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PasswordResetRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PasswordResetRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PasswordResetRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PasswordResetRequestValidationError) ErrorName() string {
+	return "PasswordResetRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PasswordResetRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPasswordResetRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PasswordResetRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PasswordResetRequestValidationError{}
+
+// Validate checks the field values on PasswordResetReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PasswordResetReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PasswordResetReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PasswordResetReplyMultiError, or nil if none found.
+func (m *PasswordResetReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PasswordResetReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	if len(errors) > 0 {
+		return PasswordResetReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// PasswordResetReplyMultiError is an error wrapping multiple validation errors
+// returned by PasswordResetReply.ValidateAll() if the designated constraints
+// aren't met.
+type PasswordResetReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PasswordResetReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PasswordResetReplyMultiError) AllErrors() []error { return m }
+
+// PasswordResetReplyValidationError is the validation error returned by
+// PasswordResetReply.Validate if the designated constraints aren't met.
+type PasswordResetReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PasswordResetReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PasswordResetReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PasswordResetReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PasswordResetReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PasswordResetReplyValidationError) ErrorName() string {
+	return "PasswordResetReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PasswordResetReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPasswordResetReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PasswordResetReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PasswordResetReplyValidationError{}
+
+// Validate checks the field values on ConfirmPasswordResetRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ConfirmPasswordResetRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConfirmPasswordResetRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ConfirmPasswordResetRequestMultiError, or nil if none found.
+func (m *ConfirmPasswordResetRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConfirmPasswordResetRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetUsername()) < 1 {
+		err := ConfirmPasswordResetRequestValidationError{
+			field:  "Username",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetResetToken()) < 1 {
+		err := ConfirmPasswordResetRequestValidationError{
+			field:  "ResetToken",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetNewPassword()); l < 6 || l > 12 {
+		err := ConfirmPasswordResetRequestValidationError{
+			field:  "NewPassword",
+			reason: "value length must be between 6 and 12 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_ConfirmPasswordResetRequest_NewPassword_Pattern.MatchString(m.GetNewPassword()) {
+		err := ConfirmPasswordResetRequestValidationError{
+			field:  "NewPassword",
+			reason: "value does not match regex pattern \"^([a-zA-Z0-9]+)$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for RotateKey
+
+	if len(errors) > 0 {
+		return ConfirmPasswordResetRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordResetRequestMultiError is an error wrapping multiple
+// validation errors returned by ConfirmPasswordResetRequest.ValidateAll() if
+// the designated constraints aren't met.
+type ConfirmPasswordResetRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConfirmPasswordResetRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConfirmPasswordResetRequestMultiError) AllErrors() []error { return m }
+
+// ConfirmPasswordResetRequestValidationError is the validation error returned
+// by ConfirmPasswordResetRequest.Validate if the designated constraints
+// aren't met.
+type ConfirmPasswordResetRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConfirmPasswordResetRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConfirmPasswordResetRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConfirmPasswordResetRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConfirmPasswordResetRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConfirmPasswordResetRequestValidationError) ErrorName() string {
+	return "ConfirmPasswordResetRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ConfirmPasswordResetRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConfirmPasswordResetRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConfirmPasswordResetRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConfirmPasswordResetRequestValidationError{}
+
+var _ConfirmPasswordResetRequest_NewPassword_Pattern = regexp.MustCompile("^([a-zA-Z0-9]+)$")
+
+// Validate checks the field values on ConfirmPasswordResetReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ConfirmPasswordResetReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConfirmPasswordResetReply with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ConfirmPasswordResetReplyMultiError, or nil if none found.
+func (m *ConfirmPasswordResetReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConfirmPasswordResetReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return ConfirmPasswordResetReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordResetReplyMultiError is an error wrapping multiple validation
+// errors returned by ConfirmPasswordResetReply.ValidateAll() if the
+// designated constraints aren't met.
+type ConfirmPasswordResetReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConfirmPasswordResetReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConfirmPasswordResetReplyMultiError) AllErrors() []error { return m }
+
+// ConfirmPasswordResetReplyValidationError is the validation error returned by
+// ConfirmPasswordResetReply.Validate if the designated constraints aren't met.
+type ConfirmPasswordResetReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConfirmPasswordResetReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConfirmPasswordResetReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConfirmPasswordResetReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConfirmPasswordResetReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConfirmPasswordResetReplyValidationError) ErrorName() string {
+	return "ConfirmPasswordResetReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ConfirmPasswordResetReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConfirmPasswordResetReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConfirmPasswordResetReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConfirmPasswordResetReplyValidationError{}
+
+// Validate checks the field values on VerifyEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *VerifyEmailRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on VerifyEmailRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// VerifyEmailRequestMultiError, or nil if none found.
+func (m *VerifyEmailRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *VerifyEmailRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := VerifyEmailRequestValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return VerifyEmailRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// VerifyEmailRequestMultiError is an error wrapping multiple validation errors
+// returned by VerifyEmailRequest.ValidateAll() if the designated constraints
+// aren't met.
+type VerifyEmailRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VerifyEmailRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VerifyEmailRequestMultiError) AllErrors() []error { return m }
+
+// VerifyEmailRequestValidationError is the validation error returned by
+// VerifyEmailRequest.Validate if the designated constraints aren't met.
+type VerifyEmailRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VerifyEmailRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VerifyEmailRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VerifyEmailRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VerifyEmailRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VerifyEmailRequestValidationError) ErrorName() string {
+	return "VerifyEmailRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e VerifyEmailRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVerifyEmailRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VerifyEmailRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VerifyEmailRequestValidationError{}
+
+// Validate checks the field values on GetRegisterInfoRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetRegisterInfoRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetRegisterInfoRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetRegisterInfoRequestMultiError, or nil if none found.
+func (m *GetRegisterInfoRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetRegisterInfoRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return GetRegisterInfoRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetRegisterInfoRequestMultiError is an error wrapping multiple validation
+// errors returned by GetRegisterInfoRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GetRegisterInfoRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetRegisterInfoRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetRegisterInfoRequestMultiError) AllErrors() []error { return m }
+
+// GetRegisterInfoRequestValidationError is the validation error returned by
+// GetRegisterInfoRequest.Validate if the designated constraints aren't met.
+type GetRegisterInfoRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetRegisterInfoRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetRegisterInfoRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetRegisterInfoRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetRegisterInfoRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetRegisterInfoRequestValidationError) ErrorName() string {
+	return "GetRegisterInfoRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetRegisterInfoRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetRegisterInfoRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetRegisterInfoRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetRegisterInfoRequestValidationError{}
+
+// Validate checks the field values on GetRegisterInfoReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GetRegisterInfoReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetRegisterInfoReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetRegisterInfoReplyMultiError, or nil if none found.
+func (m *GetRegisterInfoReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetRegisterInfoReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetUser()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetRegisterInfoReplyValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetRegisterInfoReplyValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetRegisterInfoReplyValidationError{
+				field:  "User",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetDeviceConfigRegisterInfos() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetRegisterInfoReplyValidationError{
+						field:  fmt.Sprintf("DeviceConfigRegisterInfos[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetRegisterInfoReplyValidationError{
+						field:  fmt.Sprintf("DeviceConfigRegisterInfos[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetRegisterInfoReplyValidationError{
+					field:  fmt.Sprintf("DeviceConfigRegisterInfos[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetDeviceStateRegisterInfos() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetRegisterInfoReplyValidationError{
+						field:  fmt.Sprintf("DeviceStateRegisterInfos[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetRegisterInfoReplyValidationError{
+						field:  fmt.Sprintf("DeviceStateRegisterInfos[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetRegisterInfoReplyValidationError{
+					field:  fmt.Sprintf("DeviceStateRegisterInfos[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetRegisterInfoReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetRegisterInfoReplyMultiError is an error wrapping multiple validation
+// errors returned by GetRegisterInfoReply.ValidateAll() if the designated
+// constraints aren't met.
+type GetRegisterInfoReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetRegisterInfoReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetRegisterInfoReplyMultiError) AllErrors() []error { return m }
+
+// GetRegisterInfoReplyValidationError is the validation error returned by
+// GetRegisterInfoReply.Validate if the designated constraints aren't met.
+type GetRegisterInfoReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetRegisterInfoReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetRegisterInfoReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetRegisterInfoReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetRegisterInfoReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetRegisterInfoReplyValidationError) ErrorName() string {
+	return "GetRegisterInfoReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GetRegisterInfoReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetRegisterInfoReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetRegisterInfoReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetRegisterInfoReplyValidationError{}
+
+// Validate checks the field values on LoginReply with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LoginReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LoginReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LoginReplyMultiError, or
+// nil if none found.
+func (m *LoginReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LoginReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return LoginReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// LoginReplyMultiError is an error wrapping multiple validation errors
+// returned by LoginReply.ValidateAll() if the designated constraints aren't met.
+type LoginReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LoginReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LoginReplyMultiError) AllErrors() []error { return m }
+
+// LoginReplyValidationError is the validation error returned by
+// LoginReply.Validate if the designated constraints aren't met.
+type LoginReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LoginReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LoginReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LoginReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LoginReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LoginReplyValidationError) ErrorName() string { return "LoginReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LoginReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLoginReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LoginReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LoginReplyValidationError{}
+
+// Validate checks the field values on UnregisterRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *UnregisterRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnregisterRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnregisterRequestMultiError, or nil if none found.
+func (m *UnregisterRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnregisterRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		err := UnregisterRequestValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPassword()) < 1 {
+		err := UnregisterRequestValidationError{
+			field:  "Password",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ExportFirst
+
+	if _, ok := _UnregisterRequest_ExportFormat_InLookup[m.GetExportFormat()]; !ok {
+		err := UnregisterRequestValidationError{
+			field:  "ExportFormat",
+			reason: "value must be in list [ csv line_protocol]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UnregisterRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnregisterRequestMultiError is an error wrapping multiple validation errors
+// returned by UnregisterRequest.ValidateAll() if the designated constraints
+// aren't met.
+type UnregisterRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnregisterRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnregisterRequestMultiError) AllErrors() []error { return m }
+
+// UnregisterRequestValidationError is the validation error returned by
+// UnregisterRequest.Validate if the designated constraints aren't met.
+type UnregisterRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnregisterRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnregisterRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnregisterRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnregisterRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnregisterRequestValidationError) ErrorName() string {
+	return "UnregisterRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UnregisterRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnregisterRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnregisterRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnregisterRequestValidationError{}
+
+var _UnregisterRequest_ExportFormat_InLookup = map[string]struct{}{
+	"":              {},
+	"csv":           {},
+	"line_protocol": {},
+}
+
+// Validate checks the field values on UnregisterReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *UnregisterReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnregisterReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnregisterReplyMultiError, or nil if none found.
+func (m *UnregisterReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnregisterReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	if all {
+		switch v := interface{}(m.GetPurgeTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UnregisterReplyValidationError{
+					field:  "PurgeTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UnregisterReplyValidationError{
+					field:  "PurgeTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPurgeTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UnregisterReplyValidationError{
+				field:  "PurgeTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ExportId
+
+	// no validation rules for ExportError
+
+	if len(errors) > 0 {
+		return UnregisterReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnregisterReplyMultiError is an error wrapping multiple validation errors
+// returned by UnregisterReply.ValidateAll() if the designated constraints
+// aren't met.
+type UnregisterReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnregisterReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnregisterReplyMultiError) AllErrors() []error { return m }
+
+// UnregisterReplyValidationError is the validation error returned by
+// UnregisterReply.Validate if the designated constraints aren't met.
+type UnregisterReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnregisterReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnregisterReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnregisterReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnregisterReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnregisterReplyValidationError) ErrorName() string { return "UnregisterReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UnregisterReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnregisterReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnregisterReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnregisterReplyValidationError{}
+
+// Validate checks the field values on RestoreReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RestoreReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RestoreReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RestoreReplyMultiError, or
+// nil if none found.
+func (m *RestoreReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RestoreReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	if len(errors) > 0 {
+		return RestoreReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// RestoreReplyMultiError is an error wrapping multiple validation errors
+// returned by RestoreReply.ValidateAll() if the designated constraints aren't met.
+type RestoreReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RestoreReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RestoreReplyMultiError) AllErrors() []error { return m }
+
+// RestoreReplyValidationError is the validation error returned by
+// RestoreReply.Validate if the designated constraints aren't met.
+type RestoreReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RestoreReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RestoreReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RestoreReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RestoreReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RestoreReplyValidationError) ErrorName() string { return "RestoreReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RestoreReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRestoreReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RestoreReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RestoreReplyValidationError{}
+
+// Validate checks the field values on DownloadClientCodeRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DownloadClientCodeRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DownloadClientCodeRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DownloadClientCodeRequestMultiError, or nil if none found.
+func (m *DownloadClientCodeRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DownloadClientCodeRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Username
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return DownloadClientCodeRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DownloadClientCodeRequestMultiError is an error wrapping multiple validation
+// errors returned by DownloadClientCodeRequest.ValidateAll() if the
+// designated constraints aren't met.
+type DownloadClientCodeRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DownloadClientCodeRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DownloadClientCodeRequestMultiError) AllErrors() []error { return m }
+
+// DownloadClientCodeRequestValidationError is the validation error returned by
+// DownloadClientCodeRequest.Validate if the designated constraints aren't met.
+type DownloadClientCodeRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DownloadClientCodeRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DownloadClientCodeRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DownloadClientCodeRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DownloadClientCodeRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DownloadClientCodeRequestValidationError) ErrorName() string {
+	return "DownloadClientCodeRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DownloadClientCodeRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDownloadClientCodeRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DownloadClientCodeRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DownloadClientCodeRequestValidationError{}
+
+// Validate checks the field values on File with the rules defined in the proto
+// definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
+func (m *File) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on File with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in FileMultiError, or nil if none found.
+func (m *File) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *File) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Content
+
+	// no validation rules for Name
+
+	if len(errors) > 0 {
+		return FileMultiError(errors)
+	}
+
+	return nil
+}
+
+// FileMultiError is an error wrapping multiple validation errors returned by
+// File.ValidateAll() if the designated constraints aren't met.
+type FileMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m FileMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m FileMultiError) AllErrors() []error { return m }
+
+// FileValidationError is the validation error returned by File.Validate if the
+// designated constraints aren't met.
+type FileValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e FileValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e FileValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e FileValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e FileValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e FileValidationError) ErrorName() string { return "FileValidationError" }
+
+// Error satisfies the builtin error interface
+func (e FileValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sFile.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = FileValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = FileValidationError{}
+
+// Validate checks the field values on GetStatusRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetStatusRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetStatusRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetStatusRequestMultiError, or nil if none found.
+func (m *GetStatusRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetStatusRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return GetStatusRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetStatusRequestMultiError is an error wrapping multiple validation errors
+// returned by GetStatusRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetStatusRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetStatusRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetStatusRequestMultiError) AllErrors() []error { return m }
+
+// GetStatusRequestValidationError is the validation error returned by
+// GetStatusRequest.Validate if the designated constraints aren't met.
+type GetStatusRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetStatusRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetStatusRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetStatusRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetStatusRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetStatusRequestValidationError) ErrorName() string { return "GetStatusRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetStatusRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetStatusRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetStatusRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetStatusRequestValidationError{}
+
+// Validate checks the field values on GetStatusReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GetStatusReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetStatusReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GetStatusReplyMultiError,
+// or nil if none found.
+func (m *GetStatusReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetStatusReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetServices() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("Services[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("Services[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetStatusReplyValidationError{
+					field:  fmt.Sprintf("Services[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetLastRollout()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GetStatusReplyValidationError{
+					field:  "LastRollout",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GetStatusReplyValidationError{
+					field:  "LastRollout",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetLastRollout()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GetStatusReplyValidationError{
+				field:  "LastRollout",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetGatewayObjects() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("GatewayObjects[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("GatewayObjects[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetStatusReplyValidationError{
+					field:  fmt.Sprintf("GatewayObjects[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetBuckets() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetStatusReplyValidationError{
+						field:  fmt.Sprintf("Buckets[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetStatusReplyValidationError{
+					field:  fmt.Sprintf("Buckets[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetStatusReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetStatusReplyMultiError is an error wrapping multiple validation errors
+// returned by GetStatusReply.ValidateAll() if the designated constraints
+// aren't met.
+type GetStatusReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetStatusReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetStatusReplyMultiError) AllErrors() []error { return m }
+
+// GetStatusReplyValidationError is the validation error returned by
+// GetStatusReply.Validate if the designated constraints aren't met.
+type GetStatusReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetStatusReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetStatusReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetStatusReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetStatusReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetStatusReplyValidationError) ErrorName() string { return "GetStatusReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetStatusReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetStatusReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetStatusReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetStatusReplyValidationError{}
+
+// Validate checks the field values on ServiceStatus with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ServiceStatus) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ServiceStatus with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ServiceStatusMultiError, or
+// nil if none found.
+func (m *ServiceStatus) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ServiceStatus) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Exists
+
+	// no validation rules for DesiredReplicas
+
+	// no validation rules for ReadyReplicas
+
+	// no validation rules for RestartCount
+
+	if all {
+		switch v := interface{}(m.GetCompile()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ServiceStatusValidationError{
+					field:  "Compile",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ServiceStatusValidationError{
+					field:  "Compile",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCompile()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ServiceStatusValidationError{
+				field:  "Compile",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ServiceStatusMultiError(errors)
+	}
+
+	return nil
+}
+
+// ServiceStatusMultiError is an error wrapping multiple validation errors
+// returned by ServiceStatus.ValidateAll() if the designated constraints
+// aren't met.
+type ServiceStatusMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ServiceStatusMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ServiceStatusMultiError) AllErrors() []error { return m }
+
+// ServiceStatusValidationError is the validation error returned by
+// ServiceStatus.Validate if the designated constraints aren't met.
+type ServiceStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ServiceStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ServiceStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ServiceStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ServiceStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ServiceStatusValidationError) ErrorName() string { return "ServiceStatusValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ServiceStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sServiceStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ServiceStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ServiceStatusValidationError{}
+
+// Validate checks the field values on CompileOutcome with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CompileOutcome) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CompileOutcome with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CompileOutcomeMultiError,
+// or nil if none found.
+func (m *CompileOutcome) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CompileOutcome) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for State
+
+	// no validation rules for Message
+
+	if all {
+		switch v := interface{}(m.GetFinishTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CompileOutcomeValidationError{
+					field:  "FinishTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CompileOutcomeValidationError{
+					field:  "FinishTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetFinishTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CompileOutcomeValidationError{
+				field:  "FinishTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return CompileOutcomeMultiError(errors)
+	}
+
+	return nil
+}
+
+// CompileOutcomeMultiError is an error wrapping multiple validation errors
+// returned by CompileOutcome.ValidateAll() if the designated constraints
+// aren't met.
+type CompileOutcomeMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CompileOutcomeMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CompileOutcomeMultiError) AllErrors() []error { return m }
+
+// CompileOutcomeValidationError is the validation error returned by
+// CompileOutcome.Validate if the designated constraints aren't met.
+type CompileOutcomeValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CompileOutcomeValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CompileOutcomeValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CompileOutcomeValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CompileOutcomeValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CompileOutcomeValidationError) ErrorName() string { return "CompileOutcomeValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CompileOutcomeValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCompileOutcome.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CompileOutcomeValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CompileOutcomeValidationError{}
+
+// Validate checks the field values on RolloutOutcome with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RolloutOutcome) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RolloutOutcome with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RolloutOutcomeMultiError,
+// or nil if none found.
+func (m *RolloutOutcome) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RolloutOutcome) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Operation
+
+	// no validation rules for Success
+
+	// no validation rules for Message
+
+	if all {
+		switch v := interface{}(m.GetTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RolloutOutcomeValidationError{
+					field:  "Time",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RolloutOutcomeValidationError{
+					field:  "Time",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RolloutOutcomeValidationError{
+				field:  "Time",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RolloutOutcomeMultiError(errors)
+	}
+
+	return nil
+}
+
+// RolloutOutcomeMultiError is an error wrapping multiple validation errors
+// returned by RolloutOutcome.ValidateAll() if the designated constraints
+// aren't met.
+type RolloutOutcomeMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RolloutOutcomeMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RolloutOutcomeMultiError) AllErrors() []error { return m }
+
+// RolloutOutcomeValidationError is the validation error returned by
+// RolloutOutcome.Validate if the designated constraints aren't met.
+type RolloutOutcomeValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RolloutOutcomeValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RolloutOutcomeValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RolloutOutcomeValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RolloutOutcomeValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RolloutOutcomeValidationError) ErrorName() string { return "RolloutOutcomeValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RolloutOutcomeValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRolloutOutcome.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RolloutOutcomeValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RolloutOutcomeValidationError{}
+
+// Validate checks the field values on GatewayObjectStatus with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GatewayObjectStatus) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GatewayObjectStatus with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GatewayObjectStatusMultiError, or nil if none found.
+func (m *GatewayObjectStatus) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GatewayObjectStatus) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Kind
+
+	// no validation rules for Name
+
+	// no validation rules for Present
+
+	if len(errors) > 0 {
+		return GatewayObjectStatusMultiError(errors)
+	}
+
+	return nil
+}
+
+// GatewayObjectStatusMultiError is an error wrapping multiple validation
+// errors returned by GatewayObjectStatus.ValidateAll() if the designated
+// constraints aren't met.
+type GatewayObjectStatusMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GatewayObjectStatusMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GatewayObjectStatusMultiError) AllErrors() []error { return m }
+
+// GatewayObjectStatusValidationError is the validation error returned by
+// GatewayObjectStatus.Validate if the designated constraints aren't met.
+type GatewayObjectStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GatewayObjectStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GatewayObjectStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GatewayObjectStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GatewayObjectStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GatewayObjectStatusValidationError) ErrorName() string {
+	return "GatewayObjectStatusValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GatewayObjectStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGatewayObjectStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GatewayObjectStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GatewayObjectStatusValidationError{}
+
+// Validate checks the field values on BucketStatus with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *BucketStatus) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BucketStatus with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in BucketStatusMultiError, or
+// nil if none found.
+func (m *BucketStatus) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BucketStatus) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Exists
+
+	// no validation rules for RetentionSeconds
+
+	// no validation rules for SizeBytes
+
+	if len(errors) > 0 {
+		return BucketStatusMultiError(errors)
+	}
+
+	return nil
+}
+
+// BucketStatusMultiError is an error wrapping multiple validation errors
+// returned by BucketStatus.ValidateAll() if the designated constraints aren't met.
+type BucketStatusMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BucketStatusMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BucketStatusMultiError) AllErrors() []error { return m }
+
+// BucketStatusValidationError is the validation error returned by
+// BucketStatus.Validate if the designated constraints aren't met.
+type BucketStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BucketStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BucketStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BucketStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BucketStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BucketStatusValidationError) ErrorName() string { return "BucketStatusValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BucketStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBucketStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BucketStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BucketStatusValidationError{}
+
+// Validate checks the field values on GetUsageRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetUsageRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetUsageRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GetUsageRequestMultiError, or nil if none found.
+func (m *GetUsageRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetUsageRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	// no validation rules for StartDate
+
+	// no validation rules for EndDate
+
+	if len(errors) > 0 {
+		return GetUsageRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetUsageRequestMultiError is an error wrapping multiple validation errors
+// returned by GetUsageRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetUsageRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetUsageRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetUsageRequestMultiError) AllErrors() []error { return m }
+
+// GetUsageRequestValidationError is the validation error returned by
+// GetUsageRequest.Validate if the designated constraints aren't met.
+type GetUsageRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetUsageRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetUsageRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetUsageRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetUsageRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetUsageRequestValidationError) ErrorName() string { return "GetUsageRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetUsageRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetUsageRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetUsageRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetUsageRequestValidationError{}
+
+// Validate checks the field values on GetUsageReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GetUsageReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GetUsageReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GetUsageReplyMultiError, or
+// nil if none found.
+func (m *GetUsageReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GetUsageReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetRecords() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GetUsageReplyValidationError{
+						field:  fmt.Sprintf("Records[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GetUsageReplyValidationError{
+						field:  fmt.Sprintf("Records[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GetUsageReplyValidationError{
+					field:  fmt.Sprintf("Records[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GetUsageReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// GetUsageReplyMultiError is an error wrapping multiple validation errors
+// returned by GetUsageReply.ValidateAll() if the designated constraints
+// aren't met.
+type GetUsageReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GetUsageReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GetUsageReplyMultiError) AllErrors() []error { return m }
+
+// GetUsageReplyValidationError is the validation error returned by
+// GetUsageReply.Validate if the designated constraints aren't met.
+type GetUsageReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GetUsageReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GetUsageReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GetUsageReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GetUsageReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GetUsageReplyValidationError) ErrorName() string { return "GetUsageReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GetUsageReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGetUsageReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GetUsageReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GetUsageReplyValidationError{}
+
+// Validate checks the field values on UsageRecord with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *UsageRecord) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UsageRecord with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in UsageRecordMultiError, or
+// nil if none found.
+func (m *UsageRecord) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UsageRecord) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Username
+
+	// no validation rules for Date
+
+	// no validation rules for PointsWritten
+
+	// no validation rules for WarningPoints
+
+	// no validation rules for GatewayRequests
+
+	// no validation rules for CpuCoreSeconds
+
+	// no validation rules for MemoryByteSeconds
+
+	if all {
+		switch v := interface{}(m.GetUpdateTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UsageRecordValidationError{
+					field:  "UpdateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UsageRecordValidationError{
+					field:  "UpdateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpdateTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UsageRecordValidationError{
+				field:  "UpdateTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return UsageRecordMultiError(errors)
+	}
+
+	return nil
+}
+
+// UsageRecordMultiError is an error wrapping multiple validation errors
+// returned by UsageRecord.ValidateAll() if the designated constraints aren't met.
+type UsageRecordMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UsageRecordMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UsageRecordMultiError) AllErrors() []error { return m }
+
+// UsageRecordValidationError is the validation error returned by
+// UsageRecord.Validate if the designated constraints aren't met.
+type UsageRecordValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UsageRecordValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UsageRecordValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UsageRecordValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UsageRecordValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UsageRecordValidationError) ErrorName() string { return "UsageRecordValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UsageRecordValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUsageRecord.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UsageRecordValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UsageRecordValidationError{}
+
+// Validate checks the field values on CreateInvitationRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CreateInvitationRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CreateInvitationRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// CreateInvitationRequestMultiError, or nil if none found.
+func (m *CreateInvitationRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CreateInvitationRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if _, ok := _CreateInvitationRequest_Role_NotInLookup[m.GetRole()]; ok {
+		err := CreateInvitationRequestValidationError{
+			field:  "Role",
+			reason: "value must not be in list [3]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := Role_name[int32(m.GetRole())]; !ok {
+		err := CreateInvitationRequestValidationError{
+			field:  "Role",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CreateInvitationRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CreateInvitationRequestMultiError is an error wrapping multiple validation
+// errors returned by CreateInvitationRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CreateInvitationRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreateInvitationRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreateInvitationRequestMultiError) AllErrors() []error { return m }
+
+// CreateInvitationRequestValidationError is the validation error returned by
+// CreateInvitationRequest.Validate if the designated constraints aren't met.
+type CreateInvitationRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreateInvitationRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreateInvitationRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreateInvitationRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreateInvitationRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreateInvitationRequestValidationError) ErrorName() string {
+	return "CreateInvitationRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreateInvitationRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreateInvitationRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreateInvitationRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreateInvitationRequestValidationError{}
+
+var _CreateInvitationRequest_Role_NotInLookup = map[Role]struct{}{
+	3: {},
+}
+
+// Validate checks the field values on Invitation with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Invitation) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Invitation with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in InvitationMultiError, or
+// nil if none found.
+func (m *Invitation) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Invitation) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Code
+
+	// no validation rules for Org
+
+	// no validation rules for Role
+
+	if all {
+		switch v := interface{}(m.GetExpireTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, InvitationValidationError{
+					field:  "ExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, InvitationValidationError{
+					field:  "ExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpireTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return InvitationValidationError{
+				field:  "ExpireTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return InvitationMultiError(errors)
+	}
+
+	return nil
+}
+
+// InvitationMultiError is an error wrapping multiple validation errors
+// returned by Invitation.ValidateAll() if the designated constraints aren't met.
+type InvitationMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InvitationMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InvitationMultiError) AllErrors() []error { return m }
+
+// InvitationValidationError is the validation error returned by
+// Invitation.Validate if the designated constraints aren't met.
+type InvitationValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InvitationValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InvitationValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InvitationValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InvitationValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InvitationValidationError) ErrorName() string { return "InvitationValidationError" }
+
+// Error satisfies the builtin error interface
+func (e InvitationValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInvitation.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InvitationValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InvitationValidationError{}
+
+// Validate checks the field values on AcceptInvitationRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *AcceptInvitationRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AcceptInvitationRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AcceptInvitationRequestMultiError, or nil if none found.
+func (m *AcceptInvitationRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AcceptInvitationRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetCode()) < 1 {
+		err := AcceptInvitationRequestValidationError{
+			field:  "Code",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetUser() == nil {
+		err := AcceptInvitationRequestValidationError{
+			field:  "User",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetUser()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AcceptInvitationRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AcceptInvitationRequestValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AcceptInvitationRequestValidationError{
+				field:  "User",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return AcceptInvitationRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AcceptInvitationRequestMultiError is an error wrapping multiple validation
+// errors returned by AcceptInvitationRequest.ValidateAll() if the designated
+// constraints aren't met.
+type AcceptInvitationRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AcceptInvitationRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AcceptInvitationRequestMultiError) AllErrors() []error { return m }
+
+// AcceptInvitationRequestValidationError is the validation error returned by
+// AcceptInvitationRequest.Validate if the designated constraints aren't met.
+type AcceptInvitationRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AcceptInvitationRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AcceptInvitationRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AcceptInvitationRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AcceptInvitationRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AcceptInvitationRequestValidationError) ErrorName() string {
+	return "AcceptInvitationRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AcceptInvitationRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAcceptInvitationRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AcceptInvitationRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AcceptInvitationRequestValidationError{}
+
+// Validate checks the field values on ListMembersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListMembersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListMembersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListMembersRequestMultiError, or nil if none found.
+func (m *ListMembersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListMembersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return ListMembersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListMembersRequestMultiError is an error wrapping multiple validation errors
+// returned by ListMembersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ListMembersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListMembersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListMembersRequestMultiError) AllErrors() []error { return m }
+
+// ListMembersRequestValidationError is the validation error returned by
+// ListMembersRequest.Validate if the designated constraints aren't met.
+type ListMembersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListMembersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListMembersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListMembersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListMembersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListMembersRequestValidationError) ErrorName() string {
+	return "ListMembersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListMembersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListMembersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListMembersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListMembersRequestValidationError{}
+
+// Validate checks the field values on ListMembersReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListMembersReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListMembersReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListMembersReplyMultiError, or nil if none found.
+func (m *ListMembersReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListMembersReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Org
+
+	for idx, item := range m.GetMembers() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListMembersReplyValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListMembersReplyValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListMembersReplyValidationError{
+					field:  fmt.Sprintf("Members[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListMembersReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListMembersReplyMultiError is an error wrapping multiple validation errors
+// returned by ListMembersReply.ValidateAll() if the designated constraints
+// aren't met.
+type ListMembersReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListMembersReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListMembersReplyMultiError) AllErrors() []error { return m }
+
+// ListMembersReplyValidationError is the validation error returned by
+// ListMembersReply.Validate if the designated constraints aren't met.
+type ListMembersReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListMembersReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListMembersReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListMembersReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListMembersReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListMembersReplyValidationError) ErrorName() string { return "ListMembersReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ListMembersReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListMembersReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListMembersReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListMembersReplyValidationError{}
+
+// Validate checks the field values on Member with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Member) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Member with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in MemberMultiError, or nil if none found.
+func (m *Member) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Member) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Role
+
+	if all {
+		switch v := interface{}(m.GetCreateTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, MemberValidationError{
+					field:  "CreateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, MemberValidationError{
+					field:  "CreateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreateTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return MemberValidationError{
+				field:  "CreateTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return MemberMultiError(errors)
+	}
+
+	return nil
+}
+
+// MemberMultiError is an error wrapping multiple validation errors returned by
+// Member.ValidateAll() if the designated constraints aren't met.
+type MemberMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MemberMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MemberMultiError) AllErrors() []error { return m }
+
+// MemberValidationError is the validation error returned by Member.Validate if
+// the designated constraints aren't met.
+type MemberValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MemberValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MemberValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MemberValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MemberValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MemberValidationError) ErrorName() string { return "MemberValidationError" }
+
+// Error satisfies the builtin error interface
+func (e MemberValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMember.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MemberValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MemberValidationError{}
+
+// Validate checks the field values on UpdateMemberRoleRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UpdateMemberRoleRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpdateMemberRoleRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UpdateMemberRoleRequestMultiError, or nil if none found.
+func (m *UpdateMemberRoleRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpdateMemberRoleRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	// no validation rules for MemberId
+
+	if _, ok := _UpdateMemberRoleRequest_Role_NotInLookup[m.GetRole()]; ok {
+		err := UpdateMemberRoleRequestValidationError{
+			field:  "Role",
+			reason: "value must not be in list [3]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := Role_name[int32(m.GetRole())]; !ok {
+		err := UpdateMemberRoleRequestValidationError{
+			field:  "Role",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UpdateMemberRoleRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpdateMemberRoleRequestMultiError is an error wrapping multiple validation
+// errors returned by UpdateMemberRoleRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UpdateMemberRoleRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpdateMemberRoleRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpdateMemberRoleRequestMultiError) AllErrors() []error { return m }
+
+// UpdateMemberRoleRequestValidationError is the validation error returned by
+// UpdateMemberRoleRequest.Validate if the designated constraints aren't met.
+type UpdateMemberRoleRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpdateMemberRoleRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpdateMemberRoleRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpdateMemberRoleRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpdateMemberRoleRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpdateMemberRoleRequestValidationError) ErrorName() string {
+	return "UpdateMemberRoleRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UpdateMemberRoleRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpdateMemberRoleRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpdateMemberRoleRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpdateMemberRoleRequestValidationError{}
+
+var _UpdateMemberRoleRequest_Role_NotInLookup = map[Role]struct{}{
+	3: {},
+}
+
+// Validate checks the field values on RemoveMemberRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RemoveMemberRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RemoveMemberRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RemoveMemberRequestMultiError, or nil if none found.
+func (m *RemoveMemberRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RemoveMemberRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	// no validation rules for MemberId
+
+	if len(errors) > 0 {
+		return RemoveMemberRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RemoveMemberRequestMultiError is an error wrapping multiple validation
+// errors returned by RemoveMemberRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RemoveMemberRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RemoveMemberRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RemoveMemberRequestMultiError) AllErrors() []error { return m }
+
+// RemoveMemberRequestValidationError is the validation error returned by
+// RemoveMemberRequest.Validate if the designated constraints aren't met.
+type RemoveMemberRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RemoveMemberRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RemoveMemberRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RemoveMemberRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RemoveMemberRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RemoveMemberRequestValidationError) ErrorName() string {
+	return "RemoveMemberRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RemoveMemberRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRemoveMemberRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RemoveMemberRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RemoveMemberRequestValidationError{}
+
+// Validate checks the field values on RemoveMemberReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *RemoveMemberReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RemoveMemberReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RemoveMemberReplyMultiError, or nil if none found.
+func (m *RemoveMemberReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RemoveMemberReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	if len(errors) > 0 {
+		return RemoveMemberReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// RemoveMemberReplyMultiError is an error wrapping multiple validation errors
+// returned by RemoveMemberReply.ValidateAll() if the designated constraints
+// aren't met.
+type RemoveMemberReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RemoveMemberReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RemoveMemberReplyMultiError) AllErrors() []error { return m }
+
+// RemoveMemberReplyValidationError is the validation error returned by
+// RemoveMemberReply.Validate if the designated constraints aren't met.
+type RemoveMemberReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RemoveMemberReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RemoveMemberReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RemoveMemberReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RemoveMemberReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RemoveMemberReplyValidationError) ErrorName() string {
+	return "RemoveMemberReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RemoveMemberReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRemoveMemberReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RemoveMemberReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RemoveMemberReplyValidationError{}
+
+// Validate checks the field values on EnrollTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *EnrollTwoFactorRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EnrollTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EnrollTwoFactorRequestMultiError, or nil if none found.
+func (m *EnrollTwoFactorRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EnrollTwoFactorRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return EnrollTwoFactorRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// EnrollTwoFactorRequestMultiError is an error wrapping multiple validation
+// errors returned by EnrollTwoFactorRequest.ValidateAll() if the designated
+// constraints aren't met.
+type EnrollTwoFactorRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EnrollTwoFactorRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EnrollTwoFactorRequestMultiError) AllErrors() []error { return m }
+
+// EnrollTwoFactorRequestValidationError is the validation error returned by
+// EnrollTwoFactorRequest.Validate if the designated constraints aren't met.
+type EnrollTwoFactorRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EnrollTwoFactorRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EnrollTwoFactorRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EnrollTwoFactorRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EnrollTwoFactorRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EnrollTwoFactorRequestValidationError) ErrorName() string {
+	return "EnrollTwoFactorRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EnrollTwoFactorRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEnrollTwoFactorRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EnrollTwoFactorRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EnrollTwoFactorRequestValidationError{}
+
+// Validate checks the field values on EnrollTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *EnrollTwoFactorReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on EnrollTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// EnrollTwoFactorReplyMultiError, or nil if none found.
+func (m *EnrollTwoFactorReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *EnrollTwoFactorReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Secret
+
+	// no validation rules for OtpauthUrl
+
+	if len(errors) > 0 {
+		return EnrollTwoFactorReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// EnrollTwoFactorReplyMultiError is an error wrapping multiple validation
+// errors returned by EnrollTwoFactorReply.ValidateAll() if the designated
+// constraints aren't met.
+type EnrollTwoFactorReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m EnrollTwoFactorReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m EnrollTwoFactorReplyMultiError) AllErrors() []error { return m }
+
+// EnrollTwoFactorReplyValidationError is the validation error returned by
+// EnrollTwoFactorReply.Validate if the designated constraints aren't met.
+type EnrollTwoFactorReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EnrollTwoFactorReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EnrollTwoFactorReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EnrollTwoFactorReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EnrollTwoFactorReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EnrollTwoFactorReplyValidationError) ErrorName() string {
+	return "EnrollTwoFactorReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EnrollTwoFactorReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEnrollTwoFactorReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EnrollTwoFactorReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EnrollTwoFactorReplyValidationError{}
+
+// Validate checks the field values on VerifyTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *VerifyTwoFactorRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on VerifyTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// VerifyTwoFactorRequestMultiError, or nil if none found.
+func (m *VerifyTwoFactorRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *VerifyTwoFactorRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if utf8.RuneCountInString(m.GetCode()) < 1 {
+		err := VerifyTwoFactorRequestValidationError{
+			field:  "Code",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return VerifyTwoFactorRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// VerifyTwoFactorRequestMultiError is an error wrapping multiple validation
+// errors returned by VerifyTwoFactorRequest.ValidateAll() if the designated
+// constraints aren't met.
+type VerifyTwoFactorRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VerifyTwoFactorRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VerifyTwoFactorRequestMultiError) AllErrors() []error { return m }
+
+// VerifyTwoFactorRequestValidationError is the validation error returned by
+// VerifyTwoFactorRequest.Validate if the designated constraints aren't met.
+type VerifyTwoFactorRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VerifyTwoFactorRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VerifyTwoFactorRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VerifyTwoFactorRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VerifyTwoFactorRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VerifyTwoFactorRequestValidationError) ErrorName() string {
+	return "VerifyTwoFactorRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e VerifyTwoFactorRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVerifyTwoFactorRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VerifyTwoFactorRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VerifyTwoFactorRequestValidationError{}
+
+// Validate checks the field values on VerifyTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *VerifyTwoFactorReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on VerifyTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// VerifyTwoFactorReplyMultiError, or nil if none found.
+func (m *VerifyTwoFactorReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *VerifyTwoFactorReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return VerifyTwoFactorReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// VerifyTwoFactorReplyMultiError is an error wrapping multiple validation
+// errors returned by VerifyTwoFactorReply.ValidateAll() if the designated
+// constraints aren't met.
+type VerifyTwoFactorReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m VerifyTwoFactorReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m VerifyTwoFactorReplyMultiError) AllErrors() []error { return m }
+
+// VerifyTwoFactorReplyValidationError is the validation error returned by
+// VerifyTwoFactorReply.Validate if the designated constraints aren't met.
+type VerifyTwoFactorReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VerifyTwoFactorReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VerifyTwoFactorReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VerifyTwoFactorReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VerifyTwoFactorReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VerifyTwoFactorReplyValidationError) ErrorName() string {
+	return "VerifyTwoFactorReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e VerifyTwoFactorReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVerifyTwoFactorReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VerifyTwoFactorReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VerifyTwoFactorReplyValidationError{}
+
+// Validate checks the field values on DisableTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DisableTwoFactorRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DisableTwoFactorRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DisableTwoFactorRequestMultiError, or nil if none found.
+func (m *DisableTwoFactorRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DisableTwoFactorRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Token
+
+	if utf8.RuneCountInString(m.GetCode()) < 1 {
+		err := DisableTwoFactorRequestValidationError{
+			field:  "Code",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return DisableTwoFactorRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// DisableTwoFactorRequestMultiError is an error wrapping multiple validation
+// errors returned by DisableTwoFactorRequest.ValidateAll() if the designated
+// constraints aren't met.
+type DisableTwoFactorRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DisableTwoFactorRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DisableTwoFactorRequestMultiError) AllErrors() []error { return m }
+
+// DisableTwoFactorRequestValidationError is the validation error returned by
+// DisableTwoFactorRequest.Validate if the designated constraints aren't met.
+type DisableTwoFactorRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DisableTwoFactorRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DisableTwoFactorRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DisableTwoFactorRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DisableTwoFactorRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DisableTwoFactorRequestValidationError) ErrorName() string {
+	return "DisableTwoFactorRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DisableTwoFactorRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDisableTwoFactorRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DisableTwoFactorRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DisableTwoFactorRequestValidationError{}
+
+// Validate checks the field values on DisableTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *DisableTwoFactorReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on DisableTwoFactorReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// DisableTwoFactorReplyMultiError, or nil if none found.
+func (m *DisableTwoFactorReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *DisableTwoFactorReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	if len(errors) > 0 {
+		return DisableTwoFactorReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// DisableTwoFactorReplyMultiError is an error wrapping multiple validation
+// errors returned by DisableTwoFactorReply.ValidateAll() if the designated
+// constraints aren't met.
+type DisableTwoFactorReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m DisableTwoFactorReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m DisableTwoFactorReplyMultiError) AllErrors() []error { return m }
+
+// DisableTwoFactorReplyValidationError is the validation error returned by
+// DisableTwoFactorReply.Validate if the designated constraints aren't met.
+type DisableTwoFactorReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e DisableTwoFactorReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e DisableTwoFactorReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e DisableTwoFactorReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e DisableTwoFactorReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e DisableTwoFactorReplyValidationError) ErrorName() string {
+	return "DisableTwoFactorReplyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e DisableTwoFactorReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sDisableTwoFactorReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = DisableTwoFactorReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = DisableTwoFactorReplyValidationError{}
+
+// Validate checks the field values on OidcLoginRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *OidcLoginRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OidcLoginRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// OidcLoginRequestMultiError, or nil if none found.
+func (m *OidcLoginRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OidcLoginRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Admin
+
+	if len(errors) > 0 {
+		return OidcLoginRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// OidcLoginRequestMultiError is an error wrapping multiple validation errors
+// returned by OidcLoginRequest.ValidateAll() if the designated constraints
+// aren't met.
+type OidcLoginRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OidcLoginRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OidcLoginRequestMultiError) AllErrors() []error { return m }
+
+// OidcLoginRequestValidationError is the validation error returned by
+// OidcLoginRequest.Validate if the designated constraints aren't met.
+type OidcLoginRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OidcLoginRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OidcLoginRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OidcLoginRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OidcLoginRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OidcLoginRequestValidationError) ErrorName() string { return "OidcLoginRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e OidcLoginRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOidcLoginRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OidcLoginRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OidcLoginRequestValidationError{}
+
+// Validate checks the field values on OidcLoginReply with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *OidcLoginReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OidcLoginReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in OidcLoginReplyMultiError,
+// or nil if none found.
+func (m *OidcLoginReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OidcLoginReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AuthorizationUrl
+
+	// no validation rules for State
+
+	if len(errors) > 0 {
+		return OidcLoginReplyMultiError(errors)
+	}
+
+	return nil
+}
+
+// OidcLoginReplyMultiError is an error wrapping multiple validation errors
+// returned by OidcLoginReply.ValidateAll() if the designated constraints
+// aren't met.
+type OidcLoginReplyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OidcLoginReplyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OidcLoginReplyMultiError) AllErrors() []error { return m }
+
+// OidcLoginReplyValidationError is the validation error returned by
+// OidcLoginReply.Validate if the designated constraints aren't met.
+type OidcLoginReplyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OidcLoginReplyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OidcLoginReplyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OidcLoginReplyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OidcLoginReplyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OidcLoginReplyValidationError) ErrorName() string { return "OidcLoginReplyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e OidcLoginReplyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOidcLoginReply.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OidcLoginReplyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OidcLoginReplyValidationError{}
+
+// Validate checks the field values on OidcCallbackRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *OidcCallbackRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OidcCallbackRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// OidcCallbackRequestMultiError, or nil if none found.
+func (m *OidcCallbackRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OidcCallbackRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetCode()) < 1 {
+		err := OidcCallbackRequestValidationError{
+			field:  "Code",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetState()) < 1 {
+		err := OidcCallbackRequestValidationError{
+			field:  "State",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return OidcCallbackRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// OidcCallbackRequestMultiError is an error wrapping multiple validation
+// errors returned by OidcCallbackRequest.ValidateAll() if the designated
+// constraints aren't met.
+type OidcCallbackRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OidcCallbackRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OidcCallbackRequestMultiError) AllErrors() []error { return m }
+
+// OidcCallbackRequestValidationError is the validation error returned by
+// OidcCallbackRequest.Validate if the designated constraints aren't met.
+type OidcCallbackRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OidcCallbackRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OidcCallbackRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OidcCallbackRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OidcCallbackRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OidcCallbackRequestValidationError) ErrorName() string {
+	return "OidcCallbackRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e OidcCallbackRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOidcCallbackRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OidcCallbackRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OidcCallbackRequestValidationError{}
+
+// Validate checks the field values on OidcCallbackReply with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *OidcCallbackReply) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OidcCallbackReply with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// OidcCallbackReplyMultiError, or nil if none found.
+func (m *OidcCallbackReply) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OidcCallbackReply) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Success
+
+	// no validation rules for Username
+
+	// no validation rules for Token
+
+	// no validation rules for RegistrationTicket
+
+	if all {
+		switch v := interface{}(m.GetTicketExpireTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, OidcCallbackReplyValidationError{
+					field:  "TicketExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, OidcCallbackReplyValidationError{
+					field:  "TicketExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTicketExpireTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return OidcCallbackReplyValidationError{
+				field:  "TicketExpireTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for AdminToken
+
+	if all {
+		switch v := interface{}(m.GetAdminExpireTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, OidcCallbackReplyValidationError{
+					field:  "AdminExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, OidcCallbackReplyValidationError{
+					field:  "AdminExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAdminExpireTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return OidcCallbackReplyValidationError{
+				field:  "AdminExpireTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for TwoFactorTicket
 
 	if len(errors) > 0 {
-		return RegisterReplyMultiError(errors)
+		return OidcCallbackReplyMultiError(errors)
 	}
 
 	return nil
 }
 
-// RegisterReplyMultiError is an error wrapping multiple validation errors
-// returned by RegisterReply.ValidateAll() if the designated constraints
+// OidcCallbackReplyMultiError is an error wrapping multiple validation errors
+// returned by OidcCallbackReply.ValidateAll() if the designated constraints
 // aren't met.
-type RegisterReplyMultiError []error
+type OidcCallbackReplyMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m RegisterReplyMultiError) Error() string {
+func (m OidcCallbackReplyMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -302,11 +5905,11 @@ func (m RegisterReplyMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m RegisterReplyMultiError) AllErrors() []error { return m }
+func (m OidcCallbackReplyMultiError) AllErrors() []error { return m }
 
-// RegisterReplyValidationError is the validation error returned by
-// RegisterReply.Validate if the designated constraints aren't met.
-type RegisterReplyValidationError struct {
+// OidcCallbackReplyValidationError is the validation error returned by
+// OidcCallbackReply.Validate if the designated constraints aren't met.
+type OidcCallbackReplyValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -314,22 +5917,24 @@ type RegisterReplyValidationError struct {
 }
 
 // Field function returns field value.
-func (e RegisterReplyValidationError) Field() string { return e.field }
+func (e OidcCallbackReplyValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e RegisterReplyValidationError) Reason() string { return e.reason }
+func (e OidcCallbackReplyValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e RegisterReplyValidationError) Cause() error { return e.cause }
+func (e OidcCallbackReplyValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e RegisterReplyValidationError) Key() bool { return e.key }
+func (e OidcCallbackReplyValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e RegisterReplyValidationError) ErrorName() string { return "RegisterReplyValidationError" }
+func (e OidcCallbackReplyValidationError) ErrorName() string {
+	return "OidcCallbackReplyValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e RegisterReplyValidationError) Error() string {
+func (e OidcCallbackReplyValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -341,14 +5946,14 @@ func (e RegisterReplyValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sRegisterReply.%s: %s%s",
+		"invalid %sOidcCallbackReply.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = RegisterReplyValidationError{}
+var _ error = OidcCallbackReplyValidationError{}
 
 var _ interface {
 	Field() string
@@ -356,47 +5961,55 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = RegisterReplyValidationError{}
+} = OidcCallbackReplyValidationError{}
 
-// Validate checks the field values on LoginReply with the rules defined in the
-// proto definition for this message. If any rules are violated, the first
-// error encountered is returned, or nil if there are no violations.
-func (m *LoginReply) Validate() error {
+// Validate checks the field values on OidcTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *OidcTwoFactorRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on LoginReply with the rules defined in
-// the proto definition for this message. If any rules are violated, the
-// result is a list of violation errors wrapped in LoginReplyMultiError, or
-// nil if none found.
-func (m *LoginReply) ValidateAll() error {
+// ValidateAll checks the field values on OidcTwoFactorRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// OidcTwoFactorRequestMultiError, or nil if none found.
+func (m *OidcTwoFactorRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *LoginReply) validate(all bool) error {
+func (m *OidcTwoFactorRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Success
-
-	// no validation rules for Token
+	if utf8.RuneCountInString(m.GetTicket()) < 1 {
+		err := OidcTwoFactorRequestValidationError{
+			field:  "Ticket",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
 
 	if len(errors) > 0 {
-		return LoginReplyMultiError(errors)
+		return OidcTwoFactorRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// LoginReplyMultiError is an error wrapping multiple validation errors
-// returned by LoginReply.ValidateAll() if the designated constraints aren't met.
-type LoginReplyMultiError []error
+// OidcTwoFactorRequestMultiError is an error wrapping multiple validation
+// errors returned by OidcTwoFactorRequest.ValidateAll() if the designated
+// constraints aren't met.
+type OidcTwoFactorRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m LoginReplyMultiError) Error() string {
+func (m OidcTwoFactorRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -405,11 +6018,11 @@ func (m LoginReplyMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m LoginReplyMultiError) AllErrors() []error { return m }
+func (m OidcTwoFactorRequestMultiError) AllErrors() []error { return m }
 
-// LoginReplyValidationError is the validation error returned by
-// LoginReply.Validate if the designated constraints aren't met.
-type LoginReplyValidationError struct {
+// OidcTwoFactorRequestValidationError is the validation error returned by
+// OidcTwoFactorRequest.Validate if the designated constraints aren't met.
+type OidcTwoFactorRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -417,22 +6030,24 @@ type LoginReplyValidationError struct {
 }
 
 // Field function returns field value.
-func (e LoginReplyValidationError) Field() string { return e.field }
+func (e OidcTwoFactorRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e LoginReplyValidationError) Reason() string { return e.reason }
+func (e OidcTwoFactorRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e LoginReplyValidationError) Cause() error { return e.cause }
+func (e OidcTwoFactorRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e LoginReplyValidationError) Key() bool { return e.key }
+func (e OidcTwoFactorRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e LoginReplyValidationError) ErrorName() string { return "LoginReplyValidationError" }
+func (e OidcTwoFactorRequestValidationError) ErrorName() string {
+	return "OidcTwoFactorRequestValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e LoginReplyValidationError) Error() string {
+func (e OidcTwoFactorRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -444,14 +6059,14 @@ func (e LoginReplyValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sLoginReply.%s: %s%s",
+		"invalid %sOidcTwoFactorRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = LoginReplyValidationError{}
+var _ error = OidcTwoFactorRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -459,46 +6074,66 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = LoginReplyValidationError{}
+} = OidcTwoFactorRequestValidationError{}
 
-// Validate checks the field values on UnregisterReply with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *UnregisterReply) Validate() error {
+// Validate checks the field values on CreateExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *CreateExportRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on UnregisterReply with the rules
+// ValidateAll checks the field values on CreateExportRequest with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// UnregisterReplyMultiError, or nil if none found.
-func (m *UnregisterReply) ValidateAll() error {
+// CreateExportRequestMultiError, or nil if none found.
+func (m *CreateExportRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *UnregisterReply) validate(all bool) error {
+func (m *CreateExportRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Success
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := CreateExportRequestValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := _CreateExportRequest_Format_InLookup[m.GetFormat()]; !ok {
+		err := CreateExportRequestValidationError{
+			field:  "Format",
+			reason: "value must be in list [ csv line_protocol]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
 
 	if len(errors) > 0 {
-		return UnregisterReplyMultiError(errors)
+		return CreateExportRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// UnregisterReplyMultiError is an error wrapping multiple validation errors
-// returned by UnregisterReply.ValidateAll() if the designated constraints
-// aren't met.
-type UnregisterReplyMultiError []error
+// CreateExportRequestMultiError is an error wrapping multiple validation
+// errors returned by CreateExportRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CreateExportRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m UnregisterReplyMultiError) Error() string {
+func (m CreateExportRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -507,11 +6142,11 @@ func (m UnregisterReplyMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m UnregisterReplyMultiError) AllErrors() []error { return m }
+func (m CreateExportRequestMultiError) AllErrors() []error { return m }
 
-// UnregisterReplyValidationError is the validation error returned by
-// UnregisterReply.Validate if the designated constraints aren't met.
-type UnregisterReplyValidationError struct {
+// CreateExportRequestValidationError is the validation error returned by
+// CreateExportRequest.Validate if the designated constraints aren't met.
+type CreateExportRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -519,22 +6154,24 @@ type UnregisterReplyValidationError struct {
 }
 
 // Field function returns field value.
-func (e UnregisterReplyValidationError) Field() string { return e.field }
+func (e CreateExportRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e UnregisterReplyValidationError) Reason() string { return e.reason }
+func (e CreateExportRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e UnregisterReplyValidationError) Cause() error { return e.cause }
+func (e CreateExportRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e UnregisterReplyValidationError) Key() bool { return e.key }
+func (e CreateExportRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e UnregisterReplyValidationError) ErrorName() string { return "UnregisterReplyValidationError" }
+func (e CreateExportRequestValidationError) ErrorName() string {
+	return "CreateExportRequestValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e UnregisterReplyValidationError) Error() string {
+func (e CreateExportRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -546,14 +6183,14 @@ func (e UnregisterReplyValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sUnregisterReply.%s: %s%s",
+		"invalid %sCreateExportRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = UnregisterReplyValidationError{}
+var _ error = CreateExportRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -561,46 +6198,72 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = UnregisterReplyValidationError{}
+} = CreateExportRequestValidationError{}
 
-// Validate checks the field values on DownloadClientCodeRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *DownloadClientCodeRequest) Validate() error {
+var _CreateExportRequest_Format_InLookup = map[string]struct{}{
+	"":              {},
+	"csv":           {},
+	"line_protocol": {},
+}
+
+// Validate checks the field values on GetExportRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *GetExportRequest) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on DownloadClientCodeRequest with the
-// rules defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on GetExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// DownloadClientCodeRequestMultiError, or nil if none found.
-func (m *DownloadClientCodeRequest) ValidateAll() error {
+// GetExportRequestMultiError, or nil if none found.
+func (m *GetExportRequest) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *DownloadClientCodeRequest) validate(all bool) error {
+func (m *GetExportRequest) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Username
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		err := GetExportRequestValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := GetExportRequestValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
 
 	if len(errors) > 0 {
-		return DownloadClientCodeRequestMultiError(errors)
+		return GetExportRequestMultiError(errors)
 	}
 
 	return nil
 }
 
-// DownloadClientCodeRequestMultiError is an error wrapping multiple validation
-// errors returned by DownloadClientCodeRequest.ValidateAll() if the
-// designated constraints aren't met.
-type DownloadClientCodeRequestMultiError []error
+// GetExportRequestMultiError is an error wrapping multiple validation errors
+// returned by GetExportRequest.ValidateAll() if the designated constraints
+// aren't met.
+type GetExportRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m DownloadClientCodeRequestMultiError) Error() string {
+func (m GetExportRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -609,11 +6272,11 @@ func (m DownloadClientCodeRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m DownloadClientCodeRequestMultiError) AllErrors() []error { return m }
+func (m GetExportRequestMultiError) AllErrors() []error { return m }
 
-// DownloadClientCodeRequestValidationError is the validation error returned by
-// DownloadClientCodeRequest.Validate if the designated constraints aren't met.
-type DownloadClientCodeRequestValidationError struct {
+// GetExportRequestValidationError is the validation error returned by
+// GetExportRequest.Validate if the designated constraints aren't met.
+type GetExportRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -621,24 +6284,22 @@ type DownloadClientCodeRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e DownloadClientCodeRequestValidationError) Field() string { return e.field }
+func (e GetExportRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e DownloadClientCodeRequestValidationError) Reason() string { return e.reason }
+func (e GetExportRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e DownloadClientCodeRequestValidationError) Cause() error { return e.cause }
+func (e GetExportRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e DownloadClientCodeRequestValidationError) Key() bool { return e.key }
+func (e GetExportRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e DownloadClientCodeRequestValidationError) ErrorName() string {
-	return "DownloadClientCodeRequestValidationError"
-}
+func (e GetExportRequestValidationError) ErrorName() string { return "GetExportRequestValidationError" }
 
 // Error satisfies the builtin error interface
-func (e DownloadClientCodeRequestValidationError) Error() string {
+func (e GetExportRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -650,14 +6311,14 @@ func (e DownloadClientCodeRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sDownloadClientCodeRequest.%s: %s%s",
+		"invalid %sGetExportRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = DownloadClientCodeRequestValidationError{}
+var _ error = GetExportRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -665,46 +6326,117 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = DownloadClientCodeRequestValidationError{}
+} = GetExportRequestValidationError{}
 
-// Validate checks the field values on File with the rules defined in the proto
-// definition for this message. If any rules are violated, the first error
-// encountered is returned, or nil if there are no violations.
-func (m *File) Validate() error {
+// Validate checks the field values on ExportJob with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ExportJob) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on File with the rules defined in the
-// proto definition for this message. If any rules are violated, the result is
-// a list of violation errors wrapped in FileMultiError, or nil if none found.
-func (m *File) ValidateAll() error {
+// ValidateAll checks the field values on ExportJob with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ExportJobMultiError, or nil
+// if none found.
+func (m *ExportJob) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *File) validate(all bool) error {
+func (m *ExportJob) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Content
+	// no validation rules for Id
 
-	// no validation rules for Name
+	// no validation rules for Format
+
+	// no validation rules for State
+
+	// no validation rules for BucketsDone
+
+	// no validation rules for BucketsTotal
+
+	// no validation rules for Points
+
+	// no validation rules for Message
+
+	if all {
+		switch v := interface{}(m.GetCreateTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ExportJobValidationError{
+					field:  "CreateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ExportJobValidationError{
+					field:  "CreateTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetCreateTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ExportJobValidationError{
+				field:  "CreateTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for SizeBytes
+
+	if all {
+		switch v := interface{}(m.GetExpireTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ExportJobValidationError{
+					field:  "ExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ExportJobValidationError{
+					field:  "ExpireTime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpireTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ExportJobValidationError{
+				field:  "ExpireTime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
 
 	if len(errors) > 0 {
-		return FileMultiError(errors)
+		return ExportJobMultiError(errors)
 	}
 
 	return nil
 }
 
-// FileMultiError is an error wrapping multiple validation errors returned by
-// File.ValidateAll() if the designated constraints aren't met.
-type FileMultiError []error
+// ExportJobMultiError is an error wrapping multiple validation errors returned
+// by ExportJob.ValidateAll() if the designated constraints aren't met.
+type ExportJobMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m FileMultiError) Error() string {
+func (m ExportJobMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -713,11 +6445,11 @@ func (m FileMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m FileMultiError) AllErrors() []error { return m }
+func (m ExportJobMultiError) AllErrors() []error { return m }
 
-// FileValidationError is the validation error returned by File.Validate if the
-// designated constraints aren't met.
-type FileValidationError struct {
+// ExportJobValidationError is the validation error returned by
+// ExportJob.Validate if the designated constraints aren't met.
+type ExportJobValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -725,22 +6457,22 @@ type FileValidationError struct {
 }
 
 // Field function returns field value.
-func (e FileValidationError) Field() string { return e.field }
+func (e ExportJobValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e FileValidationError) Reason() string { return e.reason }
+func (e ExportJobValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e FileValidationError) Cause() error { return e.cause }
+func (e ExportJobValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e FileValidationError) Key() bool { return e.key }
+func (e ExportJobValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e FileValidationError) ErrorName() string { return "FileValidationError" }
+func (e ExportJobValidationError) ErrorName() string { return "ExportJobValidationError" }
 
 // Error satisfies the builtin error interface
-func (e FileValidationError) Error() string {
+func (e ExportJobValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -752,14 +6484,14 @@ func (e FileValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sFile.%s: %s%s",
+		"invalid %sExportJob.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = FileValidationError{}
+var _ error = ExportJobValidationError{}
 
 var _ interface {
 	Field() string
@@ -767,4 +6499,4 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = FileValidationError{}
+} = ExportJobValidationError{}
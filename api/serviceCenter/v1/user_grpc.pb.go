@@ -29,10 +29,52 @@ type UserClient interface {
 	GetRegisterInfo(ctx context.Context, in *GetRegisterInfoRequest, opts ...grpc.CallOption) (*GetRegisterInfoReply, error)
 	// 用户登录验证
 	Login(ctx context.Context, in *v1.User, opts ...grpc.CallOption) (*LoginReply, error)
-	// 用户注销
-	Unregister(ctx context.Context, in *v1.User, opts ...grpc.CallOption) (*UnregisterReply, error)
+	// 用户注销，注销后账号进入宽限期，用户服务与路由被暂停，宽限期结束后清理用户的所有资源
+	Unregister(ctx context.Context, in *UnregisterRequest, opts ...grpc.CallOption) (*UnregisterReply, error)
+	// 创建导出用户时序数据的任务，导出的归档中包括用户三个bucket中的数据以及用户的注册信息，需要admin及以上的角色
+	CreateExport(ctx context.Context, in *CreateExportRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	// 查询导出任务的进度，任务完成后通过GET /users/export/{id}?token=以流的形式下载归档，
+	// 归档在有效期后被删除
+	GetExportStatus(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	// 在宽限期内恢复已注销的账号，恢复用户服务与路由
+	Restore(ctx context.Context, in *v1.User, opts ...grpc.CallOption) (*RestoreReply, error)
 	// 获得客户端代码
 	DownloadClientCode(ctx context.Context, in *DownloadClientCodeRequest, opts ...grpc.CallOption) (*File, error)
+	// 获得用户服务的运行状态，包括容器、网关路由以及influxdb bucket的状态
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusReply, error)
+	// 获得用户每日的资源使用量
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageReply, error)
+	// 验证用户注册时填写的邮箱，验证通过后才开始为用户创建服务
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*RegisterReply, error)
+	// 申请重置密码，向用户发送一次性的重置令牌，为避免泄露账号是否存在，账号不存在时同样返回成功
+	RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*PasswordResetReply, error)
+	// 使用重置令牌设置新的密码，可选地同时更换用户的api密钥
+	ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetReply, error)
+	// 邀请成员加入token所属的组织，需要admin及以上的角色，且只能邀请角色低于自身的成员
+	CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*Invitation, error)
+	// 接受邀请，以给定的账号密码创建组织的成员，返回成员的token
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*LoginReply, error)
+	// 获得token所属组织的所有成员
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersReply, error)
+	// 修改成员的角色，需要admin及以上的角色，且只能管理角色低于自身的成员
+	UpdateMemberRole(ctx context.Context, in *UpdateMemberRoleRequest, opts ...grpc.CallOption) (*Member, error)
+	// 移除组织的成员，成员的token立即失效
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*RemoveMemberReply, error)
+	// 为token对应的账号生成TOTP二次验证的密钥，验证通过后二次验证才会生效。
+	// 二次验证生效后，登录、注销以及更换api密钥时需要在X-Two-Factor-Code请求头中携带验证码或恢复码
+	EnrollTwoFactor(ctx context.Context, in *EnrollTwoFactorRequest, opts ...grpc.CallOption) (*EnrollTwoFactorReply, error)
+	// 验证TOTP验证码，验证通过后启用二次验证，并返回一次性的恢复码
+	VerifyTwoFactor(ctx context.Context, in *VerifyTwoFactorRequest, opts ...grpc.CallOption) (*VerifyTwoFactorReply, error)
+	// 关闭二次验证，需要提供验证码或恢复码
+	DisableTwoFactor(ctx context.Context, in *DisableTwoFactorRequest, opts ...grpc.CallOption) (*DisableTwoFactorReply, error)
+	// 开始OIDC单点登录，返回身份提供方的授权地址，使用带PKCE的授权码流程
+	OidcLogin(ctx context.Context, in *OidcLoginRequest, opts ...grpc.CallOption) (*OidcLoginReply, error)
+	// OIDC登录的回调，身份已关联租户时返回租户的token，否则返回用于首次注册的凭证，
+	// 管理员登录时返回在X-Admin-Token请求头中携带的管理令牌，
+	// 租户启用了二次验证且未在X-Two-Factor-Code请求头中携带验证码时，返回用于完成二次验证的凭证
+	OidcCallback(ctx context.Context, in *OidcCallbackRequest, opts ...grpc.CallOption) (*OidcCallbackReply, error)
+	// 以OIDC回调返回的二次验证凭证以及X-Two-Factor-Code请求头中的验证码完成OIDC登录，返回租户的token
+	OidcTwoFactor(ctx context.Context, in *OidcTwoFactorRequest, opts ...grpc.CallOption) (*OidcCallbackReply, error)
 }
 
 type userClient struct {
@@ -70,7 +112,7 @@ func (c *userClient) Login(ctx context.Context, in *v1.User, opts ...grpc.CallOp
 	return out, nil
 }
 
-func (c *userClient) Unregister(ctx context.Context, in *v1.User, opts ...grpc.CallOption) (*UnregisterReply, error) {
+func (c *userClient) Unregister(ctx context.Context, in *UnregisterRequest, opts ...grpc.CallOption) (*UnregisterReply, error) {
 	out := new(UnregisterReply)
 	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/Unregister", in, out, opts...)
 	if err != nil {
@@ -79,6 +121,33 @@ func (c *userClient) Unregister(ctx context.Context, in *v1.User, opts ...grpc.C
 	return out, nil
 }
 
+func (c *userClient) CreateExport(ctx context.Context, in *CreateExportRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/CreateExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GetExportStatus(ctx context.Context, in *GetExportRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/GetExportStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Restore(ctx context.Context, in *v1.User, opts ...grpc.CallOption) (*RestoreReply, error) {
+	out := new(RestoreReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userClient) DownloadClientCode(ctx context.Context, in *DownloadClientCodeRequest, opts ...grpc.CallOption) (*File, error) {
 	out := new(File)
 	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/DownloadClientCode", in, out, opts...)
@@ -88,6 +157,150 @@ func (c *userClient) DownloadClientCode(ctx context.Context, in *DownloadClientC
 	return out, nil
 }
 
+func (c *userClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusReply, error) {
+	out := new(GetStatusReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageReply, error) {
+	out := new(GetUsageReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*RegisterReply, error) {
+	out := new(RegisterReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/VerifyEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*PasswordResetReply, error) {
+	out := new(PasswordResetReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/RequestPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetReply, error) {
+	out := new(ConfirmPasswordResetReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/ConfirmPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...grpc.CallOption) (*Invitation, error) {
+	out := new(Invitation)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/CreateInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersReply, error) {
+	out := new(ListMembersReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/ListMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UpdateMemberRole(ctx context.Context, in *UpdateMemberRoleRequest, opts ...grpc.CallOption) (*Member, error) {
+	out := new(Member)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/UpdateMemberRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*RemoveMemberReply, error) {
+	out := new(RemoveMemberReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) EnrollTwoFactor(ctx context.Context, in *EnrollTwoFactorRequest, opts ...grpc.CallOption) (*EnrollTwoFactorReply, error) {
+	out := new(EnrollTwoFactorReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/EnrollTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) VerifyTwoFactor(ctx context.Context, in *VerifyTwoFactorRequest, opts ...grpc.CallOption) (*VerifyTwoFactorReply, error) {
+	out := new(VerifyTwoFactorReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/VerifyTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DisableTwoFactor(ctx context.Context, in *DisableTwoFactorRequest, opts ...grpc.CallOption) (*DisableTwoFactorReply, error) {
+	out := new(DisableTwoFactorReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/DisableTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) OidcLogin(ctx context.Context, in *OidcLoginRequest, opts ...grpc.CallOption) (*OidcLoginReply, error) {
+	out := new(OidcLoginReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/OidcLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) OidcCallback(ctx context.Context, in *OidcCallbackRequest, opts ...grpc.CallOption) (*OidcCallbackReply, error) {
+	out := new(OidcCallbackReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/OidcCallback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) OidcTwoFactor(ctx context.Context, in *OidcTwoFactorRequest, opts ...grpc.CallOption) (*OidcCallbackReply, error) {
+	out := new(OidcCallbackReply)
+	err := c.cc.Invoke(ctx, "/api.serviceCentre.v1.User/OidcTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServer is the server API for User service.
 // All implementations must embed UnimplementedUserServer
 // for forward compatibility
@@ -98,10 +311,52 @@ type UserServer interface {
 	GetRegisterInfo(context.Context, *GetRegisterInfoRequest) (*GetRegisterInfoReply, error)
 	// 用户登录验证
 	Login(context.Context, *v1.User) (*LoginReply, error)
-	// 用户注销
-	Unregister(context.Context, *v1.User) (*UnregisterReply, error)
+	// 用户注销，注销后账号进入宽限期，用户服务与路由被暂停，宽限期结束后清理用户的所有资源
+	Unregister(context.Context, *UnregisterRequest) (*UnregisterReply, error)
+	// 创建导出用户时序数据的任务，导出的归档中包括用户三个bucket中的数据以及用户的注册信息，需要admin及以上的角色
+	CreateExport(context.Context, *CreateExportRequest) (*ExportJob, error)
+	// 查询导出任务的进度，任务完成后通过GET /users/export/{id}?token=以流的形式下载归档，
+	// 归档在有效期后被删除
+	GetExportStatus(context.Context, *GetExportRequest) (*ExportJob, error)
+	// 在宽限期内恢复已注销的账号，恢复用户服务与路由
+	Restore(context.Context, *v1.User) (*RestoreReply, error)
 	// 获得客户端代码
 	DownloadClientCode(context.Context, *DownloadClientCodeRequest) (*File, error)
+	// 获得用户服务的运行状态，包括容器、网关路由以及influxdb bucket的状态
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusReply, error)
+	// 获得用户每日的资源使用量
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageReply, error)
+	// 验证用户注册时填写的邮箱，验证通过后才开始为用户创建服务
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*RegisterReply, error)
+	// 申请重置密码，向用户发送一次性的重置令牌，为避免泄露账号是否存在，账号不存在时同样返回成功
+	RequestPasswordReset(context.Context, *PasswordResetRequest) (*PasswordResetReply, error)
+	// 使用重置令牌设置新的密码，可选地同时更换用户的api密钥
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetReply, error)
+	// 邀请成员加入token所属的组织，需要admin及以上的角色，且只能邀请角色低于自身的成员
+	CreateInvitation(context.Context, *CreateInvitationRequest) (*Invitation, error)
+	// 接受邀请，以给定的账号密码创建组织的成员，返回成员的token
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*LoginReply, error)
+	// 获得token所属组织的所有成员
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersReply, error)
+	// 修改成员的角色，需要admin及以上的角色，且只能管理角色低于自身的成员
+	UpdateMemberRole(context.Context, *UpdateMemberRoleRequest) (*Member, error)
+	// 移除组织的成员，成员的token立即失效
+	RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberReply, error)
+	// 为token对应的账号生成TOTP二次验证的密钥，验证通过后二次验证才会生效。
+	// 二次验证生效后，登录、注销以及更换api密钥时需要在X-Two-Factor-Code请求头中携带验证码或恢复码
+	EnrollTwoFactor(context.Context, *EnrollTwoFactorRequest) (*EnrollTwoFactorReply, error)
+	// 验证TOTP验证码，验证通过后启用二次验证，并返回一次性的恢复码
+	VerifyTwoFactor(context.Context, *VerifyTwoFactorRequest) (*VerifyTwoFactorReply, error)
+	// 关闭二次验证，需要提供验证码或恢复码
+	DisableTwoFactor(context.Context, *DisableTwoFactorRequest) (*DisableTwoFactorReply, error)
+	// 开始OIDC单点登录，返回身份提供方的授权地址，使用带PKCE的授权码流程
+	OidcLogin(context.Context, *OidcLoginRequest) (*OidcLoginReply, error)
+	// OIDC登录的回调，身份已关联租户时返回租户的token，否则返回用于首次注册的凭证，
+	// 管理员登录时返回在X-Admin-Token请求头中携带的管理令牌，
+	// 租户启用了二次验证且未在X-Two-Factor-Code请求头中携带验证码时，返回用于完成二次验证的凭证
+	OidcCallback(context.Context, *OidcCallbackRequest) (*OidcCallbackReply, error)
+	// 以OIDC回调返回的二次验证凭证以及X-Two-Factor-Code请求头中的验证码完成OIDC登录，返回租户的token
+	OidcTwoFactor(context.Context, *OidcTwoFactorRequest) (*OidcCallbackReply, error)
 	mustEmbedUnimplementedUserServer()
 }
 
@@ -118,12 +373,69 @@ func (UnimplementedUserServer) GetRegisterInfo(context.Context, *GetRegisterInfo
 func (UnimplementedUserServer) Login(context.Context, *v1.User) (*LoginReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
-func (UnimplementedUserServer) Unregister(context.Context, *v1.User) (*UnregisterReply, error) {
+func (UnimplementedUserServer) Unregister(context.Context, *UnregisterRequest) (*UnregisterReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Unregister not implemented")
 }
+func (UnimplementedUserServer) CreateExport(context.Context, *CreateExportRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateExport not implemented")
+}
+func (UnimplementedUserServer) GetExportStatus(context.Context, *GetExportRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportStatus not implemented")
+}
+func (UnimplementedUserServer) Restore(context.Context, *v1.User) (*RestoreReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 func (UnimplementedUserServer) DownloadClientCode(context.Context, *DownloadClientCodeRequest) (*File, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DownloadClientCode not implemented")
 }
+func (UnimplementedUserServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedUserServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedUserServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*RegisterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedUserServer) RequestPasswordReset(context.Context, *PasswordResetRequest) (*PasswordResetReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedUserServer) ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPasswordReset not implemented")
+}
+func (UnimplementedUserServer) CreateInvitation(context.Context, *CreateInvitationRequest) (*Invitation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvitation not implemented")
+}
+func (UnimplementedUserServer) AcceptInvitation(context.Context, *AcceptInvitationRequest) (*LoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (UnimplementedUserServer) ListMembers(context.Context, *ListMembersRequest) (*ListMembersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedUserServer) UpdateMemberRole(context.Context, *UpdateMemberRoleRequest) (*Member, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMemberRole not implemented")
+}
+func (UnimplementedUserServer) RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedUserServer) EnrollTwoFactor(context.Context, *EnrollTwoFactorRequest) (*EnrollTwoFactorReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTwoFactor not implemented")
+}
+func (UnimplementedUserServer) VerifyTwoFactor(context.Context, *VerifyTwoFactorRequest) (*VerifyTwoFactorReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyTwoFactor not implemented")
+}
+func (UnimplementedUserServer) DisableTwoFactor(context.Context, *DisableTwoFactorRequest) (*DisableTwoFactorReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTwoFactor not implemented")
+}
+func (UnimplementedUserServer) OidcLogin(context.Context, *OidcLoginRequest) (*OidcLoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OidcLogin not implemented")
+}
+func (UnimplementedUserServer) OidcCallback(context.Context, *OidcCallbackRequest) (*OidcCallbackReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OidcCallback not implemented")
+}
+func (UnimplementedUserServer) OidcTwoFactor(context.Context, *OidcTwoFactorRequest) (*OidcCallbackReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OidcTwoFactor not implemented")
+}
 func (UnimplementedUserServer) mustEmbedUnimplementedUserServer() {}
 
 // UnsafeUserServer may be embedded to opt out of forward compatibility for this service.
@@ -192,7 +504,7 @@ func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interfac
 }
 
 func _User_Unregister_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(v1.User)
+	in := new(UnregisterRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -204,7 +516,61 @@ func _User_Unregister_Handler(srv interface{}, ctx context.Context, dec func(int
 		FullMethod: "/api.serviceCentre.v1.User/Unregister",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Unregister(ctx, req.(*v1.User))
+		return srv.(UserServer).Unregister(ctx, req.(*UnregisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/CreateExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateExport(ctx, req.(*CreateExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetExportStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetExportStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/GetExportStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetExportStatus(ctx, req.(*GetExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Restore(ctx, req.(*v1.User))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -227,6 +593,294 @@ func _User_DownloadClientCode_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _User_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/VerifyEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/RequestPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RequestPasswordReset(ctx, req.(*PasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ConfirmPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ConfirmPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/ConfirmPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/CreateInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateInvitation(ctx, req.(*CreateInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/ListMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ListMembers(ctx, req.(*ListMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UpdateMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMemberRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UpdateMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/UpdateMemberRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UpdateMemberRole(ctx, req.(*UpdateMemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_EnrollTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).EnrollTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/EnrollTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).EnrollTwoFactor(ctx, req.(*EnrollTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_VerifyTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).VerifyTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/VerifyTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).VerifyTwoFactor(ctx, req.(*VerifyTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_DisableTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DisableTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/DisableTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DisableTwoFactor(ctx, req.(*DisableTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_OidcLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OidcLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).OidcLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/OidcLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).OidcLogin(ctx, req.(*OidcLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_OidcCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OidcCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).OidcCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/OidcCallback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).OidcCallback(ctx, req.(*OidcCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_OidcTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OidcTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).OidcTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.serviceCentre.v1.User/OidcTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).OidcTwoFactor(ctx, req.(*OidcTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // User_ServiceDesc is the grpc.ServiceDesc for User service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -250,10 +904,86 @@ var User_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Unregister",
 			Handler:    _User_Unregister_Handler,
 		},
+		{
+			MethodName: "CreateExport",
+			Handler:    _User_CreateExport_Handler,
+		},
+		{
+			MethodName: "GetExportStatus",
+			Handler:    _User_GetExportStatus_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _User_Restore_Handler,
+		},
 		{
 			MethodName: "DownloadClientCode",
 			Handler:    _User_DownloadClientCode_Handler,
 		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _User_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _User_GetUsage_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _User_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _User_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ConfirmPasswordReset",
+			Handler:    _User_ConfirmPasswordReset_Handler,
+		},
+		{
+			MethodName: "CreateInvitation",
+			Handler:    _User_CreateInvitation_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _User_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _User_ListMembers_Handler,
+		},
+		{
+			MethodName: "UpdateMemberRole",
+			Handler:    _User_UpdateMemberRole_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _User_RemoveMember_Handler,
+		},
+		{
+			MethodName: "EnrollTwoFactor",
+			Handler:    _User_EnrollTwoFactor_Handler,
+		},
+		{
+			MethodName: "VerifyTwoFactor",
+			Handler:    _User_VerifyTwoFactor_Handler,
+		},
+		{
+			MethodName: "DisableTwoFactor",
+			Handler:    _User_DisableTwoFactor_Handler,
+		},
+		{
+			MethodName: "OidcLogin",
+			Handler:    _User_OidcLogin_Handler,
+		},
+		{
+			MethodName: "OidcCallback",
+			Handler:    _User_OidcCallback_Handler,
+		},
+		{
+			MethodName: "OidcTwoFactor",
+			Handler:    _User_OidcTwoFactor_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/serviceCenter/v1/user.proto",
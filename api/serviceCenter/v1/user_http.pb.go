@@ -19,11 +19,30 @@ var _ = binding.EncodeURL
 const _ = http.SupportPackageIsVersion1
 
 type UserHTTPServer interface {
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*LoginReply, error)
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetReply, error)
+	CreateExport(context.Context, *CreateExportRequest) (*ExportJob, error)
+	CreateInvitation(context.Context, *CreateInvitationRequest) (*Invitation, error)
+	DisableTwoFactor(context.Context, *DisableTwoFactorRequest) (*DisableTwoFactorReply, error)
 	DownloadClientCode(context.Context, *DownloadClientCodeRequest) (*File, error)
+	EnrollTwoFactor(context.Context, *EnrollTwoFactorRequest) (*EnrollTwoFactorReply, error)
+	GetExportStatus(context.Context, *GetExportRequest) (*ExportJob, error)
 	GetRegisterInfo(context.Context, *GetRegisterInfoRequest) (*GetRegisterInfoReply, error)
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusReply, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageReply, error)
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersReply, error)
 	Login(context.Context, *v1.User) (*LoginReply, error)
+	OidcCallback(context.Context, *OidcCallbackRequest) (*OidcCallbackReply, error)
+	OidcLogin(context.Context, *OidcLoginRequest) (*OidcLoginReply, error)
+	OidcTwoFactor(context.Context, *OidcTwoFactorRequest) (*OidcCallbackReply, error)
 	Register(context.Context, *RegisterRequest) (*RegisterReply, error)
-	Unregister(context.Context, *v1.User) (*UnregisterReply, error)
+	RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberReply, error)
+	RequestPasswordReset(context.Context, *PasswordResetRequest) (*PasswordResetReply, error)
+	Restore(context.Context, *v1.User) (*RestoreReply, error)
+	Unregister(context.Context, *UnregisterRequest) (*UnregisterReply, error)
+	UpdateMemberRole(context.Context, *UpdateMemberRoleRequest) (*Member, error)
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*RegisterReply, error)
+	VerifyTwoFactor(context.Context, *VerifyTwoFactorRequest) (*VerifyTwoFactorReply, error)
 }
 
 func RegisterUserHTTPServer(s *http.Server, srv UserHTTPServer) {
@@ -32,7 +51,26 @@ func RegisterUserHTTPServer(s *http.Server, srv UserHTTPServer) {
 	r.GET("/users/register-info/{token}", _User_GetRegisterInfo0_HTTP_Handler(srv))
 	r.GET("/users", _User_Login0_HTTP_Handler(srv))
 	r.DELETE("/users", _User_Unregister0_HTTP_Handler(srv))
+	r.POST("/users/export", _User_CreateExport0_HTTP_Handler(srv))
+	r.GET("/users/export/{id}/status", _User_GetExportStatus0_HTTP_Handler(srv))
+	r.POST("/users/restore", _User_Restore0_HTTP_Handler(srv))
 	r.GET("/users/client-code/{username}", _User_DownloadClientCode0_HTTP_Handler(srv))
+	r.GET("/users/status", _User_GetStatus0_HTTP_Handler(srv))
+	r.GET("/users/usage", _User_GetUsage0_HTTP_Handler(srv))
+	r.GET("/users/verify", _User_VerifyEmail0_HTTP_Handler(srv))
+	r.POST("/users/password-reset", _User_RequestPasswordReset0_HTTP_Handler(srv))
+	r.POST("/users/password-reset/confirm", _User_ConfirmPasswordReset0_HTTP_Handler(srv))
+	r.POST("/orgs/invitations", _User_CreateInvitation0_HTTP_Handler(srv))
+	r.POST("/orgs/invitations/accept", _User_AcceptInvitation0_HTTP_Handler(srv))
+	r.GET("/orgs/members", _User_ListMembers0_HTTP_Handler(srv))
+	r.POST("/orgs/members/{member_id}/role", _User_UpdateMemberRole0_HTTP_Handler(srv))
+	r.DELETE("/orgs/members/{member_id}", _User_RemoveMember0_HTTP_Handler(srv))
+	r.POST("/users/2fa/enroll", _User_EnrollTwoFactor0_HTTP_Handler(srv))
+	r.POST("/users/2fa/verify", _User_VerifyTwoFactor0_HTTP_Handler(srv))
+	r.POST("/users/2fa/disable", _User_DisableTwoFactor0_HTTP_Handler(srv))
+	r.GET("/oidc/login", _User_OidcLogin0_HTTP_Handler(srv))
+	r.GET("/oidc/callback", _User_OidcCallback0_HTTP_Handler(srv))
+	r.POST("/oidc/2fa", _User_OidcTwoFactor0_HTTP_Handler(srv))
 }
 
 func _User_Register0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
@@ -97,13 +135,13 @@ func _User_Login0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error
 
 func _User_Unregister0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
 	return func(ctx http.Context) error {
-		var in v1.User
+		var in UnregisterRequest
 		if err := ctx.BindQuery(&in); err != nil {
 			return err
 		}
 		http.SetOperation(ctx, "/api.serviceCentre.v1.User/Unregister")
 		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
-			return srv.Unregister(ctx, req.(*v1.User))
+			return srv.Unregister(ctx, req.(*UnregisterRequest))
 		})
 		out, err := h(ctx, &in)
 		if err != nil {
@@ -114,6 +152,66 @@ func _User_Unregister0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) e
 	}
 }
 
+func _User_CreateExport0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CreateExportRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/CreateExport")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreateExport(ctx, req.(*CreateExportRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ExportJob)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_GetExportStatus0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetExportRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/GetExportStatus")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetExportStatus(ctx, req.(*GetExportRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ExportJob)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_Restore0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in v1.User
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/Restore")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.Restore(ctx, req.(*v1.User))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RestoreReply)
+		return ctx.Result(200, reply)
+	}
+}
+
 func _User_DownloadClientCode0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
 	return func(ctx http.Context) error {
 		var in DownloadClientCodeRequest
@@ -136,12 +234,341 @@ func _User_DownloadClientCode0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Co
 	}
 }
 
+func _User_GetStatus0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetStatusRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/GetStatus")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetStatus(ctx, req.(*GetStatusRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetStatusReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_GetUsage0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetUsageRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/GetUsage")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetUsage(ctx, req.(*GetUsageRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*GetUsageReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_VerifyEmail0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in VerifyEmailRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/VerifyEmail")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.VerifyEmail(ctx, req.(*VerifyEmailRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RegisterReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_RequestPasswordReset0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in PasswordResetRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/RequestPasswordReset")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RequestPasswordReset(ctx, req.(*PasswordResetRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*PasswordResetReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_ConfirmPasswordReset0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ConfirmPasswordResetRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/ConfirmPasswordReset")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ConfirmPasswordResetReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_CreateInvitation0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CreateInvitationRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/CreateInvitation")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreateInvitation(ctx, req.(*CreateInvitationRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*Invitation)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_AcceptInvitation0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in AcceptInvitationRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/AcceptInvitation")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*LoginReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_ListMembers0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListMembersRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/ListMembers")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListMembers(ctx, req.(*ListMembersRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListMembersReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_UpdateMemberRole0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in UpdateMemberRoleRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/UpdateMemberRole")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.UpdateMemberRole(ctx, req.(*UpdateMemberRoleRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*Member)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_RemoveMember0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in RemoveMemberRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/RemoveMember")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.RemoveMember(ctx, req.(*RemoveMemberRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*RemoveMemberReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_EnrollTwoFactor0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in EnrollTwoFactorRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/EnrollTwoFactor")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.EnrollTwoFactor(ctx, req.(*EnrollTwoFactorRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*EnrollTwoFactorReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_VerifyTwoFactor0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in VerifyTwoFactorRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/VerifyTwoFactor")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.VerifyTwoFactor(ctx, req.(*VerifyTwoFactorRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*VerifyTwoFactorReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_DisableTwoFactor0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in DisableTwoFactorRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/DisableTwoFactor")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.DisableTwoFactor(ctx, req.(*DisableTwoFactorRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*DisableTwoFactorReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_OidcLogin0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in OidcLoginRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/OidcLogin")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.OidcLogin(ctx, req.(*OidcLoginRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*OidcLoginReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_OidcCallback0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in OidcCallbackRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/OidcCallback")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.OidcCallback(ctx, req.(*OidcCallbackRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*OidcCallbackReply)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _User_OidcTwoFactor0_HTTP_Handler(srv UserHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in OidcTwoFactorRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, "/api.serviceCentre.v1.User/OidcTwoFactor")
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.OidcTwoFactor(ctx, req.(*OidcTwoFactorRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*OidcCallbackReply)
+		return ctx.Result(200, reply)
+	}
+}
+
 type UserHTTPClient interface {
+	AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest, opts ...http.CallOption) (rsp *LoginReply, err error)
+	ConfirmPasswordReset(ctx context.Context, req *ConfirmPasswordResetRequest, opts ...http.CallOption) (rsp *ConfirmPasswordResetReply, err error)
+	CreateExport(ctx context.Context, req *CreateExportRequest, opts ...http.CallOption) (rsp *ExportJob, err error)
+	CreateInvitation(ctx context.Context, req *CreateInvitationRequest, opts ...http.CallOption) (rsp *Invitation, err error)
+	DisableTwoFactor(ctx context.Context, req *DisableTwoFactorRequest, opts ...http.CallOption) (rsp *DisableTwoFactorReply, err error)
 	DownloadClientCode(ctx context.Context, req *DownloadClientCodeRequest, opts ...http.CallOption) (rsp *File, err error)
+	EnrollTwoFactor(ctx context.Context, req *EnrollTwoFactorRequest, opts ...http.CallOption) (rsp *EnrollTwoFactorReply, err error)
+	GetExportStatus(ctx context.Context, req *GetExportRequest, opts ...http.CallOption) (rsp *ExportJob, err error)
 	GetRegisterInfo(ctx context.Context, req *GetRegisterInfoRequest, opts ...http.CallOption) (rsp *GetRegisterInfoReply, err error)
+	GetStatus(ctx context.Context, req *GetStatusRequest, opts ...http.CallOption) (rsp *GetStatusReply, err error)
+	GetUsage(ctx context.Context, req *GetUsageRequest, opts ...http.CallOption) (rsp *GetUsageReply, err error)
+	ListMembers(ctx context.Context, req *ListMembersRequest, opts ...http.CallOption) (rsp *ListMembersReply, err error)
 	Login(ctx context.Context, req *v1.User, opts ...http.CallOption) (rsp *LoginReply, err error)
+	OidcCallback(ctx context.Context, req *OidcCallbackRequest, opts ...http.CallOption) (rsp *OidcCallbackReply, err error)
+	OidcLogin(ctx context.Context, req *OidcLoginRequest, opts ...http.CallOption) (rsp *OidcLoginReply, err error)
+	OidcTwoFactor(ctx context.Context, req *OidcTwoFactorRequest, opts ...http.CallOption) (rsp *OidcCallbackReply, err error)
 	Register(ctx context.Context, req *RegisterRequest, opts ...http.CallOption) (rsp *RegisterReply, err error)
-	Unregister(ctx context.Context, req *v1.User, opts ...http.CallOption) (rsp *UnregisterReply, err error)
+	RemoveMember(ctx context.Context, req *RemoveMemberRequest, opts ...http.CallOption) (rsp *RemoveMemberReply, err error)
+	RequestPasswordReset(ctx context.Context, req *PasswordResetRequest, opts ...http.CallOption) (rsp *PasswordResetReply, err error)
+	Restore(ctx context.Context, req *v1.User, opts ...http.CallOption) (rsp *RestoreReply, err error)
+	Unregister(ctx context.Context, req *UnregisterRequest, opts ...http.CallOption) (rsp *UnregisterReply, err error)
+	UpdateMemberRole(ctx context.Context, req *UpdateMemberRoleRequest, opts ...http.CallOption) (rsp *Member, err error)
+	VerifyEmail(ctx context.Context, req *VerifyEmailRequest, opts ...http.CallOption) (rsp *RegisterReply, err error)
+	VerifyTwoFactor(ctx context.Context, req *VerifyTwoFactorRequest, opts ...http.CallOption) (rsp *VerifyTwoFactorReply, err error)
 }
 
 type UserHTTPClientImpl struct {
@@ -152,6 +579,71 @@ func NewUserHTTPClient(client *http.Client) UserHTTPClient {
 	return &UserHTTPClientImpl{client}
 }
 
+func (c *UserHTTPClientImpl) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...http.CallOption) (*LoginReply, error) {
+	var out LoginReply
+	pattern := "/orgs/invitations/accept"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/AcceptInvitation"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...http.CallOption) (*ConfirmPasswordResetReply, error) {
+	var out ConfirmPasswordResetReply
+	pattern := "/users/password-reset/confirm"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/ConfirmPasswordReset"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) CreateExport(ctx context.Context, in *CreateExportRequest, opts ...http.CallOption) (*ExportJob, error) {
+	var out ExportJob
+	pattern := "/users/export"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/CreateExport"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) CreateInvitation(ctx context.Context, in *CreateInvitationRequest, opts ...http.CallOption) (*Invitation, error) {
+	var out Invitation
+	pattern := "/orgs/invitations"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/CreateInvitation"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) DisableTwoFactor(ctx context.Context, in *DisableTwoFactorRequest, opts ...http.CallOption) (*DisableTwoFactorReply, error) {
+	var out DisableTwoFactorReply
+	pattern := "/users/2fa/disable"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/DisableTwoFactor"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
 func (c *UserHTTPClientImpl) DownloadClientCode(ctx context.Context, in *DownloadClientCodeRequest, opts ...http.CallOption) (*File, error) {
 	var out File
 	pattern := "/users/client-code/{username}"
@@ -165,6 +657,32 @@ func (c *UserHTTPClientImpl) DownloadClientCode(ctx context.Context, in *Downloa
 	return &out, err
 }
 
+func (c *UserHTTPClientImpl) EnrollTwoFactor(ctx context.Context, in *EnrollTwoFactorRequest, opts ...http.CallOption) (*EnrollTwoFactorReply, error) {
+	var out EnrollTwoFactorReply
+	pattern := "/users/2fa/enroll"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/EnrollTwoFactor"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) GetExportStatus(ctx context.Context, in *GetExportRequest, opts ...http.CallOption) (*ExportJob, error) {
+	var out ExportJob
+	pattern := "/users/export/{id}/status"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/GetExportStatus"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
 func (c *UserHTTPClientImpl) GetRegisterInfo(ctx context.Context, in *GetRegisterInfoRequest, opts ...http.CallOption) (*GetRegisterInfoReply, error) {
 	var out GetRegisterInfoReply
 	pattern := "/users/register-info/{token}"
@@ -178,6 +696,45 @@ func (c *UserHTTPClientImpl) GetRegisterInfo(ctx context.Context, in *GetRegiste
 	return &out, err
 }
 
+func (c *UserHTTPClientImpl) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...http.CallOption) (*GetStatusReply, error) {
+	var out GetStatusReply
+	pattern := "/users/status"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/GetStatus"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...http.CallOption) (*GetUsageReply, error) {
+	var out GetUsageReply
+	pattern := "/users/usage"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/GetUsage"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...http.CallOption) (*ListMembersReply, error) {
+	var out ListMembersReply
+	pattern := "/orgs/members"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/ListMembers"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
 func (c *UserHTTPClientImpl) Login(ctx context.Context, in *v1.User, opts ...http.CallOption) (*LoginReply, error) {
 	var out LoginReply
 	pattern := "/users"
@@ -191,6 +748,45 @@ func (c *UserHTTPClientImpl) Login(ctx context.Context, in *v1.User, opts ...htt
 	return &out, err
 }
 
+func (c *UserHTTPClientImpl) OidcCallback(ctx context.Context, in *OidcCallbackRequest, opts ...http.CallOption) (*OidcCallbackReply, error) {
+	var out OidcCallbackReply
+	pattern := "/oidc/callback"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/OidcCallback"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) OidcLogin(ctx context.Context, in *OidcLoginRequest, opts ...http.CallOption) (*OidcLoginReply, error) {
+	var out OidcLoginReply
+	pattern := "/oidc/login"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/OidcLogin"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) OidcTwoFactor(ctx context.Context, in *OidcTwoFactorRequest, opts ...http.CallOption) (*OidcCallbackReply, error) {
+	var out OidcCallbackReply
+	pattern := "/oidc/2fa"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/OidcTwoFactor"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
 func (c *UserHTTPClientImpl) Register(ctx context.Context, in *RegisterRequest, opts ...http.CallOption) (*RegisterReply, error) {
 	var out RegisterReply
 	pattern := "/users"
@@ -204,7 +800,46 @@ func (c *UserHTTPClientImpl) Register(ctx context.Context, in *RegisterRequest,
 	return &out, err
 }
 
-func (c *UserHTTPClientImpl) Unregister(ctx context.Context, in *v1.User, opts ...http.CallOption) (*UnregisterReply, error) {
+func (c *UserHTTPClientImpl) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...http.CallOption) (*RemoveMemberReply, error) {
+	var out RemoveMemberReply
+	pattern := "/orgs/members/{member_id}"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/RemoveMember"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "DELETE", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...http.CallOption) (*PasswordResetReply, error) {
+	var out PasswordResetReply
+	pattern := "/users/password-reset"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/RequestPasswordReset"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) Restore(ctx context.Context, in *v1.User, opts ...http.CallOption) (*RestoreReply, error) {
+	var out RestoreReply
+	pattern := "/users/restore"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/Restore"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) Unregister(ctx context.Context, in *UnregisterRequest, opts ...http.CallOption) (*UnregisterReply, error) {
 	var out UnregisterReply
 	pattern := "/users"
 	path := binding.EncodeURL(pattern, in, true)
@@ -216,3 +851,42 @@ func (c *UserHTTPClientImpl) Unregister(ctx context.Context, in *v1.User, opts .
 	}
 	return &out, err
 }
+
+func (c *UserHTTPClientImpl) UpdateMemberRole(ctx context.Context, in *UpdateMemberRoleRequest, opts ...http.CallOption) (*Member, error) {
+	var out Member
+	pattern := "/orgs/members/{member_id}/role"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/UpdateMemberRole"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...http.CallOption) (*RegisterReply, error) {
+	var out RegisterReply
+	pattern := "/users/verify"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/VerifyEmail"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
+
+func (c *UserHTTPClientImpl) VerifyTwoFactor(ctx context.Context, in *VerifyTwoFactorRequest, opts ...http.CallOption) (*VerifyTwoFactorReply, error) {
+	var out VerifyTwoFactorReply
+	pattern := "/users/2fa/verify"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation("/api.serviceCentre.v1.User/VerifyTwoFactor"))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, err
+}
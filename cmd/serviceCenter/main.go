@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"gitee.com/moyusir/service-centre/internal/conf"
+	"gitee.com/moyusir/service-centre/internal/server"
 	util "gitee.com/moyusir/util/logger"
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/log"
@@ -28,7 +29,7 @@ func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
 }
 
-func newApp(logger log.Logger, hs *http.Server) *kratos.App {
+func newApp(logger log.Logger, hs *http.Server, ws *server.WorkerServer) *kratos.App {
 	return kratos.New(
 		kratos.ID(id),
 		kratos.Name(Name),
@@ -37,6 +38,7 @@ func newApp(logger log.Logger, hs *http.Server) *kratos.App {
 		kratos.Logger(logger),
 		kratos.Server(
 			hs,
+			ws,
 		),
 	)
 }
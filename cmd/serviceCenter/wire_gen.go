@@ -25,15 +25,102 @@ func initApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*
 		return nil, nil, err
 	}
 	userRepo := data.NewRedisRepo(dataData)
-	userUsecase, err := biz.NewUserUsecase(confServer, userRepo, logger)
+	deletionRepo := data.NewDeletionRepo(dataData)
+	timeSeriesAccountRepo := data.NewTimeSeriesAccountRepo(dataData)
+	externalInfluxdbRepo := data.NewExternalInfluxdbRepo(dataData)
+	eventOutbox := data.NewEventOutbox(dataData)
+	eventBus, cleanup2, err := data.NewEventBus(confData, dataData, logger)
 	if err != nil {
 		cleanup()
 		return nil, nil, err
 	}
-	userService := service.NewUserService(userUsecase)
-	httpServer := server.NewHTTPServer(confServer, userService, logger)
-	app := newApp(logger, httpServer)
+	eventPublisher := biz.NewEventPublisher(confData, eventOutbox, eventBus, logger)
+	webhookRepo := data.NewWebhookRepo(dataData)
+	webhookUsecase, err := biz.NewWebhookUsecase(confServer, webhookRepo, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	twoFactorRepo := data.NewTwoFactorRepo(dataData)
+	twoFactorUsecase, err := biz.NewTwoFactorUsecase(confServer, twoFactorRepo, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	auditRepo := data.NewAuditRepo(dataData)
+	auditUsecase := biz.NewAuditUsecase(confServer, auditRepo, logger)
+	userUsecase, err := biz.NewUserUsecase(confServer, userRepo, deletionRepo, timeSeriesAccountRepo, externalInfluxdbRepo, eventPublisher, webhookUsecase, twoFactorUsecase, auditUsecase, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	usageRepo := data.NewUsageRepo(dataData)
+	meteringUsecase, err := biz.NewMeteringUsecase(confServer, userUsecase, usageRepo, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	verificationRepo := data.NewVerificationRepo(dataData)
+	mailSender, err := data.NewMailSender(confServer, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	verificationUsecase, err := biz.NewVerificationUsecase(confServer, userUsecase, verificationRepo, mailSender, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	passwordResetRepo := data.NewPasswordResetRepo(dataData)
+	notifier, err := data.NewNotifier(confServer, mailSender, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	passwordResetUsecase := biz.NewPasswordResetUsecase(confServer, userUsecase, passwordResetRepo, notifier, logger)
+	orgRepo := data.NewOrgRepo(dataData)
+	organizationUsecase := biz.NewOrganizationUsecase(userUsecase, orgRepo, logger)
+	oidcRepo := data.NewOidcRepo(dataData)
+	oidcUsecase, err := biz.NewOidcUsecase(confServer, userUsecase, oidcRepo, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	exportRepo := data.NewExportRepo(dataData)
+	blobStore, err := data.NewBlobStore(confServer, dataData)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	exportUsecase := biz.NewExportUsecase(confServer, userUsecase, exportRepo, blobStore, logger)
+	userService := service.NewUserService(userUsecase, meteringUsecase, verificationUsecase, passwordResetUsecase, organizationUsecase, twoFactorUsecase, oidcUsecase, exportUsecase)
+	upgradeRepo := data.NewUpgradeRepo(dataData)
+	upgradeUsecase := biz.NewUpgradeUsecase(confServer, userUsecase, upgradeRepo, logger)
+	retentionUsecase := biz.NewRetentionUsecase(userUsecase, logger)
+	backupRepo := data.NewBackupRepo(dataData)
+	backupUsecase := biz.NewBackupUsecase(userUsecase, backupRepo, blobStore, logger)
+	tenantTokenUsecase := biz.NewTenantTokenUsecase(userUsecase, logger)
+	adminService := service.NewAdminService(upgradeUsecase, meteringUsecase, webhookUsecase, auditUsecase, retentionUsecase, backupUsecase, tenantTokenUsecase)
+	httpServer, err := server.NewHTTPServer(confServer, userService, adminService, oidcUsecase, auditUsecase, organizationUsecase, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	deletionUsecase := biz.NewDeletionUsecase(confServer, userUsecase, logger)
+	workerServer := server.NewWorkerServer(meteringUsecase, eventPublisher, webhookUsecase, deletionUsecase, organizationUsecase, exportUsecase)
+	app := newApp(logger, httpServer, workerServer)
 	return app, func() {
+		cleanup2()
 		cleanup()
 	}, nil
 }
@@ -0,0 +1,196 @@
+package biz
+
+import (
+	"bytes"
+	"encoding/json"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
+	"time"
+)
+
+const (
+	// 查询审计记录时默认以及最多返回的记录数
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+	// 每次从数据库中读取的审计记录数
+	auditPageSize = 500
+)
+
+// AuditUsecase 以只追加的方式保存审计记录，超出保留时长的记录在追加时被删除，
+// 租户与管理员的每次请求由服务的中间件记录，服务对网关、influxdb和k8s的每次修改由相应的组件记录
+type AuditUsecase struct {
+	repo      AuditRepo
+	retention time.Duration
+	logger    *log.Helper
+}
+type AuditRepo interface {
+	// AppendAudit 追加审计记录，并删除早于minTime的记录
+	AppendAudit(record []byte, minTime time.Time) error
+	// RangeAudit 按时间顺序获得[start, end]时间范围内，id大于after的至多count条记录，
+	// after为空时从start开始，返回记录的id以及内容
+	RangeAudit(start, end time.Time, after string, count int64) (ids []string, records [][]byte, err error)
+}
+
+// AuditFilter 查询审计记录的条件
+type AuditFilter struct {
+	// 操作者或涉及的租户
+	Username string
+	// 操作名称的前缀
+	Action string
+	// 起止时间，为零值时不限制
+	Start, End time.Time
+}
+
+func NewAuditUsecase(server *conf.Server, repo AuditRepo, logger log.Logger) *AuditUsecase {
+	auditUsecase := &AuditUsecase{
+		repo:      repo,
+		retention: 90 * 24 * time.Hour,
+		logger:    log.NewHelper(logger),
+	}
+	if c := server.Audit; c != nil && c.Retention != nil && c.Retention.AsDuration() > 0 {
+		auditUsecase.retention = c.Retention.AsDuration()
+	}
+
+	return auditUsecase
+}
+
+// Record 保存审计记录，保存失败时只记录日志，不影响操作本身
+func (a *AuditUsecase) Record(entry *audit.Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	marshal, err := json.Marshal(entry)
+	if err == nil {
+		err = a.repo.AppendAudit(marshal, time.Now().Add(-a.retention))
+	}
+	if err != nil {
+		a.logger.Errorf("保存 %v 的审计记录时发生了错误:%v", entry.Action, err)
+	}
+}
+
+// List 按时间顺序查询满足条件的审计记录，cursor为上一次查询返回的nextCursor，
+// 返回的记录达到limit时nextCursor不为空
+func (a *AuditUsecase) List(filter *AuditFilter, limit int64, cursor string) (
+	entries []*v1.AuditEntry, nextCursor string, err error) {
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	} else if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	nextCursor, err = a.scan(filter, cursor, func(entry *audit.Entry) bool {
+		entries = append(entries, auditEntryToProto(entry))
+		return int64(len(entries)) < limit
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return entries, nextCursor, nil
+}
+
+// Export 以JSON Lines的格式导出满足条件的所有审计记录
+func (a *AuditUsecase) Export(filter *AuditFilter) (*v1.File, error) {
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	var err error
+	_, scanErr := a.scan(filter, "", func(entry *audit.Entry) bool {
+		err = encoder.Encode(entry)
+		return err == nil
+	})
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	if err != nil {
+		return nil, errors.Newf(500, "Audit_Error", "将审计记录转换为json时发生了错误:%v", err)
+	}
+
+	return &v1.File{
+		Content: buffer.Bytes(),
+		Name:    "audit_" + time.Now().UTC().Format("20060102T150405Z") + ".jsonl",
+	}, nil
+}
+
+// ParseAuditFilter 解析查询审计记录的条件，时间的格式为RFC3339
+func ParseAuditFilter(username, action, startTime, endTime string) (*AuditFilter, error) {
+	filter := &AuditFilter{Username: username, Action: action}
+	for _, t := range []struct {
+		value  string
+		result *time.Time
+	}{{startTime, &filter.Start}, {endTime, &filter.End}} {
+		if t.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return nil, errors.BadRequest("Audit_Error", "时间的格式需要为RFC3339，如2006-01-02T15:04:05Z")
+		}
+		*t.result = parsed
+	}
+	if !filter.End.IsZero() && filter.End.Before(filter.Start) {
+		return nil, errors.BadRequest("Audit_Error", "结束时间不能早于开始时间")
+	}
+
+	return filter, nil
+}
+
+// 从cursor之后按时间顺序遍历满足条件的审计记录，直到visit返回false，
+// 返回最后访问的记录的id，遍历完所有记录时返回空字符串
+func (a *AuditUsecase) scan(filter *AuditFilter, cursor string, visit func(entry *audit.Entry) bool) (string, error) {
+	for {
+		ids, records, err := a.repo.RangeAudit(filter.Start, filter.End, cursor, auditPageSize)
+		if err != nil {
+			return "", err
+		}
+
+		for i, r := range records {
+			cursor = ids[i]
+			entry := new(audit.Entry)
+			if err := json.Unmarshal(r, entry); err != nil {
+				a.logger.Errorf("对审计记录 %v 进行json解码时发生了错误:%v", ids[i], err)
+				continue
+			}
+			entry.ID = ids[i]
+			if !filter.match(entry) {
+				continue
+			}
+			if !visit(entry) {
+				// 之后没有更多记录时不返回cursor
+				if i == len(records)-1 && len(records) < auditPageSize {
+					return "", nil
+				}
+				return cursor, nil
+			}
+		}
+
+		if len(records) < auditPageSize {
+			return "", nil
+		}
+	}
+}
+
+func (f *AuditFilter) match(entry *audit.Entry) bool {
+	if f.Username != "" && entry.Actor != f.Username && entry.Tenant != f.Username {
+		return false
+	}
+	return strings.HasPrefix(entry.Action, f.Action)
+}
+
+func auditEntryToProto(entry *audit.Entry) *v1.AuditEntry {
+	return &v1.AuditEntry{
+		Id:      entry.ID,
+		Time:    timestamppb.New(entry.Time),
+		Actor:   entry.Actor,
+		Ip:      entry.IP,
+		Action:  entry.Action,
+		Tenant:  entry.Tenant,
+		Targets: entry.Targets,
+		Outcome: entry.Outcome,
+		Message: entry.Message,
+	}
+}
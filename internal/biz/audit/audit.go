@@ -0,0 +1,58 @@
+// Package audit 定义审计记录以及记录审计的方式，供业务逻辑以及网关、influxdb、k8s等组件记录各自的操作
+package audit
+
+import "time"
+
+const (
+	// SystemActor 服务自身对网关、influxdb以及k8s进行修改时记录的操作者
+	SystemActor = "service-centre"
+
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// Entry 一条审计记录
+type Entry struct {
+	// 记录的id，由保存记录的存储生成，按时间递增
+	ID   string    `json:"id,omitempty"`
+	Time time.Time `json:"time"`
+	// 操作者，即发起请求的用户、组织成员或管理员，服务自身的操作为SystemActor
+	Actor string `json:"actor"`
+	// 发起请求的客户端ip
+	IP string `json:"ip,omitempty"`
+	// 操作的名称，格式为<组件>.<操作>，如user.register、gateway.create_consumer
+	Action string `json:"action"`
+	// 操作涉及的租户
+	Tenant string `json:"tenant,omitempty"`
+	// 操作涉及的资源，如网关中的service、route名称，influxdb的bucket名称，k8s资源的<类型>/<名称>
+	Targets []string `json:"targets,omitempty"`
+	Outcome string   `json:"outcome"`
+	// 操作失败时的错误信息
+	Message string `json:"message,omitempty"`
+}
+
+// Recorder 记录审计的方式，记录失败不影响操作本身
+type Recorder interface {
+	Record(entry *Entry)
+}
+
+// Record 以SystemActor记录服务自身对外部组件的一次修改，recorder为nil时忽略
+func Record(recorder Recorder, action, tenant string, targets []string, err error) {
+	if recorder == nil {
+		return
+	}
+
+	entry := &Entry{
+		Time:    time.Now().UTC(),
+		Actor:   SystemActor,
+		Action:  action,
+		Tenant:  tenant,
+		Targets: targets,
+		Outcome: OutcomeSucceeded,
+	}
+	if err != nil {
+		entry.Outcome = OutcomeFailed
+		entry.Message = err.Error()
+	}
+	recorder.Record(entry)
+}
@@ -0,0 +1,143 @@
+package biz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的审计记录，id为递增的序号
+type memoryAuditRepo struct {
+	seq     int
+	ids     []string
+	times   []time.Time
+	records [][]byte
+}
+
+func (r *memoryAuditRepo) AppendAudit(record []byte, minTime time.Time) error {
+	for len(r.times) > 0 && r.times[0].Before(minTime) {
+		r.ids, r.times, r.records = r.ids[1:], r.times[1:], r.records[1:]
+	}
+	r.seq++
+	r.ids = append(r.ids, fmt.Sprintf("%08d", r.seq))
+	r.times = append(r.times, time.Now())
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *memoryAuditRepo) RangeAudit(start, end time.Time, after string, count int64) (
+	ids []string, records [][]byte, err error) {
+	for i, id := range r.ids {
+		if id <= after || r.times[i].Before(start) || (!end.IsZero() && r.times[i].After(end)) {
+			continue
+		}
+		if int64(len(ids)) == count {
+			break
+		}
+		ids = append(ids, id)
+		records = append(records, r.records[i])
+	}
+	return ids, records, nil
+}
+
+func TestAuditUsecase(t *testing.T) {
+	repo := new(memoryAuditRepo)
+	audits := NewAuditUsecase(&conf.Server{}, repo, log.DefaultLogger)
+
+	audits.Record(&audit.Entry{Actor: "alice", Action: "user.login", Outcome: audit.OutcomeSucceeded})
+	audits.Record(&audit.Entry{Actor: "bob", Tenant: "alice", Action: "user.remove_member", Targets: []string{"carol"}})
+	audits.Record(&audit.Entry{Actor: audit.SystemActor, Tenant: "alice", Action: "gateway.delete_consumer"})
+	audits.Record(&audit.Entry{Actor: "dave", Action: "user.login", Outcome: audit.OutcomeFailed})
+	audits.Record(&audit.Entry{Actor: "admin", Action: "admin.export_usage"})
+
+	t.Run("filter by username and page with cursor", func(t *testing.T) {
+		filter, err := ParseAuditFilter("alice", "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries, cursor, err := audits.List(filter, 2, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 || entries[0].Action != "user.login" || entries[1].Actor != "bob" {
+			t.Fatalf("unexpected first page: %v", entries)
+		}
+		if cursor == "" {
+			t.Fatal("expected a cursor for the next page")
+		}
+
+		entries, cursor, err = audits.List(filter, 2, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Action != "gateway.delete_consumer" || cursor != "" {
+			t.Fatalf("unexpected second page: %v, cursor %q", entries, cursor)
+		}
+	})
+
+	t.Run("filter by action prefix", func(t *testing.T) {
+		entries, _, err := audits.List(&AuditFilter{Action: "user."}, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 3 {
+			t.Fatalf("expected 3 user operations, got %v", len(entries))
+		}
+	})
+
+	t.Run("export as json lines", func(t *testing.T) {
+		file, err := audits.Export(&AuditFilter{Action: "user.login"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := bytes.Split(bytes.TrimSpace(file.Content), []byte("\n"))
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 exported entries, got %v", len(lines))
+		}
+		entry := new(audit.Entry)
+		if err := json.Unmarshal(lines[1], entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.Actor != "dave" || entry.Outcome != audit.OutcomeFailed || entry.ID == "" {
+			t.Fatalf("unexpected exported entry: %+v", entry)
+		}
+	})
+
+	t.Run("entries outside the retention are removed", func(t *testing.T) {
+		audits.retention = time.Millisecond
+		time.Sleep(5 * time.Millisecond)
+		audits.Record(&audit.Entry{Actor: "eve", Action: "user.login"})
+
+		entries, _, err := audits.List(&AuditFilter{}, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Actor != "eve" {
+			t.Fatalf("expected only the latest entry, got %v", entries)
+		}
+	})
+}
+
+func TestParseAuditFilter(t *testing.T) {
+	filter, err := ParseAuditFilter("", "", "2022-01-01T00:00:00Z", "2022-02-01T00:00:00+08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Start.Month() != time.January || filter.End.IsZero() {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+
+	if _, err := ParseAuditFilter("", "", "2022-01-01", ""); err == nil {
+		t.Fatal("expected an error for a time without RFC3339 format")
+	}
+	if _, err := ParseAuditFilter("", "", "2022-02-01T00:00:00Z", "2022-01-01T00:00:00Z"); err == nil {
+		t.Fatal("expected an error when the end time is before the start time")
+	}
+}
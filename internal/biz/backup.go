@@ -0,0 +1,468 @@
+package biz
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 备份归档的格式版本，归档格式不兼容地改变时递增，恢复时拒绝更高版本的归档
+const backupFormatVersion = 1
+
+// 备份归档中各部分的文件名
+const (
+	backupManifestFile     = "manifest.json"
+	backupRegisterInfoFile = "register_info.pb"
+	backupRedisFile        = "redis.json"
+	backupGatewayFile      = "gateway.json"
+	backupBucketDir        = "buckets/"
+	// 用户自带influxdb时，以服务的密钥加密的连接信息
+	backupExternalInfluxdbFile = "external_influxdb.bin"
+)
+
+// BackupUsecase 将租户在数据库、网关以及influxdb中的数据保存为带版本的归档，并可由归档恢复已不存在的租户，
+// 归档保存在可替换的存储中
+type BackupUsecase struct {
+	repo   BackupRepo
+	blobs  BlobStore
+	uc     *UserUsecase
+	logger *log.Helper
+}
+type BackupRepo interface {
+	// TenantExists 查询用户是否已注册
+	TenantExists(username string) (bool, error)
+	// SnapshotTenant 获得用户在数据库中的所有数据
+	SnapshotTenant(username string) (*TenantSnapshot, error)
+	// RestoreTenant 将快照中的数据写回数据库，已存在的数据被覆盖
+	RestoreTenant(username string, snapshot *TenantSnapshot) error
+}
+
+// BlobStore 保存备份归档与导出归档的存储
+type BlobStore interface {
+	// PutBlob 保存数据，已存在的数据被覆盖
+	PutBlob(key string, blob []byte) error
+	// GetBlob 获得保存的数据，不存在时返回nil
+	GetBlob(key string) ([]byte, error)
+	// ListBlobs 获得以prefix为前缀的所有由PutBlob保存的key
+	ListBlobs(prefix string) ([]string, error)
+	// CreateBlob 以流的形式写入数据，已存在的数据在关闭返回的writer后被覆盖，关闭前数据不可读取
+	CreateBlob(key string) (io.WriteCloser, error)
+	// OpenBlob 以流的形式读取由CreateBlob写入的数据，不存在时返回nil
+	OpenBlob(key string) (io.ReadCloser, error)
+	// DeleteBlob 删除数据，不存在时忽略
+	DeleteBlob(key string) error
+}
+
+// TenantSnapshot 用户在数据库中的数据
+type TenantSnapshot struct {
+	// 以hash的key为键，用户在各个hash中对应字段的值，包括密码、token、注册信息以及客户端代码等
+	Fields map[string]string `json:"fields"`
+	// 以"用户名:"为前缀的key经DUMP序列化后的值，包括设备配置、状态以及警告信息等
+	Keys map[string][]byte `json:"keys"`
+}
+
+// 备份的描述信息，与归档分开保存，使查询备份时不需要读取完整的归档
+type backupManifest struct {
+	FormatVersion int       `json:"format_version"`
+	Username      string    `json:"username"`
+	Version       string    `json:"version"`
+	CreateTime    time.Time `json:"create_time"`
+	Buckets       []string  `json:"buckets"`
+	Points        int64     `json:"points"`
+	SizeBytes     int64     `json:"size_bytes"`
+}
+
+func NewBackupUsecase(uc *UserUsecase, repo BackupRepo, blobs BlobStore, logger log.Logger) *BackupUsecase {
+	return &BackupUsecase{repo: repo, blobs: blobs, uc: uc, logger: log.NewHelper(logger)}
+}
+
+// Create 为用户创建备份，归档中包括用户的注册信息、数据库中的数据、网关中的consumer与api密钥以及各个bucket中的数据
+func (b *BackupUsecase) Create(username string) (*v1.Backup, error) {
+	exists, err := b.repo.TenantExists(username)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NotFound("Backup_Error", "用户 "+username+" 不存在")
+	}
+
+	now := time.Now().UTC()
+	manifest := &backupManifest{
+		FormatVersion: backupFormatVersion,
+		Username:      username,
+		Version:       now.Format("20060102T150405.000Z"),
+		CreateTime:    now,
+	}
+	// 时序数据库不支持导出数据时，备份中只包括用户的注册信息、数据库中的数据以及网关中的consumer
+	backend, err := b.uc.timeSeriesOf(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := backend.(bucketExporter); ok {
+		manifest.Buckets = influxdb.BucketNames(username)
+	}
+
+	buffer := new(bytes.Buffer)
+	err = b.writeArchive(buffer, manifest)
+	if err != nil {
+		return nil, errors.Newf(500, "Backup_Error", "创建用户 %v 的备份时发生了错误:%v", username, err)
+	}
+	manifest.SizeBytes = int64(buffer.Len())
+
+	// 先保存归档再保存描述信息，使查询到的备份总是可以恢复
+	err = b.blobs.PutBlob(backupKey(username, manifest.Version, ".zip"), buffer.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	marshal, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Newf(500, "Backup_Error", "对备份信息进行json序列化时发生了错误:%v", err)
+	}
+	err = b.blobs.PutBlob(backupKey(username, manifest.Version, ".json"), marshal)
+	if err != nil {
+		return nil, err
+	}
+
+	b.logger.Infof("创建了用户 %v 的备份 %v", username, manifest.Version)
+	return manifest.toProto(), nil
+}
+
+// List 获得用户的所有备份，按创建时间升序排列
+func (b *BackupUsecase) List(username string) ([]*v1.Backup, error) {
+	keys, err := b.blobs.ListBlobs(backupKey(username, "", ""))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	backups := make([]*v1.Backup, 0, len(keys))
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") {
+			continue
+		}
+		manifest, err := b.manifest(key)
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			backups = append(backups, manifest.toProto())
+		}
+	}
+
+	return backups, nil
+}
+
+// Restore 由备份恢复已不存在的用户，依据备份中的注册信息重新部署用户的服务与路由，以原有的api密钥重建consumer，
+// 然后导入bucket中的数据并写回数据库中的数据，region不为空时将用户的服务部署在该区域的集群中，返回导入的数据点数量
+func (b *BackupUsecase) Restore(username, version, region string) (points int64, err error) {
+	manifest, err := b.manifest(backupKey(username, version, ".json"))
+	if err != nil {
+		return 0, err
+	}
+	if manifest == nil {
+		return 0, errors.NotFound("Backup_Error", "备份 "+version+" 不存在")
+	}
+	if manifest.FormatVersion > backupFormatVersion || manifest.Username != username {
+		return 0, errors.BadRequest("Backup_Error", fmt.Sprintf(
+			"不支持的备份，格式版本为%v，所属用户为%v", manifest.FormatVersion, manifest.Username))
+	}
+
+	exists, err := b.repo.TenantExists(username)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, errors.Conflict("Backup_Error", "用户 "+username+" 仍然存在，只能恢复已不存在的用户")
+	}
+
+	marshal, err := b.blobs.GetBlob(backupKey(username, version, ".zip"))
+	if err != nil {
+		return 0, err
+	}
+	if marshal == nil {
+		return 0, errors.NotFound("Backup_Error", "备份 "+version+" 的归档不存在")
+	}
+	archive, err := openBackupArchive(marshal)
+	if err != nil {
+		return 0, errors.Newf(500, "Backup_Error", "读取备份 %v 时发生了错误:%v", version, err)
+	}
+	// 用户自带influxdb时数据导入其自带的influxdb，否则需要共享的时序数据库支持导入
+	if _, ok := b.uc.timeSeries.(bucketImporter); !ok && len(manifest.Buckets) > 0 &&
+		archive.files[backupExternalInfluxdbFile] == nil {
+		return 0, unsupportedTimeSeries(b.uc.timeSeries, "导入备份中的时序数据")
+	}
+
+	b.logger.Infof("开始由备份 %v 恢复用户 %v", version, username)
+	points, err = b.restore(username, archive, manifest, region)
+	if err != nil {
+		// 恢复失败时清理已创建的资源，使恢复可以重试
+		if e := b.uc.clear(username); e != nil {
+			b.logger.Errorf("清理恢复失败的用户 %v 的资源时发生了错误:%v", username, e)
+		}
+		return 0, errors.Newf(500, "Backup_Error", "由备份 %v 恢复用户 %v 时发生了错误:%v", version, username, err)
+	}
+
+	b.logger.Infof("由备份 %v 恢复了用户 %v，导入了 %v 个数据点", version, username, points)
+	return points, nil
+}
+
+func (b *BackupUsecase) restore(username string, archive *backupArchive,
+	manifest *backupManifest, region string) (points int64, err error) {
+	info := new(v1.RegisterRequest)
+	if err := archive.decode(backupRegisterInfoFile, func(data []byte) error {
+		return proto.Unmarshal(data, info)
+	}); err != nil {
+		return 0, err
+	}
+	consumer := new(gateway.ConsumerSnapshot)
+	if err := archive.decode(backupGatewayFile, func(data []byte) error {
+		return json.Unmarshal(data, consumer)
+	}); err != nil {
+		return 0, err
+	}
+	snapshot := new(TenantSnapshot)
+	if err := archive.decode(backupRedisFile, func(data []byte) error {
+		return json.Unmarshal(data, snapshot)
+	}); err != nil {
+		return 0, err
+	}
+
+	// 套餐可能已从服务配置中移除，此时使用默认的套餐
+	_, retention, err := b.uc.plans.policy(info.Plan)
+	if err != nil {
+		_, retention, err = b.uc.plans.policy("")
+		if err != nil {
+			return 0, err
+		}
+	}
+	if region != "" {
+		info.Region = region
+	}
+	if archive.files[backupExternalInfluxdbFile] != nil {
+		if err := archive.decode(backupExternalInfluxdbFile, func(data []byte) (err error) {
+			info.ExternalInfluxdb, err = b.uc.external.decrypt(data)
+			return err
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = b.uc.gateway.ImportConsumer(consumer)
+	if err != nil {
+		return 0, err
+	}
+	cluster, err := b.uc.provision(username, info, retention)
+	if err != nil {
+		return 0, err
+	}
+
+	backend, err := b.uc.timeSeriesOf(username)
+	if err != nil {
+		return 0, err
+	}
+	importer, ok := backend.(bucketImporter)
+	if !ok && len(manifest.Buckets) > 0 {
+		return 0, unsupportedTimeSeries(backend, "导入备份中的时序数据")
+	}
+	for _, bucket := range manifest.Buckets {
+		file, err := archive.open(backupBucketDir + bucket + ".lp")
+		if err != nil {
+			return points, err
+		}
+		n, err := importer.ImportBucket(context.Background(), bucket, file)
+		file.Close()
+		points += n
+		if err != nil {
+			return points, err
+		}
+	}
+
+	// 最后写回数据库中的数据，使用户在服务恢复后才能登录
+	err = b.repo.RestoreTenant(username, snapshot)
+	if err != nil {
+		return points, err
+	}
+	saveRolloutOutcome(b.uc.repo, b.logger, username, "restore", nil)
+	b.uc.events.Publish(EventTenantRestored, username, tenantResources(username, cluster),
+		map[string]string{"backup": manifest.Version})
+
+	return points, nil
+}
+
+func (b *BackupUsecase) writeArchive(buffer *bytes.Buffer, manifest *backupManifest) error {
+	username := manifest.Username
+	archive := zip.NewWriter(buffer)
+	write := func(name string, data []byte) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	info, err := b.uc.repo.GetRegisterInfo(username)
+	if err != nil {
+		return err
+	}
+	if err := write(backupRegisterInfoFile, info); err != nil {
+		return err
+	}
+
+	snapshot, err := b.repo.SnapshotTenant(username)
+	if err != nil {
+		return err
+	}
+	marshal, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := write(backupRedisFile, marshal); err != nil {
+		return err
+	}
+
+	consumer, err := b.uc.gateway.ExportConsumer(username)
+	if err != nil {
+		return err
+	}
+	marshal, err = json.Marshal(consumer)
+	if err != nil {
+		return err
+	}
+	if err := write(backupGatewayFile, marshal); err != nil {
+		return err
+	}
+
+	// 自带influxdb的连接信息保持加密，只能由使用相同密钥的服务恢复
+	backend, err := b.uc.timeSeriesOf(username)
+	if err != nil {
+		return err
+	}
+	if b.uc.external != nil {
+		encrypted, err := b.uc.external.repo.GetExternalInfluxdb(username)
+		if err != nil {
+			return err
+		}
+		if encrypted != nil {
+			if err := write(backupExternalInfluxdbFile, encrypted); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 以行协议保存bucket中的数据，恢复时可以直接写回influxdb
+	for _, bucket := range manifest.Buckets {
+		w, err := archive.Create(backupBucketDir + bucket + ".lp")
+		if err != nil {
+			return err
+		}
+		points, err := backend.(bucketExporter).ExportBucket(
+			context.Background(), bucket, influxdb.ExportFormatLineProtocol, w, nil)
+		if err != nil {
+			return fmt.Errorf("导出bucket %s 的数据时发生了错误: %w", bucket, err)
+		}
+		manifest.Points += points
+	}
+
+	marshal, err = json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := write(backupManifestFile, marshal); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// 获得备份的描述信息，不存在时返回nil
+func (b *BackupUsecase) manifest(key string) (*backupManifest, error) {
+	marshal, err := b.blobs.GetBlob(key)
+	if err != nil || marshal == nil {
+		return nil, err
+	}
+
+	manifest := new(backupManifest)
+	if err := json.Unmarshal(marshal, manifest); err != nil {
+		return nil, errors.Newf(500, "Backup_Error", "对备份信息进行json解码时发生了错误:%v", err)
+	}
+	return manifest, nil
+}
+
+func (m *backupManifest) toProto() *v1.Backup {
+	return &v1.Backup{
+		Username:      m.Username,
+		Version:       m.Version,
+		FormatVersion: int32(m.FormatVersion),
+		CreateTime:    timestamppb.New(m.CreateTime),
+		Points:        m.Points,
+		SizeBytes:     m.SizeBytes,
+	}
+}
+
+// 获得备份在存储中的key，version为空时即用户所有备份的前缀
+func backupKey(username, version, extension string) string {
+	return "backups/" + username + "/" + version + extension
+}
+
+// 备份归档中的文件
+type backupArchive struct {
+	files map[string]*zip.File
+}
+
+func openBackupArchive(data []byte) (*backupArchive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &backupArchive{files: make(map[string]*zip.File, len(reader.File))}
+	for _, f := range reader.File {
+		archive.files[f.Name] = f
+	}
+	for _, name := range []string{backupManifestFile, backupRegisterInfoFile, backupRedisFile, backupGatewayFile} {
+		if archive.files[name] == nil {
+			return nil, fmt.Errorf("归档中缺少 %s", name)
+		}
+	}
+	return archive, nil
+}
+
+func (a *backupArchive) open(name string) (io.ReadCloser, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("归档中缺少 %s", name)
+	}
+	return f.Open()
+}
+
+// 读取归档中的文件并解码
+func (a *backupArchive) decode(name string, decode func(data []byte) error) error {
+	file, err := a.open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if err := decode(data); err != nil {
+		return fmt.Errorf("解码归档中的 %s 时发生了错误: %w", name, err)
+	}
+	return nil
+}
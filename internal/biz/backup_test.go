@@ -0,0 +1,146 @@
+package biz
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的备份存储
+type memoryBlobStore map[string][]byte
+
+func (s memoryBlobStore) PutBlob(key string, blob []byte) error {
+	s[key] = blob
+	return nil
+}
+
+func (s memoryBlobStore) GetBlob(key string) ([]byte, error) {
+	return s[key], nil
+}
+
+func (s memoryBlobStore) ListBlobs(prefix string) ([]string, error) {
+	var keys []string
+	for key := range s {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s memoryBlobStore) CreateBlob(key string) (io.WriteCloser, error) {
+	return &memoryBlobWriter{store: s, key: key}, nil
+}
+
+func (s memoryBlobStore) OpenBlob(key string) (io.ReadCloser, error) {
+	blob, ok := s[key]
+	if !ok {
+		return nil, nil
+	}
+	return io.NopCloser(bytes.NewReader(blob)), nil
+}
+
+func (s memoryBlobStore) DeleteBlob(key string) error {
+	delete(s, key)
+	return nil
+}
+
+// 关闭时将写入的数据保存到memoryBlobStore中
+type memoryBlobWriter struct {
+	bytes.Buffer
+	store memoryBlobStore
+	key   string
+}
+
+func (w *memoryBlobWriter) Close() error {
+	w.store[w.key] = w.Bytes()
+	return nil
+}
+
+// 只记录已注册用户的数据库
+type memoryBackupRepo map[string]*TenantSnapshot
+
+func (r memoryBackupRepo) TenantExists(username string) (bool, error) {
+	_, ok := r[username]
+	return ok, nil
+}
+
+func (r memoryBackupRepo) SnapshotTenant(username string) (*TenantSnapshot, error) {
+	return r[username], nil
+}
+
+func (r memoryBackupRepo) RestoreTenant(username string, snapshot *TenantSnapshot) error {
+	r[username] = snapshot
+	return nil
+}
+
+func saveManifest(t *testing.T, blobs memoryBlobStore, manifest *backupManifest) {
+	marshal, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs[backupKey(manifest.Username, manifest.Version, ".json")] = marshal
+}
+
+func TestBackupUsecase(t *testing.T) {
+	blobs := memoryBlobStore{}
+	repo := memoryBackupRepo{"bob": &TenantSnapshot{}}
+	backups := &BackupUsecase{repo: repo, blobs: blobs, logger: log.NewHelper(log.DefaultLogger)}
+
+	older := &backupManifest{FormatVersion: backupFormatVersion, Username: "alice",
+		Version: "20220101T000000.000Z", CreateTime: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := &backupManifest{FormatVersion: backupFormatVersion + 1, Username: "alice",
+		Version: "20220201T000000.000Z", CreateTime: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)}
+	saveManifest(t, blobs, newer)
+	saveManifest(t, blobs, older)
+	saveManifest(t, blobs, &backupManifest{FormatVersion: backupFormatVersion, Username: "bob", Version: "v"})
+	blobs[backupKey("alice", older.Version, ".zip")] = []byte("zip")
+
+	t.Run("list backups of a tenant in order", func(t *testing.T) {
+		list, err := backups.List("alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || list[0].Version != older.Version || list[1].Version != newer.Version {
+			t.Fatalf("unexpected backups: %v", list)
+		}
+	})
+
+	t.Run("reject unsupported or missing backups", func(t *testing.T) {
+		if _, err := backups.Restore("alice", "missing", ""); errors.Code(err) != 404 {
+			t.Fatalf("不存在的备份应返回404:%v", err)
+		}
+		if _, err := backups.Restore("alice", newer.Version, ""); errors.Code(err) != 400 {
+			t.Fatalf("更高格式版本的备份应返回400:%v", err)
+		}
+		if _, err := backups.Restore("bob", "v", ""); errors.Code(err) != 409 {
+			t.Fatalf("仍然存在的用户不能恢复:%v", err)
+		}
+	})
+
+	t.Run("reject archives missing required files", func(t *testing.T) {
+		if _, err := backups.Restore("alice", older.Version, ""); errors.Code(err) != 500 {
+			t.Fatalf("无效的归档应返回错误:%v", err)
+		}
+
+		buffer := new(bytes.Buffer)
+		archive := zip.NewWriter(buffer)
+		for _, name := range []string{backupManifestFile, backupRegisterInfoFile, backupRedisFile} {
+			if _, err := archive.Create(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := archive.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := openBackupArchive(buffer.Bytes()); err == nil || !strings.Contains(err.Error(), backupGatewayFile) {
+			t.Fatalf("缺少网关备份的归档应返回错误:%v", err)
+		}
+	})
+}
@@ -3,4 +3,7 @@ package biz
 import "github.com/google/wire"
 
 // ProviderSet is biz providers.
-var ProviderSet = wire.NewSet(NewUserUsecase)
+var ProviderSet = wire.NewSet(NewUserUsecase, NewUpgradeUsecase, NewMeteringUsecase,
+	NewEventPublisher, NewWebhookUsecase, NewVerificationUsecase, NewPasswordResetUsecase,
+	NewOrganizationUsecase, NewTwoFactorUsecase, NewOidcUsecase, NewAuditUsecase, NewDeletionUsecase,
+	NewRetentionUsecase, NewExportUsecase, NewBackupUsecase, NewTenantTokenUsecase)
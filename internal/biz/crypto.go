@@ -0,0 +1,37 @@
+package biz
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// 以配置的密钥的sha256摘要作为AES-256的密钥，创建加密保存敏感信息使用的AES-GCM
+func newAEAD(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 加密明文，随机生成的nonce保存在密文的开头
+func sealAEAD(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// 解密由sealAEAD加密的密文
+func openAEAD(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	size := aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("密文的长度过短")
+	}
+	return aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
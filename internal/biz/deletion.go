@@ -0,0 +1,108 @@
+package biz
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"time"
+)
+
+// 注销后等待清理的默认宽限期
+const defaultGracePeriod = 7 * 24 * time.Hour
+
+// 领取清理任务的默认租约
+const defaultPurgeLease = 10 * time.Minute
+
+// DeletionUsecase 在后台定期清理宽限期已结束的注销用户，清理失败时通知用户订阅的webhook，并在下一个周期重试
+type DeletionUsecase struct {
+	repo     DeletionRepo
+	uc       *UserUsecase
+	interval time.Duration
+	// 领取清理任务的租约，需要长于清理一个用户所需的时间
+	lease  time.Duration
+	logger *log.Helper
+}
+type DeletionRepo interface {
+	// ScheduleDeletion 记录用户已注销，并在purgeTime之后清理，已有的记录被覆盖
+	ScheduleDeletion(username string, purgeTime time.Time) error
+	// GetDeletion 获得已注销用户的清理时间，用户未注销时返回零值
+	GetDeletion(username string) (time.Time, error)
+	// CancelDeletion 删除用户的清理计划，返回false时表示用户未注销、计划已被删除或已开始清理
+	CancelDeletion(username string) (bool, error)
+	// DueDeletions 获得清理时间不晚于now的所有用户
+	DueDeletions(now time.Time) ([]string, error)
+	// ClaimDeletion 在用户的清理时间不晚于now时领取清理任务，将清理时间推迟到leaseUntil并标记用户已开始清理，
+	// 返回false时表示用户未到期或已被其他实例领取，已开始清理的用户无法再取消清理计划，
+	// 清理成功后清理计划随用户信息一同删除
+	ClaimDeletion(username string, now, leaseUntil time.Time) (bool, error)
+}
+
+func NewDeletionUsecase(server *conf.Server, uc *UserUsecase, logger log.Logger) *DeletionUsecase {
+	deletion := &DeletionUsecase{
+		repo:     uc.deletions,
+		uc:       uc,
+		interval: time.Minute,
+		lease:    defaultPurgeLease,
+		logger:   log.NewHelper(logger),
+	}
+	if c := server.Deletion; c != nil && c.PurgeInterval != nil && c.PurgeInterval.AsDuration() > 0 {
+		deletion.interval = c.PurgeInterval.AsDuration()
+	}
+	if c := server.Deletion; c != nil && c.PurgeLease != nil && c.PurgeLease.AsDuration() > 0 {
+		deletion.lease = c.PurgeLease.AsDuration()
+	}
+
+	return deletion
+}
+
+// Run 按照配置的间隔清理宽限期已结束的注销用户，直到ctx被取消
+func (d *DeletionUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := d.purgeDue(); err != nil {
+			d.logger.Warnf("查询到期的注销用户时发生了错误，将在稍后重试:%v", err)
+		}
+	}
+}
+
+// 清理所有宽限期已结束的注销用户
+func (d *DeletionUsecase) purgeDue() error {
+	usernames, err := d.repo.DueDeletions(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, username := range usernames {
+		// 以租约领取清理任务，避免多个实例同时清理，且领取后用户无法再恢复账号，
+		// 实例在清理过程中退出时，租约到期后由其余实例重新清理
+		now := time.Now()
+		claimed, err := d.repo.ClaimDeletion(username, now, now.Add(d.lease))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		// 清理成功时清理计划随用户信息一同删除
+		err = d.uc.purge(username)
+		if err == nil {
+			continue
+		}
+		retry := time.Now().Add(d.interval)
+		d.logger.Errorf("清理已注销用户 %v 的资源时发生了错误，将在 %v 重试:%v",
+			username, retry.Format(time.RFC3339), err)
+		if err := d.repo.ScheduleDeletion(username, retry); err != nil {
+			d.logger.Errorf("重新记录用户 %v 的清理计划时发生了错误:%v", username, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的清理计划
+type memoryDeletionRepo struct {
+	schedules map[string]time.Time
+	// 已开始清理的用户
+	purging map[string]bool
+}
+
+func newMemoryDeletionRepo() *memoryDeletionRepo {
+	return &memoryDeletionRepo{schedules: make(map[string]time.Time), purging: make(map[string]bool)}
+}
+
+func (r *memoryDeletionRepo) ScheduleDeletion(username string, purgeTime time.Time) error {
+	r.schedules[username] = purgeTime
+	return nil
+}
+
+func (r *memoryDeletionRepo) GetDeletion(username string) (time.Time, error) {
+	return r.schedules[username], nil
+}
+
+func (r *memoryDeletionRepo) CancelDeletion(username string) (bool, error) {
+	if r.purging[username] {
+		return false, nil
+	}
+	_, ok := r.schedules[username]
+	delete(r.schedules, username)
+	return ok, nil
+}
+
+func (r *memoryDeletionRepo) DueDeletions(now time.Time) ([]string, error) {
+	var usernames []string
+	for username, purgeTime := range r.schedules {
+		if !purgeTime.After(now) {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames, nil
+}
+
+func (r *memoryDeletionRepo) ClaimDeletion(username string, now, leaseUntil time.Time) (bool, error) {
+	purgeTime, ok := r.schedules[username]
+	if !ok || purgeTime.After(now) {
+		return false, nil
+	}
+	r.schedules[username] = leaseUntil
+	r.purging[username] = true
+	return true, nil
+}
+
+// 只实现登录与查询所在集群的用户数据库，查询所在集群时总是返回错误
+type loginRepo struct {
+	UserRepo
+	passwords map[string]string
+}
+
+func (r *loginRepo) Login(username, password string) (string, error) {
+	if p, ok := r.passwords[username]; !ok || p != password {
+		return "", errors.New(400, "Repo_Error", "用户账号或者密码错误")
+	}
+	return username + "-token", nil
+}
+
+func (r *loginRepo) GetPlacement(username string) (string, error) {
+	return "", errors.New(500, "Repo_Error", "数据库不可用")
+}
+
+func TestUserUsecase_pendingDeletion(t *testing.T) {
+	repo := &loginRepo{passwords: map[string]string{"alice": "secret"}}
+	deletions := newMemoryDeletionRepo()
+	uc := &UserUsecase{
+		repo:      repo,
+		deletions: deletions,
+		clusters:  &clusterPlacement{repo: repo},
+		twoFactor: &TwoFactorUsecase{repo: newMemoryTwoFactorRepo(), logger: log.NewHelper(log.DefaultLogger)},
+		events:    new(EventPublisher),
+		logger:    log.NewHelper(log.DefaultLogger),
+	}
+
+	if _, err := uc.Unregister("alice", "wrong", ""); errors.Reason(err) != "UnRegister_Error" {
+		t.Fatalf("密码错误时不能注销:%v", err)
+	}
+	if err := uc.Restore("alice", "secret", ""); errors.Code(err) != 400 {
+		t.Fatalf("未注销的账号不能恢复:%v", err)
+	}
+
+	purgeTime := time.Now().Add(time.Hour)
+	deletions.schedules["alice"] = purgeTime
+
+	if _, err := uc.Login("alice", "secret", ""); errors.Reason(err) != "Unregister_Pending" {
+		t.Fatalf("等待清理的账号不能登录:%v", err)
+	}
+	if _, err := uc.Unregister("alice", "secret", ""); errors.Code(err) != 409 {
+		t.Fatalf("已注销的账号不能重复注销:%v", err)
+	}
+
+	// 恢复服务失败时需要保留原有的清理计划
+	if err := uc.Restore("alice", "secret", ""); err == nil {
+		t.Fatal("恢复服务失败时应返回错误")
+	}
+	if !deletions.schedules["alice"].Equal(purgeTime) {
+		t.Fatalf("恢复失败后清理计划应保持不变，实际为:%v", deletions.schedules["alice"])
+	}
+
+	delete(deletions.schedules, "alice")
+	if _, err := uc.Login("alice", "secret", ""); err != nil {
+		t.Fatalf("取消清理计划后应可以登录:%v", err)
+	}
+}
+
+func TestDeletionUsecase_purgeDue(t *testing.T) {
+	repo := &loginRepo{}
+	deletions := newMemoryDeletionRepo()
+	deletions.schedules["due"] = time.Now().Add(-time.Minute)
+	deletions.schedules["pending"] = time.Now().Add(time.Hour)
+	uc := &UserUsecase{
+		repo:      repo,
+		deletions: deletions,
+		clusters:  &clusterPlacement{repo: repo},
+		logger:    log.NewHelper(log.DefaultLogger),
+	}
+	deletion := &DeletionUsecase{
+		repo:     deletions,
+		uc:       uc,
+		interval: time.Minute,
+		lease:    time.Hour,
+		logger:   log.NewHelper(log.DefaultLogger),
+	}
+
+	if err := deletion.purgeDue(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 清理失败的用户需要在下一个周期重试，且已开始清理的用户无法再恢复
+	retry, ok := deletions.schedules["due"]
+	if !ok || !retry.After(time.Now()) {
+		t.Fatalf("清理失败后应重新记录清理计划，实际为:%v", retry)
+	}
+	if retry.After(time.Now().Add(deletion.interval)) {
+		t.Fatalf("重试时间应在一个周期之内，实际为:%v", retry)
+	}
+	if canceled, _ := deletions.CancelDeletion("due"); canceled {
+		t.Fatal("已开始清理的用户不应可以取消清理计划")
+	}
+	if _, ok := deletions.schedules["pending"]; !ok || deletions.purging["pending"] {
+		t.Fatal("宽限期未结束的用户不应被清理")
+	}
+
+	// 领取后在租约期间不会被重复领取
+	now := time.Now()
+	deletions.schedules["leased"] = now.Add(-time.Minute)
+	if claimed, _ := deletions.ClaimDeletion("leased", now, now.Add(deletion.lease)); !claimed {
+		t.Fatal("到期的用户应可以被领取")
+	}
+	if claimed, _ := deletions.ClaimDeletion("leased", now, now.Add(deletion.lease)); claimed {
+		t.Fatal("租约期间不应重复领取")
+	}
+}
+
+// 记录被清理的用户的网关
+type recordingGateway struct {
+	gateway.Provider
+	unregistered []string
+}
+
+func (g *recordingGateway) Unregister(username string) error {
+	g.unregistered = append(g.unregistered, username)
+	return nil
+}
+
+// 记录被清理的用户的时序数据库
+type recordingTimeSeries struct {
+	TimeSeriesBackend
+	cleared []string
+}
+
+func (b *recordingTimeSeries) RevokeCredentials(username, keep string) error {
+	return nil
+}
+
+func (b *recordingTimeSeries) ClearStorage(username string) error {
+	b.cleared = append(b.cleared, username)
+	return nil
+}
+
+func TestUserUsecase_clear(t *testing.T) {
+	repo := &loginRepo{}
+	g := &recordingGateway{}
+	backend := &recordingTimeSeries{}
+	uc := &UserUsecase{
+		repo:       repo,
+		clusters:   &clusterPlacement{repo: repo},
+		gateway:    g,
+		timeSeries: backend,
+		logger:     log.NewHelper(log.DefaultLogger),
+	}
+
+	// 查询所在集群失败时仍需清理其余的资源，并保留用户信息以便重试
+	err := uc.clear("alice")
+	if errors.Reason(err) != "Clear_Error" {
+		t.Fatalf("查询所在集群失败时应返回错误:%v", err)
+	}
+	if len(g.unregistered) != 1 || len(backend.cleared) != 1 {
+		t.Fatalf("应继续清理网关与时序数据库:%v %v", g.unregistered, backend.cleared)
+	}
+}
@@ -0,0 +1,192 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+	"time"
+)
+
+// 租户生命周期事件的类型
+const (
+	EventTenantRegistered = "com.moyusir.service-centre.tenant.registered"
+	EventTenantLoggedIn   = "com.moyusir.service-centre.tenant.logged_in"
+	EventTenantUpgraded   = "com.moyusir.service-centre.tenant.upgraded"
+	// 租户修改了服务的配置，如数据的保留策略
+	EventTenantConfigChanged = "com.moyusir.service-centre.tenant.config_changed"
+	EventTenantUnregistered  = "com.moyusir.service-centre.tenant.unregistered"
+	// 租户注销后进入宽限期，以及在宽限期内恢复账号
+	EventTenantSuspended = "com.moyusir.service-centre.tenant.suspended"
+	EventTenantRestored  = "com.moyusir.service-centre.tenant.restored"
+)
+
+const (
+	// 事件的来源
+	eventSource = "/service-centre"
+	// 单次从发件箱中取出发送的事件数量
+	eventFlushBatch = 100
+)
+
+// Event 以CloudEvents 1.0结构化格式表示的租户生命周期事件，规范参考:
+// https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md
+type Event struct {
+	SpecVersion string `json:"specversion"`
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	// 事件相关的租户用户名
+	Subject         string           `json:"subject"`
+	Time            time.Time        `json:"time"`
+	DataContentType string           `json:"datacontenttype"`
+	Data            *TenantEventData `json:"data"`
+}
+
+// TenantEventData 租户生命周期事件携带的数据
+type TenantEventData struct {
+	Username  string           `json:"username"`
+	Resources *TenantResources `json:"resources,omitempty"`
+	// 与事件类型相关的附加信息，如升级后的镜像
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// TenantResources 租户占用的系统资源
+type TenantResources struct {
+	// 租户服务所在的集群
+	Cluster string `json:"cluster,omitempty"`
+	// 租户服务对应的k8s工作负载
+	Workloads []string `json:"workloads,omitempty"`
+	// 网关中为租户服务创建的kong service组件
+	GatewayServices []string `json:"gateway_services,omitempty"`
+	// 租户使用的influxdb bucket
+	Buckets []string `json:"buckets,omitempty"`
+}
+
+// EventBus 事件发布的目标消息总线
+type EventBus interface {
+	// Publish 将序列化后的事件发布到消息总线，key为事件相关的租户用户名
+	Publish(ctx context.Context, key string, event []byte) error
+}
+
+// EventOutbox 事件的发件箱，事件先写入发件箱，再由后台任务按写入的顺序发送到消息总线，
+// 从而在消息总线不可用时不丢失事件
+type EventOutbox interface {
+	// AppendEvent 将事件追加到发件箱的末尾
+	AppendEvent(event []byte) error
+	// PeekEvents 获得发件箱中最早的n个事件
+	PeekEvents(n int64) ([][]byte, error)
+	// RemoveEvents 删除已发送的events，只删除发件箱开头与events一致的事件，
+	// 使多个实例同时发送时不会删除其余实例尚未发送的事件
+	RemoveEvents(events [][]byte) error
+}
+
+// EventPublisher 负责发布租户生命周期事件，事件至少会被发布一次，多个实例可能重复发送同一事件，
+// 消费者可以依据事件的id去重
+type EventPublisher struct {
+	outbox EventOutbox
+	// 未配置消息总线时为nil，此时不记录事件
+	bus      EventBus
+	interval time.Duration
+	logger   *log.Helper
+}
+
+func NewEventPublisher(c *conf.Data, outbox EventOutbox, bus EventBus, logger log.Logger) *EventPublisher {
+	publisher := &EventPublisher{
+		outbox:   outbox,
+		bus:      bus,
+		interval: 5 * time.Second,
+		logger:   log.NewHelper(logger),
+	}
+	if e := c.EventBus; e != nil && e.FlushInterval != nil && e.FlushInterval.AsDuration() > 0 {
+		publisher.interval = e.FlushInterval.AsDuration()
+	}
+
+	return publisher
+}
+
+// Publish 将租户生命周期事件写入发件箱，写入失败时只记录日志，不影响租户的操作
+func (p *EventPublisher) Publish(eventType, username string, resources *TenantResources, details map[string]string) {
+	if p.bus == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	event := &Event{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          eventSource,
+		Type:            eventType,
+		Subject:         username,
+		Time:            now,
+		DataContentType: "application/json",
+		Data: &TenantEventData{
+			Username:  username,
+			Resources: resources,
+			Details:   details,
+		},
+	}
+
+	marshal, err := json.Marshal(event)
+	if err == nil {
+		err = p.outbox.AppendEvent(marshal)
+	}
+	if err != nil {
+		p.logger.Errorf("记录用户 %v 的 %v 事件时发生了错误:%v", username, eventType, err)
+	}
+}
+
+// Run 定期将发件箱中积压的事件发送到消息总线，直到ctx被取消
+func (p *EventPublisher) Run(ctx context.Context) {
+	if p.bus == nil {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.flush(ctx); err != nil {
+				p.logger.Warnf("发送积压的租户生命周期事件时发生了错误，将在稍后重试:%v", err)
+			}
+		}
+	}
+}
+
+// 按写入顺序发送发件箱中的事件，出现发送失败时停止，保证事件的顺序
+func (p *EventPublisher) flush(ctx context.Context) error {
+	for {
+		events, err := p.outbox.PeekEvents(eventFlushBatch)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		var sent int
+		for _, e := range events {
+			if err = p.bus.Publish(ctx, eventKey(e), e); err != nil {
+				break
+			}
+			sent++
+		}
+		if sent != 0 {
+			if e := p.outbox.RemoveEvents(events[:sent]); e != nil {
+				return e
+			}
+		}
+		if err != nil || len(events) < eventFlushBatch {
+			return err
+		}
+	}
+}
+
+// 获得序列化后的事件相关的租户用户名，作为消息总线中的分区键
+func eventKey(event []byte) string {
+	e := &struct {
+		Subject string `json:"subject"`
+	}{}
+	json.Unmarshal(event, e)
+	return e.Subject
+}
@@ -0,0 +1,77 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"testing"
+)
+
+// 保存在内存中的发件箱
+type memoryOutbox struct {
+	events [][]byte
+}
+
+func (o *memoryOutbox) AppendEvent(event []byte) error {
+	o.events = append(o.events, event)
+	return nil
+}
+
+func (o *memoryOutbox) PeekEvents(n int64) ([][]byte, error) {
+	if n > int64(len(o.events)) {
+		n = int64(len(o.events))
+	}
+	return o.events[:n], nil
+}
+
+func (o *memoryOutbox) RemoveEvents(events [][]byte) error {
+	for _, e := range events {
+		if len(o.events) == 0 || string(o.events[0]) != string(e) {
+			break
+		}
+		o.events = o.events[1:]
+	}
+	return nil
+}
+
+// 发布指定数量的事件后开始返回错误的消息总线
+type flakyBus struct {
+	keys  []string
+	limit int
+}
+
+func (b *flakyBus) Publish(ctx context.Context, key string, event []byte) error {
+	if len(b.keys) >= b.limit {
+		return errors.New("bus is down")
+	}
+	b.keys = append(b.keys, key)
+	return nil
+}
+
+func TestEventPublisher_flush(t *testing.T) {
+	outbox := new(memoryOutbox)
+	bus := &flakyBus{limit: 2}
+	publisher := &EventPublisher{outbox: outbox, bus: bus, logger: log.NewHelper(log.DefaultLogger)}
+
+	for _, user := range []string{"a", "b", "c"} {
+		publisher.Publish(EventTenantRegistered, user, nil, nil)
+	}
+
+	if err := publisher.flush(context.Background()); err == nil {
+		t.Fatal("消息总线不可用时应返回错误")
+	}
+	if len(outbox.events) != 1 {
+		t.Fatalf("发送失败的事件应保留在发件箱中，实际剩余:%v", len(outbox.events))
+	}
+
+	bus.limit = 3
+	if err := publisher.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(outbox.events) != 0 {
+		t.Fatalf("消息总线恢复后应发送所有积压的事件，实际剩余:%v", len(outbox.events))
+	}
+	if len(bus.keys) != 3 || bus.keys[0] != "a" || bus.keys[2] != "c" {
+		t.Fatalf("事件应按照写入的顺序发送，并以用户名作为key:%v", bus.keys)
+	}
+}
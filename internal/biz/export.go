@@ -0,0 +1,448 @@
+package biz
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"sync"
+	"time"
+)
+
+// 导出任务的状态
+const (
+	exportStateRunning   = "running"
+	exportStateSucceeded = "succeeded"
+	exportStateFailed    = "failed"
+)
+
+const (
+	// 执行中的任务更新状态的间隔，作为执行任务的实例仍在运行的心跳
+	exportHeartbeatInterval = time.Minute
+	// 执行中的任务超过该时长未更新状态时，认为执行任务的实例已退出
+	exportStaleAfter = 5 * exportHeartbeatInterval
+	// 清理过期的归档以及中断的任务的间隔
+	exportSweepInterval = time.Minute
+)
+
+// ExportUsecase 以后台任务的形式导出租户三个bucket中的时序数据，与解码后的注册信息一同打包为zip归档，
+// 任务的进度保存在数据库中，归档以流的形式写入归档存储，两者在有效期后被清理
+type ExportUsecase struct {
+	repo       ExportRepo
+	uc         *UserUsecase
+	blobs      BlobStore
+	expiration time.Duration
+	logger     *log.Helper
+}
+type ExportRepo interface {
+	// SaveExportJob 保存导出任务的状态，任务在ttl后自动删除
+	SaveExportJob(id string, job []byte, ttl time.Duration) error
+	// GetExportJob 获得导出任务的状态，不存在或已过期时返回nil
+	GetExportJob(id string) ([]byte, error)
+	// ListExportJobs 获得所有未被删除的导出任务的id，包括状态已过期的任务
+	ListExportJobs() ([]string, error)
+	// RemoveExportJob 删除导出任务的状态
+	RemoveExportJob(id string) error
+}
+
+// 保存在数据库中的导出任务
+type exportRecord struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	Format       string    `json:"format"`
+	State        string    `json:"state"`
+	BucketsDone  int64     `json:"buckets_done"`
+	BucketsTotal int64     `json:"buckets_total"`
+	Points       int64     `json:"points"`
+	Message      string    `json:"message,omitempty"`
+	CreateTime   time.Time `json:"create_time"`
+	SizeBytes    int64     `json:"size_bytes,omitempty"`
+	ExpireTime   time.Time `json:"expire_time,omitempty"`
+	// 任务状态最近一次更新的时间
+	UpdateTime time.Time `json:"update_time"`
+	// 任务状态与归档被清理的时间
+	Deadline time.Time `json:"deadline"`
+}
+
+func NewExportUsecase(server *conf.Server, uc *UserUsecase, repo ExportRepo, blobs BlobStore, logger log.Logger) *ExportUsecase {
+	export := &ExportUsecase{
+		repo:       repo,
+		uc:         uc,
+		blobs:      blobs,
+		expiration: 24 * time.Hour,
+		logger:     log.NewHelper(logger),
+	}
+	if c := server.Export; c != nil && c.Expiration != nil && c.Expiration.AsDuration() > 0 {
+		export.expiration = c.Expiration.AsDuration()
+	}
+
+	return export
+}
+
+// Start 创建导出用户时序数据的任务并在后台执行，format为空时导出annotated csv，
+// 用户已注销时，归档保留到宽限期结束，使用户在清理前总能下载注销前导出的数据
+func (e *ExportUsecase) Start(username, format string) (*v1.ExportJob, error) {
+	if format == "" {
+		format = influxdb.ExportFormatCSV
+	}
+	if format != influxdb.ExportFormatCSV && format != influxdb.ExportFormatLineProtocol {
+		return nil, errors.BadRequest("Export_Error", "导出的格式只能为csv或line_protocol")
+	}
+	backend, err := e.uc.timeSeriesOf(username)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := backend.(bucketExporter); !ok {
+		return nil, unsupportedTimeSeries(backend, "导出租户的时序数据")
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Newf(500, "Export_Error", "生成导出任务的id时发生了错误:%v", err)
+	}
+	record := &exportRecord{
+		ID:           hex.EncodeToString(id),
+		Username:     username,
+		Format:       format,
+		State:        exportStateRunning,
+		BucketsTotal: int64(len(influxdb.BucketNames(username))),
+		CreateTime:   time.Now().UTC(),
+	}
+	record.Deadline = record.CreateTime.Add(e.expiration)
+	purgeTime, err := e.uc.deletions.GetDeletion(username)
+	if err != nil {
+		return nil, err
+	}
+	if purgeTime.After(record.Deadline) {
+		record.Deadline = purgeTime.UTC()
+	}
+	err = e.save(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// 任务开始执行后record会被并发修改，因此提前获得返回的任务状态
+	job := record.toProto()
+	go e.run(record)
+
+	e.logger.Infof("开始导出用户 %v 的时序数据，任务id为 %v", username, job.Id)
+	return job, nil
+}
+
+// Status 获得用户导出任务的进度
+func (e *ExportUsecase) Status(username, id string) (*v1.ExportJob, error) {
+	record, err := e.get(username, id)
+	if err != nil {
+		return nil, err
+	}
+	return record.toProto(), nil
+}
+
+// Open 获得已完成的导出任务生成的归档，返回归档的文件名与大小，调用方需要关闭返回的归档
+func (e *ExportUsecase) Open(username, id string) (name string, size int64, archive io.ReadCloser, err error) {
+	record, err := e.get(username, id)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if record.State != exportStateSucceeded {
+		return "", 0, nil, errors.BadRequest(
+			"Export_Error", fmt.Sprintf("导出任务尚未成功完成，当前状态为%v", record.State))
+	}
+
+	archive, err = e.blobs.OpenBlob(exportBlobKey(id))
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if archive == nil {
+		return "", 0, nil, errors.NotFound("Export_Error", "导出的归档不存在或已过期")
+	}
+
+	return exportArchiveName(record), record.SizeBytes, archive, nil
+}
+
+// Run 定期清理过期的导出任务与归档，并将执行实例已退出的任务标记为失败，
+// 启动时立即执行一次，使服务重启前中断的任务尽快结束
+func (e *ExportUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(exportSweepInterval)
+	defer ticker.Stop()
+	for {
+		if err := e.sweep(time.Now()); err != nil {
+			e.logger.Warnf("清理导出任务时发生了错误，将在稍后重试:%v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 清理过期的导出任务与归档，并将超过exportStaleAfter未更新状态的执行中任务标记为失败
+func (e *ExportUsecase) sweep(now time.Time) error {
+	ids, err := e.repo.ListExportJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		record, err := e.load(id)
+		if err != nil {
+			return err
+		}
+		if record == nil || !now.Before(record.Deadline) {
+			if err := e.blobs.DeleteBlob(exportBlobKey(id)); err != nil {
+				return err
+			}
+			if err := e.repo.RemoveExportJob(id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if record.State == exportStateRunning && now.Sub(record.UpdateTime) > exportStaleAfter {
+			record.State = exportStateFailed
+			record.Message = "执行导出任务的实例已退出，请重新创建导出任务"
+			if err := e.save(record); err != nil {
+				return err
+			}
+			if err := e.blobs.DeleteBlob(exportBlobKey(id)); err != nil {
+				return err
+			}
+			e.logger.Warnf("用户 %v 的导出任务 %v 已中断，已标记为失败", record.Username, id)
+		}
+	}
+
+	return nil
+}
+
+// 执行导出任务，依次将用户的注册信息以及各个bucket的数据写入zip归档，并随时保存任务的进度
+func (e *ExportUsecase) run(record *exportRecord) {
+	// 进度回调、心跳与任务本身可能同时保存任务状态
+	mutex := new(sync.Mutex)
+	update := func(f func()) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		f()
+		if err := e.save(record); err != nil {
+			e.logger.Errorf("保存导出任务 %v 的进度时发生了错误:%v", record.ID, err)
+		}
+	}
+
+	// 导出单个bucket可能长时间没有进度，因此定期保存任务状态作为心跳
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(exportHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				update(func() {})
+			}
+		}
+	}()
+
+	key := exportBlobKey(record.ID)
+	size, err := e.writeBlob(key, record, update)
+	close(stop)
+	if err != nil {
+		if deleteErr := e.blobs.DeleteBlob(key); deleteErr != nil {
+			err = fmt.Errorf("%w，且删除未完成的归档时发生了错误: %v", err, deleteErr)
+		}
+	}
+
+	update(func() {
+		if err != nil {
+			record.State = exportStateFailed
+			record.Message = err.Error()
+			return
+		}
+		record.State = exportStateSucceeded
+		record.SizeBytes = size
+		record.ExpireTime = record.Deadline
+	})
+	if err != nil {
+		e.logger.Errorf("导出用户 %v 的时序数据时发生了错误:%v", record.Username, err)
+	} else {
+		e.logger.Infof("完成了用户 %v 的时序数据导出，任务id为 %v", record.Username, record.ID)
+	}
+}
+
+// 将归档以流的形式写入归档存储，返回归档的大小
+func (e *ExportUsecase) writeBlob(key string, record *exportRecord, update func(func())) (int64, error) {
+	blob, err := e.blobs.CreateBlob(key)
+	if err != nil {
+		return 0, err
+	}
+	counter := &countingWriter{Writer: blob}
+	err = e.writeArchive(counter, record, update)
+	if closeErr := blob.Close(); err == nil {
+		err = closeErr
+	}
+	return counter.n, err
+}
+
+func (e *ExportUsecase) writeArchive(writer io.Writer, record *exportRecord, update func(func())) error {
+	archive := zip.NewWriter(writer)
+
+	info, err := e.registerInfo(record.Username)
+	if err != nil {
+		return err
+	}
+	w, err := archive.Create("register_info.json")
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(info); err != nil {
+		return err
+	}
+
+	backend, err := e.uc.timeSeriesOf(record.Username)
+	if err != nil {
+		return err
+	}
+	exporter, ok := backend.(bucketExporter)
+	if !ok {
+		return unsupportedTimeSeries(backend, "导出租户的时序数据")
+	}
+
+	extension := map[string]string{
+		influxdb.ExportFormatCSV:          ".csv",
+		influxdb.ExportFormatLineProtocol: ".lp",
+	}[record.Format]
+	var exported int64
+	for _, bucket := range influxdb.BucketNames(record.Username) {
+		w, err := archive.Create(bucket + extension)
+		if err != nil {
+			return err
+		}
+		points, err := exporter.ExportBucket(context.Background(), bucket, record.Format, w,
+			func(points int64) {
+				update(func() { record.Points = exported + points })
+			})
+		if err != nil {
+			return fmt.Errorf("导出bucket %s 的数据时发生了错误: %w", bucket, err)
+		}
+
+		exported += points
+		update(func() {
+			record.Points = exported
+			record.BucketsDone++
+		})
+	}
+
+	return archive.Close()
+}
+
+// 获得以json表示的用户注册信息，其中不包括用户的密码
+func (e *ExportUsecase) registerInfo(username string) ([]byte, error) {
+	marshal, err := e.uc.repo.GetRegisterInfo(username)
+	if err != nil {
+		return nil, err
+	}
+	info := new(v1.RegisterRequest)
+	if err := proto.Unmarshal(marshal, info); err != nil {
+		return nil, fmt.Errorf("对用户注册信息进行protobuf解码时发生了错误: %w", err)
+	}
+	if info.User != nil {
+		info.User.Password = ""
+	}
+
+	return protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(info)
+}
+
+// 获得用户的导出任务，任务不存在或不属于该用户时返回404
+func (e *ExportUsecase) get(username, id string) (*exportRecord, error) {
+	record, err := e.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.Username != username {
+		return nil, errors.NotFound("Export_Error", "导出任务不存在或已过期")
+	}
+	return record, nil
+}
+
+// 获得导出任务，任务不存在、已过期或无法解码时返回nil
+func (e *ExportUsecase) load(id string) (*exportRecord, error) {
+	marshal, err := e.repo.GetExportJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	record := new(exportRecord)
+	if marshal == nil || json.Unmarshal(marshal, record) != nil {
+		return nil, nil
+	}
+	// 兼容未记录清理时间的任务
+	if record.Deadline.IsZero() {
+		record.Deadline = record.CreateTime.Add(e.expiration)
+	}
+	return record, nil
+}
+
+func (e *ExportUsecase) save(record *exportRecord) error {
+	record.UpdateTime = time.Now().UTC()
+	marshal, err := json.Marshal(record)
+	if err != nil {
+		return errors.Newf(500, "Export_Error", "对导出任务进行json序列化时发生了错误:%v", err)
+	}
+	// 任务状态在清理时间后过期，归档由Run在任务状态过期后删除
+	ttl := time.Until(record.Deadline)
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	return e.repo.SaveExportJob(record.ID, marshal, ttl)
+}
+
+func (r *exportRecord) toProto() *v1.ExportJob {
+	job := &v1.ExportJob{
+		Id:           r.ID,
+		Format:       r.Format,
+		State:        r.State,
+		BucketsDone:  r.BucketsDone,
+		BucketsTotal: r.BucketsTotal,
+		Points:       r.Points,
+		Message:      r.Message,
+		CreateTime:   timestamppb.New(r.CreateTime),
+		SizeBytes:    r.SizeBytes,
+	}
+	if !r.ExpireTime.IsZero() {
+		job.ExpireTime = timestamppb.New(r.ExpireTime)
+	}
+	return job
+}
+
+// 导出的归档在归档存储中的key
+func exportBlobKey(id string) string {
+	return "exports/" + id + ".zip"
+}
+
+// 记录写入的字节数的writer
+type countingWriter struct {
+	io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func exportArchiveName(record *exportRecord) string {
+	return fmt.Sprintf("%s_export_%s.zip", record.Username, record.CreateTime.Format("20060102T150405Z"))
+}
@@ -0,0 +1,204 @@
+package biz
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的导出任务，忽略过期时间
+type memoryExportRepo struct {
+	mutex sync.Mutex
+	jobs  map[string][]byte
+}
+
+func (r *memoryExportRepo) SaveExportJob(id string, job []byte, ttl time.Duration) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *memoryExportRepo) GetExportJob(id string) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.jobs[id], nil
+}
+
+func (r *memoryExportRepo) ListExportJobs() ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var ids []string
+	for id := range r.jobs {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *memoryExportRepo) RemoveExportJob(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.jobs, id)
+	return nil
+}
+
+// 每个bucket导出一个数据点的时序数据库
+type exportingTimeSeries struct {
+	TimeSeriesBackend
+}
+
+func (b *exportingTimeSeries) ExportBucket(ctx context.Context, bucket, format string, w io.Writer,
+	progress func(points int64)) (int64, error) {
+	_, err := io.WriteString(w, bucket+" value=1\n")
+	return 1, err
+}
+
+func TestExportUsecase(t *testing.T) {
+	repo := &memoryExportRepo{jobs: map[string][]byte{}}
+	blobs := memoryBlobStore{}
+	exports := &ExportUsecase{repo: repo, blobs: blobs, expiration: time.Hour, logger: log.NewHelper(log.DefaultLogger)}
+
+	if _, err := exports.Start("alice", "parquet"); errors.Code(err) != 400 {
+		t.Fatalf("不支持的导出格式应返回400:%v", err)
+	}
+
+	running := &exportRecord{ID: "running", Username: "alice", Format: "csv", State: exportStateRunning,
+		BucketsTotal: 3, CreateTime: time.Now()}
+	if err := exports.save(running); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := exports.Status("alice", "running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != exportStateRunning || job.BucketsTotal != 3 || job.ExpireTime != nil {
+		t.Fatalf("unexpected job: %v", job)
+	}
+	if _, err := exports.Status("bob", "running"); errors.Code(err) != 404 {
+		t.Fatalf("其他租户的导出任务应返回404:%v", err)
+	}
+	if _, _, _, err := exports.Open("alice", "running"); errors.Code(err) != 400 {
+		t.Fatalf("未完成的导出任务不能下载:%v", err)
+	}
+
+	running.State = exportStateSucceeded
+	if err := exports.save(running); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := exports.Open("alice", "running"); errors.Code(err) != 404 {
+		t.Fatalf("归档过期后应返回404:%v", err)
+	}
+
+	blobs[exportBlobKey("running")] = []byte("zip")
+	name, _, archive, err := exports.Open("alice", "running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(archive)
+	archive.Close()
+	if string(content) != "zip" || name == "" {
+		t.Fatalf("unexpected archive: %s %q", name, content)
+	}
+}
+
+func TestExportUsecase_sweep(t *testing.T) {
+	repo := &memoryExportRepo{jobs: map[string][]byte{}}
+	blobs := memoryBlobStore{}
+	exports := &ExportUsecase{repo: repo, blobs: blobs, expiration: time.Hour, logger: log.NewHelper(log.DefaultLogger)}
+
+	now := time.Now()
+	for _, record := range []*exportRecord{
+		{ID: "running", Username: "alice", State: exportStateRunning, CreateTime: now},
+		{ID: "succeeded", Username: "alice", State: exportStateSucceeded, CreateTime: now},
+	} {
+		if err := exports.save(record); err != nil {
+			t.Fatal(err)
+		}
+		blobs[exportBlobKey(record.ID)] = []byte("zip")
+	}
+
+	// 状态仍在更新的任务不受影响
+	if err := exports.sweep(now); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := exports.Status("alice", "running"); job.State != exportStateRunning {
+		t.Fatalf("仍在执行的任务不应被标记为失败:%v", job)
+	}
+
+	// 执行任务的实例退出后，任务被标记为失败并删除未完成的归档
+	if err := exports.sweep(now.Add(exportStaleAfter + time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := exports.Status("alice", "running"); job.State != exportStateFailed {
+		t.Fatalf("中断的任务应被标记为失败:%v", job)
+	}
+	if _, ok := blobs[exportBlobKey("running")]; ok {
+		t.Fatal("应删除中断的任务未完成的归档")
+	}
+	if _, ok := blobs[exportBlobKey("succeeded")]; !ok {
+		t.Fatal("已完成的任务的归档不应被删除")
+	}
+
+	// 过期的任务与归档被清理
+	if err := exports.sweep(now.Add(2 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.jobs) != 0 || len(blobs) != 0 {
+		t.Fatalf("过期的任务与归档应被清理:%v %v", repo.jobs, blobs)
+	}
+}
+
+func TestExportUsecase_Start(t *testing.T) {
+	deletions := newMemoryDeletionRepo()
+	exports := &ExportUsecase{
+		repo:       &memoryExportRepo{jobs: map[string][]byte{}},
+		blobs:      memoryBlobStore{},
+		uc:         &UserUsecase{repo: &registerInfoRepo{infos: map[string][]byte{"alice": nil}}, deletions: deletions, timeSeries: &exportingTimeSeries{}},
+		expiration: time.Hour,
+		logger:     log.NewHelper(log.DefaultLogger),
+	}
+
+	// 注销后创建的归档保留到宽限期结束
+	purgeTime := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	deletions.schedules["alice"] = purgeTime
+	job, err := exports.Start("alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := exports.get("alice", job.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.Deadline.Equal(purgeTime) {
+		t.Fatalf("注销后创建的归档应保留到 %v，实际为 %v", purgeTime, record.Deadline)
+	}
+
+	// 归档以流的形式写入归档存储，完成后可以下载
+	for i := 0; job.State == exportStateRunning && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		job, _ = exports.Status("alice", job.Id)
+	}
+	if job.State != exportStateSucceeded || job.Points != 3 || !job.ExpireTime.AsTime().Equal(purgeTime) {
+		t.Fatalf("导出任务应成功完成:%v", job)
+	}
+	_, size, archive, err := exports.Open("alice", job.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	content, _ := io.ReadAll(archive)
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil || int64(len(content)) != size {
+		t.Fatalf("归档应为完整的zip文件:%v %d/%d", err, len(content), size)
+	}
+	if len(reader.File) != 4 {
+		t.Fatalf("归档中应包含注册信息与三个bucket的数据:%v", reader.File)
+	}
+}
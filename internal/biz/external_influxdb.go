@@ -0,0 +1,201 @@
+package biz
+
+import (
+	"context"
+	"crypto/cipher"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// 校验用户自带的influxdb时的超时时间
+const externalInfluxdbCheckTimeout = 10 * time.Second
+
+type ExternalInfluxdbRepo interface {
+	// SaveExternalInfluxdb 保存用户自带的influxdb加密后的连接信息
+	SaveExternalInfluxdb(username string, encrypted []byte) error
+	// GetExternalInfluxdb 获得用户自带的influxdb加密后的连接信息，用户使用共享的influxdb时返回nil
+	GetExternalInfluxdb(username string) ([]byte, error)
+	// RemoveExternalInfluxdb 删除用户自带的influxdb的连接信息
+	RemoveExternalInfluxdb(username string) error
+}
+
+// 管理用户自带的influxdb，连接信息加密后保存，并缓存以连接信息创建的客户端
+type externalInfluxdbs struct {
+	repo ExternalInfluxdbRepo
+	// 加密连接信息使用的AES-GCM，未配置加密密钥时为nil，此时不允许用户使用自带的influxdb
+	aead cipher.AEAD
+	// 限制可以连接的地址，防止用户以集群内部的服务作为自带的influxdb
+	guard    *outboundGuard
+	recorder audit.Recorder
+	mutex    sync.Mutex
+	clients  map[string]*influxdb.Client
+}
+
+func newExternalInfluxdbs(c *conf.Server_TimeSeries, guard *outboundGuard, repo ExternalInfluxdbRepo,
+	recorder audit.Recorder) (*externalInfluxdbs, error) {
+	external := &externalInfluxdbs{
+		repo:     repo,
+		guard:    guard,
+		recorder: recorder,
+		clients:  make(map[string]*influxdb.Client),
+	}
+	if key := c.GetEncryptionKey(); key != "" {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "初始化连接信息的加密密钥时发生了错误:%v", err)
+		}
+		external.aead = aead
+	}
+
+	return external, nil
+}
+
+// 以连接信息创建influxdb客户端，并校验可以连接以及token的权限
+func (e *externalInfluxdbs) connect(connection *v1.ExternalInfluxdb) (*influxdb.Client, error) {
+	if e.aead == nil {
+		return nil, errors.BadRequest("External_Influxdb_Error", "服务未配置连接信息的加密密钥，不允许使用自带的influxdb")
+	}
+
+	if err := e.guard.checkURL(connection.Url); err != nil {
+		return nil, err
+	}
+	client, err := e.newClient(connection)
+	if err != nil {
+		return nil, errors.BadRequest("External_Influxdb_Error",
+			"无法以给定的token查询influxdb中的org "+connection.Org+":"+err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), externalInfluxdbCheckTimeout)
+	defer cancel()
+	if err := client.CheckAccess(ctx); err != nil {
+		client.Close()
+		return nil, errors.BadRequest("External_Influxdb_Error", err.Error())
+	}
+
+	client.Recorder = e.recorder
+	return client, nil
+}
+
+// 校验连接信息，加密后保存，并缓存创建的客户端
+func (e *externalInfluxdbs) save(username string, connection *v1.ExternalInfluxdb) error {
+	client, err := e.connect(connection)
+	if err != nil {
+		return err
+	}
+
+	marshal, err := proto.Marshal(connection)
+	if err != nil {
+		client.Close()
+		return errors.Newf(500, "External_Influxdb_Error", "对influxdb的连接信息进行protobuf序列化时发生了错误:%v", err)
+	}
+	encrypted, err := sealAEAD(e.aead, marshal)
+	if err != nil {
+		client.Close()
+		return errors.Newf(500, "External_Influxdb_Error", "加密influxdb的连接信息时发生了错误:%v", err)
+	}
+	err = e.repo.SaveExternalInfluxdb(username, encrypted)
+	if err != nil {
+		client.Close()
+		return err
+	}
+
+	// 连接信息可能已经改变，因此替换已缓存的客户端
+	e.mutex.Lock()
+	previous, ok := e.clients[username]
+	e.clients[username] = client
+	e.mutex.Unlock()
+	if ok {
+		previous.Close()
+	}
+	return nil
+}
+
+// 获得用户自带的influxdb的连接信息，用户使用共享的influxdb时返回nil
+func (e *externalInfluxdbs) connection(username string) (*v1.ExternalInfluxdb, error) {
+	encrypted, err := e.repo.GetExternalInfluxdb(username)
+	if err != nil || encrypted == nil {
+		return nil, err
+	}
+	return e.decrypt(encrypted)
+}
+
+// 解密并解码保存的连接信息
+func (e *externalInfluxdbs) decrypt(encrypted []byte) (*v1.ExternalInfluxdb, error) {
+	if e.aead == nil {
+		return nil, errors.New(500, "External_Influxdb_Error", "服务未配置连接信息的加密密钥，无法读取用户自带的influxdb的连接信息")
+	}
+
+	marshal, err := openAEAD(e.aead, encrypted)
+	if err != nil {
+		return nil, errors.Newf(500, "External_Influxdb_Error", "解密influxdb的连接信息时发生了错误:%v", err)
+	}
+	connection := new(v1.ExternalInfluxdb)
+	if err := proto.Unmarshal(marshal, connection); err != nil {
+		return nil, errors.Newf(500, "External_Influxdb_Error", "对influxdb的连接信息进行protobuf解码时发生了错误:%v", err)
+	}
+	return connection, nil
+}
+
+// 获得用户自带的influxdb的客户端，用户使用共享的influxdb时返回nil
+func (e *externalInfluxdbs) client(username string) (*influxdb.Client, error) {
+	e.mutex.Lock()
+	client, ok := e.clients[username]
+	e.mutex.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	connection, err := e.connection(username)
+	if err != nil || connection == nil {
+		return nil, err
+	}
+	client, err = e.newClient(connection)
+	if err != nil {
+		return nil, errors.Newf(500, "External_Influxdb_Error", "连接用户 %v 自带的influxdb时发生了错误:%v", username, err)
+	}
+	client.Recorder = e.recorder
+
+	return e.cache(username, client), nil
+}
+
+// 以连接信息创建客户端，客户端只能连接外部地址
+func (e *externalInfluxdbs) newClient(connection *v1.ExternalInfluxdb) (*influxdb.Client, error) {
+	timeout := time.Duration(influxdb2.DefaultOptions().HTTPRequestTimeout()) * time.Second
+	options := influxdb2.DefaultOptions().SetHTTPClient(e.guard.httpClient(timeout))
+	return influxdb.NewInfluxdbClientWithOptions(connection.Url, connection.Token, connection.Org, options)
+}
+
+// 删除用户自带的influxdb的连接信息，并关闭缓存的客户端
+func (e *externalInfluxdbs) remove(username string) error {
+	err := e.repo.RemoveExternalInfluxdb(username)
+	if err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	client, ok := e.clients[username]
+	delete(e.clients, username)
+	e.mutex.Unlock()
+	if ok {
+		client.Close()
+	}
+	return nil
+}
+
+// 缓存用户的客户端，并发创建时保留先缓存的客户端，返回缓存中的客户端
+func (e *externalInfluxdbs) cache(username string, client *influxdb.Client) *influxdb.Client {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if cached, ok := e.clients[username]; ok {
+		client.Close()
+		return cached
+	}
+	e.clients[username] = client
+	return client
+}
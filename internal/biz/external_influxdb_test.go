@@ -0,0 +1,139 @@
+package biz
+
+import (
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/victoriametrics"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"testing"
+)
+
+type memoryExternalInfluxdbRepo map[string][]byte
+
+func (r memoryExternalInfluxdbRepo) SaveExternalInfluxdb(username string, encrypted []byte) error {
+	r[username] = encrypted
+	return nil
+}
+
+func (r memoryExternalInfluxdbRepo) GetExternalInfluxdb(username string) ([]byte, error) {
+	return r[username], nil
+}
+
+func (r memoryExternalInfluxdbRepo) RemoveExternalInfluxdb(username string) error {
+	delete(r, username)
+	return nil
+}
+
+func TestExternalInfluxdbs(t *testing.T) {
+	connection := &v1.ExternalInfluxdb{Url: "http://127.0.0.1:1", Org: "acme", Token: "secret-token"}
+
+	disabled, err := newExternalInfluxdbs(nil, new(outboundGuard), memoryExternalInfluxdbRepo{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := disabled.connect(connection); errors.Code(err) != 400 {
+		t.Fatalf("未配置加密密钥时不允许使用自带的influxdb:%v", err)
+	}
+
+	repo := memoryExternalInfluxdbRepo{}
+	external, err := newExternalInfluxdbs(&conf.Server_TimeSeries{EncryptionKey: "test"}, new(outboundGuard), repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 无法连接的influxdb在创建任何资源之前被拒绝
+	if _, err := external.connect(connection); errors.Code(err) != 400 {
+		t.Fatalf("无法连接的influxdb应返回400:%v", err)
+	}
+
+	marshal, err := proto.Marshal(connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := sealAEAD(external.aead, marshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encrypted), connection.Token) {
+		t.Fatal("保存的连接信息不应包括明文的token")
+	}
+	repo["alice"] = encrypted
+
+	got, err := external.connection("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, connection) {
+		t.Fatalf("解密的连接信息错误:%v", got)
+	}
+	if got, err := external.connection("bob"); err != nil || got != nil {
+		t.Fatalf("使用共享influxdb的用户不应有连接信息:%v %v", got, err)
+	}
+
+	// 以不同的密钥无法解密
+	other, _ := newExternalInfluxdbs(&conf.Server_TimeSeries{EncryptionKey: "other"}, new(outboundGuard), repo, nil)
+	if _, err := other.connection("alice"); err == nil {
+		t.Fatal("以不同的密钥不应能解密连接信息")
+	}
+
+	shared, err := victoriametrics.NewClient(
+		"http://vminsert:8480", "http://vmselect:8481", "http://vmauth:8427", newMemoryAccountRepo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := &UserUsecase{timeSeries: shared, external: external}
+	if backend, err := uc.timeSeriesOf("bob"); err != nil || backend != TimeSeriesBackend(shared) {
+		t.Fatalf("使用共享influxdb的用户应使用共享的时序数据库:%v %v", backend, err)
+	}
+
+	if err := external.remove("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo["alice"]; ok {
+		t.Fatal("应删除用户自带的influxdb的连接信息")
+	}
+}
+
+func TestUserUsecase_clearExternal(t *testing.T) {
+	repo := memoryExternalInfluxdbRepo{}
+	external, err := newExternalInfluxdbs(&conf.Server_TimeSeries{EncryptionKey: "test"}, new(outboundGuard), repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshal, err := proto.Marshal(&v1.ExternalInfluxdb{Url: "http://127.0.0.1:1", Org: "acme", Token: "secret-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo["alice"], err = sealAEAD(external.aead, marshal); err != nil {
+		t.Fatal(err)
+	}
+
+	users := &loginRepo{}
+	g := &recordingGateway{}
+	shared := &recordingTimeSeries{}
+	uc := &UserUsecase{
+		repo:       users,
+		clusters:   &clusterPlacement{repo: users},
+		gateway:    g,
+		timeSeries: shared,
+		external:   external,
+		logger:     log.NewHelper(log.DefaultLogger),
+	}
+
+	// 用户自带的influxdb无法连接时不应影响其余资源的清理，错误中只包括查询所在集群的错误
+	err = uc.clear("alice")
+	if err == nil || strings.Contains(err.Error(), "influxdb") {
+		t.Fatalf("自带的influxdb无法连接时不应返回其错误:%v", err)
+	}
+	if len(g.unregistered) != 1 {
+		t.Fatal("应继续清理网关")
+	}
+	if len(shared.cleared) != 0 {
+		t.Fatal("不应清理共享的时序数据库")
+	}
+	if _, ok := repo["alice"]; ok {
+		t.Fatal("应删除用户自带的influxdb的连接信息")
+	}
+}
@@ -0,0 +1,65 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"gitee.com/moyusir/service-centre/internal/biz/kubecontroller"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// 依据配置创建发布用户服务的网关，默认为kong，recorder记录对网关的每次修改
+func newGatewayProvider(server *conf.Server, recorder audit.Recorder) (gateway.Provider, error) {
+	config := server.Gateway
+	if config == nil {
+		return nil, errors.New(500, "Config_Error", "服务配置中缺少网关的配置")
+	}
+
+	switch config.Type {
+	case "", "kong":
+		manager, err := gateway.NewManager(config.Address, server.AppDomainName)
+		if err != nil {
+			return nil, err
+		}
+		manager.Recorder = recorder
+		return manager, nil
+	case gateway.GatewayAPIType:
+		api := config.GatewayApi
+		if api == nil {
+			return nil, errors.New(500, "Config_Error", "服务配置中缺少Gateway API的配置")
+		}
+		var authenticator gateway.Authenticator
+		switch api.Authenticator {
+		case "", gateway.EnvoyAuthenticatorType:
+			authenticator = gateway.EnvoyAuthenticator{}
+		default:
+			return nil, errors.Newf(500, "Config_Error", "不支持的网关认证方式:%s", api.Authenticator)
+		}
+
+		// 路由与用户服务位于同一命名空间，多集群部署时为首个集群的命名空间，
+		// 且路由只能转发到网关所在集群的服务
+		namespace := ""
+		if c := server.Cluster; c != nil && len(c.Targets) > 0 {
+			namespace = c.Targets[0].Namespace
+		} else if c != nil {
+			namespace = c.Namespace
+		}
+		store, err := kubecontroller.NewKubeControllerWithKubeconfig(api.Kubeconfig, namespace)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := gateway.NewGatewayAPI(store, authenticator, gateway.GatewayAPIOption{
+			GatewayName:      api.GatewayName,
+			GatewayNamespace: api.GatewayNamespace,
+			Namespace:        namespace,
+			AppDomainName:    server.AppDomainName,
+		})
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "%v", err)
+		}
+		provider.Recorder = recorder
+		return provider, nil
+	default:
+		return nil, errors.Newf(500, "Config_Error", "不支持的网关:%s", config.Type)
+	}
+}
@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/util/kong"
+	"net/http"
+	"sort"
+)
+
+// OrgGroup 组织所有成员所在的acl分组，可以访问组织的数据收集与数据处理服务
+func OrgGroup(org string) string {
+	return org
+}
+
+// OperatorGroup 组织中operator及以上角色的成员所在的acl分组，还可以访问更新设备配置的服务
+func OperatorGroup(org string) string {
+	return org + "-operators"
+}
+
+// EnableOrgACL 为组织的kong service组件创建acl插件，只允许组织相应分组中的consumer访问，
+// 并将组织owner的consumer加入所有分组，重复调用不会失败
+func (m *Manager) EnableOrgACL(org string) (err error) {
+	allows := map[string]string{
+		org + "-dc":               OrgGroup(org),
+		org + "-dc-config-update": OperatorGroup(org),
+		org + "-dp":               OrgGroup(org),
+	}
+	defer func() {
+		targets := make([]string, 0, len(allows))
+		for service := range allows {
+			targets = append(targets, "plugins/acl@"+service)
+		}
+		sort.Strings(targets)
+		audit.Record(m.Recorder, "gateway.enable_org_acl", org, targets, err)
+	}()
+	for service, group := range allows {
+		response, err := m.Client.R().
+			SetBodyJsonMarshal(map[string]interface{}{
+				"name":    "acl",
+				"enabled": true,
+				"service": map[string]string{"name": service},
+				"config":  map[string]interface{}{"allow": []string{group}},
+				"tags":    []string{org},
+			}).
+			Post("/plugins")
+		if err != nil {
+			return fmt.Errorf("为服务 %s 创建acl插件时发生了错误: %w", service, err)
+		}
+		// 服务已存在acl插件时kong返回409，说明此前已经启用，使重复启用不会失败
+		if response.IsError() && response.StatusCode != http.StatusConflict {
+			return fmt.Errorf("为服务 %s 创建acl插件时发生了错误: %s", service, response.String())
+		}
+	}
+
+	return m.SetACLGroups(org, org, true)
+}
+
+// CreateMemberConsumer 为组织的成员创建consumer以及相应的api密钥，consumer以custom_id记录所属的组织，
+// 并附上组织的tag，使其随组织一同注销
+func (m *Manager) CreateMemberConsumer(org, member string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.create_member_consumer", org,
+			[]string{"consumers/" + member, "consumers/" + member + "/key-auth"}, err)
+	}()
+
+	response, err := m.Client.R().
+		SetBodyJsonMarshal(map[string]interface{}{
+			"username":  member,
+			"custom_id": org,
+			"tags":      []string{org},
+		}).
+		Post("/consumers")
+	if err != nil {
+		return "", fmt.Errorf("创建成员 %s 的consumer时发生了错误: %w", member, err)
+	}
+	if response.IsError() {
+		return "", fmt.Errorf("创建成员 %s 的consumer时发生了错误: %s", member, response.String())
+	}
+
+	key, err := m.Create(&kong.KeyCreateOption{Username: member})
+	if err != nil {
+		m.DeleteConsumer(member)
+		return "", err
+	}
+
+	return key.(*kong.Key).Key, nil
+}
+
+// DeleteConsumer 删除consumer，consumer的api密钥以及acl分组随之删除
+func (m *Manager) DeleteConsumer(username string) (err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.delete_consumer", "", []string{"consumers/" + username}, err)
+	}()
+
+	response, err := m.Client.R().
+		SetPathParam("username", username).
+		Delete("/consumers/{username}")
+	if err != nil {
+		return fmt.Errorf("删除consumer %s 时发生了错误: %w", username, err)
+	}
+	if response.IsError() && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("删除consumer %s 时发生了错误: %s", username, response.String())
+	}
+	return nil
+}
+
+// SetACLGroups 将consumer加入组织的分组，operate为true时同时加入operator分组，否则将其移出operator分组
+func (m *Manager) SetACLGroups(org, consumer string, operate bool) (err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.set_acl_groups", org, []string{
+			"consumers/" + consumer + "/acls/" + OrgGroup(org),
+			"consumers/" + consumer + "/acls/" + OperatorGroup(org),
+		}, err)
+	}()
+
+	if err := m.addACLGroup(consumer, OrgGroup(org)); err != nil {
+		return err
+	}
+	if operate {
+		return m.addACLGroup(consumer, OperatorGroup(org))
+	}
+	return m.removeACLGroup(consumer, OperatorGroup(org))
+}
+
+// 将consumer加入acl分组，已在分组中时kong返回409
+func (m *Manager) addACLGroup(consumer, group string) error {
+	response, err := m.Client.R().
+		SetPathParam("consumer", consumer).
+		SetBodyJsonMarshal(map[string]string{"group": group}).
+		Post("/consumers/{consumer}/acls")
+	if err != nil {
+		return fmt.Errorf("将consumer %s 加入分组 %s 时发生了错误: %w", consumer, group, err)
+	}
+	if response.IsError() && response.StatusCode != http.StatusConflict {
+		return fmt.Errorf("将consumer %s 加入分组 %s 时发生了错误: %s", consumer, group, response.String())
+	}
+	return nil
+}
+
+// 将consumer移出acl分组，不在分组中时kong返回404
+func (m *Manager) removeACLGroup(consumer, group string) error {
+	response, err := m.Client.R().
+		SetPathParams(map[string]string{"consumer": consumer, "group": group}).
+		Delete("/consumers/{consumer}/acls/{group}")
+	if err != nil {
+		return fmt.Errorf("将consumer %s 移出分组 %s 时发生了错误: %w", consumer, group, err)
+	}
+	if response.IsError() && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("将consumer %s 移出分组 %s 时发生了错误: %s", consumer, group, response.String())
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package gateway
+
+// RouteRef 认证对象所保护的路由
+type RouteRef struct {
+	// 路由的类型，包括HTTPRoute与GRPCRoute
+	Kind string
+	Name string
+}
+
+// Authenticator 为Gateway API的路由生成校验api密钥的对象，Gateway API本身不包含认证的定义，
+// 需要由网关的实现提供
+type Authenticator interface {
+	// Policies 返回要求访问route的请求携带credentials中任一api密钥的对象，
+	// credentials为保存api密钥的secret的名称，secret的每个键值对为一个api密钥
+	Policies(route RouteRef, credentials []string) []map[string]interface{}
+}
+
+// EnvoyAuthenticator 使用envoy gateway的SecurityPolicy校验请求的query或者header中的X-Api-Key
+type EnvoyAuthenticator struct{}
+
+// EnvoyAuthenticatorType 配置中envoy gateway认证方式的名称
+const EnvoyAuthenticatorType = "envoy"
+
+func (EnvoyAuthenticator) Policies(route RouteRef, credentials []string) []map[string]interface{} {
+	refs := make([]interface{}, len(credentials))
+	for i, c := range credentials {
+		refs[i] = map[string]interface{}{"group": "", "kind": "Secret", "name": c}
+	}
+
+	return []map[string]interface{}{{
+		"apiVersion": "gateway.envoyproxy.io/v1alpha1",
+		"kind":       "SecurityPolicy",
+		"metadata":   map[string]interface{}{"name": route.Name + "-api-key"},
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{map[string]interface{}{
+				"group": gatewayAPIGroup,
+				"kind":  route.Kind,
+				"name":  route.Name,
+			}},
+			"apiKeyAuth": map[string]interface{}{
+				"credentialRefs": refs,
+				"extractFrom": []interface{}{
+					map[string]interface{}{"headers": []interface{}{"X-Api-Key"}},
+					map[string]interface{}{"params": []interface{}{"X-Api-Key"}},
+				},
+			},
+		},
+	}}
+}
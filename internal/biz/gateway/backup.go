@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+)
+
+// ConsumerSnapshot 用户在网关中的consumer以及其api密钥，用于备份与恢复用户
+type ConsumerSnapshot struct {
+	Username string   `json:"username"`
+	CustomId string   `json:"custom_id,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Keys     []string `json:"keys"`
+}
+
+// ExportConsumer 获得用户的consumer以及其所有的api密钥
+func (m *Manager) ExportConsumer(username string) (*ConsumerSnapshot, error) {
+	snapshot := new(ConsumerSnapshot)
+	response, err := m.Client.R().
+		SetPathParam("username", username).
+		SetResult(snapshot).
+		Get("/consumers/{username}")
+	if err != nil {
+		return nil, fmt.Errorf("查询用户 %s 的consumer时发生了错误: %w", username, err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("查询用户 %s 的consumer时发生了错误: %s", username, response.String())
+	}
+
+	result := &struct {
+		Data []struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}{}
+	response, err = m.Client.R().
+		SetPathParam("username", username).
+		SetResult(result).
+		Get("/consumers/{username}/key-auth")
+	if err != nil {
+		return nil, fmt.Errorf("查询用户 %s 的api密钥时发生了错误: %w", username, err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("查询用户 %s 的api密钥时发生了错误: %s", username, response.String())
+	}
+	snapshot.Keys = make([]string, 0, len(result.Data))
+	for _, k := range result.Data {
+		snapshot.Keys = append(snapshot.Keys, k.Key)
+	}
+
+	return snapshot, nil
+}
+
+// ImportConsumer 依据备份重新创建用户的consumer，并以原有的值创建api密钥，使用户的设备无需更换密钥，
+// 返回第一个api密钥
+func (m *Manager) ImportConsumer(snapshot *ConsumerSnapshot) (apiKey string, err error) {
+	if snapshot == nil || snapshot.Username == "" || len(snapshot.Keys) == 0 {
+		return "", fmt.Errorf("consumer的备份中缺少用户名或api密钥")
+	}
+	username := snapshot.Username
+	defer func() {
+		audit.Record(m.Recorder, "gateway.import_consumer", username,
+			[]string{"consumers/" + username, "consumers/" + username + "/key-auth"}, err)
+	}()
+
+	consumer := map[string]interface{}{"username": username, "tags": snapshot.Tags}
+	if snapshot.CustomId != "" {
+		consumer["custom_id"] = snapshot.CustomId
+	}
+	response, err := m.Client.R().SetBodyJsonMarshal(consumer).Post("/consumers")
+	if err != nil {
+		return "", fmt.Errorf("创建用户 %s 的consumer时发生了错误: %w", username, err)
+	}
+	if response.IsError() {
+		return "", fmt.Errorf("创建用户 %s 的consumer时发生了错误: %s", username, response.String())
+	}
+
+	for _, key := range snapshot.Keys {
+		response, err := m.Client.R().
+			SetPathParam("username", username).
+			SetBodyJsonMarshal(map[string]string{"key": key}).
+			Post("/consumers/{username}/key-auth")
+		if err == nil && response.IsError() {
+			err = fmt.Errorf("%s", response.String())
+		}
+		if err != nil {
+			m.DeleteConsumer(username)
+			return "", fmt.Errorf("恢复用户 %s 的api密钥时发生了错误: %w", username, err)
+		}
+	}
+
+	return snapshot.Keys[0], nil
+}
@@ -0,0 +1,316 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// KongEntity kong中的一个实体，字段与admin api中实体的字段一致
+type KongEntity map[string]interface{}
+
+// KongConfig 用户的一个服务在kong中应有的services、routes与plugins，结构与kong的声明式配置一致，
+// route与plugin以{"service":{"name":<service名>}}引用其所属的service
+type KongConfig struct {
+	Services []KongEntity `json:"services"`
+	Routes   []KongEntity `json:"routes"`
+	Plugins  []KongEntity `json:"plugins"`
+}
+
+// SyncConfig 使kong中以username与scope为tag的对象与config一致：创建缺少的对象，更新与config不一致的对象，
+// 并删除config中不存在的对象，config中的所有对象都将附上这两个tag。
+// 同步是幂等的，中途失败时重新同步即可收敛，不会遗留或重复创建对象。
+// service的enabled字段由SuspendRoutes管理，同步时不会修改
+func (m *Manager) SyncConfig(username, scope string, config *KongConfig) error {
+	tags := []interface{}{username, scope}
+	tagQuery := username + "," + scope
+
+	// 依次同步service、route与plugin，使route与plugin可以引用已存在的service
+	serviceIDs := make(map[string]string, len(config.Services))
+	for _, s := range config.Services {
+		name, _ := s["name"].(string)
+		id, err := m.syncEntity("/services/"+name, "/services", withTags(s, tags))
+		if err != nil {
+			return err
+		}
+		serviceIDs[name] = id
+	}
+
+	desiredRoutes := make(map[string]bool, len(config.Routes))
+	for _, r := range config.Routes {
+		name, _ := r["name"].(string)
+		route, err := resolveService(withTags(r, tags), serviceIDs)
+		if err != nil {
+			return err
+		}
+		if _, err := m.syncEntity("/routes/"+name, "/routes", route); err != nil {
+			return err
+		}
+		desiredRoutes[name] = true
+	}
+
+	// plugin没有名称，以<插件名>@<service名>标识，因此需要查询service上已有的插件
+	desiredPlugins := make(map[string]bool, len(config.Plugins))
+	existingPlugins := make(map[string]map[string]interface{})
+	for name := range serviceIDs {
+		plugins, err := m.listEntities("/services/" + name + "/plugins")
+		if err != nil {
+			return err
+		}
+		for _, p := range plugins {
+			existingPlugins[fmt.Sprintf("%v@%s", p["name"], name)] = p
+		}
+	}
+	for _, p := range config.Plugins {
+		plugin, err := resolveService(withTags(p, tags), serviceIDs)
+		if err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%v@%s", p["name"], referencedService(p))
+		desiredPlugins[key] = true
+
+		path := ""
+		if existing, ok := existingPlugins[key]; ok {
+			path = fmt.Sprintf("/plugins/%v", existing["id"])
+		}
+		if _, err := m.syncEntity(path, "/plugins", plugin); err != nil {
+			return err
+		}
+	}
+
+	// 删除以scope为tag但不在config中的对象，service上的其余插件(如acl插件)不属于该scope，因此保留
+	for key, p := range existingPlugins {
+		if !desiredPlugins[key] && hasTag(p, scope) {
+			if err := m.deleteEntity(fmt.Sprintf("/plugins/%v", p["id"])); err != nil {
+				return err
+			}
+		}
+	}
+	routes, err := m.listEntities("/routes?tags=" + tagQuery)
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		if name, _ := r["name"].(string); !desiredRoutes[name] {
+			if err := m.deleteEntity(fmt.Sprintf("/routes/%v", r["id"])); err != nil {
+				return err
+			}
+		}
+	}
+	services, err := m.listEntities("/services?tags=" + tagQuery)
+	if err != nil {
+		return err
+	}
+	for _, s := range services {
+		if name, _ := s["name"].(string); serviceIDs[name] == "" {
+			// service上的插件随service一同删除
+			if err := m.deleteEntity(fmt.Sprintf("/services/%v", s["id"])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// 使path处的实体与desired一致，path为空或实体不存在时在collection中创建实体，返回实体的id
+func (m *Manager) syncEntity(path, collection string, desired KongEntity) (string, error) {
+	var current map[string]interface{}
+	if path != "" {
+		var err error
+		current, err = m.getEntity(path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if current == nil {
+		created, err := m.sendEntity(http.MethodPost, collection, desired)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprint(created["id"]), nil
+	}
+	if entityMatches(desired, current) {
+		return fmt.Sprint(current["id"]), nil
+	}
+	// 以patch的方式更新，保留desired中未包含的字段，如service的enabled
+	updated, err := m.sendEntity(http.MethodPatch, path, desired)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(updated["id"]), nil
+}
+
+// 查询指定的实体，实体不存在时返回nil
+func (m *Manager) getEntity(path string) (map[string]interface{}, error) {
+	entity := make(map[string]interface{})
+	response, err := m.Client.R().SetResult(&entity).Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("查询 %s 时发生了错误: %w", path, err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("查询 %s 时发生了错误: %s", path, response.String())
+	}
+	return entity, nil
+}
+
+// 查询实体列表，依据next字段获得所有分页，path对应的父实体不存在时返回空列表
+func (m *Manager) listEntities(path string) ([]map[string]interface{}, error) {
+	var entities []map[string]interface{}
+	for path != "" {
+		result := &struct {
+			Data []map[string]interface{} `json:"data"`
+			Next *string                  `json:"next"`
+		}{}
+		response, err := m.Client.R().SetResult(result).Get(path)
+		if err != nil {
+			return nil, fmt.Errorf("查询 %s 时发生了错误: %w", path, err)
+		}
+		if response.StatusCode == http.StatusNotFound {
+			return entities, nil
+		}
+		if response.IsError() {
+			return nil, fmt.Errorf("查询 %s 时发生了错误: %s", path, response.String())
+		}
+
+		entities = append(entities, result.Data...)
+		path = ""
+		if result.Next != nil {
+			path = *result.Next
+		}
+	}
+	return entities, nil
+}
+
+func (m *Manager) sendEntity(method, path string, entity KongEntity) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	request := m.Client.R().SetBodyJsonMarshal(entity).SetResult(&result)
+	response, err := request.Send(method, path)
+	if err != nil {
+		return nil, fmt.Errorf("更新 %s 时发生了错误: %w", path, err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("更新 %s 时发生了错误: %s", path, response.String())
+	}
+	return result, nil
+}
+
+// 删除指定的实体，实体不存在时视为删除成功
+func (m *Manager) deleteEntity(path string) error {
+	response, err := m.Client.R().Delete(path)
+	if err != nil {
+		return fmt.Errorf("删除 %s 时发生了错误: %w", path, err)
+	}
+	if response.IsError() && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("删除 %s 时发生了错误: %s", path, response.String())
+	}
+	return nil
+}
+
+// 判断kong中的实体是否已包含desired中的所有字段，kong返回的实体中还包含其余字段的默认值，因此只比较desired中的字段
+func entityMatches(desired KongEntity, current map[string]interface{}) bool {
+	// 经由json转换，使desired中的数字与切片的类型与kong返回的一致
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return false
+	}
+	return contains(normalized, current)
+}
+
+func contains(desired, current interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range d {
+			if !contains(v, c[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok || len(c) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !contains(d[i], c[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, current)
+	}
+}
+
+// 复制实体并附上tag
+func withTags(entity KongEntity, tags []interface{}) KongEntity {
+	result := make(KongEntity, len(entity)+1)
+	for k, v := range entity {
+		result[k] = v
+	}
+	result["tags"] = tags
+	return result
+}
+
+// 将实体中以名称对service的引用替换为以id引用，kong返回的实体中只包含service的id
+func resolveService(entity KongEntity, serviceIDs map[string]string) (KongEntity, error) {
+	name := referencedService(entity)
+	if name == "" {
+		return entity, nil
+	}
+	id, ok := serviceIDs[name]
+	if !ok {
+		return nil, fmt.Errorf("引用的服务 %s 不在配置中", name)
+	}
+	entity["service"] = map[string]interface{}{"id": id}
+	return entity, nil
+}
+
+func referencedService(entity KongEntity) string {
+	switch s := entity["service"].(type) {
+	case map[string]interface{}:
+		name, _ := s["name"].(string)
+		return name
+	case map[string]string:
+		return s["name"]
+	}
+	return ""
+}
+
+func hasTag(entity map[string]interface{}, tag string) bool {
+	tags, _ := entity["tags"].([]interface{})
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// 辅助函数，创建要求在query或者header中添加X-Api-Key的key-auth插件
+func keyAuthPlugin(service string) KongEntity {
+	return KongEntity{
+		"name":    "key-auth",
+		"enabled": true,
+		"service": map[string]interface{}{"name": service},
+		"config": map[string]interface{}{
+			"key_names":     []string{"X-Api-Key"},
+			"key_in_query":  true,
+			"key_in_header": true,
+			"key_in_body":   false,
+		},
+	}
+}
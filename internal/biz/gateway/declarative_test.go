@@ -0,0 +1,64 @@
+package gateway
+
+import (
+	"encoding/json"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_entityMatches(t *testing.T) {
+	desired := withTags(keyAuthPlugin("a-dp"), []interface{}{"a", "a-dp"})
+	desired["service"] = map[string]interface{}{"id": "1"}
+
+	// kong返回的插件中还包含其余字段的默认值
+	current := make(map[string]interface{})
+	json.Unmarshal([]byte(`{
+  "id": "p1", "name": "key-auth", "enabled": true, "protocols": ["grpc", "http"],
+  "service": {"id": "1"}, "route": null, "consumer": null, "tags": ["a", "a-dp"],
+  "config": {"key_names": ["X-Api-Key"], "key_in_query": true, "key_in_header": true,
+             "key_in_body": false, "hide_credentials": false, "anonymous": null}
+}`), &current)
+	if !entityMatches(desired, current) {
+		t.Fatal("只比较期望状态中的字段时应视为一致")
+	}
+
+	current["tags"] = []interface{}{"a"}
+	if entityMatches(desired, current) {
+		t.Fatal("缺少tag时应视为不一致")
+	}
+	current["tags"] = []interface{}{"a", "a-dp"}
+	current["config"].(map[string]interface{})["key_names"] = []interface{}{"X-Api-Key", "apikey"}
+	if entityMatches(desired, current) {
+		t.Fatal("列表字段的元素不同时应视为不一致")
+	}
+}
+
+func TestManager_ServiceConfig(t *testing.T) {
+	m := &Manager{AppDomainName: "app.test"}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-dc"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "grpc", Port: 9001}}},
+	}
+
+	dc := m.DcServiceConfig("a", service, "a-dc")
+	if len(dc.Services) != 2 || len(dc.Routes) != 2 {
+		t.Fatalf("数据收集服务应包含grpc与更新设备配置的service以及路由:%v", dc)
+	}
+	// 两个service都需要认证插件
+	if len(dc.Plugins) != 2 || referencedService(dc.Plugins[1]) != "a-dc-config-update" {
+		t.Fatalf("每个service都应有key-auth插件:%v", dc.Plugins)
+	}
+	if dc.Services[0]["port"] != int32(9001) || dc.Services[1]["port"] != int32(8000) {
+		t.Fatalf("应使用service的端口或默认端口:%v", dc.Services)
+	}
+
+	service.Name = "a_b-dp"
+	dp := m.DpServiceConfig("a_b", service, "a_b-dp")
+	if len(dp.Routes) != 2 || len(dp.Plugins) != 1 {
+		t.Fatalf("数据处理服务应包含两条路由以及认证插件:%v", dp)
+	}
+	if paths := dp.Routes[1]["paths"].([]string); paths[0] != "/warnings/push/a-b" {
+		t.Fatalf("预警推送路由的path错误:%v", paths)
+	}
+}
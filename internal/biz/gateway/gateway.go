@@ -1,16 +1,21 @@
 package gateway
 
 import (
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
 	"gitee.com/moyusir/util/kong"
 	"github.com/go-kratos/kratos/v2/errors"
 	corev1 "k8s.io/api/core/v1"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
 type Manager struct {
 	*kong.Admin
 	AppDomainName string
+	// 记录对网关的每次修改，为nil时不记录
+	Recorder audit.Recorder
 }
 
 func NewManager(address, appDomainName string) (*Manager, error) {
@@ -23,6 +28,11 @@ func NewManager(address, appDomainName string) (*Manager, error) {
 
 // CreateConsumerAndKey 为用户创建在网关中的consumer实体以及相应的api密钥
 func (m *Manager) CreateConsumerAndKey(username string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.create_consumer", username,
+			[]string{"consumers/" + username, "consumers/" + username + "/key-auth"}, err)
+	}()
+
 	consumerCreateOption := &kong.ConsumerCreateOption{
 		Username: username,
 		Tags:     []string{username},
@@ -42,20 +52,53 @@ func (m *Manager) CreateConsumerAndKey(username string) (apiKey string, err erro
 	return key.(*kong.Key).Key, nil
 }
 
-// Unregister 清空用户在网关相关的组件
-func (m *Manager) Unregister(username string) error {
-	m.Clear(
-		kong.FLAG_SERVICE|kong.FLAG_ROUTE|kong.FLAG_PLUGIN|kong.FLAG_CONSUMER, username)
+// Unregister 清空用户在网关相关的组件，依次删除带有用户tag的插件、consumer、路由以及service，
+// 重复调用时只删除剩余的组件
+func (m *Manager) Unregister(username string) (err error) {
+	collections := []string{"plugins", "consumers", "routes", "services"}
+	defer func() {
+		targets := make([]string, len(collections))
+		for i, c := range collections {
+			targets[i] = c + "?tags=" + username
+		}
+		audit.Record(m.Recorder, "gateway.unregister", username, targets, err)
+	}()
+
+	for _, collection := range collections {
+		entities, err := m.listEntities("/" + collection + "?tags=" + url.QueryEscape(username))
+		if err != nil {
+			return err
+		}
+		for _, e := range entities {
+			if err := m.deleteEntity(fmt.Sprintf("/%s/%v", collection, e["id"])); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-// CreateDcServiceRoute 为数据收集服务的service组件创建外部路由
-func (m *Manager) CreateDcServiceRoute(username string, service *corev1.Service) error {
+// CreateDcServiceRoute 为数据收集服务的service组件创建外部路由，host为网关访问该service时使用的地址，
+// 重复调用时使kong中的对象与期望的状态一致
+func (m *Manager) CreateDcServiceRoute(username string, service *corev1.Service, host string) (err error) {
 	if service == nil {
 		return errors.New(500, "service is nil", "")
 	}
-	// 为数据收集服务的grpc连接创建路由
-	// 查询service提供grpc的端口，默认为9000
+	configUpdateSvcName := service.Name + "-config-update"
+	defer func() {
+		audit.Record(m.Recorder, "gateway.create_dc_routes", username, []string{
+			"services/" + service.Name, "services/" + configUpdateSvcName,
+			"routes/" + service.Name, "routes/" + configUpdateSvcName,
+			"plugins/key-auth@" + service.Name, "plugins/key-auth@" + configUpdateSvcName,
+		}, err)
+	}()
+
+	return m.SyncConfig(username, service.Name, m.DcServiceConfig(username, service, host))
+}
+
+// DcServiceConfig 数据收集服务在kong中应有的对象，包括grpc连接与更新设备配置的service、路由以及认证插件
+func (m *Manager) DcServiceConfig(username string, service *corev1.Service, host string) *KongConfig {
+	// 查询service提供grpc与http的端口，默认分别为9000与8000
 	var grpcPort int32 = 9000
 	var httpPort int32 = 8000
 	for _, p := range service.Spec.Ports {
@@ -65,147 +108,73 @@ func (m *Manager) CreateDcServiceRoute(username string, service *corev1.Service)
 			httpPort = p.Port
 		}
 	}
-
-	var (
-		err     error
-		objects []kong.Object
-	)
-	defer func() {
-		if err != nil {
-			for _, o := range objects {
-				m.Admin.Delete(o)
-			}
-		}
-	}()
-
-	// 配置kong service组件的创建选项，需要附上用户名的tag方便后续用户注销
-	serviceCreateOption := &kong.ServiceCreateOption{
-		Name:     service.Name,
-		Protocol: "grpc",
-		// k8s中服务名即相应的域名
-		Host:           service.Name,
-		Port:           int(grpcPort),
-		Enabled:        true,
-		WriteTimeout:   600000,
-		ReadTimeout:    600000,
-		ConnectTimeout: 600000,
-		Tags:           []string{username},
-	}
-	svc, err := m.Create(serviceCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, svc)
-
 	configUpdateSvcName := service.Name + "-config-update"
-	configUpdateServiceCreateOption := &kong.ServiceCreateOption{
-		Name:     configUpdateSvcName,
-		Protocol: "http",
-		// k8s中服务名即相应的域名
-		Host:    service.Name,
-		Port:    int(httpPort),
-		Path:    "/",
-		Enabled: true,
-		Tags:    []string{username},
-	}
-	configUpdateSvc, err := m.Create(configUpdateServiceCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, configUpdateSvc)
-
-	// 创建路由，路由匹配条件包括host请求头和X-Service-Type:<用户名>-dc，
-	// tag部分需要附上用户名，方便后续用户注销
-	routeCreateOption := &kong.RouteCreateOption{
-		Name:      service.Name,
-		Protocols: []string{"grpc"},
-		Hosts:     []string{m.AppDomainName},
-		Paths:     []string{"/"},
-		Headers: map[string][]string{
-			"X-Service-Type": {username + "-dc"},
-		},
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: service.Name},
-		Tags: []string{username},
-	}
-	route, err := m.Create(routeCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, route)
-
-	configUpdateRouteCreateOption := &kong.RouteCreateOption{
-		Name:      configUpdateSvcName,
-		Protocols: []string{"http"},
-		Methods:   []string{http.MethodPost},
-		Hosts:     []string{m.AppDomainName},
-		Paths:     []string{"/"},
-		Headers: map[string][]string{
-			"X-Service-Type": {username + "-dc-config-update"},
-		},
-		StripPath: false,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: configUpdateSvcName},
-		Tags: []string{username},
-	}
-	configUpdateRoute, err := m.Create(configUpdateRouteCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, configUpdateRoute)
 
-	// 创建认证插件，要求外部的grpc请求在query或者header中添加注册时得到的X-Api-Key
-	pluginCreateOption := &kong.KeyAuthPluginCreateOption{
-		Enabled: true,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: service.Name},
-		Config: &kong.KeyAuthPluginConfig{
-			KeyNames:    []string{"X-Api-Key"},
-			KeyInQuery:  true,
-			KeyInHeader: true,
-			KeyInBody:   false,
+	return &KongConfig{
+		Services: []KongEntity{
+			{
+				"name":     service.Name,
+				"protocol": "grpc",
+				// 单集群部署时即k8s中的服务名，多集群部署时为服务在其所在集群外可访问的地址
+				"host":            host,
+				"port":            grpcPort,
+				"write_timeout":   600000,
+				"read_timeout":    600000,
+				"connect_timeout": 600000,
+			},
+			{
+				"name":     configUpdateSvcName,
+				"protocol": "http",
+				"host":     host,
+				"port":     httpPort,
+				"path":     "/",
+			},
 		},
-	}
-	plugin, err := m.Create(pluginCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, plugin)
-
-	pluginCreateOption2 := &kong.KeyAuthPluginCreateOption{
-		Enabled: true,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: configUpdateSvcName},
-		Config: &kong.KeyAuthPluginConfig{
-			KeyNames:    []string{"X-Api-Key"},
-			KeyInQuery:  true,
-			KeyInHeader: true,
-			KeyInBody:   false,
+		// 路由匹配条件包括host请求头和X-Service-Type:<用户名>-dc
+		Routes: []KongEntity{
+			{
+				"name":       service.Name,
+				"protocols":  []string{"grpc"},
+				"hosts":      []string{m.AppDomainName},
+				"paths":      []string{"/"},
+				"headers":    map[string][]string{"X-Service-Type": {username + "-dc"}},
+				"strip_path": false,
+				"service":    map[string]interface{}{"name": service.Name},
+			},
+			{
+				"name":       configUpdateSvcName,
+				"protocols":  []string{"http"},
+				"methods":    []string{http.MethodPost},
+				"hosts":      []string{m.AppDomainName},
+				"paths":      []string{"/"},
+				"headers":    map[string][]string{"X-Service-Type": {username + "-dc-config-update"}},
+				"strip_path": false,
+				"service":    map[string]interface{}{"name": configUpdateSvcName},
+			},
 		},
+		// 要求外部的请求在query或者header中添加注册时得到的X-Api-Key
+		Plugins: []KongEntity{keyAuthPlugin(service.Name), keyAuthPlugin(configUpdateSvcName)},
 	}
-	_, err = m.Create(pluginCreateOption2)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// CreateDpServiceRoute 为数据处理服务的service组件创建外部路由
-func (m *Manager) CreateDpServiceRoute(username string, service *corev1.Service) error {
+// CreateDpServiceRoute 为数据处理服务的service组件创建外部路由，host为网关访问该service时使用的地址，
+// 重复调用时使kong中的对象与期望的状态一致
+func (m *Manager) CreateDpServiceRoute(username string, service *corev1.Service, host string) (err error) {
 	if service == nil {
 		return errors.New(500, "service is nil", "")
 	}
+	defer func() {
+		audit.Record(m.Recorder, "gateway.create_dp_routes", username, []string{
+			"services/" + service.Name, "routes/" + service.Name,
+			"routes/" + service.Name + "-warning-push", "plugins/key-auth@" + service.Name,
+		}, err)
+	}()
 
-	// 为数据处理服务的http连接创建路由
+	return m.SyncConfig(username, service.Name, m.DpServiceConfig(username, service, host))
+}
+
+// DpServiceConfig 数据处理服务在kong中应有的对象，包括service、基于请求头以及基于path的路由和认证插件
+func (m *Manager) DpServiceConfig(username string, service *corev1.Service, host string) *KongConfig {
 	// 查询service提供http服务的端口，默认为8000
 	var port int32 = 8000
 	for _, p := range service.Spec.Ports {
@@ -215,108 +184,57 @@ func (m *Manager) CreateDpServiceRoute(username string, service *corev1.Service)
 		}
 	}
 
-	var (
-		objects []kong.Object
-		err     error
-	)
-	defer func() {
-		if err != nil {
-			for _, o := range objects {
-				m.Delete(o)
-			}
-		}
-	}()
-
-	// 配置kong service组件的创建选项，需要附上用户名的tag方便后续用户注销
-	serviceCreateOption := &kong.ServiceCreateOption{
-		Name:     service.Name,
-		Protocol: "http",
-		// k8s中服务名即相应的域名
-		Host:         service.Name,
-		Port:         int(port),
-		Path:         "/",
-		WriteTimeout: 600000,
-		ReadTimeout:  600000,
-		Enabled:      true,
-		Tags:         []string{username},
-	}
-	svc, err := m.Create(serviceCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, svc)
-
-	// 创建路由，路由匹配条件包括host请求头和X-Service-Type:<用户名>-dp，
-	// tag部分需要附上用户名，方便后续用户注销
-	routeCreateOption := &kong.RouteCreateOption{
-		Name:      service.Name,
-		Protocols: []string{"http"},
-		Methods:   []string{http.MethodGet, http.MethodPut, http.MethodDelete},
-		Hosts:     []string{m.AppDomainName},
-		Paths:     []string{"/"},
-		Headers: map[string][]string{
-			"X-Service-Type": {username + "-dp"},
-		},
-		StripPath: false,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: service.Name},
-		Tags: []string{username},
-	}
-	route, err := m.Create(routeCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, route)
-
-	// 由于浏览器发起ws连接时无法添加请求头，
-	// 因此需要为建立预警推送ws连接的服务额外增加一个基于path匹配的路由
-	wsRouteCreateOption := &kong.RouteCreateOption{
-		Name:      service.Name + "-warning-push",
-		Protocols: []string{"http"},
-		Methods:   []string{http.MethodGet},
-		Hosts:     []string{m.AppDomainName},
-		Paths:     []string{"/warnings/push/" + strings.Replace(username, "_", "-", -1)},
-		StripPath: false,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: service.Name},
-		Tags: []string{username},
-	}
-	wsRoute, err := m.Create(wsRouteCreateOption)
-	if err != nil {
-		return err
-	}
-	objects = append(objects, wsRoute)
-
-	// 创建认证插件，要求外部的http请求在query或者header中添加注册时得到的X-Api-Key
-	pluginCreateOption := &kong.KeyAuthPluginCreateOption{
-		Enabled: true,
-		Service: &struct {
-			Name string `json:"name,omitempty"`
-			Id   string `json:"id,omitempty"`
-		}{Name: service.Name},
-		Config: &kong.KeyAuthPluginConfig{
-			KeyNames:    []string{"X-Api-Key"},
-			KeyInQuery:  true,
-			KeyInHeader: true,
-			KeyInBody:   false,
+	return &KongConfig{
+		Services: []KongEntity{{
+			"name":     service.Name,
+			"protocol": "http",
+			// 单集群部署时即k8s中的服务名，多集群部署时为服务在其所在集群外可访问的地址
+			"host":          host,
+			"port":          port,
+			"path":          "/",
+			"write_timeout": 600000,
+			"read_timeout":  600000,
+		}},
+		Routes: []KongEntity{
+			// 路由匹配条件包括host请求头和X-Service-Type:<用户名>-dp
+			{
+				"name":       service.Name,
+				"protocols":  []string{"http"},
+				"methods":    []string{http.MethodGet, http.MethodPut, http.MethodDelete},
+				"hosts":      []string{m.AppDomainName},
+				"paths":      []string{"/"},
+				"headers":    map[string][]string{"X-Service-Type": {username + "-dp"}},
+				"strip_path": false,
+				"service":    map[string]interface{}{"name": service.Name},
+			},
+			// 由于浏览器发起ws连接时无法添加请求头，
+			// 因此需要为建立预警推送ws连接的服务额外增加一个基于path匹配的路由
+			{
+				"name":       service.Name + "-warning-push",
+				"protocols":  []string{"http"},
+				"methods":    []string{http.MethodGet},
+				"hosts":      []string{m.AppDomainName},
+				"paths":      []string{"/warnings/push/" + strings.Replace(username, "_", "-", -1)},
+				"strip_path": false,
+				"service":    map[string]interface{}{"name": service.Name},
+			},
 		},
+		Plugins: []KongEntity{keyAuthPlugin(service.Name)},
 	}
-	_, err = m.Create(pluginCreateOption)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// GetUsernameOfToken 获得与token相关的用户名
+// GetUsernameOfToken 获得与token相关的用户名，token属于组织的成员时返回成员所属组织的用户名
 func (m *Manager) GetUsernameOfToken(token string) (string, error) {
+	_, org, err := m.GetConsumerOfToken(token)
+	return org, err
+}
+
+// GetConsumerOfToken 获得与token相关的consumer的用户名以及其所属组织的用户名，
+// 组织成员的consumer以custom_id记录所属的组织，组织的owner即注册的用户本身
+func (m *Manager) GetConsumerOfToken(token string) (username, org string, err error) {
 	result := &struct {
 		Username string `json:"username"`
+		CustomId string `json:"custom_id"`
 	}{}
 
 	response, err := m.Client.R().
@@ -324,17 +242,20 @@ func (m *Manager) GetUsernameOfToken(token string) (string, error) {
 		SetResult(result).
 		Get("/key-auths/{token}/consumer")
 	if err != nil {
-		return "", errors.Newf(500, "获得token相关的用户名时发生了错误: %s", err.Error())
+		return "", "", errors.Newf(500, "获得token相关的用户名时发生了错误: %s", err.Error())
 	}
 	if response.IsError() {
-		return "", errors.Newf(
+		return "", "", errors.Newf(
 			500, "获得token相关的用户名时发生了错误: %s", response.String())
 	}
 
 	if result.Username == "" {
-		return "", errors.Newf(
+		return "", "", errors.Newf(
 			400, "与该token相关的用户不存在: %s", token)
 	}
 
-	return result.Username, nil
+	if result.CustomId != "" {
+		return result.Username, result.CustomId, nil
+	}
+	return result.Username, result.Username, nil
 }
@@ -0,0 +1,447 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"github.com/go-kratos/kratos/v2/errors"
+	corev1 "k8s.io/api/core/v1"
+	"net/http"
+	"strings"
+)
+
+const (
+	// GatewayAPIType 配置中Gateway API实现的名称
+	GatewayAPIType    = "gateway-api"
+	gatewayAPIGroup   = "gateway.networking.k8s.io"
+	gatewayAPIVersion = gatewayAPIGroup + "/v1"
+
+	// 网关中所有对象所属租户的label，用户注销时依据该label清理
+	tenantLabel = "service-center/tenant"
+	// 暂停路由时在路由的注解中记录原有的backendRefs
+	suspendedBackendsAnnotation = "service-center/suspended-backend-refs"
+)
+
+// ObjectStore 网关对象所在的集群，对象以json的形式读写，由kubecontroller实现
+type ObjectStore interface {
+	// ApplyObject 创建或更新对象，对象中未包含的字段将被删除
+	ApplyObject(object map[string]interface{}) error
+	// GetObject 查询指定的对象，对象不存在时返回nil
+	GetObject(kind, name string) (map[string]interface{}, error)
+	// ListObjects 依据label查询指定类型的所有对象
+	ListObjects(kind, labelSelector string) ([]map[string]interface{}, error)
+	// DeleteObject 删除指定的对象，对象不存在时视为删除成功
+	DeleteObject(kind, name string) error
+}
+
+// GatewayAPIOption Gateway API实现的配置
+type GatewayAPIOption struct {
+	// 路由挂载的Gateway对象
+	GatewayName      string
+	GatewayNamespace string
+	// 路由、认证对象以及保存api密钥的secret所在的命名空间，即用户服务所在的命名空间
+	Namespace     string
+	AppDomainName string
+}
+
+// GatewayAPI 以Kubernetes Gateway API的HTTPRoute与GRPCRoute发布用户的服务，
+// consumer与api密钥保存在secret中，路由的认证由Authenticator完成，适用于未部署kong的集群
+type GatewayAPI struct {
+	store         ObjectStore
+	authenticator Authenticator
+	option        GatewayAPIOption
+	// 记录对网关的每次修改，为nil时不记录
+	Recorder audit.Recorder
+}
+
+func NewGatewayAPI(store ObjectStore, authenticator Authenticator, option GatewayAPIOption) (*GatewayAPI, error) {
+	if store == nil || authenticator == nil {
+		return nil, fmt.Errorf("Gateway API的实现缺少对象存储或认证方式")
+	}
+	if option.GatewayName == "" {
+		return nil, fmt.Errorf("Gateway API的实现缺少路由挂载的Gateway")
+	}
+	if option.GatewayNamespace == "" {
+		option.GatewayNamespace = option.Namespace
+	}
+	return &GatewayAPI{store: store, authenticator: authenticator, option: option}, nil
+}
+
+var _ Provider = (*GatewayAPI)(nil)
+
+// 用户的一条路由，groups为允许访问该路由的分组
+type gatewayRoute struct {
+	RouteRef
+	groups []string
+}
+
+// 用户应有的所有路由，与kong中的路由同名
+func tenantRoutes(username string) []gatewayRoute {
+	dc, dp := username+"-dc", username+"-dp"
+	return []gatewayRoute{
+		{RouteRef{"GRPCRoute", dc}, []string{OrgGroup(username)}},
+		{RouteRef{"HTTPRoute", dc + "-config-update"}, []string{OperatorGroup(username)}},
+		{RouteRef{"HTTPRoute", dp}, []string{OrgGroup(username)}},
+		{RouteRef{"HTTPRoute", dp + "-warning-push"}, []string{OrgGroup(username)}},
+	}
+}
+
+// CreateDcServiceRoute 为数据收集服务创建匹配X-Service-Type的grpc路由以及更新设备配置的http路由，
+// host为网关访问该service时使用的地址
+func (g *GatewayAPI) CreateDcServiceRoute(username string, service *corev1.Service, host string) (err error) {
+	if service == nil {
+		return errors.New(500, "service is nil", "")
+	}
+	routes := tenantRoutes(username)[:2]
+	defer func() {
+		audit.Record(g.Recorder, "gateway.create_dc_routes", username, routeTargets(routes), err)
+	}()
+
+	var grpcPort, httpPort int64 = 9000, 8000
+	for _, p := range service.Spec.Ports {
+		if p.Name == "grpc" {
+			grpcPort = int64(p.Port)
+		} else if p.Name == "http" {
+			httpPort = int64(p.Port)
+		}
+	}
+	grpcBackend, err := backendRef(service.Name, host, grpcPort)
+	if err != nil {
+		return err
+	}
+	httpBackend, err := backendRef(service.Name, host, httpPort)
+	if err != nil {
+		return err
+	}
+
+	grpcRule := map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{
+			"headers": []interface{}{serviceTypeHeader(username + "-dc")},
+		}},
+		"backendRefs": []interface{}{grpcBackend},
+	}
+	configUpdateRule := map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{
+			"path":    pathPrefix("/"),
+			"method":  http.MethodPost,
+			"headers": []interface{}{serviceTypeHeader(username + "-dc-config-update")},
+		}},
+		"backendRefs": []interface{}{httpBackend},
+	}
+
+	return g.applyRoutes(username, routes, [][]interface{}{{grpcRule}, {configUpdateRule}})
+}
+
+// CreateDpServiceRoute 为数据处理服务创建匹配X-Service-Type的http路由，以及浏览器建立预警推送WebSocket连接时
+// 使用的基于path匹配的路由，host为网关访问该service时使用的地址
+func (g *GatewayAPI) CreateDpServiceRoute(username string, service *corev1.Service, host string) (err error) {
+	if service == nil {
+		return errors.New(500, "service is nil", "")
+	}
+	routes := tenantRoutes(username)[2:]
+	defer func() {
+		audit.Record(g.Recorder, "gateway.create_dp_routes", username, routeTargets(routes), err)
+	}()
+
+	var port int64 = 8000
+	for _, p := range service.Spec.Ports {
+		if p.Name == "http" {
+			port = int64(p.Port)
+			break
+		}
+	}
+	backend, err := backendRef(service.Name, host, port)
+	if err != nil {
+		return err
+	}
+
+	// HTTPRouteMatch中只能指定一个method，因此每个method使用一个match
+	matches := make([]interface{}, 0, 3)
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		matches = append(matches, map[string]interface{}{
+			"path":    pathPrefix("/"),
+			"method":  method,
+			"headers": []interface{}{serviceTypeHeader(username + "-dp")},
+		})
+	}
+	dpRule := map[string]interface{}{"matches": matches, "backendRefs": []interface{}{backend}}
+	// 由于浏览器发起ws连接时无法添加请求头，因此基于path匹配
+	wsRule := map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{
+			"path":   pathPrefix("/warnings/push/" + strings.Replace(username, "_", "-", -1)),
+			"method": http.MethodGet,
+		}},
+		"backendRefs": []interface{}{backend},
+	}
+
+	return g.applyRoutes(username, routes, [][]interface{}{{dpRule}, {wsRule}})
+}
+
+// 创建路由以及路由的认证对象，任一对象创建失败时删除已创建的对象
+func (g *GatewayAPI) applyRoutes(username string, routes []gatewayRoute, rules [][]interface{}) (err error) {
+	var created []map[string]interface{}
+	defer func() {
+		if err != nil {
+			for _, o := range created {
+				g.store.DeleteObject(o["kind"].(string), nameOf(o))
+			}
+		}
+	}()
+
+	for i, r := range routes {
+		route := g.newObject(gatewayAPIVersion, r.Kind, r.Name, username)
+		route["spec"] = map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{
+				"name":      g.option.GatewayName,
+				"namespace": g.option.GatewayNamespace,
+			}},
+			"hostnames": []interface{}{g.option.AppDomainName},
+			"rules":     rules[i],
+		}
+		if err = g.store.ApplyObject(route); err != nil {
+			return err
+		}
+		created = append(created, route)
+
+		for _, policy := range g.policies(username, r) {
+			if err = g.store.ApplyObject(policy); err != nil {
+				return err
+			}
+			created = append(created, policy)
+		}
+	}
+	return nil
+}
+
+// 路由的认证对象，附上命名空间以及租户的label
+func (g *GatewayAPI) policies(username string, route gatewayRoute) []map[string]interface{} {
+	credentials := make([]string, len(route.groups))
+	for i, group := range route.groups {
+		credentials[i] = credentialSecretName(group)
+	}
+
+	policies := g.authenticator.Policies(route.RouteRef, credentials)
+	for _, p := range policies {
+		metadata, _ := p["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+			p["metadata"] = metadata
+		}
+		metadata["namespace"] = g.option.Namespace
+		metadata["labels"] = map[string]interface{}{tenantLabel: username}
+	}
+	return policies
+}
+
+// SuspendRoutes 清空用户所有路由的backendRefs，使其不再转发请求，原有的backendRefs记录在路由的注解中
+func (g *GatewayAPI) SuspendRoutes(username string) (err error) {
+	routes := tenantRoutes(username)
+	defer func() {
+		audit.Record(g.Recorder, "gateway.suspend_routes", username, routeTargets(routes), err)
+	}()
+
+	return g.updateRoutes(routes, func(annotations map[string]interface{}, rules []interface{}) (bool, error) {
+		if _, ok := annotations[suspendedBackendsAnnotation]; ok {
+			return false, nil
+		}
+		backends := make([]interface{}, len(rules))
+		for i, r := range rules {
+			rule, _ := r.(map[string]interface{})
+			backends[i] = rule["backendRefs"]
+			rule["backendRefs"] = []interface{}{}
+		}
+		data, err := json.Marshal(backends)
+		if err != nil {
+			return false, err
+		}
+		annotations[suspendedBackendsAnnotation] = string(data)
+		return true, nil
+	})
+}
+
+// ResumeRoutes 依据路由注解中记录的backendRefs恢复用户的路由
+func (g *GatewayAPI) ResumeRoutes(username string) (err error) {
+	routes := tenantRoutes(username)
+	defer func() {
+		audit.Record(g.Recorder, "gateway.resume_routes", username, routeTargets(routes), err)
+	}()
+
+	return g.updateRoutes(routes, func(annotations map[string]interface{}, rules []interface{}) (bool, error) {
+		data, ok := annotations[suspendedBackendsAnnotation].(string)
+		if !ok {
+			return false, nil
+		}
+		var backends []interface{}
+		if err := json.Unmarshal([]byte(data), &backends); err != nil {
+			return false, fmt.Errorf("解析路由暂停前的backendRefs时发生了错误: %w", err)
+		}
+		for i, r := range rules {
+			if rule, ok := r.(map[string]interface{}); ok && i < len(backends) {
+				rule["backendRefs"] = backends[i]
+			}
+		}
+		delete(annotations, suspendedBackendsAnnotation)
+		return true, nil
+	})
+}
+
+// 依次修改用户已存在的路由的注解与规则，update返回false时不更新该路由
+func (g *GatewayAPI) updateRoutes(routes []gatewayRoute,
+	update func(annotations map[string]interface{}, rules []interface{}) (bool, error)) error {
+	for _, r := range routes {
+		route, err := g.store.GetObject(r.Kind, r.Name)
+		if err != nil {
+			return err
+		}
+		if route == nil {
+			continue
+		}
+		route = applyable(route)
+
+		metadata := route["metadata"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if annotations == nil {
+			annotations = make(map[string]interface{})
+		}
+		spec, _ := route["spec"].(map[string]interface{})
+		rules, _ := spec["rules"].([]interface{})
+
+		changed, err := update(annotations, rules)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		metadata["annotations"] = annotations
+		if err := g.store.ApplyObject(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatus 检查用户服务在网关中应有的路由以及路由的认证对象是否存在
+func (g *GatewayAPI) GetStatus(username string) ([]ObjectStatus, error) {
+	var status []ObjectStatus
+	for _, r := range tenantRoutes(username) {
+		route, err := g.store.GetObject(r.Kind, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		status = append(status, ObjectStatus{Kind: "route", Name: r.Name, Present: route != nil})
+
+		for _, p := range g.policies(username, r) {
+			kind, name := p["kind"].(string), nameOf(p)
+			policy, err := g.store.GetObject(kind, name)
+			if err != nil {
+				return nil, err
+			}
+			status = append(status, ObjectStatus{
+				Kind: "policy", Name: fmt.Sprintf("%s/%s", kind, name), Present: policy != nil})
+		}
+	}
+	return status, nil
+}
+
+// Unregister 删除用户的路由、认证对象以及用户与组织成员的consumer
+func (g *GatewayAPI) Unregister(username string) (err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.unregister", username, []string{
+			"HTTPRoute?" + tenantLabel + "=" + username, "GRPCRoute?" + tenantLabel + "=" + username,
+			"Secret?" + tenantLabel + "=" + username,
+		}, err)
+	}()
+
+	for _, r := range tenantRoutes(username) {
+		for _, p := range g.policies(username, r) {
+			if err := g.store.DeleteObject(p["kind"].(string), nameOf(p)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, kind := range []string{"HTTPRoute", "GRPCRoute", "Secret"} {
+		objects, err := g.store.ListObjects(kind, tenantLabel+"="+username)
+		if err != nil {
+			return err
+		}
+		for _, o := range objects {
+			if err := g.store.DeleteObject(kind, nameOf(o)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 创建对象的基本结构，附上命名空间以及租户的label
+func (g *GatewayAPI) newObject(apiVersion, kind, name, tenant string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": g.option.Namespace,
+			"labels":    map[string]interface{}{tenantLabel: tenant},
+		},
+	}
+}
+
+// 只保留对象中由service center管理的字段，使查询得到的对象可以重新apply
+func applyable(object map[string]interface{}) map[string]interface{} {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	result := map[string]interface{}{
+		"apiVersion": object["apiVersion"],
+		"kind":       object["kind"],
+		"metadata": map[string]interface{}{
+			"name":        metadata["name"],
+			"namespace":   metadata["namespace"],
+			"labels":      metadata["labels"],
+			"annotations": metadata["annotations"],
+		},
+	}
+	for _, field := range []string{"spec", "type", "data"} {
+		if v, ok := object[field]; ok {
+			result[field] = v
+		}
+	}
+	return result
+}
+
+func nameOf(object map[string]interface{}) string {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
+
+// 辅助函数，创建指向用户服务的backendRef，host为service名或者<service名>.<命名空间>开头的集群内地址，
+// 路由无法转发到集群外的地址，因此不支持多集群部署
+func backendRef(service, host string, port int64) (map[string]interface{}, error) {
+	parts := strings.SplitN(host, ".", 3)
+	if parts[0] != service {
+		return nil, errors.Newf(400, "Gateway_Unsupported",
+			"Gateway API的路由只能转发到网关所在集群内的服务，无法访问%s", host)
+	}
+
+	backend := map[string]interface{}{"name": service, "port": port}
+	if len(parts) > 1 {
+		backend["namespace"] = parts[1]
+	}
+	return backend, nil
+}
+
+func serviceTypeHeader(value string) map[string]interface{} {
+	return map[string]interface{}{"type": "Exact", "name": "X-Service-Type", "value": value}
+}
+
+func pathPrefix(path string) map[string]interface{} {
+	return map[string]interface{}{"type": "PathPrefix", "value": path}
+}
+
+func routeTargets(routes []gatewayRoute) []string {
+	targets := make([]string, len(routes))
+	for i, r := range routes {
+		targets[i] = r.Kind + "/" + r.Name
+	}
+	return targets
+}
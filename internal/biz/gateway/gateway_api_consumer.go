@@ -0,0 +1,357 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"github.com/go-kratos/kratos/v2/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// 保存consumer的secret的label
+	consumerLabel = "service-center/gateway-consumer"
+	// consumer所在的分组以<前缀><分组名>为label
+	groupLabelPrefix = "group.service-center/"
+	// consumer的每个api密钥以<前缀><密钥sha256的前32位>为label，用于依据api密钥查询consumer
+	keyLabelPrefix = "key.service-center/"
+	// 保存consumer用户名的注解
+	consumerAnnotation = "service-center/consumer"
+)
+
+// 保存在secret中的consumer，org为consumer所属组织的用户名，注册的用户本身即其组织的owner
+type gatewayConsumer struct {
+	username string
+	org      string
+	groups   []string
+	keys     []string
+}
+
+// 保存consumer的secret名
+func consumerSecretName(username string) string {
+	return username + "-gateway-consumer"
+}
+
+// 保存分组中所有consumer的api密钥的secret名，路由的认证对象以该secret校验api密钥
+func credentialSecretName(group string) string {
+	return group + "-gateway-credentials"
+}
+
+// 分组的secret中api密钥的键，同一consumer的多个密钥以序号区分
+func credentialID(username string, index int) string {
+	if index == 0 {
+		return username
+	}
+	return username + "-" + strconv.Itoa(index)
+}
+
+func keyLabel(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return keyLabelPrefix + hex.EncodeToString(sum[:])[:32]
+}
+
+// 生成与kong的key-auth插件相同长度的api密钥
+func newAPIKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("生成api密钥时发生了错误: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// CreateConsumerAndKey 为用户创建consumer以及相应的api密钥，用户即其组织的owner，因此加入组织的所有分组
+func (g *GatewayAPI) CreateConsumerAndKey(username string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.create_consumer", username,
+			[]string{"Secret/" + consumerSecretName(username)}, err)
+	}()
+
+	return g.createConsumer(username, username, []string{OrgGroup(username), OperatorGroup(username)})
+}
+
+// CreateMemberConsumer 为组织的成员创建consumer以及相应的api密钥，consumer以组织为租户，随组织一同注销
+func (g *GatewayAPI) CreateMemberConsumer(org, member string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.create_member_consumer", org,
+			[]string{"Secret/" + consumerSecretName(member)}, err)
+	}()
+
+	return g.createConsumer(org, member, []string{OrgGroup(org)})
+}
+
+func (g *GatewayAPI) createConsumer(org, username string, groups []string) (string, error) {
+	existing, err := g.getConsumer(username)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return "", fmt.Errorf("consumer %s 已存在", username)
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return "", err
+	}
+	err = g.saveConsumer(&gatewayConsumer{username: username, org: org, groups: groups, keys: []string{key}})
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// DeleteConsumer 删除consumer，consumer的api密钥随之从其所在的分组中移除
+func (g *GatewayAPI) DeleteConsumer(username string) (err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.delete_consumer", "",
+			[]string{"Secret/" + consumerSecretName(username)}, err)
+	}()
+
+	consumer, err := g.getConsumer(username)
+	if err != nil || consumer == nil {
+		return err
+	}
+	if err := g.store.DeleteObject("Secret", consumerSecretName(username)); err != nil {
+		return err
+	}
+	return g.syncGroups(consumer.org, consumer.groups...)
+}
+
+// RotateKey 为用户创建新的api密钥，并删除用户原有的所有密钥，使其立即失效
+func (g *GatewayAPI) RotateKey(username string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.rotate_key", username,
+			[]string{"Secret/" + consumerSecretName(username)}, err)
+	}()
+
+	consumer, err := g.mustGetConsumer(username)
+	if err != nil {
+		return "", err
+	}
+	key, err := newAPIKey()
+	if err != nil {
+		return "", err
+	}
+	consumer.keys = []string{key}
+	if err := g.saveConsumer(consumer); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// GetUsernameOfToken 获得与token相关的用户名，token属于组织的成员时返回成员所属组织的用户名
+func (g *GatewayAPI) GetUsernameOfToken(token string) (string, error) {
+	_, org, err := g.GetConsumerOfToken(token)
+	return org, err
+}
+
+// GetConsumerOfToken 获得与token相关的consumer的用户名以及其所属组织的用户名
+func (g *GatewayAPI) GetConsumerOfToken(token string) (username, org string, err error) {
+	objects, err := g.store.ListObjects("Secret", consumerLabel+","+keyLabel(token))
+	if err != nil {
+		return "", "", errors.Newf(500, "获得token相关的用户名时发生了错误: %s", err.Error())
+	}
+	// label中只有密钥摘要的一部分，因此还需要比较密钥本身
+	for _, o := range objects {
+		consumer, err := parseConsumer(o)
+		if err != nil {
+			return "", "", errors.Newf(500, "获得token相关的用户名时发生了错误: %s", err.Error())
+		}
+		for _, key := range consumer.keys {
+			if key == token {
+				return consumer.username, consumer.org, nil
+			}
+		}
+	}
+	return "", "", errors.Newf(400, "与该token相关的用户不存在: %s", token)
+}
+
+// EnableOrgACL 路由创建时即只允许组织相应分组中的consumer访问，因此只需确保组织owner的consumer位于所有分组中
+func (g *GatewayAPI) EnableOrgACL(org string) (err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.enable_org_acl", org, []string{
+			"Secret/" + credentialSecretName(OrgGroup(org)),
+			"Secret/" + credentialSecretName(OperatorGroup(org)),
+		}, err)
+	}()
+
+	return g.setGroups(org, org, true)
+}
+
+// SetACLGroups 将consumer加入组织的分组，operate为true时同时加入operator分组，否则将其移出operator分组
+func (g *GatewayAPI) SetACLGroups(org, consumer string, operate bool) (err error) {
+	defer func() {
+		audit.Record(g.Recorder, "gateway.set_acl_groups", org, []string{
+			"Secret/" + consumerSecretName(consumer),
+			"Secret/" + credentialSecretName(OrgGroup(org)),
+			"Secret/" + credentialSecretName(OperatorGroup(org)),
+		}, err)
+	}()
+
+	return g.setGroups(org, consumer, operate)
+}
+
+func (g *GatewayAPI) setGroups(org, username string, operate bool) error {
+	consumer, err := g.mustGetConsumer(username)
+	if err != nil {
+		return err
+	}
+	previous := consumer.groups
+	consumer.groups = []string{OrgGroup(org)}
+	if operate {
+		consumer.groups = append(consumer.groups, OperatorGroup(org))
+	}
+	if err := g.saveConsumer(consumer); err != nil {
+		return err
+	}
+	// 同时更新consumer原先所在的分组，使其移出的分组不再包含其api密钥
+	return g.syncGroups(org, previous...)
+}
+
+// ExportConsumer 获得用户的consumer以及其所有的api密钥
+func (g *GatewayAPI) ExportConsumer(username string) (*ConsumerSnapshot, error) {
+	consumer, err := g.mustGetConsumer(username)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &ConsumerSnapshot{Username: username, Tags: []string{consumer.org}, Keys: consumer.keys}
+	if consumer.org != username {
+		snapshot.CustomId = consumer.org
+	}
+	return snapshot, nil
+}
+
+// ImportConsumer 依据备份重新创建用户的consumer，并以原有的值创建api密钥，使用户的设备无需更换密钥，
+// 返回第一个api密钥
+func (g *GatewayAPI) ImportConsumer(snapshot *ConsumerSnapshot) (apiKey string, err error) {
+	if snapshot == nil || snapshot.Username == "" || len(snapshot.Keys) == 0 {
+		return "", fmt.Errorf("consumer的备份中缺少用户名或api密钥")
+	}
+	username := snapshot.Username
+	defer func() {
+		audit.Record(g.Recorder, "gateway.import_consumer", username,
+			[]string{"Secret/" + consumerSecretName(username)}, err)
+	}()
+
+	consumer := &gatewayConsumer{username: username, org: username, keys: snapshot.Keys,
+		groups: []string{OrgGroup(username), OperatorGroup(username)}}
+	if snapshot.CustomId != "" {
+		consumer.org = snapshot.CustomId
+		consumer.groups = []string{OrgGroup(snapshot.CustomId)}
+	}
+	if err := g.saveConsumer(consumer); err != nil {
+		return "", err
+	}
+	return snapshot.Keys[0], nil
+}
+
+// 查询consumer，不存在时返回nil
+func (g *GatewayAPI) getConsumer(username string) (*gatewayConsumer, error) {
+	object, err := g.store.GetObject("Secret", consumerSecretName(username))
+	if err != nil || object == nil {
+		return nil, err
+	}
+	return parseConsumer(object)
+}
+
+func (g *GatewayAPI) mustGetConsumer(username string) (*gatewayConsumer, error) {
+	consumer, err := g.getConsumer(username)
+	if err != nil {
+		return nil, err
+	}
+	if consumer == nil {
+		return nil, fmt.Errorf("用户 %s 的consumer不存在", username)
+	}
+	return consumer, nil
+}
+
+// 保存consumer，并更新其所在分组的api密钥
+func (g *GatewayAPI) saveConsumer(consumer *gatewayConsumer) error {
+	secret := g.newObject("v1", "Secret", consumerSecretName(consumer.username), consumer.org)
+	metadata := secret["metadata"].(map[string]interface{})
+	labels := metadata["labels"].(map[string]interface{})
+	labels[consumerLabel] = "true"
+	for _, group := range consumer.groups {
+		labels[groupLabelPrefix+group] = "true"
+	}
+	data := make(map[string]interface{}, len(consumer.keys))
+	for i, key := range consumer.keys {
+		labels[keyLabel(key)] = "true"
+		data[credentialID(consumer.username, i)] = base64.StdEncoding.EncodeToString([]byte(key))
+	}
+	metadata["annotations"] = map[string]interface{}{consumerAnnotation: consumer.username}
+	secret["type"] = "Opaque"
+	secret["data"] = data
+
+	if err := g.store.ApplyObject(secret); err != nil {
+		return err
+	}
+	return g.syncGroups(consumer.org, consumer.groups...)
+}
+
+// 依据分组中所有的consumer重新生成分组的api密钥
+func (g *GatewayAPI) syncGroups(org string, groups ...string) error {
+	for _, group := range groups {
+		consumers, err := g.store.ListObjects("Secret", consumerLabel+","+groupLabelPrefix+group)
+		if err != nil {
+			return err
+		}
+
+		data := make(map[string]interface{})
+		for _, o := range consumers {
+			c, err := parseConsumer(o)
+			if err != nil {
+				return err
+			}
+			for i, key := range c.keys {
+				data[credentialID(c.username, i)] = base64.StdEncoding.EncodeToString([]byte(key))
+			}
+		}
+
+		secret := g.newObject("v1", "Secret", credentialSecretName(group), org)
+		secret["type"] = "Opaque"
+		secret["data"] = data
+		if err := g.store.ApplyObject(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 由secret解析consumer，api密钥按照其序号排列
+func parseConsumer(object map[string]interface{}) (*gatewayConsumer, error) {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+
+	consumer := new(gatewayConsumer)
+	consumer.username, _ = annotations[consumerAnnotation].(string)
+	consumer.org, _ = labels[tenantLabel].(string)
+	if consumer.username == "" || consumer.org == "" {
+		return nil, fmt.Errorf("secret %s 中缺少consumer的用户名或所属组织", nameOf(object))
+	}
+	for label := range labels {
+		if strings.HasPrefix(label, groupLabelPrefix) {
+			consumer.groups = append(consumer.groups, strings.TrimPrefix(label, groupLabelPrefix))
+		}
+	}
+	sort.Strings(consumer.groups)
+
+	data, _ := object["data"].(map[string]interface{})
+	for i := 0; ; i++ {
+		value, ok := data[credentialID(consumer.username, i)].(string)
+		if !ok {
+			break
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("解析consumer %s 的api密钥时发生了错误: %w", consumer.username, err)
+		}
+		consumer.keys = append(consumer.keys, string(key))
+	}
+	return consumer, nil
+}
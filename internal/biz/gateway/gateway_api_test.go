@@ -0,0 +1,268 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"testing"
+)
+
+// 内存中的ObjectStore，apply时整体替换对象，label选择器只支持k与k=v的形式
+type memoryObjectStore struct {
+	objects map[string]map[string]interface{}
+	// apply该类型的对象时失败，用于测试回滚
+	failKind string
+}
+
+func newMemoryObjectStore() *memoryObjectStore {
+	return &memoryObjectStore{objects: make(map[string]map[string]interface{})}
+}
+
+func (s *memoryObjectStore) ApplyObject(object map[string]interface{}) error {
+	kind := object["kind"].(string)
+	if kind == s.failKind {
+		return fmt.Errorf("apply %s failed", kind)
+	}
+	// 经由json复制对象，与api server的行为一致
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]interface{})
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	s.objects[kind+"/"+nameOf(object)] = stored
+	return nil
+}
+
+func (s *memoryObjectStore) GetObject(kind, name string) (map[string]interface{}, error) {
+	return s.objects[kind+"/"+name], nil
+}
+
+func (s *memoryObjectStore) ListObjects(kind, labelSelector string) ([]map[string]interface{}, error) {
+	var result []map[string]interface{}
+	for key, o := range s.objects {
+		if !strings.HasPrefix(key, kind+"/") {
+			continue
+		}
+		labels, _ := o["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+		matched := true
+		for _, term := range strings.Split(labelSelector, ",") {
+			kv := strings.SplitN(term, "=", 2)
+			value, ok := labels[kv[0]]
+			if !ok || (len(kv) == 2 && value != kv[1]) {
+				matched = false
+			}
+		}
+		if matched {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryObjectStore) DeleteObject(kind, name string) error {
+	delete(s.objects, kind+"/"+name)
+	return nil
+}
+
+func newTestGatewayAPI(t *testing.T) (*GatewayAPI, *memoryObjectStore) {
+	store := newMemoryObjectStore()
+	g, err := NewGatewayAPI(store, EnvoyAuthenticator{}, GatewayAPIOption{
+		GatewayName: "gateway", Namespace: "test", AppDomainName: "app.test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, store
+}
+
+// 分组的secret中的所有api密钥
+func credentialsOf(t *testing.T, store *memoryObjectStore, group string) map[string]interface{} {
+	secret, _ := store.GetObject("Secret", credentialSecretName(group))
+	if secret == nil {
+		t.Fatalf("分组 %s 的secret不存在", group)
+	}
+	data, _ := secret["data"].(map[string]interface{})
+	return data
+}
+
+func TestGatewayAPI_Consumers(t *testing.T) {
+	g, store := newTestGatewayAPI(t)
+
+	ownerKey, err := g.CreateConsumerAndKey("org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	memberKey, err := g.CreateMemberConsumer("org", "member")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	username, org, err := g.GetConsumerOfToken(memberKey)
+	if err != nil || username != "member" || org != "org" {
+		t.Fatalf("应返回成员及其所属的组织，实际为:%s %s %v", username, org, err)
+	}
+	if owner, err := g.GetUsernameOfToken(ownerKey); err != nil || owner != "org" {
+		t.Fatalf("应返回组织owner的用户名，实际为:%s %v", owner, err)
+	}
+	if _, err := g.GetUsernameOfToken("unknown"); err == nil {
+		t.Fatal("未知的token应返回错误")
+	}
+
+	if len(credentialsOf(t, store, OrgGroup("org"))) != 2 || len(credentialsOf(t, store, OperatorGroup("org"))) != 1 {
+		t.Fatal("成员应只位于组织的分组中")
+	}
+	if err := g.SetACLGroups("org", "member", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(credentialsOf(t, store, OperatorGroup("org"))) != 2 {
+		t.Fatal("成员应加入operator分组")
+	}
+	if err := g.SetACLGroups("org", "member", false); err != nil {
+		t.Fatal(err)
+	}
+	if len(credentialsOf(t, store, OperatorGroup("org"))) != 1 {
+		t.Fatal("成员应移出operator分组")
+	}
+
+	rotated, err := g.RotateKey("member")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GetUsernameOfToken(memberKey); err == nil {
+		t.Fatal("轮换后原有的api密钥应失效")
+	}
+	if org, err := g.GetUsernameOfToken(rotated); err != nil || org != "org" {
+		t.Fatalf("轮换后的api密钥应可用，实际为:%s %v", org, err)
+	}
+
+	if err := g.DeleteConsumer("member"); err != nil {
+		t.Fatal(err)
+	}
+	if data := credentialsOf(t, store, OrgGroup("org")); len(data) != 1 {
+		t.Fatalf("删除成员后组织的分组中只应包含owner的api密钥:%v", data)
+	}
+}
+
+func TestGatewayAPI_ExportImportConsumer(t *testing.T) {
+	g, store := newTestGatewayAPI(t)
+	key, err := g.CreateConsumerAndKey("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := g.ExportConsumer("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Unregister("user"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.objects) != 0 {
+		t.Fatalf("注销后不应遗留对象:%v", store.objects)
+	}
+
+	imported, err := g.ImportConsumer(snapshot)
+	if err != nil || imported != key {
+		t.Fatalf("应以原有的值恢复api密钥，实际为:%s %v", imported, err)
+	}
+	if org, err := g.GetUsernameOfToken(key); err != nil || org != "user" {
+		t.Fatalf("恢复后的api密钥应可用，实际为:%s %v", org, err)
+	}
+}
+
+func testService(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "grpc", Port: 9001}, {Name: "http", Port: 8001},
+		}},
+	}
+}
+
+func TestGatewayAPI_Routes(t *testing.T) {
+	g, store := newTestGatewayAPI(t)
+	if _, err := g.CreateConsumerAndKey("user"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateDcServiceRoute("user", testService("user-dc"), "user-dc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateDpServiceRoute("user", testService("user-dp"), "user-dp.test.svc.cluster.local"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := g.GetStatus("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 8 {
+		t.Fatalf("应包含4条路由以及其认证对象:%v", status)
+	}
+	for _, s := range status {
+		if !s.Present {
+			t.Fatalf("%s %s 应存在", s.Kind, s.Name)
+		}
+	}
+
+	policy, _ := store.GetObject("SecurityPolicy", "user-dc-config-update-api-key")
+	refs := policy["spec"].(map[string]interface{})["apiKeyAuth"].(map[string]interface{})["credentialRefs"].([]interface{})
+	if refs[0].(map[string]interface{})["name"] != credentialSecretName(OperatorGroup("user")) {
+		t.Fatalf("更新设备配置的路由应只允许operator分组访问:%v", refs)
+	}
+	route, _ := store.GetObject("HTTPRoute", "user-dp-warning-push")
+	backend := route["spec"].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})["backendRefs"].([]interface{})[0].(map[string]interface{})
+	if backend["namespace"] != "test" || backend["port"] != float64(8001) {
+		t.Fatalf("应依据host与service的端口转发:%v", backend)
+	}
+
+	if err := g.SuspendRoutes("user"); err != nil {
+		t.Fatal(err)
+	}
+	// 重复暂停不应覆盖记录的backendRefs
+	if err := g.SuspendRoutes("user"); err != nil {
+		t.Fatal(err)
+	}
+	route, _ = store.GetObject("GRPCRoute", "user-dc")
+	rule := route["spec"].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	if len(rule["backendRefs"].([]interface{})) != 0 {
+		t.Fatal("暂停后路由不应转发请求")
+	}
+	if err := g.ResumeRoutes("user"); err != nil {
+		t.Fatal(err)
+	}
+	route, _ = store.GetObject("GRPCRoute", "user-dc")
+	rule = route["spec"].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	if len(rule["backendRefs"].([]interface{})) != 1 {
+		t.Fatal("恢复后路由应重新转发请求")
+	}
+	if _, ok := route["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})[suspendedBackendsAnnotation]; ok {
+		t.Fatal("恢复后应删除记录backendRefs的注解")
+	}
+
+	if err := g.Unregister("user"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.objects) != 0 {
+		t.Fatalf("注销后不应遗留对象:%v", store.objects)
+	}
+}
+
+func TestGatewayAPI_RouteRollback(t *testing.T) {
+	g, store := newTestGatewayAPI(t)
+	store.failKind = "SecurityPolicy"
+	if err := g.CreateDpServiceRoute("user", testService("user-dp"), "user-dp"); err == nil {
+		t.Fatal("认证对象创建失败时应返回错误")
+	}
+	if len(store.objects) != 0 {
+		t.Fatalf("应删除已创建的路由:%v", store.objects)
+	}
+
+	store.failKind = ""
+	if err := g.CreateDcServiceRoute("user", testService("user-dc"), "10.0.0.1"); err == nil {
+		t.Fatal("无法转发到集群外的地址时应返回错误")
+	}
+}
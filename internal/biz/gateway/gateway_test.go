@@ -0,0 +1,230 @@
+package gateway
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/gateway/kongtest"
+	"github.com/go-kratos/kratos/v2/errors"
+	"net/http"
+	"testing"
+)
+
+func newTestManager(t *testing.T) (*Manager, *kongtest.Server) {
+	server := kongtest.NewServer()
+	t.Cleanup(server.Close)
+
+	m, err := NewManager(server.URL, "app.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, server
+}
+
+func TestManager_CreateConsumerAndKey(t *testing.T) {
+	m, server := newTestManager(t)
+
+	// 创建api密钥失败时应删除已创建的consumer，使注册可以重试
+	server.InjectError(http.MethodPost, "/consumers/a/key-auth", 0, http.StatusInternalServerError)
+	if _, err := m.CreateConsumerAndKey("a"); err == nil {
+		t.Fatal("创建api密钥失败时应返回错误")
+	}
+	if n := len(server.Entities("consumers")); n != 0 {
+		t.Fatalf("应删除已创建的consumer，实际仍有%d个", n)
+	}
+
+	key, err := m.CreateConsumerAndKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username, err := m.GetUsernameOfToken(key); err != nil || username != "a" {
+		t.Fatalf("应返回token对应的用户名，实际为:%s %v", username, err)
+	}
+}
+
+func TestManager_GetUsernameOfToken(t *testing.T) {
+	m, server := newTestManager(t)
+	if _, err := m.CreateConsumerAndKey("org"); err != nil {
+		t.Fatal(err)
+	}
+	memberKey, err := m.CreateMemberConsumer("org", "member")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	username, org, err := m.GetConsumerOfToken(memberKey)
+	if err != nil || username != "member" || org != "org" {
+		t.Fatalf("应返回成员及其所属的组织，实际为:%s %s %v", username, org, err)
+	}
+	if org, err := m.GetUsernameOfToken(memberKey); err != nil || org != "org" {
+		t.Fatalf("成员的token应返回组织的用户名，实际为:%s %v", org, err)
+	}
+
+	// kong对不存在的token返回404
+	if _, err := m.GetUsernameOfToken("unknown"); errors.Code(err) != 500 {
+		t.Fatalf("查询不存在的token时应返回错误:%v", err)
+	}
+	server.InjectError(http.MethodGet, "/key-auths/*", 0, http.StatusServiceUnavailable)
+	if _, err := m.GetUsernameOfToken(memberKey); err == nil {
+		t.Fatal("kong返回错误时应返回错误")
+	}
+}
+
+func TestManager_ImportConsumer(t *testing.T) {
+	m, server := newTestManager(t)
+	snapshot := &ConsumerSnapshot{Username: "a", Tags: []string{"a"}, Keys: []string{"k1", "k2"}}
+
+	// 恢复任一api密钥失败时应删除已创建的consumer
+	server.InjectError(http.MethodPost, "/consumers/a/key-auth", 1, http.StatusInternalServerError)
+	if _, err := m.ImportConsumer(snapshot); err == nil {
+		t.Fatal("恢复api密钥失败时应返回错误")
+	}
+	if n := len(server.Entities("consumers")); n != 0 {
+		t.Fatalf("应删除已创建的consumer，实际仍有%d个", n)
+	}
+	if n := len(server.Entities("keyauth_credentials")); n != 0 {
+		t.Fatalf("应删除已恢复的api密钥，实际仍有%d个", n)
+	}
+
+	if key, err := m.ImportConsumer(snapshot); err != nil || key != "k1" {
+		t.Fatalf("应返回第一个api密钥，实际为:%s %v", key, err)
+	}
+	exported, err := m.ExportConsumer("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Keys) != 2 || exported.Keys[1] != "k2" {
+		t.Fatalf("导出的api密钥错误:%v", exported.Keys)
+	}
+}
+
+func TestManager_RotateKey(t *testing.T) {
+	m, server := newTestManager(t)
+	old, err := m.CreateConsumerAndKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 创建新密钥失败时原有的密钥仍然可用
+	server.InjectError(http.MethodPost, "/consumers/a/key-auth", 0, http.StatusInternalServerError)
+	if _, err := m.RotateKey("a"); err == nil {
+		t.Fatal("创建新密钥失败时应返回错误")
+	}
+	if _, err := m.GetUsernameOfToken(old); err != nil {
+		t.Fatalf("轮换失败时原有的密钥应可用:%v", err)
+	}
+
+	key, err := m.RotateKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetUsernameOfToken(old); err == nil {
+		t.Fatal("轮换后原有的密钥应失效")
+	}
+	if username, err := m.GetUsernameOfToken(key); err != nil || username != "a" {
+		t.Fatalf("轮换后的密钥应可用，实际为:%s %v", username, err)
+	}
+}
+
+func TestManager_SyncConfig(t *testing.T) {
+	m, server := newTestManager(t)
+	dc, dp := testService("a-dc"), testService("a-dp")
+
+	// 创建第二个key-auth插件失败后重新同步，应收敛到完整的状态且不重复创建对象
+	server.InjectError(http.MethodPost, "/plugins", 1, http.StatusInternalServerError)
+	if err := m.CreateDcServiceRoute("a", dc, "a-dc"); err == nil {
+		t.Fatal("创建插件失败时应返回错误")
+	}
+	if err := m.CreateDcServiceRoute("a", dc, "a-dc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateDcServiceRoute("a", dc, "a-dc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateDpServiceRoute("a", dp, "a-dp"); err != nil {
+		t.Fatal(err)
+	}
+	for collection, n := range map[string]int{"services": 3, "routes": 4, "plugins": 3} {
+		if entities := server.Entities(collection, "a"); len(entities) != n {
+			t.Fatalf("%s 的数量应为%d，实际为:%v", collection, n, entities)
+		}
+	}
+	status, err := m.GetStatus("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if !s.Present {
+			t.Fatalf("%s %s 应存在", s.Kind, s.Name)
+		}
+	}
+
+	// 同步不应影响暂停状态以及其他scope的对象，如acl插件
+	if _, err := m.CreateConsumerAndKey("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.EnableOrgACL("a"); err != nil {
+		t.Fatal(err)
+	}
+	// 为已有的用户补充启用acl插件时可能重复启用
+	if err := m.EnableOrgACL("a"); err != nil {
+		t.Fatalf("重复启用acl插件不应失败:%v", err)
+	}
+	if err := m.SuspendRoutes("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateDcServiceRoute("a", dc, "a-dc.remote"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range server.Entities("services", "a-dc") {
+		if s["host"] != "a-dc.remote" || s["enabled"] != false {
+			t.Fatalf("应更新service的host并保留暂停状态:%v", s)
+		}
+	}
+	if n := len(server.Entities("plugins", "a")); n != 6 {
+		t.Fatalf("同步不应删除acl插件，实际插件数量为:%v", n)
+	}
+
+	// 删除配置中不存在的对象
+	config := m.DpServiceConfig("a", dp, "a-dp")
+	config.Routes = config.Routes[:1]
+	if err := m.SyncConfig("a", dp.Name, config); err != nil {
+		t.Fatal(err)
+	}
+	if routes := server.Entities("routes", "a-dp"); len(routes) != 1 {
+		t.Fatalf("应删除配置中不存在的路由:%v", routes)
+	}
+
+	if err := m.Unregister("a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, collection := range []string{"consumers", "services", "routes", "plugins"} {
+		if entities := server.Entities(collection, "a"); len(entities) != 0 {
+			t.Fatalf("注销后不应遗留 %s:%v", collection, entities)
+		}
+	}
+}
+
+func TestManager_Unregister(t *testing.T) {
+	m, server := newTestManager(t)
+	if _, err := m.CreateConsumerAndKey("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateDcServiceRoute("a", testService("a-dc"), "a-dc"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 删除失败时应返回错误，重试时只删除剩余的对象
+	server.InjectError(http.MethodDelete, "/routes/*", 0, http.StatusInternalServerError)
+	if err := m.Unregister("a"); err == nil {
+		t.Fatal("删除路由失败时应返回错误")
+	}
+	if n := len(server.Entities("routes", "a")); n == 0 {
+		t.Fatal("删除失败时不应继续删除service")
+	}
+	if err := m.Unregister("a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, collection := range []string{"consumers", "services", "routes", "plugins"} {
+		if entities := server.Entities(collection, "a"); len(entities) != 0 {
+			t.Fatalf("注销后不应遗留 %s:%v", collection, entities)
+		}
+	}
+}
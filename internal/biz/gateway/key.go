@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/util/kong"
+)
+
+// RotateKey 为用户创建新的api密钥，并删除用户原有的所有密钥，使其立即失效
+func (m *Manager) RotateKey(username string) (apiKey string, err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.rotate_key", username,
+			[]string{"consumers/" + username + "/key-auth"}, err)
+	}()
+
+	result := &struct {
+		Data []struct {
+			Id string `json:"id"`
+		} `json:"data"`
+	}{}
+
+	response, err := m.Client.R().
+		SetPathParam("username", username).
+		SetResult(result).
+		Get("/consumers/{username}/key-auth")
+	if err != nil {
+		return "", fmt.Errorf("查询用户 %s 的api密钥时发生了错误: %w", username, err)
+	}
+	if response.IsError() {
+		return "", fmt.Errorf("查询用户 %s 的api密钥时发生了错误: %s", username, response.String())
+	}
+
+	// 先创建新的密钥，避免删除旧密钥后创建失败导致用户无法访问服务
+	key, err := m.Create(&kong.KeyCreateOption{Username: username})
+	if err != nil {
+		return "", err
+	}
+
+	for _, k := range result.Data {
+		response, err := m.Client.R().
+			SetPathParams(map[string]string{"username": username, "id": k.Id}).
+			Delete("/consumers/{username}/key-auth/{id}")
+		if err != nil {
+			return "", fmt.Errorf("删除用户 %s 的旧api密钥时发生了错误: %w", username, err)
+		}
+		if response.IsError() {
+			return "", fmt.Errorf("删除用户 %s 的旧api密钥时发生了错误: %s", username, response.String())
+		}
+	}
+
+	return key.(*kong.Key).Key, nil
+}
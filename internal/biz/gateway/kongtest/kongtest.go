@@ -0,0 +1,463 @@
+// Package kongtest 提供在内存中模拟kong admin api的http服务器，用于在没有kong的环境中测试网关相关的代码
+package kongtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Entity kong中的一个实体
+type Entity map[string]interface{}
+
+// Server 模拟kong admin api中consumers、key-auth凭证、acl分组、services、routes、plugins、
+// 依据tag的查询以及/key-auths/{key}/consumer，实体之间的引用以{"id":<id>}的形式保存，
+// 与kong一致，删除仍被route引用的service时返回400
+type Server struct {
+	*httptest.Server
+	// Metrics /metrics返回的prometheus指标
+	Metrics string
+
+	mutex    sync.Mutex
+	seq      int
+	entities map[string][]Entity
+	failures []*failure
+}
+
+// 注入的错误
+type failure struct {
+	method string
+	path   string
+	// 跳过的匹配请求数量
+	skip   int
+	status int
+}
+
+const (
+	consumers = "consumers"
+	services  = "services"
+	routes    = "routes"
+	plugins   = "plugins"
+	keyAuths  = "keyauth_credentials"
+	acls      = "acls"
+)
+
+// NewServer 启动模拟kong admin api的服务器，使用完毕后需要调用Close
+func NewServer() *Server {
+	s := &Server{entities: make(map[string][]Entity)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// InjectError 使之后第skip+1个匹配method与path的请求返回status，该错误只注入一次，
+// method为空时匹配任意方法，path以*结尾时匹配前缀
+func (s *Server) InjectError(method, path string, skip, status int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures = append(s.failures, &failure{method: method, path: path, skip: skip, status: status})
+}
+
+// Entities 获得集合中包含所有给定tag的实体的副本，集合包括consumers、services、routes、plugins、
+// keyauth_credentials与acls
+func (s *Server) Entities(collection string, tags ...string) []Entity {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []Entity
+	for _, e := range s.entities[collection] {
+		if hasTags(e, tags) {
+			result = append(result, copyEntity(e))
+		}
+	}
+	return result
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if status, ok := s.injectedError(r.Method, r.URL.Path); ok {
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	body := make(Entity)
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+			return
+		}
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/":
+		writeJSON(w, http.StatusOK, Entity{"version": "kongtest"})
+	case r.URL.Path == "/metrics":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(s.Metrics))
+	case len(path) == 3 && path[0] == "key-auths" && path[2] == "consumer" && r.Method == http.MethodGet:
+		s.keyConsumer(w, path[1])
+	case len(path) >= 3 && path[0] == consumers && (path[2] == "key-auth" || path[2] == "acls"):
+		s.credentials(w, r.Method, path, body)
+	case len(path) == 3 && path[0] == services && path[2] == plugins && r.Method == http.MethodGet:
+		service := s.find(services, path[1])
+		if service == nil {
+			writeError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		s.list(w, plugins, func(e Entity) bool { return refID(e, "service") == service["id"] })
+	case len(path) == 1 && isCollection(path[0]):
+		switch r.Method {
+		case http.MethodGet:
+			var tags []string
+			if t := r.URL.Query().Get("tags"); t != "" {
+				tags = strings.Split(t, ",")
+			}
+			s.list(w, path[0], func(e Entity) bool { return hasTags(e, tags) })
+		case http.MethodPost:
+			s.create(w, path[0], body)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case len(path) == 2 && isCollection(path[0]):
+		switch r.Method {
+		case http.MethodGet:
+			if e := s.find(path[0], path[1]); e != nil {
+				writeJSON(w, http.StatusOK, e)
+			} else {
+				writeError(w, http.StatusNotFound, "Not found")
+			}
+		case http.MethodPatch:
+			s.update(w, path[0], path[1], body)
+		case http.MethodDelete:
+			s.delete(w, path[0], path[1])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// 查询是否需要为该请求返回注入的错误
+func (s *Server) injectedError(method, path string) (int, bool) {
+	for i, f := range s.failures {
+		if f.method != "" && f.method != method {
+			continue
+		}
+		if prefix := strings.TrimSuffix(f.path, "*"); prefix != f.path {
+			if !strings.HasPrefix(path, prefix) {
+				continue
+			}
+		} else if f.path != path {
+			continue
+		}
+		if f.skip > 0 {
+			f.skip--
+			continue
+		}
+		s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		return f.status, true
+	}
+	return 0, false
+}
+
+func (s *Server) list(w http.ResponseWriter, collection string, filter func(Entity) bool) {
+	data := make([]Entity, 0)
+	for _, e := range s.entities[collection] {
+		if filter(e) {
+			data = append(data, e)
+		}
+	}
+	writeJSON(w, http.StatusOK, Entity{"data": data, "next": nil})
+}
+
+func (s *Server) create(w http.ResponseWriter, collection string, body Entity) {
+	if status, message := s.validate(collection, body, nil); status != 0 {
+		writeError(w, status, message)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.insert(collection, body))
+}
+
+func (s *Server) update(w http.ResponseWriter, collection, idOrName string, body Entity) {
+	entity := s.find(collection, idOrName)
+	if entity == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	merged := copyEntity(entity)
+	for k, v := range body {
+		merged[k] = v
+	}
+	if status, message := s.validate(collection, merged, entity); status != 0 {
+		writeError(w, status, message)
+		return
+	}
+	for k, v := range merged {
+		entity[k] = v
+	}
+	writeJSON(w, http.StatusOK, entity)
+}
+
+func (s *Server) delete(w http.ResponseWriter, collection, idOrName string) {
+	entity := s.find(collection, idOrName)
+	if entity == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	id := entity["id"]
+	if collection == services {
+		for _, r := range s.entities[routes] {
+			if refID(r, "service") == id {
+				writeError(w, http.StatusBadRequest, "an existing 'routes' entity references this 'services' entity")
+				return
+			}
+		}
+	}
+
+	s.remove(collection, func(e Entity) bool { return e["id"] == id })
+	// 与kong一致，级联删除引用该实体的插件与凭证
+	field := strings.TrimSuffix(collection, "s")
+	s.remove(plugins, func(e Entity) bool { return refID(e, field) == id })
+	if collection == consumers {
+		s.remove(keyAuths, func(e Entity) bool { return refID(e, "consumer") == id })
+		s.remove(acls, func(e Entity) bool { return refID(e, "consumer") == id })
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 处理consumer的key-auth凭证与acl分组
+func (s *Server) credentials(w http.ResponseWriter, method string, path []string, body Entity) {
+	consumer := s.find(consumers, path[1])
+	if consumer == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	collection := keyAuths
+	if path[2] == "acls" {
+		collection = acls
+	}
+	ofConsumer := func(e Entity) bool { return refID(e, "consumer") == consumer["id"] }
+
+	switch {
+	case len(path) == 3 && method == http.MethodGet:
+		s.list(w, collection, ofConsumer)
+	case len(path) == 3 && method == http.MethodPost:
+		if collection == keyAuths {
+			if key, _ := body["key"].(string); key == "" {
+				body["key"] = randomKey()
+			}
+			for _, e := range s.entities[keyAuths] {
+				if e["key"] == body["key"] {
+					writeError(w, http.StatusConflict, "UNIQUE violation detected on key")
+					return
+				}
+			}
+		} else {
+			for _, e := range s.entities[acls] {
+				if ofConsumer(e) && e["group"] == body["group"] {
+					writeError(w, http.StatusConflict, "UNIQUE violation detected on group")
+					return
+				}
+			}
+		}
+		body["consumer"] = map[string]interface{}{"id": consumer["id"]}
+		writeJSON(w, http.StatusCreated, s.insert(collection, body))
+	case len(path) == 4 && method == http.MethodDelete:
+		// acl分组可以通过id或分组名删除
+		match := func(e Entity) bool {
+			return ofConsumer(e) && (e["id"] == path[3] || (collection == acls && e["group"] == path[3]))
+		}
+		if s.remove(collection, match) == 0 {
+			writeError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) keyConsumer(w http.ResponseWriter, key string) {
+	for _, e := range s.entities[keyAuths] {
+		if e["key"] == key {
+			if consumer := s.find(consumers, fmt.Sprint(refID(e, "consumer"))); consumer != nil {
+				writeJSON(w, http.StatusOK, consumer)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found")
+}
+
+// 校验实体，并将以名称对其他实体的引用替换为以id引用，返回错误的状态码以及信息，校验通过时状态码为0
+func (s *Server) validate(collection string, entity, existing Entity) (int, string) {
+	unique := func(field string) (int, string) {
+		value, ok := entity[field]
+		if !ok || value == nil {
+			return 0, ""
+		}
+		for _, e := range s.entities[collection] {
+			if e[field] == value && (existing == nil || e["id"] != existing["id"]) {
+				return http.StatusConflict, fmt.Sprintf("UNIQUE violation detected on '{%s=\"%v\"}'", field, value)
+			}
+		}
+		return 0, ""
+	}
+
+	switch collection {
+	case consumers:
+		if entity["username"] == nil && entity["custom_id"] == nil {
+			return http.StatusBadRequest, "at least one of these fields must be non-empty: 'custom_id', 'username'"
+		}
+		return unique("username")
+	case services:
+		if entity["host"] == nil {
+			return http.StatusBadRequest, "schema violation (host: required field missing)"
+		}
+		return unique("name")
+	case routes:
+		if status, message := s.resolve(entity, "service", services); status != 0 {
+			return status, message
+		}
+		return unique("name")
+	case plugins:
+		if entity["name"] == nil {
+			return http.StatusBadRequest, "schema violation (name: required field missing)"
+		}
+		for _, ref := range []struct{ field, collection string }{
+			{"service", services}, {"route", routes}, {"consumer", consumers},
+		} {
+			if status, message := s.resolve(entity, ref.field, ref.collection); status != 0 {
+				return status, message
+			}
+		}
+		if _, ok := entity["enabled"]; !ok {
+			entity["enabled"] = true
+		}
+		// 同一实体上的同名插件只能有一个
+		for _, e := range s.entities[plugins] {
+			if e["name"] == entity["name"] && refID(e, "service") == refID(entity, "service") &&
+				refID(e, "route") == refID(entity, "route") && refID(e, "consumer") == refID(entity, "consumer") &&
+				(existing == nil || e["id"] != existing["id"]) {
+				return http.StatusConflict, fmt.Sprintf("UNIQUE violation detected on plugin %v", entity["name"])
+			}
+		}
+	}
+	return 0, ""
+}
+
+// 将实体中以名称或id对其他实体的引用统一为{"id":<id>}，被引用的实体不存在时返回400
+func (s *Server) resolve(entity Entity, field, collection string) (int, string) {
+	ref, ok := entity[field].(map[string]interface{})
+	if !ok {
+		entity[field] = nil
+		return 0, ""
+	}
+	for _, key := range []string{"id", "name", "username"} {
+		if value, ok := ref[key].(string); ok && value != "" {
+			target := s.find(collection, value)
+			if target == nil {
+				return http.StatusBadRequest, fmt.Sprintf("the foreign key '{%s=\"%s\"}' does not reference an existing '%s' entity.", key, value, collection)
+			}
+			entity[field] = map[string]interface{}{"id": target["id"]}
+			return 0, ""
+		}
+	}
+	entity[field] = nil
+	return 0, ""
+}
+
+// 依据id或名称查询实体，consumer的名称即username
+func (s *Server) find(collection, idOrName string) Entity {
+	for _, e := range s.entities[collection] {
+		if e["id"] == idOrName || (collection != plugins && (e["name"] == idOrName || e["username"] == idOrName)) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *Server) insert(collection string, entity Entity) Entity {
+	s.seq++
+	entity["id"] = fmt.Sprintf("00000000-0000-4000-8000-%012d", s.seq)
+	if _, ok := entity["tags"]; !ok {
+		entity["tags"] = nil
+	}
+	s.entities[collection] = append(s.entities[collection], entity)
+	return entity
+}
+
+// 删除集合中满足条件的实体，返回删除的数量
+func (s *Server) remove(collection string, match func(Entity) bool) int {
+	kept := s.entities[collection][:0]
+	for _, e := range s.entities[collection] {
+		if !match(e) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(s.entities[collection]) - len(kept)
+	s.entities[collection] = kept
+	return removed
+}
+
+func isCollection(name string) bool {
+	return name == consumers || name == services || name == routes || name == plugins
+}
+
+// 获得实体中对其他实体引用的id
+func refID(entity Entity, field string) interface{} {
+	ref, _ := entity[field].(map[string]interface{})
+	return ref["id"]
+}
+
+// 判断实体是否包含所有的tag，与kong中以逗号分隔多个tag的查询一致
+func hasTags(entity Entity, tags []string) bool {
+	owned, _ := entity["tags"].([]interface{})
+	for _, tag := range tags {
+		found := false
+		for _, t := range owned {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// 经由json复制实体，避免测试修改服务器中的状态
+func copyEntity(entity Entity) Entity {
+	data, _ := json.Marshal(entity)
+	result := make(Entity)
+	json.Unmarshal(data, &result)
+	return result
+}
+
+// 生成与kong相同长度的api密钥
+func randomKey() string {
+	key := make([]byte, 16)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, Entity{"message": message})
+}
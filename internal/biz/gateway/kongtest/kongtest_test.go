@@ -0,0 +1,85 @@
+package kongtest
+
+import (
+	"gitee.com/moyusir/util/kong"
+	"net/http"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	admin, err := kong.NewAdmin(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := admin.Create(&kong.ConsumerCreateOption{Username: "a", Tags: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admin.Create(&kong.ConsumerCreateOption{Username: "a"}); err == nil {
+		t.Fatal("consumer的用户名应唯一")
+	}
+	key, err := admin.Create(&kong.KeyCreateOption{Username: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer := &struct {
+		Username string `json:"username"`
+	}{}
+	response, err := admin.Client.R().SetResult(consumer).Get("/key-auths/" + key.(*kong.Key).Key + "/consumer")
+	if err != nil || response.IsError() || consumer.Username != "a" {
+		t.Fatalf("应依据api密钥查询到consumer:%v %v", response, err)
+	}
+
+	if _, err := admin.Create(&kong.ServiceCreateOption{Name: "a-dp", Host: "a-dp", Tags: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	route := &kong.RouteCreateOption{Name: "a-dp", Paths: []string{"/"}, Tags: []string{"a"},
+		Service: &struct {
+			Name string `json:"name,omitempty"`
+			Id   string `json:"id,omitempty"`
+		}{Name: "a-dp"}}
+	if _, err := admin.Create(route); err != nil {
+		t.Fatal(err)
+	}
+	route.Name, route.Service.Name = "b-dp", "b-dp"
+	if _, err := admin.Create(route); err == nil {
+		t.Fatal("引用不存在的service时应返回错误")
+	}
+	if _, err := admin.Create(&kong.KeyAuthPluginCreateOption{Enabled: true, Tags: []string{"a"},
+		Service: &struct {
+			Name string `json:"name,omitempty"`
+			Id   string `json:"id,omitempty"`
+		}{Name: "a-dp"}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(server.Entities("plugins", "a")); n != 1 {
+		t.Fatalf("插件数量错误:%v", n)
+	}
+
+	// 注入的错误只影响匹配的请求，且只注入一次
+	server.InjectError(http.MethodPost, "/consumers", 1, http.StatusInternalServerError)
+	if _, err := admin.Create(&kong.ConsumerCreateOption{Username: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admin.Create(&kong.ConsumerCreateOption{Username: "c"}); err == nil {
+		t.Fatal("应返回注入的错误")
+	}
+	if _, err := admin.Create(&kong.ConsumerCreateOption{Username: "c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.Clear(kong.FLAG_SERVICE|kong.FLAG_ROUTE|kong.FLAG_PLUGIN|kong.FLAG_CONSUMER, "a")
+	for _, collection := range []string{"consumers", "services", "routes", "plugins", "keyauth_credentials"} {
+		if n := len(server.Entities(collection, "a")); n != 0 {
+			t.Fatalf("%s 中仍有 %d 个以a为tag的实体", collection, n)
+		}
+	}
+	if n := len(server.Entities("keyauth_credentials")); n != 0 {
+		t.Fatalf("删除consumer时应一并删除其api密钥:%v", n)
+	}
+	if n := len(server.Entities("consumers")); n != 2 {
+		t.Fatalf("不应删除其余的consumer:%v", n)
+	}
+}
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"bufio"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// kong prometheus插件中记录请求数量的指标，2.x版本为kong_http_status，3.x版本为kong_http_requests_total
+var requestMetrics = []string{"kong_http_status", "kong_http_requests_total"}
+
+// EnsurePrometheusPlugin 确保网关中启用了全局的prometheus插件，用于统计每个服务的请求数量
+func (m *Manager) EnsurePrometheusPlugin() (err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.ensure_prometheus_plugin", "", []string{"plugins/prometheus"}, err)
+	}()
+
+	response, err := m.Client.R().
+		SetBodyJsonMarshal(map[string]interface{}{"name": "prometheus", "enabled": true}).
+		Post("/plugins")
+	if err != nil {
+		return fmt.Errorf("创建prometheus插件时发生了错误: %w", err)
+	}
+	// 全局插件已经存在时，kong返回409
+	if response.IsError() && response.StatusCode != http.StatusConflict {
+		return fmt.Errorf("创建prometheus插件时发生了错误: %s", response.String())
+	}
+	return nil
+}
+
+// GetRequestCounts 从prometheus插件暴露的指标中获得各个kong service组件累计处理的请求数量
+func (m *Manager) GetRequestCounts() (map[string]int64, error) {
+	response, err := m.Client.R().Get("/metrics")
+	if err != nil {
+		return nil, fmt.Errorf("查询网关的指标时发生了错误: %w", err)
+	}
+	if response.IsError() {
+		return nil, fmt.Errorf("查询网关的指标时发生了错误: %s", response.String())
+	}
+
+	return parseRequestCounts(strings.NewReader(response.String()))
+}
+
+// 解析prometheus文本格式的指标，按service标签累加请求数量，
+// 指标的格式如: kong_http_requests_total{service="a-dp",route="a-dp",code="200",...} 10
+func parseRequestCounts(metrics io.Reader) (map[string]int64, error) {
+	counts := make(map[string]int64)
+
+	scanner := bufio.NewScanner(metrics)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var labels string
+		for _, name := range requestMetrics {
+			if strings.HasPrefix(line, name+"{") {
+				labels = line[len(name)+1:]
+				break
+			}
+		}
+		end := strings.LastIndex(labels, "}")
+		if end == -1 {
+			continue
+		}
+
+		service := ""
+		for _, l := range strings.Split(labels[:end], ",") {
+			if kv := strings.SplitN(l, "=", 2); len(kv) == 2 && kv[0] == "service" {
+				service = strings.Trim(kv[1], `"`)
+				break
+			}
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(labels[end+1:]), 64)
+		if service == "" || err != nil {
+			continue
+		}
+		counts[service] += int64(value)
+	}
+
+	return counts, scanner.Err()
+}
+
+// ServiceNames 获得为用户服务创建的所有kong service组件的名称
+func ServiceNames(username string) []string {
+	return []string{username + "-dc", username + "-dc-config-update", username + "-dp"}
+}
@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_parseRequestCounts(t *testing.T) {
+	metrics := `# HELP kong_http_requests_total HTTP status codes per consumer/service/route in Kong
+# TYPE kong_http_requests_total counter
+kong_http_requests_total{service="a-dp",route="a-dp",code="200",source="service",consumer=""} 10
+kong_http_requests_total{service="a-dp",route="a-dp-warning-push",code="101",source="service",consumer=""} 2
+kong_http_status{service="b-dc",route="b-dc",code="200"} 5
+kong_bandwidth_bytes{service="a-dp",route="a-dp",direction="egress",consumer=""} 4096
+`
+	counts, err := parseRequestCounts(strings.NewReader(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["a-dp"] != 12 {
+		t.Fatalf("应累加同一服务所有路由的请求数量，实际为:%v", counts["a-dp"])
+	}
+	if counts["b-dc"] != 5 {
+		t.Fatalf("应兼容2.x版本的指标名称，实际为:%v", counts["b-dc"])
+	}
+	if len(counts) != 2 {
+		t.Fatalf("不应统计其他指标:%v", counts)
+	}
+}
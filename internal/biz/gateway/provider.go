@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Provider 网关的实现，负责租户及其组织成员的consumer与api密钥、数据收集与数据处理服务的路由
+// 以及租户注销时的清理，默认使用kong，即Manager
+type Provider interface {
+	// CreateConsumerAndKey 为用户创建consumer以及相应的api密钥
+	CreateConsumerAndKey(username string) (apiKey string, err error)
+	// CreateMemberConsumer 为组织的成员创建consumer以及相应的api密钥，使其随组织一同注销
+	CreateMemberConsumer(org, member string) (apiKey string, err error)
+	// DeleteConsumer 删除consumer及其api密钥
+	DeleteConsumer(username string) error
+	// RotateKey 为用户创建新的api密钥，并使原有的密钥立即失效
+	RotateKey(username string) (apiKey string, err error)
+	// GetUsernameOfToken 获得与token相关的用户名，token属于组织的成员时返回成员所属组织的用户名
+	GetUsernameOfToken(token string) (string, error)
+	// GetConsumerOfToken 获得与token相关的consumer的用户名以及其所属组织的用户名
+	GetConsumerOfToken(token string) (username, org string, err error)
+	// EnableOrgACL 只允许组织相应分组中的consumer访问组织的服务
+	EnableOrgACL(org string) error
+	// SetACLGroups 将consumer加入组织的分组，operate为true时同时加入operator分组，否则将其移出operator分组
+	SetACLGroups(org, consumer string, operate bool) error
+	// ExportConsumer 获得用户的consumer以及其所有的api密钥
+	ExportConsumer(username string) (*ConsumerSnapshot, error)
+	// ImportConsumer 依据备份重新创建用户的consumer以及原有的api密钥，返回第一个api密钥
+	ImportConsumer(snapshot *ConsumerSnapshot) (apiKey string, err error)
+
+	// CreateDcServiceRoute 为数据收集服务创建grpc路由以及更新设备配置的http路由，host为网关访问该service时使用的地址
+	CreateDcServiceRoute(username string, service *corev1.Service, host string) error
+	// CreateDpServiceRoute 为数据处理服务创建http路由，以及浏览器建立预警推送WebSocket连接时使用的基于path匹配的路由
+	CreateDpServiceRoute(username string, service *corev1.Service, host string) error
+	// SuspendRoutes 使用户的路由不再转发请求，用户在网关中的其余组件保持不变
+	SuspendRoutes(username string) error
+	// ResumeRoutes 恢复用户的路由
+	ResumeRoutes(username string) error
+	// GetStatus 检查用户服务在网关中应有的路由以及认证组件是否存在
+	GetStatus(username string) ([]ObjectStatus, error)
+
+	// Unregister 清空用户在网关中相关的组件
+	Unregister(username string) error
+}
+
+// RequestCounter 可以统计每个服务累计处理的请求数量的网关，用于计量
+type RequestCounter interface {
+	// EnsurePrometheusPlugin 确保网关暴露了统计请求数量的指标
+	EnsurePrometheusPlugin() error
+	// GetRequestCounts 获得各个服务累计处理的请求数量，以服务名为键
+	GetRequestCounts() (map[string]int64, error)
+}
+
+var (
+	_ Provider       = (*Manager)(nil)
+	_ RequestCounter = (*Manager)(nil)
+)
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ObjectStatus 网关中单个对象的状态
+type ObjectStatus struct {
+	// 对象的类型，包括route与plugin
+	Kind string
+	// 对象的名称，插件以<插件名>@<服务名>的形式命名
+	Name string
+	// 对象是否存在于网关中
+	Present bool
+}
+
+// GetStatus 检查用户服务在网关中应有的路由以及认证插件是否存在
+func (m *Manager) GetStatus(username string) ([]ObjectStatus, error) {
+	dc, dp := username+"-dc", username+"-dp"
+	routes := []string{dc, dc + "-config-update", dp, dp + "-warning-push"}
+	// 每个kong service组件上都应当绑定key-auth认证插件
+	services := ServiceNames(username)
+
+	status := make([]ObjectStatus, 0, len(routes)+len(services))
+	for _, route := range routes {
+		present, err := m.routeExists(route)
+		if err != nil {
+			return nil, err
+		}
+		status = append(status, ObjectStatus{Kind: "route", Name: route, Present: present})
+	}
+	for _, service := range services {
+		present, err := m.pluginExists(service, "key-auth")
+		if err != nil {
+			return nil, err
+		}
+		status = append(status, ObjectStatus{
+			Kind: "plugin", Name: fmt.Sprintf("key-auth@%s", service), Present: present})
+	}
+
+	return status, nil
+}
+
+// 查询指定名称的路由是否存在
+func (m *Manager) routeExists(name string) (bool, error) {
+	response, err := m.Client.R().
+		SetPathParam("name", name).
+		Get("/routes/{name}")
+	if err != nil {
+		return false, fmt.Errorf("查询路由 %s 时发生了错误: %w", name, err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.IsError() {
+		return false, fmt.Errorf("查询路由 %s 时发生了错误: %s", name, response.String())
+	}
+	return true, nil
+}
+
+// 查询指定的kong service组件上是否绑定了给定名称的插件，service不存在时视为插件不存在
+func (m *Manager) pluginExists(service, plugin string) (bool, error) {
+	result := &struct {
+		Data []struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}{}
+
+	response, err := m.Client.R().
+		SetPathParam("service", service).
+		SetResult(result).
+		Get("/services/{service}/plugins")
+	if err != nil {
+		return false, fmt.Errorf("查询服务 %s 的插件时发生了错误: %w", service, err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.IsError() {
+		return false, fmt.Errorf("查询服务 %s 的插件时发生了错误: %s", service, response.String())
+	}
+
+	for _, p := range result.Data {
+		if p.Name == plugin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+)
+
+// SuspendRoutes 停用用户的所有kong service组件，使其路由不再转发请求，用户在网关中的其余组件保持不变
+func (m *Manager) SuspendRoutes(username string) (err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.suspend_routes", username, serviceTargets(username), err)
+	}()
+	return m.setServicesEnabled(username, false)
+}
+
+// ResumeRoutes 重新启用用户的所有kong service组件
+func (m *Manager) ResumeRoutes(username string) (err error) {
+	defer func() {
+		audit.Record(m.Recorder, "gateway.resume_routes", username, serviceTargets(username), err)
+	}()
+	return m.setServicesEnabled(username, true)
+}
+
+func (m *Manager) setServicesEnabled(username string, enabled bool) error {
+	for _, service := range ServiceNames(username) {
+		response, err := m.Client.R().
+			SetPathParam("service", service).
+			SetBodyJsonMarshal(map[string]interface{}{"enabled": enabled}).
+			Patch("/services/{service}")
+		if err != nil {
+			return fmt.Errorf("更新服务 %s 的启用状态时发生了错误: %w", service, err)
+		}
+		if response.IsError() {
+			return fmt.Errorf("更新服务 %s 的启用状态时发生了错误: %s", service, response.String())
+		}
+	}
+	return nil
+}
+
+func serviceTargets(username string) []string {
+	names := ServiceNames(username)
+	targets := make([]string, len(names))
+	for i, name := range names {
+		targets[i] = "services/" + name
+	}
+	return targets
+}
@@ -0,0 +1,22 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"testing"
+)
+
+func Test_newGatewayProvider(t *testing.T) {
+	for _, c := range []*conf.Server{
+		{},
+		{Gateway: &conf.Server_Gateway{Type: "traefik"}},
+		{Gateway: &conf.Server_Gateway{Type: gateway.GatewayAPIType}},
+		{Gateway: &conf.Server_Gateway{Type: gateway.GatewayAPIType,
+			GatewayApi: &conf.Server_Gateway_GatewayApi{GatewayName: "gateway", Authenticator: "oauth2"}}},
+	} {
+		if _, err := newGatewayProvider(c, nil); errors.Reason(err) != "Config_Error" {
+			t.Fatalf("无效的配置%v应返回配置错误:%v", c.Gateway, err)
+		}
+	}
+}
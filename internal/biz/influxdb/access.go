@@ -0,0 +1,30 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+)
+
+// CheckAccess 校验influxdb可以连接，且token可以查询org中的bucket、task以及authorization，
+// 用于在使用租户自带的influxdb之前尽早发现错误的配置，创建资源所需的写权限在创建时校验
+func (c *Client) CheckAccess(ctx context.Context) error {
+	if ok, err := c.Client.Ping(ctx); err != nil || !ok {
+		return fmt.Errorf("无法连接influxdb %s: %v", c.serverUrl, err)
+	}
+
+	_, err := c.Client.BucketsAPI().FindBucketsByOrgID(ctx, c.orgID, api.PagingWithLimit(1))
+	if err != nil {
+		return fmt.Errorf("token没有查询org %s 中bucket的权限: %w", c.org, err)
+	}
+	_, err = c.Client.TasksAPI().FindTasks(ctx, &api.TaskFilter{OrgID: c.orgID, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("token没有查询org %s 中task的权限: %w", c.org, err)
+	}
+	_, err = c.Client.AuthorizationsAPI().FindAuthorizationsByOrgID(ctx, c.orgID)
+	if err != nil {
+		return fmt.Errorf("token没有查询org %s 中authorization的权限: %w", c.org, err)
+	}
+
+	return nil
+}
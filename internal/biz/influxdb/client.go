@@ -3,60 +3,95 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	"github.com/influxdata/influxdb-client-go/v2/domain"
-	"time"
 )
 
+// Type 服务配置中influxdb对应的时序数据库类型
+const Type = "influxdb"
+
 type Client struct {
 	influxdb2.Client
-	org   string
-	orgID string
+	serverUrl string
+	org       string
+	orgID     string
+	// 记录对influxdb的每次修改，为nil时不记录
+	Recorder audit.Recorder
 }
 
 func NewInfluxdbClient(serverUrl, authToken, orgName string) (*Client, error) {
-	client := influxdb2.NewClient(serverUrl, authToken)
+	return NewInfluxdbClientWithOptions(serverUrl, authToken, orgName, influxdb2.DefaultOptions())
+}
+
+// NewInfluxdbClientWithOptions 以指定的选项创建客户端，如以限制了可访问地址的http客户端连接用户提供的influxdb
+func NewInfluxdbClientWithOptions(serverUrl, authToken, orgName string, options *influxdb2.Options) (*Client, error) {
+	client := influxdb2.NewClientWithOptions(serverUrl, authToken, options)
 	organization, err := client.OrganizationsAPI().FindOrganizationByName(
 		context.Background(), orgName)
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
 	return &Client{
-		Client: client,
-		org:    orgName,
-		orgID:  *organization.Id,
+		Client:    client,
+		serverUrl: serverUrl,
+		org:       orgName,
+		orgID:     *organization.Id,
 	}, nil
 }
 
-// CreateBucket 为用户创建保存设备状态信息、保存下采样数据、保存警告信息的三个bucket
-func (c *Client) CreateBucket(username string) error {
-	bucketsAPI := c.Client.BucketsAPI()
-	buckets := map[string]int64{
-		// 不同的桶保留的数据时长不同，下采样的数据是临时的，因此只保留一天
-		// 设备状态和警告信息的信息需要提供给前端查询，因此保留一个月
-		username: int64(720 * time.Hour.Seconds()),
-		fmt.Sprintf("%s-warning_detect", username): int64(24 * time.Hour.Seconds()),
-		fmt.Sprintf("%s-warnings", username):       int64(720 * time.Hour.Seconds()),
+// BucketNames 获得用户相关的bucket名，依次为保存设备状态信息、保存下采样数据、保存警告信息的bucket
+func BucketNames(username string) []string {
+	return []string{
+		username,
+		fmt.Sprintf("%s-warning_detect", username),
+		fmt.Sprintf("%s-warnings", username),
+	}
+}
+
+// Type 时序数据库的类型
+func (c *Client) Type() string {
+	return Type
+}
+
+// ConnectionEnv 用户服务连接influxdb所需的服务器地址、organization以及各个bucket名，以环境变量名为键
+func (c *Client) ConnectionEnv(username string) (map[string]string, error) {
+	names := BucketNames(username)
+	return map[string]string{
+		timeseries.BackendEnv:            Type,
+		"INFLUXDB_URL":                   c.serverUrl,
+		"INFLUXDB_ORG":                   c.org,
+		"INFLUXDB_BUCKET":                names[0],
+		"INFLUXDB_WARNING_DETECT_BUCKET": names[1],
+		"INFLUXDB_WARNINGS_BUCKET":       names[2],
+	}, nil
+}
+
+// CreateStorage 为用户创建保存设备状态信息、保存下采样数据、保存警告信息的三个bucket，
+// policy为nil时使用默认的保留策略
+func (c *Client) CreateStorage(username string, policy *timeseries.RetentionPolicy) error {
+	if policy == nil {
+		policy = timeseries.DefaultRetentionPolicy()
 	}
-	var shardGroupDurationSeconds int64 = 0
+	bucketsAPI := c.Client.BucketsAPI()
+	names := BucketNames(username)
+	rules := retentionRules(username, policy)
 
 	var err error
 	defer func() {
 		if err != nil {
-			c.ClearBucket(username)
+			c.ClearStorage(username)
 		}
+		audit.Record(c.Recorder, "influxdb.create_bucket", username, bucketTargets(names), err)
 	}()
 
-	for bucket, seconds := range buckets {
+	for _, bucket := range names {
 		_, err = bucketsAPI.CreateBucketWithNameWithID(
 			context.Background(),
 			c.orgID,
 			bucket,
-			domain.RetentionRule{
-				EverySeconds:              seconds,
-				ShardGroupDurationSeconds: &shardGroupDurationSeconds,
-				Type:                      "expire",
-			},
+			rules[bucket],
 		)
 		if err != nil {
 			return err
@@ -65,20 +100,38 @@ func (c *Client) CreateBucket(username string) error {
 	return nil
 }
 
-// ClearBucket 删除用户相关的bucket
-func (c *Client) ClearBucket(username string) error {
+// ClearStorage 删除用户相关的bucket以及向其中写入下采样数据的task，不存在的bucket视为已删除
+func (c *Client) ClearStorage(username string) (err error) {
 	bucketsAPI := c.Client.BucketsAPI()
-	buckets := []string{
-		username,
-		fmt.Sprintf("%s-warning_detect", username),
-		fmt.Sprintf("%s-warnings", username),
-	}
+	names := BucketNames(username)
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.clear_bucket", username, bucketTargets(names), err)
+	}()
 
-	for _, bucket := range buckets {
+	// task在bucket删除后会持续执行失败，因此需要先于bucket删除
+	if err := c.DeleteDownsampleTasks(username); err != nil {
+		return err
+	}
+	for _, bucket := range names {
 		b, err := bucketsAPI.FindBucketByName(context.Background(), bucket)
-		if err == nil {
-			bucketsAPI.DeleteBucket(context.Background(), b)
+		if err != nil {
+			if bucketNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("查询bucket %s 时发生了错误: %w", bucket, err)
+		}
+		if err := bucketsAPI.DeleteBucket(context.Background(), b); err != nil {
+			return fmt.Errorf("删除bucket %s 时发生了错误: %w", bucket, err)
 		}
 	}
 	return nil
 }
+
+// 审计记录中bucket的表示
+func bucketTargets(names []string) []string {
+	targets := make([]string, len(names))
+	for i, name := range names {
+		targets[i] = "buckets/" + name
+	}
+	return targets
+}
@@ -0,0 +1,206 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 每页查询的task数量，即influxdb允许的最大值
+const taskPageSize = 500
+
+// 预警规则的聚合操作对应的flux聚合函数，不进行聚合的规则不需要下采样
+var aggregationFunctions = map[utilApi.DeviceStateRegisterInfo_AggregationOperation]string{
+	utilApi.DeviceStateRegisterInfo_AVG: "mean",
+	utilApi.DeviceStateRegisterInfo_MAX: "max",
+	utilApi.DeviceStateRegisterInfo_MIN: "min",
+	utilApi.DeviceStateRegisterInfo_SUM: "sum",
+}
+
+// DownsampleTask 依据预警规则对设备状态字段进行下采样的influxdb task
+type DownsampleTask struct {
+	Name string
+	// task执行的间隔，即预警规则的时间范围
+	Every time.Duration
+	// 不包括task选项的flux脚本
+	Flux string
+}
+
+// StateMeasurement 设备状态信息在bucket中的measurement，即设备状态注册信息的下标
+func StateMeasurement(deviceClassID int) string {
+	return strconv.Itoa(deviceClassID)
+}
+
+// DownsampleTaskPrefix 用户所有下采样task的名称前缀
+func DownsampleTaskPrefix(username string) string {
+	return fmt.Sprintf("%s-warning_detect-", username)
+}
+
+// DownsampleTasks 依据设备状态注册信息中的预警规则生成下采样task，每个预警规则对应一个task，
+// task以规则的时间范围为窗口聚合数据收集bucket中的字段，并写入保存下采样数据的bucket，
+// 不进行聚合的规则以及非数值类型的字段不生成task
+func DownsampleTasks(username, org string, states []*utilApi.DeviceStateRegisterInfo) []*DownsampleTask {
+	names := BucketNames(username)
+	var tasks []*DownsampleTask
+	for i, state := range states {
+		for _, field := range state.GetFields() {
+			rule := field.GetWarningRule()
+			if rule == nil || !numericType(field.Type) || rule.Duration.AsDuration() <= 0 {
+				continue
+			}
+			fn, ok := aggregationFunctions[rule.AggregationOperation]
+			if !ok {
+				continue
+			}
+
+			// task的执行间隔至少为1秒
+			every := rule.Duration.AsDuration().Round(time.Second)
+			if every < time.Second {
+				every = time.Second
+			}
+			window := fluxDuration(every)
+			tasks = append(tasks, &DownsampleTask{
+				Name:  fmt.Sprintf("%s%d-%s", DownsampleTaskPrefix(username), i, field.Name),
+				Every: every,
+				Flux: fmt.Sprintf(`from(bucket: %q)
+  |> range(start: -task.every)
+  |> filter(fn: (r) => r._measurement == %q and r._field == %q)
+  |> aggregateWindow(every: %s, fn: %s, createEmpty: false)
+  |> to(bucket: %q, org: %q)`,
+					names[0], StateMeasurement(i), field.Name, window, fn, names[1], org),
+			})
+		}
+	}
+	return tasks
+}
+
+// SyncDownsampleTasks 使用户在influxdb中的下采样task与设备状态注册信息中的预警规则保持一致，
+// 创建缺少的task，重建规则发生了变化的task，并删除不再对应任何规则的task。
+// 重建时先创建新的task再删除旧的task，删除失败时同名的旧task会在下次同步时删除，避免规则在同步失败后没有对应的task
+func (c *Client) SyncDownsampleTasks(username string, states []*utilApi.DeviceStateRegisterInfo) (err error) {
+	desired := DownsampleTasks(username, c.org, states)
+	targets := make([]string, 0, len(desired))
+	for _, t := range desired {
+		targets = append(targets, "tasks/"+t.Name)
+	}
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.sync_downsample_tasks", username, targets, err)
+	}()
+
+	existing, err := c.findTasks(DownsampleTaskPrefix(username))
+	if err != nil {
+		return err
+	}
+
+	// 与规则一致的task予以保留，其余的task在创建了所有新的task后删除
+	tasksAPI := c.Client.TasksAPI()
+	for _, t := range desired {
+		fingerprint := t.fingerprint()
+		current := -1
+		for i, task := range existing[t.Name] {
+			if task.Description != nil && *task.Description == fingerprint {
+				current = i
+				break
+			}
+		}
+		if current >= 0 {
+			tasks := existing[t.Name]
+			existing[t.Name] = append(tasks[:current:current], tasks[current+1:]...)
+			continue
+		}
+
+		every := fluxDuration(t.Every)
+		status := domain.TaskStatusTypeActive
+		_, err := tasksAPI.CreateTask(context.Background(), &domain.Task{
+			Name:        t.Name,
+			OrgID:       c.orgID,
+			Every:       &every,
+			Flux:        t.Flux,
+			Description: &fingerprint,
+			Status:      &status,
+		})
+		if err != nil {
+			return fmt.Errorf("创建下采样task %s 时发生了错误: %w", t.Name, err)
+		}
+	}
+
+	return c.deleteTasks(existing)
+}
+
+// DeleteDownsampleTasks 删除用户所有的下采样task
+func (c *Client) DeleteDownsampleTasks(username string) error {
+	existing, err := c.findTasks(DownsampleTaskPrefix(username))
+	if err != nil {
+		return err
+	}
+	return c.deleteTasks(existing)
+}
+
+// 查询组织中名称以prefix为前缀的所有task，以task名为键，同步中断时可能存在多个同名的task
+func (c *Client) findTasks(prefix string) (map[string][]domain.Task, error) {
+	found := make(map[string][]domain.Task)
+	filter := &api.TaskFilter{OrgID: c.orgID, Limit: taskPageSize}
+	for {
+		tasks, err := c.Client.TasksAPI().FindTasks(context.Background(), filter)
+		if err != nil {
+			return nil, fmt.Errorf("查询下采样task时发生了错误: %w", err)
+		}
+		for _, t := range tasks {
+			if strings.HasPrefix(t.Name, prefix) {
+				found[t.Name] = append(found[t.Name], t)
+			}
+		}
+		if len(tasks) < taskPageSize {
+			return found, nil
+		}
+		filter.After = tasks[len(tasks)-1].Id
+	}
+}
+
+func (c *Client) deleteTasks(tasks map[string][]domain.Task) error {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, task := range tasks[name] {
+			err := c.Client.TasksAPI().DeleteTaskWithID(context.Background(), task.Id)
+			if err != nil {
+				return fmt.Errorf("删除下采样task %s 时发生了错误: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// 以task的执行间隔与flux脚本计算的摘要，保存在task的描述中，用于判断规则是否发生了变化
+func (t *DownsampleTask) fingerprint() string {
+	sum := sha256.Sum256([]byte(t.Every.String() + "\n" + t.Flux))
+	return "downsample:" + hex.EncodeToString(sum[:8])
+}
+
+// 判断字段类型是否可以进行数值聚合
+func numericType(t utilApi.Type) bool {
+	switch t {
+	case utilApi.Type_DOUBLE, utilApi.Type_INT32, utilApi.Type_INT64, utilApi.Type_UINT32, utilApi.Type_UINT64:
+		return true
+	default:
+		return false
+	}
+}
+
+// 将时长转换为flux的duration字面量，精确到秒
+func fluxDuration(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
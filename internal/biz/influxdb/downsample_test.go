@@ -0,0 +1,200 @@
+package influxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownsampleTasks(t *testing.T) {
+	rule := func(op utilApi.DeviceStateRegisterInfo_AggregationOperation, d time.Duration) *utilApi.DeviceStateRegisterInfo_WarningRule {
+		return &utilApi.DeviceStateRegisterInfo_WarningRule{AggregationOperation: op, Duration: durationpb.New(d)}
+	}
+	states := []*utilApi.DeviceStateRegisterInfo{
+		{Fields: []*utilApi.DeviceStateRegisterInfo_Field{
+			{Name: "voltage", Type: utilApi.Type_DOUBLE, WarningRule: rule(utilApi.DeviceStateRegisterInfo_AVG, time.Minute)},
+			{Name: "id", Type: utilApi.Type_STRING},
+			{Name: "on", Type: utilApi.Type_BOOL, WarningRule: rule(utilApi.DeviceStateRegisterInfo_MAX, time.Minute)},
+		}},
+		{Fields: []*utilApi.DeviceStateRegisterInfo_Field{
+			{Name: "current", Type: utilApi.Type_INT64, WarningRule: rule(utilApi.DeviceStateRegisterInfo_SUM, 90*time.Second)},
+			{Name: "raw", Type: utilApi.Type_INT64, WarningRule: rule(utilApi.DeviceStateRegisterInfo_NONE, time.Minute)},
+			{Name: "fast", Type: utilApi.Type_UINT32, WarningRule: rule(utilApi.DeviceStateRegisterInfo_MIN, time.Millisecond)},
+		}},
+	}
+
+	tasks := DownsampleTasks("alice", "org", states)
+	if len(tasks) != 3 {
+		t.Fatalf("只有数值字段上需要聚合的规则生成task，实际生成了%v个", len(tasks))
+	}
+
+	voltage := tasks[0]
+	if voltage.Name != "alice-warning_detect-0-voltage" || voltage.Every != time.Minute {
+		t.Fatalf("unexpected task: %+v", voltage)
+	}
+	for _, s := range []string{`from(bucket: "alice")`, `r._measurement == "0" and r._field == "voltage"`,
+		"aggregateWindow(every: 60s, fn: mean", `to(bucket: "alice-warning_detect", org: "org")`} {
+		if !strings.Contains(voltage.Flux, s) {
+			t.Fatalf("flux脚本中缺少 %s:\n%s", s, voltage.Flux)
+		}
+	}
+
+	if tasks[1].Name != "alice-warning_detect-1-current" || !strings.Contains(tasks[1].Flux, "every: 90s, fn: sum") {
+		t.Fatalf("unexpected task: %+v", tasks[1])
+	}
+	if tasks[2].Every != time.Second {
+		t.Fatalf("task的执行间隔至少为1秒，实际为%v", tasks[2].Every)
+	}
+
+	changed := DownsampleTasks("alice", "org", states[:1])
+	if changed[0].fingerprint() != voltage.fingerprint() {
+		t.Fatal("规则不变时task的摘要应保持不变")
+	}
+	states[0].Fields[0].WarningRule.AggregationOperation = utilApi.DeviceStateRegisterInfo_MAX
+	if DownsampleTasks("alice", "org", states)[0].fingerprint() == voltage.fingerprint() {
+		t.Fatal("规则变化时task的摘要应随之变化")
+	}
+}
+
+// 在内存中保存task的influxdb task接口，按顺序记录创建与删除操作
+type fakeTaskServer struct {
+	sync.Mutex
+	tasks  []domain.Task
+	ops    []string
+	nextID int
+	// 为true时删除task返回错误
+	failDelete bool
+}
+
+var taskNamePattern = regexp.MustCompile(`option task = \{ name: "([^"]+)"`)
+
+func (f *fakeTaskServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tasks":
+		tasks := append([]domain.Task{}, f.tasks...)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(domain.Tasks{Tasks: &tasks})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/tasks":
+		var req domain.TaskCreateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.nextID++
+		task := domain.Task{
+			Id:          fmt.Sprintf("%016d", f.nextID),
+			Name:        taskNamePattern.FindStringSubmatch(req.Flux)[1],
+			Flux:        req.Flux,
+			Description: req.Description,
+		}
+		f.tasks = append(f.tasks, task)
+		f.ops = append(f.ops, "create "+task.Name)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(task)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/tasks/"):
+		if f.failDelete {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code":"internal error","message":"injected"}`))
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/tasks/")
+		for i, task := range f.tasks {
+			if task.Id == id {
+				f.ops = append(f.ops, "delete "+task.Name)
+				f.tasks = append(f.tasks[:i], f.tasks[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeTaskServer) add(name, description string) {
+	f.nextID++
+	f.tasks = append(f.tasks, domain.Task{Id: fmt.Sprintf("%016d", f.nextID), Name: name, Description: &description})
+}
+
+func (f *fakeTaskServer) names() []string {
+	f.Lock()
+	defer f.Unlock()
+	var names []string
+	for _, t := range f.tasks {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestClient_SyncDownsampleTasks(t *testing.T) {
+	fake := new(fakeTaskServer)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := &Client{Client: influxdb2.NewClient(server.URL, "token"), org: "org", orgID: "org-id"}
+	defer client.Close()
+
+	states := []*utilApi.DeviceStateRegisterInfo{
+		{Fields: []*utilApi.DeviceStateRegisterInfo_Field{
+			{Name: "voltage", Type: utilApi.Type_DOUBLE, WarningRule: &utilApi.DeviceStateRegisterInfo_WarningRule{
+				AggregationOperation: utilApi.DeviceStateRegisterInfo_AVG, Duration: durationpb.New(time.Minute)}},
+		}},
+	}
+	current := DownsampleTasks("alice", "org", states)[0]
+	// 规则已变化的task、不再对应规则的task，以及其他用户的task
+	fake.add(current.Name, "downsample:outdated")
+	fake.add("alice-warning_detect-1-removed", "downsample:removed")
+	fake.add("bob-warning_detect-0-voltage", "downsample:bob")
+
+	t.Run("RecreateBeforeDelete", func(t *testing.T) {
+		fake.failDelete = true
+		if err := client.SyncDownsampleTasks("alice", states); err == nil {
+			t.Fatal("删除旧的task失败时应返回错误")
+		}
+		if fake.ops[0] != "create "+current.Name {
+			t.Fatalf("应先创建新的task再删除旧的task，实际操作为%v", fake.ops)
+		}
+
+		// 重试时保留已创建的task，只删除残留的旧task
+		fake.failDelete = false
+		fake.ops = nil
+		if err := client.SyncDownsampleTasks("alice", states); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"delete " + current.Name, "delete alice-warning_detect-1-removed"}
+		if fmt.Sprint(fake.ops) != fmt.Sprint(want) {
+			t.Fatalf("重试时的操作应为%v，实际为%v", want, fake.ops)
+		}
+		names := fake.names()
+		if fmt.Sprint(names) != fmt.Sprint([]string{"bob-warning_detect-0-voltage", current.Name}) {
+			t.Fatalf("unexpected tasks: %v", names)
+		}
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		fake.ops = nil
+		if err := client.SyncDownsampleTasks("alice", states); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.ops) != 0 {
+			t.Fatalf("规则不变时不应修改task，实际操作为%v", fake.ops)
+		}
+	})
+	t.Run("Delete", func(t *testing.T) {
+		if err := client.DeleteDownsampleTasks("alice"); err != nil {
+			t.Fatal(err)
+		}
+		if names := fake.names(); fmt.Sprint(names) != "[bob-warning_detect-0-voltage]" {
+			t.Fatalf("unexpected tasks: %v", names)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package influxdb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/query"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 导出bucket数据的格式
+const (
+	// ExportFormatCSV annotated csv，即influxdb查询接口返回的格式
+	ExportFormatCSV = "csv"
+	// ExportFormatLineProtocol 行协议，可以直接写回influxdb
+	ExportFormatLineProtocol = "line_protocol"
+)
+
+// 每导出该数量的数据点报告一次进度
+const exportProgressInterval = 10000
+
+// ExportBucket 以format格式将bucket中的所有数据流式写入w，每导出一批数据点时以累计的数量调用progress，
+// 返回导出的数据点数量
+func (c *Client) ExportBucket(ctx context.Context, bucket, format string, w io.Writer,
+	progress func(points int64)) (int64, error) {
+	flux := fmt.Sprintf(`from(bucket: %q) |> range(start: 0)`, bucket)
+	switch format {
+	case ExportFormatCSV:
+		return c.exportCSV(ctx, flux, w, progress)
+	case ExportFormatLineProtocol:
+		return c.exportLineProtocol(ctx, flux, w, progress)
+	default:
+		return 0, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// 直接调用查询接口，将返回的annotated csv逐行写入w，不在内存中保留完整的查询结果
+func (c *Client) exportCSV(ctx context.Context, flux string, w io.Writer, progress func(int64)) (int64, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":   flux,
+		"type":    "flux",
+		"dialect": api.DefaultDialect(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost,
+		strings.TrimSuffix(c.Client.ServerURL(), "/")+"/api/v2/query?org="+url.QueryEscape(c.org),
+		bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "text/csv")
+
+	response, err := c.Client.HTTPService().DoHTTPRequestWithResponse(request, nil)
+	if err != nil {
+		return 0, fmt.Errorf("查询bucket的数据时发生了错误: %w", err)
+	}
+	defer response.Body.Close()
+
+	return copyAnnotatedCSV(response.Body, w, progress)
+}
+
+// 逐行复制annotated csv，并统计其中的数据行数，每张表由注解行、表头以及数据行组成，表之间以空行分隔
+func copyAnnotatedCSV(r io.Reader, w io.Writer, progress func(int64)) (int64, error) {
+	var points int64
+	header := true
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		switch {
+		case len(bytes.TrimSpace(line)) == 0, bytes.HasPrefix(line, []byte("#")):
+			header = true
+		case header:
+			header = false
+		default:
+			points++
+			if progress != nil && points%exportProgressInterval == 0 {
+				progress(points)
+			}
+		}
+
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return points, err
+		}
+	}
+
+	return points, scanner.Err()
+}
+
+// 逐条读取查询结果，转换为行协议后写入w
+func (c *Client) exportLineProtocol(ctx context.Context, flux string, w io.Writer, progress func(int64)) (int64, error) {
+	result, err := c.Client.QueryAPI(c.org).Query(ctx, flux)
+	if err != nil {
+		return 0, fmt.Errorf("查询bucket的数据时发生了错误: %w", err)
+	}
+	defer result.Close()
+
+	var points int64
+	for result.Next() {
+		if _, err := io.WriteString(w, recordToLineProtocol(result.Record())); err != nil {
+			return points, err
+		}
+		points++
+		if progress != nil && points%exportProgressInterval == 0 {
+			progress(points)
+		}
+	}
+
+	return points, result.Err()
+}
+
+// 将查询结果中的一条记录转换为行协议，除查询附带的列外，不以下划线开头的列均视为tag
+func recordToLineProtocol(record *query.FluxRecord) string {
+	tags := make(map[string]string)
+	for key, value := range record.Values() {
+		if strings.HasPrefix(key, "_") || key == "result" || key == "table" {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			tags[key] = s
+		}
+	}
+
+	point := write.NewPoint(
+		record.Measurement(), tags, map[string]interface{}{record.Field(): record.Value()}, record.Time())
+	return write.PointToLineProtocol(point, time.Nanosecond)
+}
@@ -0,0 +1,62 @@
+package influxdb
+
+import (
+	"bytes"
+	"github.com/influxdata/influxdb-client-go/v2/api/query"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyAnnotatedCSV(t *testing.T) {
+	csv := strings.Join([]string{
+		"#datatype,string,long,dateTime:RFC3339,double,string,string",
+		"#group,false,false,false,false,true,true",
+		"#default,_result,,,,,",
+		",result,table,_time,_value,_field,_measurement",
+		",,0,2022-01-01T00:00:00Z,1.5,voltage,device",
+		",,0,2022-01-01T00:00:01Z,2.5,voltage,device",
+		"",
+		"#datatype,string,long,dateTime:RFC3339,boolean,string,string",
+		"#group,false,false,false,false,true,true",
+		"#default,_result,,,,,",
+		",result,table,_time,_value,_field,_measurement",
+		",,1,2022-01-01T00:00:00Z,true,switch,device",
+		"",
+	}, "\n")
+
+	var progress []int64
+	out := new(bytes.Buffer)
+	points, err := copyAnnotatedCSV(strings.NewReader(csv), out, func(p int64) { progress = append(progress, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points != 3 {
+		t.Fatalf("应只统计数据行，期望3，实际为%v", points)
+	}
+	if out.String() != csv {
+		t.Fatalf("导出的内容应与查询结果一致，实际为:\n%s", out.String())
+	}
+	if len(progress) != 0 {
+		t.Fatalf("未达到进度间隔时不应回调，实际回调了%v次", len(progress))
+	}
+}
+
+func TestRecordToLineProtocol(t *testing.T) {
+	record := query.NewFluxRecord(0, map[string]interface{}{
+		"result":       "_result",
+		"table":        int64(0),
+		"_start":       time.Unix(0, 0),
+		"_stop":        time.Unix(100, 0),
+		"_time":        time.Unix(1, 0),
+		"_measurement": "device",
+		"_field":       "voltage",
+		"_value":       1.5,
+		"id":           "d1",
+	})
+
+	line := recordToLineProtocol(record)
+	if line != "device,id=d1 voltage=1.5 1000000000\n" {
+		t.Fatalf("unexpected line protocol: %q", line)
+	}
+}
@@ -0,0 +1,57 @@
+package influxdb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"io"
+	"strings"
+)
+
+// 每批写入influxdb的行数
+const importBatchSize = 5000
+
+// ImportBucket 将行协议格式的数据分批写入bucket，返回写入的数据点数量，
+// 数据来自以ExportFormatLineProtocol格式导出的bucket
+func (c *Client) ImportBucket(ctx context.Context, bucket string, r io.Reader) (points int64, err error) {
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.import_bucket", "", []string{"buckets/" + bucket}, err)
+	}()
+
+	writeAPI := c.Client.WriteAPIBlocking(c.org, bucket)
+	batch := make([]string, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		// 超出bucket保留时长的数据点会被influxdb丢弃，其余数据点仍被写入
+		err := writeAPI.WriteRecord(ctx, batch...)
+		if err != nil && !strings.Contains(err.Error(), "beyond retention policy") {
+			return fmt.Errorf("向bucket %s 写入数据时发生了错误: %w", bucket, err)
+		}
+		points += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		batch = append(batch, line)
+		if len(batch) == importBatchSize {
+			if err = flush(); err != nil {
+				return points, err
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return points, err
+	}
+	err = flush()
+	return points, err
+}
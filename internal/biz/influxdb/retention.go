@@ -0,0 +1,69 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"time"
+)
+
+// 依据保留策略获得用户各个bucket的保留规则，以bucket名为键
+func retentionRules(username string, p *timeseries.RetentionPolicy) map[string]domain.RetentionRule {
+	names := BucketNames(username)
+	shardGroupDurationSeconds := int64(p.ShardGroupDuration.Seconds())
+	rules := make(map[string]domain.RetentionRule, len(names))
+	for i, retention := range []time.Duration{p.Data, p.WarningDetect, p.Warnings} {
+		rules[names[i]] = domain.RetentionRule{
+			EverySeconds:              int64(retention.Seconds()),
+			ShardGroupDurationSeconds: &shardGroupDurationSeconds,
+			Type:                      "expire",
+		}
+	}
+	return rules
+}
+
+// UpdateRetention 修改用户已有bucket的保留策略，某个bucket修改失败时将已修改的bucket还原为原有的保留规则，
+// 使各个bucket的保留策略保持一致
+func (c *Client) UpdateRetention(username string, policy *timeseries.RetentionPolicy) (err error) {
+	names := BucketNames(username)
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.update_retention", username, bucketTargets(names), err)
+	}()
+
+	bucketsAPI := c.Client.BucketsAPI()
+	rules := retentionRules(username, policy)
+	// 已修改的bucket及其原有的保留规则
+	var updated []*domain.Bucket
+	var previous []domain.RetentionRules
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i, bucket := range updated {
+			bucket.RetentionRules = previous[i]
+			if _, e := bucketsAPI.UpdateBucket(context.Background(), bucket); e != nil {
+				err = fmt.Errorf("%w，且还原bucket %s 的保留策略时发生了错误: %v", err, bucket.Name, e)
+			}
+		}
+	}()
+
+	for _, name := range names {
+		bucket, err := bucketsAPI.FindBucketByName(context.Background(), name)
+		if err != nil {
+			return fmt.Errorf("查询bucket %s 时发生了错误: %w", name, err)
+		}
+
+		original := bucket.RetentionRules
+		bucket.RetentionRules = domain.RetentionRules{rules[name]}
+		_, err = bucketsAPI.UpdateBucket(context.Background(), bucket)
+		if err != nil {
+			return fmt.Errorf("修改bucket %s 的保留策略时发生了错误: %w", name, err)
+		}
+		updated = append(updated, bucket)
+		previous = append(previous, original)
+	}
+
+	return nil
+}
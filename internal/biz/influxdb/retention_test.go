@@ -0,0 +1,22 @@
+package influxdb
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"testing"
+	"time"
+)
+
+func Test_retentionRules(t *testing.T) {
+	policy := &timeseries.RetentionPolicy{Data: 365 * 24 * time.Hour, WarningDetect: time.Hour, Warnings: 0, ShardGroupDuration: time.Hour}
+	rules := retentionRules("test", policy)
+
+	if rules["test"].EverySeconds != 365*24*3600 || rules["test-warning_detect"].EverySeconds != 3600 {
+		t.Fatalf("bucket的保留时长错误:%v", rules)
+	}
+	if rules["test-warnings"].EverySeconds != 0 {
+		t.Fatalf("永久保留的bucket的保留时长应为0:%v", rules["test-warnings"])
+	}
+	if *rules["test"].ShardGroupDurationSeconds != 3600 {
+		t.Fatalf("shard group时长错误:%v", *rules["test"].ShardGroupDurationSeconds)
+	}
+}
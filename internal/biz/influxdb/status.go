@@ -0,0 +1,128 @@
+package influxdb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+	"io"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+)
+
+// 记录influxdb中各个shard占用磁盘空间的prometheus指标
+const shardDiskSizeMetric = "storage_shard_disk_size"
+
+// BucketStatus 用户相关的bucket的状态
+type BucketStatus struct {
+	Name string
+	// bucket是否存在
+	Exists bool
+	// 数据保留的时长，0表示永久保留
+	RetentionSeconds int64
+	// bucket下所有shard占用的磁盘空间
+	SizeBytes int64
+}
+
+// GetBucketStatus 查询用户相关的bucket是否存在，以及其数据保留时长和占用的磁盘空间，
+// 磁盘空间由influxdb的/metrics接口提供的shard大小统计得到
+func (c *Client) GetBucketStatus(username string) ([]BucketStatus, error) {
+	bucketsAPI := c.Client.BucketsAPI()
+	names := BucketNames(username)
+
+	status := make([]BucketStatus, len(names))
+	ids := make(map[string]int, len(names))
+	for i, name := range names {
+		status[i].Name = name
+
+		b, err := bucketsAPI.FindBucketByName(context.Background(), name)
+		if err != nil {
+			if !bucketNotFound(err) {
+				return nil, fmt.Errorf("查询bucket %s 时发生了错误: %w", name, err)
+			}
+			continue
+		}
+
+		status[i].Exists = true
+		for _, rule := range b.RetentionRules {
+			if rule.Type == "expire" {
+				status[i].RetentionSeconds = rule.EverySeconds
+			}
+		}
+		if b.Id != nil {
+			ids[*b.Id] = i
+		}
+	}
+
+	if len(ids) == 0 {
+		return status, nil
+	}
+	sizes, err := c.getBucketSizes()
+	if err != nil {
+		return nil, err
+	}
+	for id, i := range ids {
+		status[i].SizeBytes = sizes[id]
+	}
+
+	return status, nil
+}
+
+// 查询成功但没有匹配的bucket时，influxdb客户端返回的是普通的not found错误
+func bucketNotFound(err error) bool {
+	_, ok := err.(*http.Error)
+	return !ok && strings.HasSuffix(err.Error(), "not found")
+}
+
+// 从influxdb的/metrics接口获得各个bucket占用的磁盘空间
+func (c *Client) getBucketSizes() (map[string]int64, error) {
+	request, err := nethttp.NewRequestWithContext(
+		context.Background(), nethttp.MethodGet,
+		strings.TrimSuffix(c.Client.ServerURL(), "/")+"/metrics", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.Client.HTTPService().DoHTTPRequestWithResponse(request, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询influxdb的指标时发生了错误: %w", err)
+	}
+	defer response.Body.Close()
+
+	return parseBucketSizes(response.Body)
+}
+
+// 解析prometheus文本格式的指标，累加每个bucket下所有shard的磁盘空间，
+// 指标的格式如: storage_shard_disk_size{bucket="<id>",engine="tsm1",id="1",...} 1024
+func parseBucketSizes(metrics io.Reader) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+
+	scanner := bufio.NewScanner(metrics)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, shardDiskSizeMetric+"{") {
+			continue
+		}
+
+		end := strings.LastIndex(line, "}")
+		if end == -1 {
+			continue
+		}
+		bucket := ""
+		for _, l := range strings.Split(line[len(shardDiskSizeMetric)+1:end], ",") {
+			if kv := strings.SplitN(l, "=", 2); len(kv) == 2 && kv[0] == "bucket" {
+				bucket = strings.Trim(kv[1], `"`)
+				break
+			}
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64)
+		if bucket == "" || err != nil {
+			continue
+		}
+		sizes[bucket] += int64(value)
+	}
+
+	return sizes, scanner.Err()
+}
@@ -0,0 +1,29 @@
+package influxdb
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_parseBucketSizes(t *testing.T) {
+	metrics := `# HELP storage_shard_disk_size Gauge of the disk size for the shard
+# TYPE storage_shard_disk_size gauge
+storage_shard_disk_size{bucket="a1",engine="tsm1",id="1",path="/a1/1",walPath="/wal/a1/1"} 1024
+storage_shard_disk_size{bucket="a1",engine="tsm1",id="2",path="/a1/2",walPath="/wal/a1/2"} 2048
+storage_shard_disk_size{bucket="b2",engine="tsm1",id="3",path="/b2/3",walPath="/wal/b2/3"} 1.5e+06
+storage_shard_fields_created{bucket="a1",engine="tsm1",id="1"} 10
+`
+	sizes, err := parseBucketSizes(strings.NewReader(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes["a1"] != 3072 {
+		t.Fatalf("bucket a1 的磁盘空间应为所有shard之和，实际为:%v", sizes["a1"])
+	}
+	if sizes["b2"] != 1500000 {
+		t.Fatalf("bucket b2 的磁盘空间解析错误:%v", sizes["b2"])
+	}
+	if len(sizes) != 2 {
+		t.Fatalf("不应统计其他指标:%v", sizes)
+	}
+}
@@ -0,0 +1,85 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+)
+
+// TenantTokenDescription 租户token的描述，用于查询租户已有的token
+func TenantTokenDescription(username string) string {
+	return "service-centre tenant " + username
+}
+
+// CreateCredential 为租户创建只能读写其三个bucket的authorization，bucket需要已经存在
+func (c *Client) CreateCredential(username string) (token *timeseries.Credential, err error) {
+	names := BucketNames(username)
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.create_tenant_token", username, bucketTargets(names), err)
+	}()
+
+	bucketsAPI := c.Client.BucketsAPI()
+	permissions := make([]domain.Permission, 0, 2*len(names))
+	for _, name := range names {
+		bucket, err := bucketsAPI.FindBucketByName(context.Background(), name)
+		if err != nil {
+			return nil, fmt.Errorf("查询bucket %s 时发生了错误: %w", name, err)
+		}
+		for _, action := range []domain.PermissionAction{domain.PermissionActionRead, domain.PermissionActionWrite} {
+			permissions = append(permissions, domain.Permission{
+				Action: action,
+				Resource: domain.Resource{
+					Type:  domain.ResourceTypeBuckets,
+					Id:    bucket.Id,
+					OrgID: &c.orgID,
+				},
+			})
+		}
+	}
+
+	description := TenantTokenDescription(username)
+	authorization, err := c.Client.AuthorizationsAPI().CreateAuthorization(context.Background(), &domain.Authorization{
+		AuthorizationUpdateRequest: domain.AuthorizationUpdateRequest{Description: &description},
+		OrgID:                      &c.orgID,
+		Permissions:                &permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("为用户 %s 创建influxdb token时发生了错误: %w", username, err)
+	}
+	if authorization.Id == nil || authorization.Token == nil {
+		return nil, fmt.Errorf("为用户 %s 创建的influxdb token缺少id或token", username)
+	}
+
+	return &timeseries.Credential{ID: *authorization.Id, Token: *authorization.Token}, nil
+}
+
+// RevokeCredentials 吊销租户除keep以外的所有token，keep为空时吊销全部token
+func (c *Client) RevokeCredentials(username, keep string) (err error) {
+	defer func() {
+		audit.Record(c.Recorder, "influxdb.revoke_tenant_tokens", username,
+			[]string{"authorizations?description=" + TenantTokenDescription(username)}, err)
+	}()
+
+	authorizationsAPI := c.Client.AuthorizationsAPI()
+	authorizations, err := authorizationsAPI.FindAuthorizationsByOrgID(context.Background(), c.orgID)
+	if err != nil {
+		return fmt.Errorf("查询用户 %s 的influxdb token时发生了错误: %w", username, err)
+	}
+	if authorizations == nil {
+		return nil
+	}
+
+	description := TenantTokenDescription(username)
+	for _, a := range *authorizations {
+		if a.Id == nil || *a.Id == keep || a.Description == nil || *a.Description != description {
+			continue
+		}
+		err := authorizationsAPI.DeleteAuthorizationWithID(context.Background(), *a.Id)
+		if err != nil {
+			return fmt.Errorf("吊销用户 %s 的influxdb token时发生了错误: %w", username, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CountPoints 统计指定bucket在[start, stop)时间范围内写入的数据点数量
+func (c *Client) CountPoints(bucket string, start, stop time.Time) (int64, error) {
+	query := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: %s, stop: %s)
+  |> count()
+  |> group()
+  |> sum()`,
+		bucket, start.UTC().Format(time.RFC3339), stop.UTC().Format(time.RFC3339))
+
+	result, err := c.Client.QueryAPI(c.org).Query(context.Background(), query)
+	if err != nil {
+		return 0, err
+	}
+	defer result.Close()
+
+	var count int64
+	for result.Next() {
+		if v, ok := result.Record().Value().(int64); ok {
+			count += v
+		}
+	}
+	return count, result.Err()
+}
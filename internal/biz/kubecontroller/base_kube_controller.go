@@ -7,7 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	label "k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/types"
 	client_appsv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	client_corev1 "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -23,9 +23,17 @@ type baseKubeController struct {
 	namespace string
 }
 
-func newBaseKubeController(namespace string) (*baseKubeController, error) {
-	// 通过service account获得访问api server的config实例
-	config, err := rest.InClusterConfig()
+func newBaseKubeController(kubeconfig, namespace string) (*baseKubeController, error) {
+	var (
+		config *rest.Config
+		err    error
+	)
+	if kubeconfig == "" {
+		// 通过service account获得访问api server的config实例
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = loadKubeconfig(kubeconfig)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +65,21 @@ func (c *baseKubeController) CreateConfigMap(
 	)
 }
 
+// CreateSecret 创建或更新指定的secret
+func (c *baseKubeController) CreateSecret(
+	name string, labels map[string]string, data map[string][]byte) (*corev1.Secret, error) {
+	secretApplyConfiguration := client_corev1.Secret(name, c.namespace).
+		WithLabels(labels).WithType(corev1.SecretTypeOpaque).WithData(data)
+
+	return c.client.CoreV1().Secrets(c.namespace).Apply(
+		context.Background(),
+		secretApplyConfiguration,
+		client_metav1.ApplyOptions{
+			FieldManager: fieldManager,
+		},
+	)
+}
+
 // CreateService 创建指定的service
 func (c *baseKubeController) CreateService(
 	name string, labels map[string]string,
@@ -93,56 +116,24 @@ func (c *baseKubeController) CreateDeployment(
 		return nil, err
 	}
 
-	// 构建标签选择器和字段选择器，筛选出然后执行watch操作
-	// 标签选择器和字段选择器的格式参考:
-	// https://kubernetes.io/zh/docs/concepts/overview/working-with-objects/labels/
-	// https://kubernetes.io/zh/docs/concepts/overview/working-with-objects/field-selectors/
-	// watch响应的object的包含的字段与格式参考:
-	// https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#watch-deployment-v1-apps
-	formatLabels := label.FormatLabels(labels)
-	w, err := c.client.AppsV1().Deployments(c.namespace).Watch(
-		context.Background(),
-		client_metav1.ListOptions{
-			TypeMeta:             deployment.TypeMeta,
-			LabelSelector:        formatLabels,
-			FieldSelector:        "",
-			Watch:                true,
-			AllowWatchBookmarks:  false,
-			ResourceVersion:      "",
-			ResourceVersionMatch: "",
-			TimeoutSeconds:       nil,
-			Limit:                0,
-			Continue:             "",
+	// 等待deployment的所有pod都ready，超时时删除创建的deployment
+	diagnosis, err := c.waitForDeployment(
+		client_metav1.ListOptions{LabelSelector: label.FormatLabels(labels)},
+		podSelector(deployment.Spec.Selector),
+		timeout,
+		func(d *appsv1.Deployment) bool {
+			return d.Status.ReadyReplicas == *deployment.Spec.Replicas
 		},
 	)
 	if err != nil {
-		return nil, err
-	}
-	defer w.Stop()
-
-	// 设置等待更新的计时器为五分钟
-	timer := time.After(timeout)
-	for {
-		select {
-		case <-timer:
-			// 超时，删除创建的deployment
+		if errors.Is(err, errWaitTimeout) {
 			c.DeleteResource(deployment.Name, "Deployment")
-
-			return nil, errors.New(
-				500, "CREATE_DEPLOYMENT_FAIL", "failed to create the deployment")
-		case event := <-w.ResultChan():
-			if event.Type == watch.Error || event.Type == watch.Deleted {
-				return nil, errors.New(
-					500, "CREATE_DEPLOYMENT_FAIL", "failed to create the deployment")
-			}
-
-			// 当deployment的所有pod都ready时，表示deployment创建成功
-			deployStatus := event.Object.(*appsv1.Deployment).Status
-			if deployStatus.ReadyReplicas == *deployment.Spec.Replicas {
-				return deployment, nil
-			}
 		}
+		return nil, withDiagnosis(errors.New(
+			500, "CREATE_DEPLOYMENT_FAIL", "failed to create the deployment"), diagnosis)
 	}
+
+	return deployment, nil
 }
 
 // CreateStatefulSet 创建指定的statefulSet,并执行watch直到statefulSet能够提供服务
@@ -163,53 +154,104 @@ func (c *baseKubeController) CreateStatefulSet(
 		return nil, err
 	}
 
-	// 构建标签选择器和字段选择器，筛选出然后执行watch操作
-	// watch响应的object的包含的字段与格式参考:
-	// https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#statefulset-v1-apps
-	formatLabels := label.FormatLabels(labels)
-	w, err := c.client.AppsV1().StatefulSets(c.namespace).Watch(
+	// 等待statefulSet的所有pod都ready，超时时删除创建的statefulSet
+	diagnosis, err := c.waitForStatefulSet(
+		client_metav1.ListOptions{LabelSelector: label.FormatLabels(labels)},
+		podSelector(statefulSet.Spec.Selector),
+		timeout,
+		func(s *appsv1.StatefulSet) bool {
+			return s.Status.ReadyReplicas == *statefulSet.Spec.Replicas
+		},
+	)
+	if errors.Is(err, errWaitTimeout) {
+		c.DeleteResource(statefulSet.Name, "StatefulSet")
+
+		return nil, withDiagnosis(errors.New(
+			500, "CREATE_STATEFULSET_TIMEOUT", "failed to create the statefulSet"), diagnosis)
+	} else if err != nil {
+		return nil, withDiagnosis(errors.New(
+			500, "CREATE_STATEFULSET_FAIL", "failed to create the statefulSet"), diagnosis)
+	}
+
+	return statefulSet, nil
+}
+
+// GetDeployment 查询指定的deployment
+func (c *baseKubeController) GetDeployment(name string) (*appsv1.Deployment, error) {
+	return c.client.AppsV1().Deployments(c.namespace).Get(
+		context.Background(), name, client_metav1.GetOptions{})
+}
+
+// GetStatefulSet 查询指定的statefulSet
+func (c *baseKubeController) GetStatefulSet(name string) (*appsv1.StatefulSet, error) {
+	return c.client.AppsV1().StatefulSets(c.namespace).Get(
+		context.Background(), name, client_metav1.GetOptions{})
+}
+
+// PatchDeployment 以strategic merge patch的方式更新指定的deployment，并执行watch直到滚动更新完成
+func (c *baseKubeController) PatchDeployment(
+	name string, patch []byte, timeout time.Duration) (*appsv1.Deployment, error) {
+	deployment, err := c.client.AppsV1().Deployments(c.namespace).Patch(
 		context.Background(),
-		client_metav1.ListOptions{
-			TypeMeta:             statefulSet.TypeMeta,
-			LabelSelector:        formatLabels,
-			FieldSelector:        "",
-			Watch:                true,
-			AllowWatchBookmarks:  false,
-			ResourceVersion:      "",
-			ResourceVersionMatch: "",
-			TimeoutSeconds:       nil,
-			Limit:                0,
-			Continue:             "",
+		name,
+		types.StrategicMergePatchType,
+		patch,
+		client_metav1.PatchOptions{
+			FieldManager: fieldManager,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
-	defer w.Stop()
 
-	// 设置等待更新的计时器为五分钟
-	timer := time.After(timeout)
-	for {
-		select {
-		case <-timer:
-			// 超时，删除创建的statefulSet
-			c.DeleteResource(statefulSet.Name, "StatefulSet")
+	diagnosis, err := c.waitForDeployment(
+		client_metav1.ListOptions{FieldSelector: "metadata.name=" + name},
+		podSelector(deployment.Spec.Selector),
+		timeout,
+		deploymentRolledOut,
+	)
+	if errors.Is(err, errWaitTimeout) {
+		return nil, withDiagnosis(errors.New(
+			500, "UPDATE_DEPLOYMENT_TIMEOUT", "timed out waiting for the deployment rollout"), diagnosis)
+	} else if err != nil {
+		return nil, withDiagnosis(errors.New(
+			500, "UPDATE_DEPLOYMENT_FAIL", "failed to update the deployment"), diagnosis)
+	}
 
-			return nil, errors.New(
-				500, "CREATE_STATEFULSET_TIMEOUT", "failed to create the statefulSet")
-		case event := <-w.ResultChan():
-			if event.Type == watch.Error || event.Type == watch.Deleted {
-				return nil, errors.New(
-					500, "CREATE_STATEFULSET_FAIL", "failed to create the statefulSet")
-			}
+	return deployment, nil
+}
 
-			// 当statefulSet的所有pod都ready时，表示statefulSet创建成功
-			status := event.Object.(*appsv1.StatefulSet).Status
-			if status.ReadyReplicas == *statefulSet.Spec.Replicas {
-				return statefulSet, nil
-			}
-		}
+// PatchStatefulSet 以strategic merge patch的方式更新指定的statefulSet，并执行watch直到滚动更新完成
+func (c *baseKubeController) PatchStatefulSet(
+	name string, patch []byte, timeout time.Duration) (*appsv1.StatefulSet, error) {
+	statefulSet, err := c.client.AppsV1().StatefulSets(c.namespace).Patch(
+		context.Background(),
+		name,
+		types.StrategicMergePatchType,
+		patch,
+		client_metav1.PatchOptions{
+			FieldManager: fieldManager,
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
+
+	diagnosis, err := c.waitForStatefulSet(
+		client_metav1.ListOptions{FieldSelector: "metadata.name=" + name},
+		podSelector(statefulSet.Spec.Selector),
+		timeout,
+		statefulSetRolledOut,
+	)
+	if errors.Is(err, errWaitTimeout) {
+		return nil, withDiagnosis(errors.New(
+			500, "UPDATE_STATEFULSET_TIMEOUT", "timed out waiting for the statefulSet rollout"), diagnosis)
+	} else if err != nil {
+		return nil, withDiagnosis(errors.New(
+			500, "UPDATE_STATEFULSET_FAIL", "failed to update the statefulSet"), diagnosis)
+	}
+
+	return statefulSet, nil
 }
 
 // DeleteResource 删除指定的k8s资源
@@ -227,6 +269,12 @@ func (c *baseKubeController) DeleteResource(name, resourceType string) error {
 			name,
 			client_metav1.DeleteOptions{},
 		)
+	case "Secret":
+		return c.client.CoreV1().Secrets(c.namespace).Delete(
+			context.Background(),
+			name,
+			client_metav1.DeleteOptions{},
+		)
 	case "Deployment":
 		return c.client.AppsV1().Deployments(c.namespace).Delete(
 			context.Background(),
@@ -273,6 +321,14 @@ func (c *baseKubeController) DeleteResources(resourceType string, labelSelector
 				LabelSelector: labelSelector,
 			},
 		)
+	case "Secret":
+		return c.client.CoreV1().Secrets(c.namespace).DeleteCollection(
+			context.Background(),
+			client_metav1.DeleteOptions{},
+			client_metav1.ListOptions{
+				LabelSelector: labelSelector,
+			},
+		)
 	case "Deployment":
 		return c.client.AppsV1().Deployments(c.namespace).DeleteCollection(
 			context.Background(),
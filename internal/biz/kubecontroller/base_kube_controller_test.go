@@ -11,7 +11,7 @@ import (
 )
 
 func Test_baseKubeController_DeleteResource(t *testing.T) {
-	controller, err := newBaseKubeController("test")
+	controller, err := newBaseKubeController("", "test")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -63,7 +63,7 @@ func Test_baseKubeController_DeleteResource(t *testing.T) {
 	}
 }
 func Test_baseKubeController_CreateDeployment(t *testing.T) {
-	controller, err := newBaseKubeController("test")
+	controller, err := newBaseKubeController("", "test")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -114,7 +114,7 @@ func Test_baseKubeController_CreateDeployment(t *testing.T) {
 }
 
 func Test_baseKubeController_CreateStatefulSet(t *testing.T) {
-	controller, err := newBaseKubeController("test")
+	controller, err := newBaseKubeController("", "test")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,233 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/errors"
+	corev1 "k8s.io/api/core/v1"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	label "k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"strings"
+)
+
+const (
+	// 编译初始容器的名称
+	buildContainerName = "build"
+	// 诊断信息中保留的pod事件数量
+	diagnosisEventLimit = 10
+	// 诊断信息中保留的编译初始容器日志的行数
+	diagnosisLogTailLines int64 = 50
+	// 容器以非0状态码退出并重启达到该次数后，判定为无法自行恢复的故障，
+	// 单次失败可能由节点或网络的短暂故障导致，重启即可恢复
+	fatalRestartCount int32 = 3
+)
+
+// 容器处于以下等待原因时，重试已无法使pod正常运行，可以直接判定滚动更新失败，
+// ErrImagePull可能由短暂的网络故障导致，因此等到kubelet进入ImagePullBackOff后才判定失败
+var fatalWaitingReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+}
+
+// RolloutDiagnosis 滚动更新失败时，对服务的pod进行诊断得到的故障信息
+type RolloutDiagnosis struct {
+	// 出现故障的pod
+	Pod string
+	// 出现故障的容器
+	Container string
+	// 故障原因，如ImagePullBackOff、CrashLoopBackOff、InitContainerFailed
+	Reason string
+	// 故障的详细信息
+	Message string
+	// pod最近的事件
+	Events []string
+	// 编译初始容器日志的末尾部分
+	BuildLogs string
+}
+
+// Metadata 将诊断信息转换为错误的metadata，便于调用者以结构化的形式获取
+func (d *RolloutDiagnosis) Metadata() map[string]string {
+	return map[string]string{
+		"pod":        d.Pod,
+		"container":  d.Container,
+		"reason":     d.Reason,
+		"message":    d.Message,
+		"events":     strings.Join(d.Events, "\n"),
+		"build_logs": d.BuildLogs,
+	}
+}
+
+// 将诊断信息附加到滚动更新失败的错误中
+func withDiagnosis(err *errors.Error, diagnosis *RolloutDiagnosis) *errors.Error {
+	if diagnosis == nil {
+		return err
+	}
+
+	e := errors.Newf(int(err.Code), err.Reason, "%s: pod %s的容器%s出现故障(%s): %s",
+		err.Message, diagnosis.Pod, diagnosis.Container, diagnosis.Reason, diagnosis.Message)
+	return e.WithMetadata(diagnosis.Metadata())
+}
+
+// diagnosePods 检查满足标签选择器的pod，发现无法自行恢复的故障时返回相应的诊断信息，
+// 当force为true时，即使未发现明确的故障，也返回首个未ready的pod的事件与编译日志
+func (c *baseKubeController) diagnosePods(podSelector string, force bool) *RolloutDiagnosis {
+	pods, err := c.client.CoreV1().Pods(c.namespace).List(
+		context.Background(),
+		client_metav1.ListOptions{LabelSelector: podSelector},
+	)
+	if err != nil || len(pods.Items) == 0 {
+		return nil
+	}
+
+	var diagnosis *RolloutDiagnosis
+	for i := range pods.Items {
+		if diagnosis = diagnosePod(&pods.Items[i]); diagnosis != nil {
+			break
+		}
+	}
+	if diagnosis == nil {
+		if !force {
+			return nil
+		}
+		for i := range pods.Items {
+			if !podReady(&pods.Items[i]) {
+				diagnosis = &RolloutDiagnosis{
+					Pod:     pods.Items[i].Name,
+					Reason:  "NotReady",
+					Message: "pod未能在超时时间内ready",
+				}
+				break
+			}
+		}
+		if diagnosis == nil {
+			return nil
+		}
+	}
+
+	diagnosis.Events = c.getPodEvents(diagnosis.Pod)
+	diagnosis.BuildLogs = c.getBuildLogs(diagnosis.Pod)
+	return diagnosis
+}
+
+// 依据pod中初始容器和应用容器的状态判断pod是否出现了无法自行恢复的故障，
+// 只有容器进入CrashLoopBackOff等等待状态、反复失败达到重启次数阈值或pod已失败时才判定为故障
+func diagnosePod(pod *corev1.Pod) *RolloutDiagnosis {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if diagnosis := diagnoseWaiting(pod.Name, &status); diagnosis != nil {
+			return diagnosis
+		}
+		// 初始容器反复以非0状态码退出，通常意味着编译失败，继续重启也无法恢复
+		if diagnosis := diagnoseRestarts(pod, &status, "InitContainerFailed"); diagnosis != nil {
+			return diagnosis
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if diagnosis := diagnoseWaiting(pod.Name, &status); diagnosis != nil {
+			return diagnosis
+		}
+		if diagnosis := diagnoseRestarts(pod, &status, "RestartLimitExceeded"); diagnosis != nil {
+			return diagnosis
+		}
+	}
+
+	return nil
+}
+
+// 检查容器是否以非0状态码退出，且重启次数已达到阈值或pod已失败而不会再重启
+func diagnoseRestarts(pod *corev1.Pod, status *corev1.ContainerStatus, reason string) *RolloutDiagnosis {
+	if status.RestartCount < fatalRestartCount && pod.Status.Phase != corev1.PodFailed {
+		return nil
+	}
+	for _, terminated := range []*corev1.ContainerStateTerminated{
+		status.State.Terminated, status.LastTerminationState.Terminated} {
+		if terminated != nil && terminated.ExitCode != 0 {
+			return &RolloutDiagnosis{
+				Pod:       pod.Name,
+				Container: status.Name,
+				Reason:    reason,
+				Message: fmt.Sprintf("restarted %d times, exit code %d, %s %s",
+					status.RestartCount, terminated.ExitCode, terminated.Reason, terminated.Message),
+			}
+		}
+	}
+	return nil
+}
+
+// 检查容器是否处于无法自行恢复的等待状态
+func diagnoseWaiting(pod string, status *corev1.ContainerStatus) *RolloutDiagnosis {
+	waiting := status.State.Waiting
+	if waiting == nil || !fatalWaitingReasons[waiting.Reason] {
+		return nil
+	}
+
+	return &RolloutDiagnosis{
+		Pod:       pod,
+		Container: status.Name,
+		Reason:    waiting.Reason,
+		Message:   waiting.Message,
+	}
+}
+
+// 将工作负载的selector转换为筛选其pod的标签选择器
+func podSelector(selector *client_metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	return label.FormatLabels(selector.MatchLabels)
+}
+
+// 判断pod是否处于ready状态
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// 获得pod最近的事件，以"类型 原因: 信息"的形式返回
+func (c *baseKubeController) getPodEvents(pod string) []string {
+	events, err := c.client.CoreV1().Events(c.namespace).List(
+		context.Background(),
+		client_metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.kind=Pod,involvedObject.name=%s", pod),
+		},
+	)
+	if err != nil {
+		return nil
+	}
+
+	items := events.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].LastTimestamp.Before(&items[j].LastTimestamp)
+	})
+	if len(items) > diagnosisEventLimit {
+		items = items[len(items)-diagnosisEventLimit:]
+	}
+
+	result := make([]string, 0, len(items))
+	for _, e := range items {
+		result = append(result, fmt.Sprintf("%s %s: %s", e.Type, e.Reason, e.Message))
+	}
+	return result
+}
+
+// 获得pod中编译初始容器日志的末尾部分，当前容器没有日志时尝试获取上一次运行的日志
+func (c *baseKubeController) getBuildLogs(pod string) string {
+	for _, previous := range []bool{false, true} {
+		tailLines := diagnosisLogTailLines
+		logs, err := c.client.CoreV1().Pods(c.namespace).GetLogs(pod, &corev1.PodLogOptions{
+			Container: buildContainerName,
+			TailLines: &tailLines,
+			Previous:  previous,
+		}).DoRaw(context.Background())
+		if err == nil && len(logs) != 0 {
+			return string(logs)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package kubecontroller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func Test_diagnosePod(t *testing.T) {
+	pod := &corev1.Pod{}
+	pod.Name = "test-dp-0"
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: buildContainerName,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: "test-dp",
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+			},
+		},
+	}
+	if diagnosis := diagnosePod(pod); diagnosis != nil {
+		t.Fatalf("正常初始化的pod被判定为故障:%v", diagnosis)
+	}
+
+	// 编译初始容器单次以非0状态码退出时，重启仍可能恢复
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+	}
+	if diagnosis := diagnosePod(pod); diagnosis != nil {
+		t.Fatalf("初始容器单次失败不应被判定为故障:%v", diagnosis)
+	}
+
+	// 编译初始容器反复以非0状态码退出
+	pod.Status.InitContainerStatuses[0].RestartCount = fatalRestartCount
+	diagnosis := diagnosePod(pod)
+	if diagnosis == nil || diagnosis.Reason != "InitContainerFailed" || diagnosis.Container != buildContainerName {
+		t.Fatalf("未能诊断出初始容器的故障:%v", diagnosis)
+	}
+
+	// 编译初始容器进入CrashLoopBackOff
+	pod.Status.InitContainerStatuses[0].RestartCount = 1
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+	}
+	diagnosis = diagnosePod(pod)
+	if diagnosis == nil || diagnosis.Reason != "CrashLoopBackOff" || diagnosis.Container != buildContainerName {
+		t.Fatalf("未能诊断出初始容器的故障:%v", diagnosis)
+	}
+
+	// 应用容器曾经异常退出一次，但已正常运行
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}
+	pod.Status.ContainerStatuses[0].RestartCount = 1
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+	}
+	if diagnosis := diagnosePod(pod); diagnosis != nil {
+		t.Fatalf("应用容器单次重启不应被判定为故障:%v", diagnosis)
+	}
+
+	// 应用容器拉取镜像失败
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+	}
+	diagnosis = diagnosePod(pod)
+	if diagnosis == nil || diagnosis.Reason != "ImagePullBackOff" || diagnosis.Container != "test-dp" {
+		t.Fatalf("未能诊断出应用容器的故障:%v", diagnosis)
+	}
+}
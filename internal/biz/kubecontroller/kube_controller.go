@@ -3,8 +3,10 @@ package kubecontroller
 
 import (
 	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
 	v1 "gitee.com/moyusir/util/api/util/v1"
 	"github.com/go-kratos/kratos/v2/errors"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -12,11 +14,14 @@ import (
 	client_corev1 "k8s.io/client-go/applyconfigurations/core/v1"
 	client_metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	"k8s.io/utils/pointer"
+	"sort"
 	"time"
 )
 
 type KubeController struct {
 	*baseKubeController
+	// 记录对集群的每次修改，为nil时不记录
+	Recorder audit.Recorder
 }
 
 // BaseDeployOption 部署时的基本配置
@@ -38,6 +43,12 @@ type BaseDeployOption struct {
 	// 服务运行时配置
 	// 运行服务使用的镜像
 	Image string
+	// 初始容器中向编译中心发起编译请求的客户端镜像
+	BuildImage string
+	// 保存用户influxdb token的secret，不为空时以环境变量的形式注入应用容器
+	InfluxdbSecret string
+	// 连接时序数据库所需的非敏感信息，以环境变量名为键注入应用容器
+	TimeSeriesEnv map[string]string
 }
 type DataProcessingDeployOption struct {
 	BaseDeployOption
@@ -49,8 +60,20 @@ type DataCollectionDeployOption struct {
 	AppDomainName string
 }
 
+// ServiceImages 用户的数据收集和数据处理服务使用的镜像
+type ServiceImages struct {
+	DataCollection    string
+	DataProcessing    string
+	CompilationClient string
+}
+
 func NewKubeController(namespace string) (*KubeController, error) {
-	controller, err := newBaseKubeController(namespace)
+	return NewKubeControllerWithKubeconfig("", namespace)
+}
+
+// NewKubeControllerWithKubeconfig 依据kubeconfig文件访问指定的集群，kubeconfig为空时访问当前所在的集群
+func NewKubeControllerWithKubeconfig(kubeconfig, namespace string) (*KubeController, error) {
+	controller, err := newBaseKubeController(kubeconfig, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +82,16 @@ func NewKubeController(namespace string) (*KubeController, error) {
 }
 
 // Unregister 清空用户相关的k8s资源
-func (c *KubeController) Unregister(username string) error {
+func (c *KubeController) Unregister(username string) (err error) {
 	labelSelector := "user=" + username
-	types := []string{"Deployment", "StatefulSet", "Service", "ConfigMap"}
+	types := []string{"Deployment", "StatefulSet", "Service", "ConfigMap", "Secret"}
+	defer func() {
+		targets := make([]string, len(types))
+		for i, t := range types {
+			targets[i] = t + "?" + labelSelector
+		}
+		audit.Record(c.Recorder, "kube.unregister", username, targets, err)
+	}()
 
 	for _, resourceType := range types {
 		err := c.DeleteResources(resourceType, labelSelector)
@@ -75,7 +105,12 @@ func (c *KubeController) Unregister(username string) error {
 
 func (c *KubeController) CreateConfigMapOfRegisterInfo(
 	username string,
-	states []*v1.DeviceStateRegisterInfo, configs []*v1.DeviceConfigRegisterInfo) (*corev1.ConfigMap, error) {
+	states []*v1.DeviceStateRegisterInfo, configs []*v1.DeviceConfigRegisterInfo) (cm *corev1.ConfigMap, err error) {
+	defer func() {
+		audit.Record(c.Recorder, "kube.create_config_map", username,
+			[]string{"ConfigMap/" + username + "-register-info"}, err)
+	}()
+
 	// 以user:username为label,username-state-register-info为名称创建cm
 	// 保存注册信息的json数据
 	stateJson, err := json.Marshal(states)
@@ -95,7 +130,8 @@ func (c *KubeController) CreateConfigMapOfRegisterInfo(
 }
 
 // DeployDataProcessingService 部署数据处理服务，返回指向应用容器endpoint的service组件的信息，提供给网关注册使用
-func (c *KubeController) DeployDataProcessingService(option *DataProcessingDeployOption) (*corev1.Service, error) {
+func (c *KubeController) DeployDataProcessingService(
+	option *DataProcessingDeployOption) (service *corev1.Service, err error) {
 	if option == nil {
 		return nil, errors.New(500, "option is nil", "")
 	}
@@ -103,9 +139,13 @@ func (c *KubeController) DeployDataProcessingService(option *DataProcessingDeplo
 	// deployment以<用户名>-dp命名，以app:<用户名>-dp和user:<username>为label
 	name := fmt.Sprintf("%s-dp", option.Username)
 	label := map[string]string{"app": name, "user": option.Username}
+	defer func() {
+		audit.Record(c.Recorder, "kube.deploy_data_processing", option.Username,
+			[]string{"Deployment/" + name, "Service/" + name}, err)
+	}()
 
 	deploymentSpec := getDataProcessingDeploymentSpec(name, label, option)
-	_, err := c.CreateDeployment(name, label, option.Timeout, deploymentSpec)
+	_, err = c.CreateDeployment(name, label, option.Timeout, deploymentSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +169,8 @@ func (c *KubeController) DeployDataProcessingService(option *DataProcessingDeplo
 }
 
 // DeployDataCollectionService 部署数据收集服务,返回指向应用容器endpoint的service组件的信息，提供给网关注册使用
-func (c *KubeController) DeployDataCollectionService(option *DataCollectionDeployOption) (*corev1.Service, error) {
+func (c *KubeController) DeployDataCollectionService(
+	option *DataCollectionDeployOption) (service *corev1.Service, err error) {
 	if option == nil {
 		return nil, errors.New(500, "option is nil", "")
 	}
@@ -140,6 +181,12 @@ func (c *KubeController) DeployDataCollectionService(option *DataCollectionDeplo
 
 	// 先创建statefulSet所需的无头服务，以<用户名>-dc-headless命名，以user:<username>为label
 	headlessServiceName := fmt.Sprintf("%s-dc-headless", option.Username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.deploy_data_collection", option.Username, []string{
+			"Service/" + headlessServiceName, "StatefulSet/" + name, "Service/" + name,
+		}, err)
+	}()
+
 	serviceLabel := map[string]string{"user": option.Username}
 	serviceType := corev1.ServiceTypeClusterIP
 	serviceSpec := client_corev1.ServiceSpecApplyConfiguration{
@@ -176,6 +223,95 @@ func (c *KubeController) DeployDataCollectionService(option *DataCollectionDeplo
 	return c.CreateService(name, serviceLabel, &serviceSpec)
 }
 
+// GetServiceImages 查询用户的数据收集和数据处理服务当前使用的镜像
+func (c *KubeController) GetServiceImages(username string) (*ServiceImages, error) {
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+
+	statefulSet, err := c.GetStatefulSet(dcName)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := c.GetDeployment(dpName)
+	if err != nil {
+		return nil, err
+	}
+
+	images := new(ServiceImages)
+	for _, container := range statefulSet.Spec.Template.Spec.Containers {
+		if container.Name == dcName {
+			images.DataCollection = container.Image
+		}
+	}
+	for _, container := range statefulSet.Spec.Template.Spec.InitContainers {
+		if container.Name == buildContainerName {
+			images.CompilationClient = container.Image
+		}
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == dpName {
+			images.DataProcessing = container.Image
+		}
+	}
+
+	return images, nil
+}
+
+// UpdateServiceImages 将用户的数据收集和数据处理服务更新为指定的镜像，并等待滚动更新完成
+func (c *KubeController) UpdateServiceImages(
+	username string, images *ServiceImages, timeout time.Duration) (err error) {
+	if images == nil {
+		return errors.New(500, "images is nil", "")
+	}
+
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.update_images", username,
+			[]string{"StatefulSet/" + dcName, "Deployment/" + dpName}, err)
+	}()
+
+	// 利用协程同时更新两个服务，通过strategic merge patch依据容器名只替换镜像字段
+	eg := &errgroup.Group{}
+	eg.Go(func() error {
+		patch, err := getImagePatch(dcName, images.DataCollection, images.CompilationClient)
+		if err != nil {
+			return err
+		}
+		_, err = c.PatchStatefulSet(dcName, patch, timeout)
+		return err
+	})
+	eg.Go(func() error {
+		patch, err := getImagePatch(dpName, images.DataProcessing, images.CompilationClient)
+		if err != nil {
+			return err
+		}
+		_, err = c.PatchDeployment(dpName, patch, timeout)
+		return err
+	})
+
+	return eg.Wait()
+}
+
+// 辅助函数，创建替换应用容器以及编译初始容器镜像的patch
+func getImagePatch(containerName, image, buildImage string) ([]byte, error) {
+	type container struct {
+		Name  string `json:"name"`
+		Image string `json:"image"`
+	}
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers":     []container{{Name: containerName, Image: image}},
+					"initContainers": []container{{Name: buildContainerName, Image: buildImage}},
+				},
+			},
+		},
+	}
+	return json.Marshal(patch)
+}
+
 // 辅助函数，创建dataProcessing服务的部署配置
 func getDataProcessingDeploymentSpec(name string, label map[string]string, option *DataProcessingDeployOption) *client_appsv1.DeploymentSpecApplyConfiguration {
 	// 配置部署选项
@@ -278,8 +414,8 @@ func getDataProcessingDeploymentSpec(name string, label map[string]string, optio
 				InitContainers: []client_corev1.ContainerApplyConfiguration{
 					// 负责向编译中心发出编译请求，获得二进制可执行程序的initContainer
 					{
-						Name:  pointer.String("build"),
-						Image: pointer.String("moyusir233/graduation-design:compilation-client"),
+						Name:  pointer.String(buildContainerName),
+						Image: &option.BuildImage,
 						Args: []string{
 							"-u", option.Username,
 							"-address", option.CompilationCenterAddress,
@@ -319,12 +455,7 @@ func getDataProcessingDeploymentSpec(name string, label map[string]string, optio
 								MountPath: pointer.String("/etc/app-configs"),
 							},
 						},
-						Env: []client_corev1.EnvVarApplyConfiguration{
-							{
-								Name:  pointer.String("USERNAME"),
-								Value: pointer.String(option.Username),
-							},
-						},
+						Env: serviceEnv(&option.BaseDeployOption),
 						Ports: []client_corev1.ContainerPortApplyConfiguration{
 							{
 								ContainerPort: pointer.Int32(8000),
@@ -440,8 +571,8 @@ func getDataCollectionStatefulSetSpec(
 				InitContainers: []client_corev1.ContainerApplyConfiguration{
 					// 负责向编译中心发出编译请求，获得二进制可执行程序的initContainer
 					{
-						Name:  pointer.String("build"),
-						Image: pointer.String("moyusir233/graduation-design:compilation-client"),
+						Name:  pointer.String(buildContainerName),
+						Image: &option.BuildImage,
 						Args: []string{
 							"-u", option.Username,
 							"-address", option.CompilationCenterAddress,
@@ -481,12 +612,7 @@ func getDataCollectionStatefulSetSpec(
 								MountPath: pointer.String("/etc/app-configs"),
 							},
 						},
-						Env: []client_corev1.EnvVarApplyConfiguration{
-							{
-								Name:  pointer.String("USERNAME"),
-								Value: pointer.String(option.Username),
-							},
-						},
+						Env: serviceEnv(&option.BaseDeployOption),
 						Ports: []client_corev1.ContainerPortApplyConfiguration{
 							{
 								ContainerPort: pointer.Int32(8000),
@@ -522,3 +648,29 @@ func getDataCollectionStatefulSetSpec(
 		ServiceName: &headlessServiceName,
 	}
 }
+
+// 辅助函数，创建应用容器的环境变量
+func serviceEnv(option *BaseDeployOption) []client_corev1.EnvVarApplyConfiguration {
+	env := []client_corev1.EnvVarApplyConfiguration{
+		{
+			Name:  pointer.String("USERNAME"),
+			Value: pointer.String(option.Username),
+		},
+	}
+	// 按名称排序，避免每次部署生成的pod模板不同而触发滚动更新
+	names := make([]string, 0, len(option.TimeSeriesEnv))
+	for name := range option.TimeSeriesEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, client_corev1.EnvVarApplyConfiguration{
+			Name:  pointer.String(name),
+			Value: pointer.String(option.TimeSeriesEnv[name]),
+		})
+	}
+	if option.InfluxdbSecret != "" {
+		env = append(env, influxdbTokenEnv(option.InfluxdbSecret))
+	}
+	return env
+}
@@ -0,0 +1,109 @@
+package kubecontroller
+
+import (
+	"fmt"
+	"io/ioutil"
+	"k8s.io/client-go/rest"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+)
+
+// kubeconfig文件中需要用到的字段，格式参考:
+// https://kubernetes.io/zh/docs/concepts/configuration/organize-cluster-access-kubeconfig/
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData []byte `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+			TLSServerName            string `json:"tls-server-name"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData []byte `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         []byte `json:"client-key-data"`
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			Username              string `json:"username"`
+			Password              string `json:"password"`
+		} `json:"user"`
+	} `json:"users"`
+	Contexts []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+}
+
+// 由kubeconfig文件的current-context建立访问api server的config实例，
+// 只支持证书、token以及basic auth的认证方式，文件中的相对路径以kubeconfig文件所在的目录为基准
+func loadKubeconfig(path string) (*rest.Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseKubeconfig(data, filepath.Dir(path))
+}
+
+func parseKubeconfig(data []byte, dir string) (*rest.Config, error) {
+	kc := new(kubeconfig)
+	if err := yaml.Unmarshal(data, kc); err != nil {
+		return nil, fmt.Errorf("解析kubeconfig时发生了错误: %w", err)
+	}
+
+	var clusterName, userName string
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+		}
+	}
+	if clusterName == "" {
+		return nil, fmt.Errorf("kubeconfig中不存在current-context: %s", kc.CurrentContext)
+	}
+
+	resolve := func(file string) string {
+		if file == "" || filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(dir, file)
+	}
+
+	config := new(rest.Config)
+	found := false
+	for _, c := range kc.Clusters {
+		if c.Name == clusterName {
+			found = true
+			config.Host = c.Cluster.Server
+			config.CAFile = resolve(c.Cluster.CertificateAuthority)
+			config.CAData = c.Cluster.CertificateAuthorityData
+			config.Insecure = c.Cluster.InsecureSkipTLSVerify
+			config.ServerName = c.Cluster.TLSServerName
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig中不存在集群: %s", clusterName)
+	}
+	for _, u := range kc.Users {
+		if u.Name == userName {
+			config.CertFile = resolve(u.User.ClientCertificate)
+			config.CertData = u.User.ClientCertificateData
+			config.KeyFile = resolve(u.User.ClientKey)
+			config.KeyData = u.User.ClientKeyData
+			config.BearerToken = u.User.Token
+			config.BearerTokenFile = resolve(u.User.TokenFile)
+			config.Username = u.User.Username
+			config.Password = u.User.Password
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,59 @@
+package kubecontroller
+
+import (
+	"testing"
+)
+
+func Test_parseKubeconfig(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Config
+current-context: cluster-b
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com:6443
+- name: b
+  cluster:
+    server: https://b.example.com:6443
+    certificate-authority: certs/ca.crt
+contexts:
+- name: cluster-a
+  context:
+    cluster: a
+    user: admin-a
+- name: cluster-b
+  context:
+    cluster: b
+    user: admin-b
+users:
+- name: admin-a
+  user:
+    token: token-a
+- name: admin-b
+  user:
+    client-certificate-data: Y2VydA==
+    client-key: /etc/keys/b.key
+`)
+	config, err := parseKubeconfig(data, "/etc/kube")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Host != "https://b.example.com:6443" {
+		t.Fatalf("应使用current-context对应的集群:%v", config.Host)
+	}
+	if config.CAFile != "/etc/kube/certs/ca.crt" {
+		t.Fatalf("相对路径应以kubeconfig所在目录为基准:%v", config.CAFile)
+	}
+	if string(config.CertData) != "cert" || config.KeyFile != "/etc/keys/b.key" {
+		t.Fatalf("用户证书解析错误:%v %v", string(config.CertData), config.KeyFile)
+	}
+	if config.BearerToken != "" {
+		t.Fatal("不应使用其他用户的token")
+	}
+
+	if _, err = parseKubeconfig([]byte("current-context: none"), ""); err == nil {
+		t.Fatal("current-context不存在时应返回错误")
+	}
+}
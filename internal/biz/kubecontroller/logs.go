@@ -0,0 +1,148 @@
+package kubecontroller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/errors"
+	corev1 "k8s.io/api/core/v1"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 日志流中缓存的日志行数
+const logStreamBufferSize = 64
+
+// LogStreamOption 获取用户服务日志流时的配置
+type LogStreamOption struct {
+	// 服务类型，dc或dp，为空时获取两个服务的日志
+	Service string
+	// 容器类型，app为应用容器，build为编译初始容器，为空时获取两类容器的日志
+	Container string
+	// 是否持续跟踪新产生的日志
+	Follow bool
+	// 只获取最近一段时间内的日志，为nil时不限制
+	SinceSeconds *int64
+	// 只获取每个容器最后若干行日志，为nil时不限制
+	TailLines *int64
+}
+
+// LogLine 日志流中的一行日志，多个副本以及容器的日志汇聚在同一日志流中，以pod与容器名区分
+type LogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	// 日志产生的时间，由k8s在日志行首添加的时间戳得到
+	Time time.Time `json:"time,omitempty"`
+	Line string    `json:"line,omitempty"`
+	// 获取该容器日志时出现的错误，如容器还未启动
+	Error string `json:"error,omitempty"`
+}
+
+// StreamLogs 获取用户服务各个副本中应用容器以及编译初始容器的日志，汇聚到同一个channel中返回，
+// 所有容器的日志读取完毕或ctx被取消后channel被关闭
+func (c *KubeController) StreamLogs(
+	ctx context.Context, username string, option *LogStreamOption) (<-chan LogLine, error) {
+	if option == nil {
+		option = new(LogStreamOption)
+	}
+
+	selector := "user=" + username
+	if option.Service != "" {
+		selector = fmt.Sprintf("app=%s-%s,%s", username, option.Service, selector)
+	}
+	pods, err := c.client.CoreV1().Pods(c.namespace).List(
+		ctx, client_metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, errors.NotFound("STREAM_LOGS_FAIL", "用户服务的pod不存在")
+	}
+
+	lines := make(chan LogLine, logStreamBufferSize)
+	wg := new(sync.WaitGroup)
+	for _, pod := range pods.Items {
+		for _, container := range logContainers(&pod, option.Container) {
+			wg.Add(1)
+			go func(pod, container string) {
+				defer wg.Done()
+				c.streamContainerLogs(ctx, pod, container, option, lines)
+			}(pod.Name, container)
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	return lines, nil
+}
+
+// 依据容器类型获得pod中需要获取日志的容器
+func logContainers(pod *corev1.Pod, containerType string) []string {
+	var containers []string
+	if containerType != "app" {
+		for _, c := range pod.Spec.InitContainers {
+			if c.Name == buildContainerName {
+				containers = append(containers, c.Name)
+			}
+		}
+	}
+	if containerType != "build" {
+		for _, c := range pod.Spec.Containers {
+			containers = append(containers, c.Name)
+		}
+	}
+	return containers
+}
+
+// 读取单个容器的日志并逐行写入lines
+func (c *KubeController) streamContainerLogs(
+	ctx context.Context, pod, container string, option *LogStreamOption, lines chan<- LogLine) {
+	send := func(line LogLine) bool {
+		select {
+		case lines <- line:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	stream, err := c.client.CoreV1().Pods(c.namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:    container,
+		Follow:       option.Follow,
+		SinceSeconds: option.SinceSeconds,
+		TailLines:    option.TailLines,
+		Timestamps:   true,
+	}).Stream(ctx)
+	if err != nil {
+		send(LogLine{Pod: pod, Container: container, Error: err.Error()})
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if !send(parseLogLine(pod, container, scanner.Text())) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		send(LogLine{Pod: pod, Container: container, Error: err.Error()})
+	}
+}
+
+// 解析带有RFC3339时间戳前缀的日志行
+func parseLogLine(pod, container, text string) LogLine {
+	line := LogLine{Pod: pod, Container: container, Line: text}
+	if i := strings.IndexByte(text, ' '); i != -1 {
+		if t, err := time.Parse(time.RFC3339Nano, text[:i]); err == nil {
+			line.Time = t
+			line.Line = text[i+1:]
+		}
+	}
+	return line
+}
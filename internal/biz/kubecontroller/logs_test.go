@@ -0,0 +1,35 @@
+package kubecontroller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func Test_logContainers(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: buildContainerName}},
+		Containers:     []corev1.Container{{Name: "test-dc"}},
+	}}
+
+	if c := logContainers(pod, ""); len(c) != 2 {
+		t.Fatalf("未指定容器类型时应获取所有容器的日志:%v", c)
+	}
+	if c := logContainers(pod, "build"); len(c) != 1 || c[0] != buildContainerName {
+		t.Fatalf("应只获取编译初始容器的日志:%v", c)
+	}
+	if c := logContainers(pod, "app"); len(c) != 1 || c[0] != "test-dc" {
+		t.Fatalf("应只获取应用容器的日志:%v", c)
+	}
+}
+
+func Test_parseLogLine(t *testing.T) {
+	line := parseLogLine("p", "c", "2022-04-01T08:00:00.123456789Z compile finished")
+	if line.Line != "compile finished" || line.Time.IsZero() {
+		t.Fatalf("应分离日志行首的时间戳:%+v", line)
+	}
+
+	line = parseLogLine("p", "c", "no timestamp")
+	if line.Line != "no timestamp" || !line.Time.IsZero() {
+		t.Fatalf("没有时间戳时应保留原始日志:%+v", line)
+	}
+}
@@ -0,0 +1,60 @@
+package kubecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// metrics.k8s.io/v1beta1 PodMetricsList中需要用到的字段，
+// client-go中不包含metrics api的客户端，因此直接通过rest客户端访问
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage map[string]resource.Quantity `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// PodUsage 用户服务所有pod当前的资源使用量
+type PodUsage struct {
+	// cpu使用量，单位为核
+	CPUCores float64
+	// 内存使用量，单位为字节
+	MemoryBytes int64
+}
+
+// GetPodUsage 通过metrics api查询用户服务所有pod当前的cpu与内存使用量之和
+func (c *KubeController) GetPodUsage(username string) (*PodUsage, error) {
+	data, err := c.client.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", c.namespace, "pods").
+		Param("labelSelector", "user="+username).
+		DoRaw(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("查询pod的资源使用量时发生了错误: %w", err)
+	}
+
+	return parsePodUsage(data)
+}
+
+// 累加metrics api返回的所有容器的资源使用量
+func parsePodUsage(data []byte) (*PodUsage, error) {
+	list := new(podMetricsList)
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("解析pod的资源使用量时发生了错误: %w", err)
+	}
+
+	usage := new(PodUsage)
+	for _, pod := range list.Items {
+		for _, container := range pod.Containers {
+			if cpu, ok := container.Usage["cpu"]; ok {
+				usage.CPUCores += cpu.AsApproximateFloat64()
+			}
+			if memory, ok := container.Usage["memory"]; ok {
+				usage.MemoryBytes += memory.Value()
+			}
+		}
+	}
+	return usage, nil
+}
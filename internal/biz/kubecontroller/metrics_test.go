@@ -0,0 +1,27 @@
+package kubecontroller
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_parsePodUsage(t *testing.T) {
+	data := []byte(`{
+  "kind": "PodMetricsList",
+  "apiVersion": "metrics.k8s.io/v1beta1",
+  "items": [
+    {"metadata": {"name": "a-dc-0"}, "containers": [{"name": "a-dc", "usage": {"cpu": "250m", "memory": "64Mi"}}]},
+    {"metadata": {"name": "a-dc-1"}, "containers": [{"name": "a-dc", "usage": {"cpu": "1500000n", "memory": "1Ki"}}]}
+  ]
+}`)
+	usage, err := parsePodUsage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(usage.CPUCores-0.2515) > 1e-9 {
+		t.Fatalf("cpu使用量应为所有容器之和，实际为:%v", usage.CPUCores)
+	}
+	if usage.MemoryBytes != 64*1024*1024+1024 {
+		t.Fatalf("内存使用量应为所有容器之和，实际为:%v", usage.MemoryBytes)
+	}
+}
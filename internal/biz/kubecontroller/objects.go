@@ -0,0 +1,118 @@
+package kubecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// client-go中不包含gateway api以及envoy gateway的客户端，因此这些对象以json的形式通过rest客户端访问，
+// 键为对象的kind，值为对象在api server中的路径前缀以及资源名
+var objectResources = map[string]struct{ prefix, resource string }{
+	"Secret":         {"/api/v1", "secrets"},
+	"HTTPRoute":      {"/apis/gateway.networking.k8s.io/v1", "httproutes"},
+	"GRPCRoute":      {"/apis/gateway.networking.k8s.io/v1", "grpcroutes"},
+	"SecurityPolicy": {"/apis/gateway.envoyproxy.io/v1alpha1", "securitypolicies"},
+}
+
+// 查询对象在api server中的路径，name为空时为对象列表的路径
+func (c *baseKubeController) objectPath(kind, name string) ([]string, error) {
+	r, ok := objectResources[kind]
+	if !ok {
+		return nil, fmt.Errorf("不支持的对象类型:%s", kind)
+	}
+	path := []string{r.prefix, "namespaces", c.namespace, r.resource}
+	if name != "" {
+		path = append(path, name)
+	}
+	return path, nil
+}
+
+// ApplyObject 以server side apply的方式创建或更新对象，对象中未包含的由service center管理的字段将被删除
+func (c *baseKubeController) ApplyObject(object map[string]interface{}) error {
+	kind, _ := object["kind"].(string)
+	metadata, _ := object["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	path, err := c.objectPath(kind, name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Discovery().RESTClient().Patch(types.ApplyPatchType).
+		AbsPath(path...).
+		Param("fieldManager", fieldManager).
+		Param("force", "true").
+		Body(data).
+		Do(context.Background()).
+		Error()
+	if err != nil {
+		return fmt.Errorf("更新%s %s时发生了错误: %w", kind, name, err)
+	}
+	return nil
+}
+
+// GetObject 查询指定的对象，对象不存在时返回nil
+func (c *baseKubeController) GetObject(kind, name string) (map[string]interface{}, error) {
+	path, err := c.objectPath(kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.client.Discovery().RESTClient().Get().AbsPath(path...).DoRaw(context.Background())
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询%s %s时发生了错误: %w", kind, name, err)
+	}
+
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("解析%s %s时发生了错误: %w", kind, name, err)
+	}
+	return object, nil
+}
+
+// ListObjects 依据label查询指定类型的所有对象
+func (c *baseKubeController) ListObjects(kind, labelSelector string) ([]map[string]interface{}, error) {
+	path, err := c.objectPath(kind, "")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.client.Discovery().RESTClient().Get().
+		AbsPath(path...).
+		Param("labelSelector", labelSelector).
+		DoRaw(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("查询%s列表时发生了错误: %w", kind, err)
+	}
+
+	list := &struct {
+		Items []map[string]interface{} `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("解析%s列表时发生了错误: %w", kind, err)
+	}
+	return list.Items, nil
+}
+
+// DeleteObject 删除指定的对象，对象不存在时视为删除成功
+func (c *baseKubeController) DeleteObject(kind, name string) error {
+	path, err := c.objectPath(kind, name)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Discovery().RESTClient().Delete().AbsPath(path...).Do(context.Background()).Error()
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("删除%s %s时发生了错误: %w", kind, name, err)
+	}
+	return nil
+}
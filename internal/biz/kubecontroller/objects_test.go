@@ -0,0 +1,27 @@
+package kubecontroller
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_objectPath(t *testing.T) {
+	c := &baseKubeController{namespace: "test"}
+	path, err := c.objectPath("GRPCRoute", "a-dc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := strings.Join(path, "/"); p != "/apis/gateway.networking.k8s.io/v1/namespaces/test/grpcroutes/a-dc" {
+		t.Fatalf("路由的路径错误:%v", p)
+	}
+	path, err = c.objectPath("Secret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := strings.Join(path, "/"); p != "/api/v1/namespaces/test/secrets" {
+		t.Fatalf("secret列表的路径错误:%v", p)
+	}
+	if _, err := c.objectPath("Ingress", "a"); err == nil {
+		t.Fatal("不支持的对象类型应返回错误")
+	}
+}
@@ -0,0 +1,129 @@
+package kubecontroller
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"time"
+)
+
+var (
+	// 等待滚动更新完成超时时返回的错误
+	errWaitTimeout = errors.New(500, "WAIT_ROLLOUT_TIMEOUT", "timed out waiting for the rollout")
+	// watch过程中出现错误事件或资源被删除时返回的错误
+	errWaitFail = errors.New(500, "WAIT_ROLLOUT_FAIL", "the watched resource failed or was deleted")
+	// 诊断发现pod出现无法自行恢复的故障时返回的错误
+	errWaitDiagnosed = errors.New(500, "WAIT_ROLLOUT_DIAGNOSED", "the pods of the resource failed")
+)
+
+// 等待滚动更新期间检查pod状态的间隔
+const diagnoseInterval = 5 * time.Second
+
+// 对满足筛选条件的deployment执行watch，直到condition返回true、出现错误事件或超时，
+// 等待期间定期检查满足podSelector的pod，发现无法自行恢复的故障时提前返回，
+// 返回错误时一并返回对pod的诊断信息
+func (c *baseKubeController) waitForDeployment(
+	options client_metav1.ListOptions,
+	podSelector string,
+	timeout time.Duration,
+	condition func(d *appsv1.Deployment) bool) (*RolloutDiagnosis, error) {
+	// 标签选择器和字段选择器的格式参考:
+	// https://kubernetes.io/zh/docs/concepts/overview/working-with-objects/labels/
+	// https://kubernetes.io/zh/docs/concepts/overview/working-with-objects/field-selectors/
+	// watch响应的object的包含的字段与格式参考:
+	// https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#watch-deployment-v1-apps
+	options.Watch = true
+	w, err := c.client.AppsV1().Deployments(c.namespace).Watch(context.Background(), options)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+
+	timer := time.After(timeout)
+	ticker := time.NewTicker(diagnoseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer:
+			return c.diagnosePods(podSelector, true), errWaitTimeout
+		case <-ticker.C:
+			if diagnosis := c.diagnosePods(podSelector, false); diagnosis != nil {
+				return diagnosis, errWaitDiagnosed
+			}
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error || event.Type == watch.Deleted {
+				return c.diagnosePods(podSelector, true), errWaitFail
+			}
+
+			if d, ok := event.Object.(*appsv1.Deployment); ok && condition(d) {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// 对满足筛选条件的statefulSet执行watch，直到condition返回true、出现错误事件或超时，
+// 等待期间定期检查满足podSelector的pod，发现无法自行恢复的故障时提前返回，
+// 返回错误时一并返回对pod的诊断信息
+func (c *baseKubeController) waitForStatefulSet(
+	options client_metav1.ListOptions,
+	podSelector string,
+	timeout time.Duration,
+	condition func(s *appsv1.StatefulSet) bool) (*RolloutDiagnosis, error) {
+	// watch响应的object的包含的字段与格式参考:
+	// https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#statefulset-v1-apps
+	options.Watch = true
+	w, err := c.client.AppsV1().StatefulSets(c.namespace).Watch(context.Background(), options)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+
+	timer := time.After(timeout)
+	ticker := time.NewTicker(diagnoseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer:
+			return c.diagnosePods(podSelector, true), errWaitTimeout
+		case <-ticker.C:
+			if diagnosis := c.diagnosePods(podSelector, false); diagnosis != nil {
+				return diagnosis, errWaitDiagnosed
+			}
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error || event.Type == watch.Deleted {
+				return c.diagnosePods(podSelector, true), errWaitFail
+			}
+
+			if s, ok := event.Object.(*appsv1.StatefulSet); ok && condition(s) {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// 判断deployment的滚动更新是否完成，即控制器已观察到最新的spec，且所有副本都已更新并ready
+func deploymentRolledOut(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.ReadyReplicas == replicas
+}
+
+// 判断statefulSet的滚动更新是否完成，即控制器已观察到最新的spec，且所有副本都已更新到最新的revision并ready
+func statefulSetRolledOut(s *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	return s.Status.ObservedGeneration >= s.Generation &&
+		s.Status.UpdatedReplicas == replicas &&
+		s.Status.ReadyReplicas == replicas &&
+		s.Status.CurrentRevision == s.Status.UpdateRevision
+}
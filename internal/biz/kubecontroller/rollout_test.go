@@ -0,0 +1,83 @@
+package kubecontroller
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+func Test_deploymentRolledOut(t *testing.T) {
+	d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32(2)}}
+	d.Generation = 2
+	d.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 1,
+		Replicas:           2,
+		UpdatedReplicas:    2,
+		ReadyReplicas:      2,
+	}
+	if deploymentRolledOut(d) {
+		t.Fatal("控制器还未观察到最新的spec时不应视为更新完成")
+	}
+
+	d.Status.ObservedGeneration = 2
+	d.Status.Replicas = 3
+	if deploymentRolledOut(d) {
+		t.Fatal("旧的副本还未终止时不应视为更新完成")
+	}
+
+	d.Status.Replicas = 2
+	if !deploymentRolledOut(d) {
+		t.Fatal("所有副本都已更新并ready时应视为更新完成")
+	}
+}
+
+func Test_statefulSetRolledOut(t *testing.T) {
+	s := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: pointer.Int32(2)}}
+	s.Generation = 1
+	s.Status = appsv1.StatefulSetStatus{
+		ObservedGeneration: 1,
+		UpdatedReplicas:    2,
+		ReadyReplicas:      2,
+		CurrentRevision:    "old",
+		UpdateRevision:     "new",
+	}
+	if statefulSetRolledOut(s) {
+		t.Fatal("revision还未切换时不应视为更新完成")
+	}
+
+	s.Status.CurrentRevision = "new"
+	if !statefulSetRolledOut(s) {
+		t.Fatal("所有副本都已更新并ready时应视为更新完成")
+	}
+}
+
+func Test_getImagePatch(t *testing.T) {
+	patch, err := getImagePatch("test-dp", "dp:v2", "client:v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers     []map[string]string `json:"containers"`
+					InitContainers []map[string]string `json:"initContainers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	err = json.Unmarshal(patch, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	podSpec := result.Spec.Template.Spec
+	if podSpec.Containers[0]["name"] != "test-dp" || podSpec.Containers[0]["image"] != "dp:v2" {
+		t.Fatalf("应用容器的patch错误:%v", podSpec.Containers)
+	}
+	if podSpec.InitContainers[0]["name"] != "build" || podSpec.InitContainers[0]["image"] != "client:v2" {
+		t.Fatalf("编译初始容器的patch错误:%v", podSpec.InitContainers)
+	}
+}
@@ -0,0 +1,113 @@
+package kubecontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	client_corev1 "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/pointer"
+	"time"
+)
+
+const (
+	// InfluxdbTokenEnv 用户服务容器中保存influxdb token的环境变量
+	InfluxdbTokenEnv = "INFLUXDB_TOKEN"
+	// secret中保存influxdb token的键
+	influxdbTokenKey = "token"
+	// 轮换token时在pod模板的注解中记录轮换的时间，使工作负载滚动更新以读取新的token
+	influxdbTokenRotatedAnnotation = "service-center/influxdb-token-rotated-at"
+)
+
+// InfluxdbSecretName 保存用户influxdb token的secret名
+func InfluxdbSecretName(username string) string {
+	return username + "-influxdb"
+}
+
+// CreateInfluxdbSecret 创建或更新保存用户influxdb token的secret，以user:<username>为label，随用户注销一同删除
+func (c *KubeController) CreateInfluxdbSecret(username, token string) (secret *corev1.Secret, err error) {
+	name := InfluxdbSecretName(username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.create_influxdb_secret", username, []string{"Secret/" + name}, err)
+	}()
+
+	return c.CreateSecret(name, map[string]string{"user": username},
+		map[string][]byte{influxdbTokenKey: []byte(token)})
+}
+
+// RotateInfluxdbToken 将secret中的influxdb token更新为token，然后滚动更新用户的数据收集和数据处理服务，
+// 并等待滚动更新完成，使服务使用新的token，注册时未注入token的服务同时注入token
+func (c *KubeController) RotateInfluxdbToken(username, token string, timeout time.Duration) (err error) {
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.rotate_influxdb_token", username, []string{
+			"Secret/" + InfluxdbSecretName(username), "StatefulSet/" + dcName, "Deployment/" + dpName,
+		}, err)
+	}()
+
+	_, err = c.CreateInfluxdbSecret(username, token)
+	if err != nil {
+		return err
+	}
+
+	rotatedAt := time.Now().UTC().Format(time.RFC3339)
+	eg := &errgroup.Group{}
+	eg.Go(func() error {
+		patch, err := influxdbTokenPatch(dcName, InfluxdbSecretName(username), rotatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = c.PatchStatefulSet(dcName, patch, timeout)
+		return err
+	})
+	eg.Go(func() error {
+		patch, err := influxdbTokenPatch(dpName, InfluxdbSecretName(username), rotatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = c.PatchDeployment(dpName, patch, timeout)
+		return err
+	})
+
+	return eg.Wait()
+}
+
+// 辅助函数，创建由secret读取influxdb token的环境变量
+func influxdbTokenEnv(secretName string) client_corev1.EnvVarApplyConfiguration {
+	return client_corev1.EnvVarApplyConfiguration{
+		Name: pointer.String(InfluxdbTokenEnv),
+		ValueFrom: &client_corev1.EnvVarSourceApplyConfiguration{
+			SecretKeyRef: &client_corev1.SecretKeySelectorApplyConfiguration{
+				LocalObjectReferenceApplyConfiguration: client_corev1.LocalObjectReferenceApplyConfiguration{
+					Name: pointer.String(secretName),
+				},
+				Key: pointer.String(influxdbTokenKey),
+			},
+		},
+	}
+}
+
+// 辅助函数，创建为应用容器注入influxdb token环境变量并记录轮换时间的strategic merge patch，
+// 环境变量依据名称合并，因此可以重复应用
+func influxdbTokenPatch(containerName, secretName, rotatedAt string) ([]byte, error) {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{influxdbTokenRotatedAnnotation: rotatedAt},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": containerName,
+							"env":  []interface{}{influxdbTokenEnv(secretName)},
+						},
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,72 @@
+package kubecontroller
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_influxdbTokenPatch(t *testing.T) {
+	patch, err := influxdbTokenPatch("test-dc", InfluxdbSecretName("test"), "2022-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Annotations map[string]string `json:"annotations"`
+				} `json:"metadata"`
+				Spec struct {
+					Containers []struct {
+						Name string `json:"name"`
+						Env  []struct {
+							Name      string `json:"name"`
+							ValueFrom struct {
+								SecretKeyRef struct {
+									Name string `json:"name"`
+									Key  string `json:"key"`
+								} `json:"secretKeyRef"`
+							} `json:"valueFrom"`
+						} `json:"env"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	template := result.Spec.Template
+	if template.Metadata.Annotations[influxdbTokenRotatedAnnotation] != "2022-01-01T00:00:00Z" {
+		t.Fatalf("未记录轮换时间:%s", patch)
+	}
+	if len(template.Spec.Containers) != 1 || template.Spec.Containers[0].Name != "test-dc" {
+		t.Fatalf("patch的容器不正确:%s", patch)
+	}
+	env := template.Spec.Containers[0].Env
+	if len(env) != 1 || env[0].Name != InfluxdbTokenEnv ||
+		env[0].ValueFrom.SecretKeyRef.Name != "test-influxdb" || env[0].ValueFrom.SecretKeyRef.Key != influxdbTokenKey {
+		t.Fatalf("patch的环境变量不正确:%s", patch)
+	}
+}
+
+func Test_serviceEnv(t *testing.T) {
+	env := serviceEnv(&BaseDeployOption{Username: "test"})
+	if len(env) != 1 || *env[0].Name != "USERNAME" || *env[0].Value != "test" {
+		t.Fatalf("未配置secret时只应包括用户名:%v", env)
+	}
+
+	env = serviceEnv(&BaseDeployOption{Username: "test", InfluxdbSecret: "test-influxdb"})
+	if len(env) != 2 || *env[1].Name != InfluxdbTokenEnv || *env[1].ValueFrom.SecretKeyRef.Name != "test-influxdb" {
+		t.Fatalf("未注入influxdb token:%v", env)
+	}
+
+	env = serviceEnv(&BaseDeployOption{Username: "test", TimeSeriesEnv: map[string]string{
+		"TIMESERIES_BACKEND": "victoriametrics", "VICTORIAMETRICS_INSERT_URL": "http://vminsert/insert/1",
+	}})
+	if len(env) != 3 || *env[1].Name != "TIMESERIES_BACKEND" || *env[2].Value != "http://vminsert/insert/1" {
+		t.Fatalf("时序数据库的连接信息应按名称排序注入:%v", env)
+	}
+}
@@ -0,0 +1,140 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// 编译初始容器的执行状态
+const (
+	CompileRunning   = "running"
+	CompileSucceeded = "succeeded"
+	CompileFailed    = "failed"
+	CompileUnknown   = "unknown"
+)
+
+// ServiceStatus 用户服务的运行状态
+type ServiceStatus struct {
+	// 服务名，即<用户名>-dc或<用户名>-dp
+	Name string
+	// 服务是否存在
+	Exists bool
+	// 期望的副本数量以及ready的副本数量
+	DesiredReplicas int32
+	ReadyReplicas   int32
+	// 服务所有pod中容器的重启次数之和
+	RestartCount int32
+	// 编译初始容器最近一次的执行结果
+	Compile CompileOutcome
+}
+
+// CompileOutcome 编译初始容器的执行结果
+type CompileOutcome struct {
+	// 执行状态，取值为CompileRunning、CompileSucceeded、CompileFailed或CompileUnknown
+	State string
+	// 编译失败时的错误信息
+	Message string
+	// 编译结束的时间，编译未结束时为零值
+	FinishTime time.Time
+}
+
+// GetServiceStatus 查询用户数据收集和数据处理服务的副本数、容器重启次数以及编译结果
+func (c *KubeController) GetServiceStatus(username string) ([]ServiceStatus, error) {
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+	dc := ServiceStatus{Name: dcName, Compile: CompileOutcome{State: CompileUnknown}}
+	dp := ServiceStatus{Name: dpName, Compile: CompileOutcome{State: CompileUnknown}}
+
+	statefulSet, err := c.GetStatefulSet(dcName)
+	if err == nil {
+		dc.Exists = true
+		dc.DesiredReplicas = replicasOf(statefulSet.Spec.Replicas)
+		dc.ReadyReplicas = statefulSet.Status.ReadyReplicas
+		err = c.fillPodStatus(&dc, podSelector(statefulSet.Spec.Selector))
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	deployment, err := c.GetDeployment(dpName)
+	if err == nil {
+		dp.Exists = true
+		dp.DesiredReplicas = replicasOf(deployment.Spec.Replicas)
+		dp.ReadyReplicas = deployment.Status.ReadyReplicas
+		err = c.fillPodStatus(&dp, podSelector(deployment.Spec.Selector))
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return []ServiceStatus{dc, dp}, nil
+}
+
+// 统计服务pod中容器的重启次数，以及编译初始容器的执行结果
+func (c *KubeController) fillPodStatus(status *ServiceStatus, podSelector string) error {
+	pods, err := c.client.CoreV1().Pods(c.namespace).List(
+		context.Background(),
+		client_metav1.ListOptions{LabelSelector: podSelector},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods.Items {
+		for _, s := range pod.Status.InitContainerStatuses {
+			status.RestartCount += s.RestartCount
+		}
+		for _, s := range pod.Status.ContainerStatuses {
+			status.RestartCount += s.RestartCount
+		}
+	}
+	status.Compile = compileOutcome(pods.Items)
+	return nil
+}
+
+// 依据各个pod中编译初始容器的状态得到服务最近一次的编译结果，
+// 任一pod仍在编译时视为编译中，否则以最近结束的一次编译为准
+func compileOutcome(pods []corev1.Pod) CompileOutcome {
+	outcome := CompileOutcome{State: CompileUnknown}
+	for _, pod := range pods {
+		for _, s := range pod.Status.InitContainerStatuses {
+			if s.Name != buildContainerName {
+				continue
+			}
+
+			terminated := s.State.Terminated
+			if terminated == nil {
+				// 容器等待重启时，以上一次的退出状态作为编译结果
+				if s.State.Waiting != nil && s.LastTerminationState.Terminated != nil {
+					terminated = s.LastTerminationState.Terminated
+				} else {
+					return CompileOutcome{State: CompileRunning}
+				}
+			}
+
+			if outcome.State != CompileUnknown && !terminated.FinishedAt.Time.After(outcome.FinishTime) {
+				continue
+			}
+			outcome = CompileOutcome{State: CompileSucceeded, FinishTime: terminated.FinishedAt.Time}
+			if terminated.ExitCode != 0 {
+				outcome.State = CompileFailed
+				outcome.Message = fmt.Sprintf("exit code %d, %s %s",
+					terminated.ExitCode, terminated.Reason, terminated.Message)
+			}
+		}
+	}
+
+	return outcome
+}
+
+// 获得工作负载的期望副本数，未指定时k8s默认为1
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,46 @@
+package kubecontroller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func buildPod(state corev1.ContainerState) corev1.Pod {
+	return corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{
+			{Name: buildContainerName, State: state},
+		},
+	}}
+}
+
+func Test_compileOutcome(t *testing.T) {
+	if outcome := compileOutcome(nil); outcome.State != CompileUnknown {
+		t.Fatalf("没有pod时编译结果应为unknown:%v", outcome)
+	}
+
+	now := time.Now()
+	succeeded := buildPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode: 0, FinishedAt: client_metav1.NewTime(now.Add(-time.Minute)),
+	}})
+	failed := buildPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode: 1, Reason: "Error", FinishedAt: client_metav1.NewTime(now),
+	}})
+	running := buildPod(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+
+	outcome := compileOutcome([]corev1.Pod{succeeded, failed})
+	if outcome.State != CompileFailed || !outcome.FinishTime.Equal(now) {
+		t.Fatalf("应以最近结束的编译为准:%v", outcome)
+	}
+
+	outcome = compileOutcome([]corev1.Pod{succeeded})
+	if outcome.State != CompileSucceeded {
+		t.Fatalf("编译初始容器正常退出时应视为编译成功:%v", outcome)
+	}
+
+	outcome = compileOutcome([]corev1.Pod{succeeded, running})
+	if outcome.State != CompileRunning {
+		t.Fatalf("任一pod仍在编译时应视为编译中:%v", outcome)
+	}
+}
@@ -0,0 +1,143 @@
+package kubecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"github.com/go-kratos/kratos/v2/errors"
+	client_metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+)
+
+// 暂停用户服务时，在工作负载的注解中记录原有的副本数，恢复时依据该注解还原
+const suspendedReplicasAnnotation = "service-center/suspended-replicas"
+
+// SuspendServices 将用户的数据收集和数据处理服务缩容为0个副本，并记录原有的副本数，已暂停的服务保持不变
+func (c *KubeController) SuspendServices(username string) (err error) {
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.suspend_services", username,
+			[]string{"StatefulSet/" + dcName, "Deployment/" + dpName}, err)
+	}()
+
+	statefulSet, err := c.GetStatefulSet(dcName)
+	if err != nil {
+		return err
+	}
+	if patch := suspendPatch(statefulSet.Annotations, replicasOf(statefulSet.Spec.Replicas)); patch != nil {
+		err = c.MergePatch("StatefulSet", dcName, patch)
+		if err != nil {
+			return err
+		}
+	}
+
+	deployment, err := c.GetDeployment(dpName)
+	if err != nil {
+		return err
+	}
+	if patch := suspendPatch(deployment.Annotations, replicasOf(deployment.Spec.Replicas)); patch != nil {
+		err = c.MergePatch("Deployment", dpName, patch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResumeServices 将暂停的数据收集和数据处理服务恢复为暂停前的副本数，未暂停的服务保持不变
+func (c *KubeController) ResumeServices(username string) (err error) {
+	dcName := fmt.Sprintf("%s-dc", username)
+	dpName := fmt.Sprintf("%s-dp", username)
+	defer func() {
+		audit.Record(c.Recorder, "kube.resume_services", username,
+			[]string{"StatefulSet/" + dcName, "Deployment/" + dpName}, err)
+	}()
+
+	statefulSet, err := c.GetStatefulSet(dcName)
+	if err != nil {
+		return err
+	}
+	patch, err := resumePatch(statefulSet.Annotations)
+	if err != nil {
+		return err
+	}
+	if patch != nil {
+		err = c.MergePatch("StatefulSet", dcName, patch)
+		if err != nil {
+			return err
+		}
+	}
+
+	deployment, err := c.GetDeployment(dpName)
+	if err != nil {
+		return err
+	}
+	patch, err = resumePatch(deployment.Annotations)
+	if err != nil {
+		return err
+	}
+	if patch != nil {
+		err = c.MergePatch("Deployment", dpName, patch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MergePatch 以json merge patch的方式更新指定的deployment或statefulSet，不等待滚动更新完成
+func (c *baseKubeController) MergePatch(resourceType, name string, patch []byte) (err error) {
+	option := client_metav1.PatchOptions{FieldManager: fieldManager}
+	switch resourceType {
+	case "Deployment":
+		_, err = c.client.AppsV1().Deployments(c.namespace).Patch(
+			context.Background(), name, types.MergePatchType, patch, option)
+	case "StatefulSet":
+		_, err = c.client.AppsV1().StatefulSets(c.namespace).Patch(
+			context.Background(), name, types.MergePatchType, patch, option)
+	default:
+		err = errors.New(500, "UNKNOWN_RESOURCE_TYPE", "can't patch the unknown resource")
+	}
+	return err
+}
+
+// 辅助函数，创建将工作负载缩容为0并记录原有副本数的patch，工作负载已暂停时返回nil
+func suspendPatch(annotations map[string]string, replicas int32) []byte {
+	if _, ok := annotations[suspendedReplicasAnnotation]; ok {
+		return nil
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{suspendedReplicasAnnotation: strconv.Itoa(int(replicas))},
+		},
+		"spec": map[string]interface{}{"replicas": 0},
+	})
+	return patch
+}
+
+// 辅助函数，创建还原工作负载副本数并删除注解的patch，工作负载未暂停时返回nil
+func resumePatch(annotations map[string]string) ([]byte, error) {
+	value, ok := annotations[suspendedReplicasAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	replicas, err := strconv.Atoi(value)
+	if err != nil || replicas < 0 {
+		return nil, errors.Newf(
+			500, "RESUME_FAIL", "the annotation %s has an invalid value %q", suspendedReplicasAnnotation, value)
+	}
+
+	// merge patch中值为null的键会被删除
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{suspendedReplicasAnnotation: nil},
+		},
+		"spec": map[string]interface{}{"replicas": replicas},
+	})
+}
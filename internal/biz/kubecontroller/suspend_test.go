@@ -0,0 +1,66 @@
+package kubecontroller
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_suspendPatch(t *testing.T) {
+	patch := suspendPatch(nil, 2)
+
+	var result struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas *int32 `json:"replicas"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Metadata.Annotations[suspendedReplicasAnnotation] != "2" {
+		t.Fatalf("未记录原有的副本数:%v", result.Metadata.Annotations)
+	}
+	if result.Spec.Replicas == nil || *result.Spec.Replicas != 0 {
+		t.Fatalf("暂停时副本数应为0:%s", patch)
+	}
+
+	// 已暂停的工作负载不能覆盖记录的副本数
+	if patch := suspendPatch(map[string]string{suspendedReplicasAnnotation: "2"}, 0); patch != nil {
+		t.Fatalf("已暂停的工作负载不应生成patch:%s", patch)
+	}
+}
+
+func Test_resumePatch(t *testing.T) {
+	patch, err := resumePatch(map[string]string{suspendedReplicasAnnotation: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Metadata struct {
+			Annotations map[string]*string `json:"annotations"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas int32 `json:"replicas"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &result); err != nil {
+		t.Fatal(err)
+	}
+	value, ok := result.Metadata.Annotations[suspendedReplicasAnnotation]
+	if !ok || value != nil {
+		t.Fatalf("恢复时需要以null删除注解:%s", patch)
+	}
+	if result.Spec.Replicas != 3 {
+		t.Fatalf("恢复后的副本数错误:%v", result.Spec.Replicas)
+	}
+
+	if patch, err := resumePatch(nil); patch != nil || err != nil {
+		t.Fatalf("未暂停的工作负载不应生成patch:%s, %v", patch, err)
+	}
+	if _, err := resumePatch(map[string]string{suspendedReplicasAnnotation: "x"}); err == nil {
+		t.Fatal("注解的值无效时应返回错误")
+	}
+}
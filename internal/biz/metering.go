@@ -0,0 +1,375 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// 使用量记录中日期的格式
+	usageDateLayout = "2006-01-02"
+	// 未指定查询范围时默认查询的天数
+	defaultUsageDays = 30
+	// 单次查询允许的最大天数
+	maxUsageDays = 366
+)
+
+// MeteringUsecase 周期性地采集每个租户的资源使用量，并按天(UTC)汇总保存，用于计费
+type MeteringUsecase struct {
+	repo         UsageRepo
+	userRepo     UserRepo
+	clusters     *clusterPlacement
+	gateway      gateway.Provider
+	tenantOf     func(token string) (string, error)
+	timeSeriesOf func(username string) (TimeSeriesBackend, error)
+	// 采集的间隔
+	interval time.Duration
+	// 上一次采集的时间，用于计算cpu与内存在两次采集之间的使用量
+	lastCollect time.Time
+	// 实例的标识，多个实例中只有持有采集锁的实例进行采集，避免重复计量
+	instance string
+	logger   *log.Helper
+}
+type UsageRepo interface {
+	// SaveUsage 保存租户在某一天的资源使用量
+	SaveUsage(username, date string, record []byte) error
+	// GetUsage 获得租户在给定日期的资源使用量，没有记录的日期对应的元素为nil
+	GetUsage(username string, dates []string) ([][]byte, error)
+	// ListUsageUsers 获得所有存在使用量记录的租户，包括已注销的租户
+	ListUsageUsers() ([]string, error)
+	// GetRequestCounter 获得上一次采集时租户在网关中累计的请求数量，不存在时exists为false
+	GetRequestCounter(username string) (counter int64, exists bool, err error)
+	// SaveRequestCounter 保存本次采集时租户在网关中累计的请求数量
+	SaveRequestCounter(username string, counter int64) error
+	// AcquireMeteringLock 获得或者续期采集资源使用量的锁，锁在ttl后过期，返回owner是否持有锁
+	AcquireMeteringLock(owner string, ttl time.Duration) (bool, error)
+}
+
+func NewMeteringUsecase(server *conf.Server, uc *UserUsecase, repo UsageRepo, logger log.Logger) (*MeteringUsecase, error) {
+	instance := make([]byte, 8)
+	if _, err := rand.Read(instance); err != nil {
+		return nil, errors.Newf(500, "Metering_Error", "生成实例的标识时发生了错误:%v", err)
+	}
+	metering := &MeteringUsecase{
+		repo:         repo,
+		userRepo:     uc.repo,
+		clusters:     uc.clusters,
+		gateway:      uc.gateway,
+		tenantOf:     uc.tenantOfToken,
+		timeSeriesOf: uc.timeSeriesOf,
+		interval:     5 * time.Minute,
+		instance:     hex.EncodeToString(instance),
+		logger:       log.NewHelper(logger),
+	}
+	if c := server.Metering; c != nil && c.Interval != nil && c.Interval.AsDuration() > 0 {
+		metering.interval = c.Interval.AsDuration()
+	}
+
+	return metering, nil
+}
+
+// Run 按照配置的间隔采集租户的资源使用量，直到ctx被取消
+func (m *MeteringUsecase) Run(ctx context.Context) {
+	// 网关请求数量的统计依赖于全局的prometheus插件，网关不支持统计请求数量时不计量请求
+	if counter, ok := m.gateway.(gateway.RequestCounter); ok {
+		if err := counter.EnsurePrometheusPlugin(); err != nil {
+			m.logger.Errorf("启用网关的prometheus插件时发生了错误:%v", err)
+		}
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if m.lead() {
+				m.collect(now.UTC())
+			}
+		}
+	}
+}
+
+// 获得或者续期采集锁，锁在两个采集间隔后过期，使持有锁的实例退出后其余实例可以接替采集
+func (m *MeteringUsecase) lead() bool {
+	held, err := m.repo.AcquireMeteringLock(m.instance, 2*m.interval)
+	if err != nil {
+		m.logger.Errorf("获得采集资源使用量的锁时发生了错误:%v", err)
+	}
+	if !held {
+		// 重新获得锁时从下一次采集开始计算间隔，不计入其余实例负责采集的时段
+		m.lastCollect = time.Time{}
+	}
+	return held
+}
+
+// GetUsage 获得token对应用户在给定日期范围内每日的资源使用量
+func (m *MeteringUsecase) GetUsage(token, startDate, endDate string) ([]*v1.UsageRecord, error) {
+	username, err := m.tenantOf(token)
+	if err != nil {
+		return nil, err
+	}
+
+	dates, err := usageDates(startDate, endDate, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return m.getUsage(username, dates)
+}
+
+// ExportUsage 以csv或json格式导出租户在给定日期范围内每日的资源使用量，username为空时导出所有租户
+func (m *MeteringUsecase) ExportUsage(username, startDate, endDate, format string) (*v1.File, error) {
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return nil, errors.BadRequest("Export_Usage_Error", "导出格式只能为csv或json")
+	}
+
+	dates, err := usageDates(startDate, endDate, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	users := []string{username}
+	if username == "" {
+		users, err = m.repo.ListUsageUsers()
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(users)
+	}
+
+	var records []*v1.UsageRecord
+	for _, user := range users {
+		r, err := m.getUsage(user, dates)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r...)
+	}
+
+	name := "usage_" + dates[0] + "_" + dates[len(dates)-1] + "." + format
+	if format == "json" {
+		content, err := protojson.Marshal(&v1.GetUsageReply{Records: records})
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Export_Usage_Error",
+				"将资源使用量转换为json时发生了错误:%v", err)
+		}
+		return &v1.File{Content: content, Name: name}, nil
+	}
+
+	content, err := usageCSV(records)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Export_Usage_Error",
+			"将资源使用量转换为csv时发生了错误:%v", err)
+	}
+	return &v1.File{Content: content, Name: name}, nil
+}
+
+// 获得租户在给定日期的资源使用量，跳过没有记录的日期
+func (m *MeteringUsecase) getUsage(username string, dates []string) ([]*v1.UsageRecord, error) {
+	data, err := m.repo.GetUsage(username, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*v1.UsageRecord, 0, len(data))
+	for _, d := range data {
+		if d == nil {
+			continue
+		}
+		record := new(v1.UsageRecord)
+		err = proto.Unmarshal(d, record)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Get_Usage_Error",
+				"对资源使用量进行protobuf解码时发生了错误:%v", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// 采集所有租户当天的资源使用量，单个租户采集失败时只记录日志
+func (m *MeteringUsecase) collect(now time.Time) {
+	elapsed := m.interval
+	if !m.lastCollect.IsZero() {
+		elapsed = now.Sub(m.lastCollect)
+	}
+	m.lastCollect = now
+
+	users, err := m.userRepo.ListUsers()
+	if err != nil {
+		m.logger.Errorf("采集资源使用量时查询租户失败:%v", err)
+		return
+	}
+	var counts map[string]int64
+	if counter, ok := m.gateway.(gateway.RequestCounter); ok {
+		counts, err = counter.GetRequestCounts()
+		if err != nil {
+			m.logger.Errorf("采集资源使用量时查询网关的请求数量失败:%v", err)
+		}
+	}
+
+	for _, username := range users {
+		err := m.collectTenant(username, now, elapsed, counts)
+		if err != nil {
+			m.logger.Errorf("采集租户 %v 的资源使用量时发生了错误:%v", username, err)
+		}
+	}
+}
+
+// 更新租户当天的使用量记录，数据点数量由influxdb直接统计当天的总量，
+// 网关请求数量为两次采集之间累计请求数量的增量，cpu与内存为当前使用量与采集间隔的乘积
+func (m *MeteringUsecase) collectTenant(
+	username string, now time.Time, elapsed time.Duration, counts map[string]int64) error {
+	date := now.Format(usageDateLayout)
+	record := &v1.UsageRecord{Username: username, Date: date}
+	data, err := m.repo.GetUsage(username, []string{date})
+	if err != nil {
+		return err
+	}
+	if data[0] != nil {
+		if err := proto.Unmarshal(data[0], record); err != nil {
+			return err
+		}
+	}
+
+	// 时序数据库不支持统计数据点数量时不采集
+	backend, err := m.timeSeriesOf(username)
+	if err != nil {
+		return err
+	}
+	if counter, ok := backend.(pointCounter); ok {
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		buckets := influxdb.BucketNames(username)
+		if points, err := counter.CountPoints(buckets[0], dayStart, now); err == nil {
+			record.PointsWritten = points
+		} else {
+			m.logger.Warnf("统计租户 %v 写入的数据点数量时发生了错误:%v", username, err)
+		}
+		if points, err := counter.CountPoints(buckets[2], dayStart, now); err == nil {
+			record.WarningPoints = points
+		} else {
+			m.logger.Warnf("统计租户 %v 的警告数量时发生了错误:%v", username, err)
+		}
+	}
+
+	if counts != nil {
+		var counter int64
+		for _, service := range gateway.ServiceNames(username) {
+			counter += counts[service]
+		}
+		last, exists, err := m.repo.GetRequestCounter(username)
+		if err != nil {
+			return err
+		}
+		// 首次采集时只记录当前的计数作为基准，计数中包括计量开始前的请求，不计入使用量；
+		// 网关重启后计数器会被重置，此时以当前的计数作为增量
+		switch {
+		case !exists:
+		case counter >= last:
+			record.GatewayRequests += counter - last
+		default:
+			record.GatewayRequests += counter
+		}
+		if err := m.repo.SaveRequestCounter(username, counter); err != nil {
+			return err
+		}
+	}
+
+	cluster, err := m.clusters.clusterOf(username)
+	if err != nil {
+		return err
+	}
+	if usage, err := cluster.GetPodUsage(username); err == nil {
+		record.CpuCoreSeconds += usage.CPUCores * elapsed.Seconds()
+		record.MemoryByteSeconds += float64(usage.MemoryBytes) * elapsed.Seconds()
+	} else {
+		m.logger.Warnf("查询租户 %v 的pod资源使用量时发生了错误:%v", username, err)
+	}
+
+	record.UpdateTime = timestamppb.New(now)
+	marshal, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return m.repo.SaveUsage(username, date, marshal)
+}
+
+// 将起止日期展开为日期列表，日期为空时默认为截止到今天的最近30天
+func usageDates(startDate, endDate string, now time.Time) ([]string, error) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if endDate != "" {
+		e, err := time.Parse(usageDateLayout, endDate)
+		if err != nil {
+			return nil, errors.BadRequest("Get_Usage_Error", "结束日期的格式应为2006-01-02")
+		}
+		end = e
+	}
+	start := end.AddDate(0, 0, 1-defaultUsageDays)
+	if startDate != "" {
+		s, err := time.Parse(usageDateLayout, startDate)
+		if err != nil {
+			return nil, errors.BadRequest("Get_Usage_Error", "起始日期的格式应为2006-01-02")
+		}
+		start = s
+	}
+
+	if start.After(end) {
+		return nil, errors.BadRequest("Get_Usage_Error", "起始日期不能晚于结束日期")
+	}
+	if end.Sub(start) >= maxUsageDays*24*time.Hour {
+		return nil, errors.BadRequest(
+			"Get_Usage_Error", "单次查询的范围不能超过"+strconv.Itoa(maxUsageDays)+"天")
+	}
+
+	var dates []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format(usageDateLayout))
+	}
+	return dates, nil
+}
+
+// 将使用量记录转换为csv格式
+func usageCSV(records []*v1.UsageRecord) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	w := csv.NewWriter(buffer)
+	w.Write([]string{
+		"username", "date", "points_written", "warning_points",
+		"gateway_requests", "cpu_core_seconds", "memory_byte_seconds",
+	})
+	for _, r := range records {
+		w.Write([]string{
+			r.Username,
+			r.Date,
+			strconv.FormatInt(r.PointsWritten, 10),
+			strconv.FormatInt(r.WarningPoints, 10),
+			strconv.FormatInt(r.GatewayRequests, 10),
+			strconv.FormatFloat(r.CpuCoreSeconds, 'f', 3, 64),
+			strconv.FormatFloat(r.MemoryByteSeconds, 'f', 0, 64),
+		})
+	}
+	w.Flush()
+	return buffer.Bytes(), w.Error()
+}
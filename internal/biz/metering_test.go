@@ -0,0 +1,97 @@
+package biz
+
+import (
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"github.com/go-kratos/kratos/v2/log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_usageDates(t *testing.T) {
+	now := time.Date(2022, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	dates, err := usageDates("", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != defaultUsageDays || dates[len(dates)-1] != "2022-03-02" {
+		t.Fatalf("默认应查询截止到今天的最近30天:%v", dates)
+	}
+
+	dates, err = usageDates("2022-02-27", "2022-03-01", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(dates, ",") != "2022-02-27,2022-02-28,2022-03-01" {
+		t.Fatalf("日期展开错误:%v", dates)
+	}
+
+	if _, err = usageDates("2022-03-02", "2022-03-01", now); err == nil {
+		t.Fatal("起始日期晚于结束日期时应返回错误")
+	}
+	if _, err = usageDates("2020-01-01", "2022-03-01", now); err == nil {
+		t.Fatal("查询范围过大时应返回错误")
+	}
+}
+
+func Test_usageCSV(t *testing.T) {
+	content, err := usageCSV([]*v1.UsageRecord{{
+		Username:          "test",
+		Date:              "2022-03-01",
+		PointsWritten:     100,
+		WarningPoints:     2,
+		GatewayRequests:   30,
+		CpuCoreSeconds:    1.5,
+		MemoryByteSeconds: 1024,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || lines[1] != "test,2022-03-01,100,2,30,1.500,1024" {
+		t.Fatalf("csv内容错误:%v", lines)
+	}
+}
+
+// 只实现采集锁的使用量数据库
+type lockUsageRepo struct {
+	UsageRepo
+	owner  string
+	expire time.Time
+}
+
+func (r *lockUsageRepo) AcquireMeteringLock(owner string, ttl time.Duration) (bool, error) {
+	if r.owner != owner && time.Now().Before(r.expire) {
+		return false, nil
+	}
+	r.owner, r.expire = owner, time.Now().Add(ttl)
+	return true, nil
+}
+
+func TestMeteringUsecase_lead(t *testing.T) {
+	repo := new(lockUsageRepo)
+	newMetering := func(instance string) *MeteringUsecase {
+		return &MeteringUsecase{repo: repo, interval: time.Minute, instance: instance,
+			logger: log.NewHelper(log.DefaultLogger)}
+	}
+	leader, follower := newMetering("a"), newMetering("b")
+
+	if !leader.lead() || !leader.lead() {
+		t.Fatal("首个实例应获得并续期采集锁")
+	}
+	follower.lastCollect = time.Now()
+	if follower.lead() {
+		t.Fatal("锁被持有时其余实例不应采集")
+	}
+	if !follower.lastCollect.IsZero() {
+		t.Fatal("未持有锁的实例应重置上一次采集的时间")
+	}
+
+	// 持有锁的实例退出后，锁过期时由其余实例接替
+	repo.expire = time.Now()
+	if !follower.lead() {
+		t.Fatal("锁过期后其余实例应获得采集锁")
+	}
+}
@@ -0,0 +1,793 @@
+package biz
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 登录请求的state以及首次注册凭证的有效期
+	oidcStateExpiration  = 10 * time.Minute
+	oidcTicketExpiration = 30 * time.Minute
+	// 校验id token有效期时允许的时钟偏差
+	oidcClockSkew = time.Minute
+	// 因未知的kid重新获取签名公钥的最小间隔，避免伪造的id token使服务频繁请求身份提供方
+	oidcKeysRefreshInterval = time.Minute
+	// 用户名冲突时尝试添加随机后缀的次数
+	oidcUsernameAttempts = 5
+)
+
+// 租户用户名的长度限制，与注册时的校验规则一致
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 12
+)
+
+// OidcUsecase 负责OIDC单点登录，使用带PKCE的授权码流程，
+// 身份提供方返回的subject与租户一一关联，未关联的身份可以凭注册凭证完成首次注册，
+// 租户启用了二次验证时，同样需要通过二次验证才能获得token，
+// 满足管理员claim的身份可以获得有时效的管理令牌
+type OidcUsecase struct {
+	uc           *UserUsecase
+	repo         OidcRepo
+	enabled      bool
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectUrl  string
+	scopes       []string
+	// 依次尝试作为租户用户名的claim
+	usernameClaims []string
+	// 用于判断管理员身份的claim以及其取值
+	adminClaim      string
+	adminValue      string
+	adminExpiration time.Duration
+	client          *http.Client
+	// 身份提供方的端点地址以及签名公钥，在首次使用时获取
+	mutex     sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+	// 最近一次获取签名公钥的时间
+	keysFetched time.Time
+	logger      *log.Helper
+}
+type OidcRepo interface {
+	// SaveOidcState 保存登录请求的state对应的信息，在ttl后自动删除
+	SaveOidcState(state string, record []byte, ttl time.Duration) error
+	// TakeOidcState 获得并删除state对应的信息，保证state只被使用一次，不存在或已过期时返回nil
+	TakeOidcState(state string) ([]byte, error)
+	// SaveOidcTicket 保存首次注册凭证对应的身份，在ttl后自动删除
+	SaveOidcTicket(ticket string, identity []byte, ttl time.Duration) error
+	// GetOidcTicket 获得首次注册凭证对应的身份，不存在或已过期时返回nil
+	GetOidcTicket(ticket string) ([]byte, error)
+	// TakeOidcTicket 获得并删除首次注册凭证对应的身份，不存在或已过期时返回nil
+	TakeOidcTicket(ticket string) ([]byte, error)
+	// LinkOidcSubject 将身份提供方的subject关联到租户，subject已关联租户时返回409错误
+	LinkOidcSubject(subject, username string) error
+	// UnlinkOidcSubject 撤销subject与租户的关联，subject未关联到该租户时不进行任何操作
+	UnlinkOidcSubject(subject, username string) error
+	// GetOidcSubject 获得subject关联的租户，未关联时返回空字符串
+	GetOidcSubject(subject string) (string, error)
+	// UsernameTaken 用户名是否已被注册的用户或组织的成员使用
+	UsernameTaken(username string) (bool, error)
+	// GetToken 获得已注册用户的token
+	GetToken(username string) (string, error)
+	// SaveAdminSession 保存管理令牌的摘要，在ttl后自动删除
+	SaveAdminSession(digest, subject string, ttl time.Duration) error
+	// GetAdminSession 获得管理令牌对应的管理员的subject，不存在或已过期时返回空字符串
+	GetAdminSession(digest string) (string, error)
+}
+
+// 身份提供方的配置信息，见OpenID Connect Discovery 1.0
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// 登录请求中保存的信息
+type oidcState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Admin    bool   `json:"admin"`
+}
+
+// 首次注册凭证或二次验证凭证对应的身份
+type oidcIdentity struct {
+	Subject  string `json:"subject"`
+	Username string `json:"username"`
+	// 是否为已关联租户的身份等待完成二次验证的凭证
+	TwoFactor bool `json:"two_factor,omitempty"`
+}
+
+func NewOidcUsecase(server *conf.Server, uc *UserUsecase, repo OidcRepo, logger log.Logger) (*OidcUsecase, error) {
+	oidc := &OidcUsecase{
+		uc:              uc,
+		repo:            repo,
+		scopes:          []string{"openid", "profile", "email"},
+		usernameClaims:  []string{"preferred_username", "email"},
+		adminExpiration: 8 * time.Hour,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		keys:            make(map[string]*rsa.PublicKey),
+		logger:          log.NewHelper(logger),
+	}
+
+	c := server.Oidc
+	if c == nil || !c.Enabled {
+		return oidc, nil
+	}
+	if c.Issuer == "" || c.ClientId == "" || c.RedirectUrl == "" {
+		return nil, errors.New(500, "Config_Error", "开启OIDC登录时需要配置issuer、client id以及回调地址")
+	}
+	oidc.enabled = true
+	oidc.issuer = strings.TrimSuffix(c.Issuer, "/")
+	oidc.clientID = c.ClientId
+	oidc.clientSecret = c.ClientSecret
+	oidc.redirectUrl = c.RedirectUrl
+	if len(c.Scopes) != 0 {
+		oidc.scopes = c.Scopes
+	}
+	if c.UsernameClaim != "" {
+		oidc.usernameClaims[0] = c.UsernameClaim
+	}
+	if len(c.UsernameFallbackClaims) != 0 {
+		oidc.usernameClaims = append(oidc.usernameClaims[:1], c.UsernameFallbackClaims...)
+	}
+	oidc.adminClaim = c.AdminClaim
+	oidc.adminValue = c.AdminValue
+	if c.AdminSessionExpiration != nil && c.AdminSessionExpiration.AsDuration() > 0 {
+		oidc.adminExpiration = c.AdminSessionExpiration.AsDuration()
+	}
+
+	return oidc, nil
+}
+
+// Login 生成登录请求的state、nonce以及PKCE的code verifier，返回身份提供方的授权地址
+func (o *OidcUsecase) Login(admin bool) (authorizationUrl, state string, err error) {
+	if !o.enabled {
+		return "", "", errors.NotFound("Oidc_Error", "服务未开启OIDC登录")
+	}
+	if admin && o.adminClaim == "" {
+		return "", "", errors.Forbidden("Oidc_Error", "服务未配置管理员的claim，不允许管理员通过OIDC登录")
+	}
+	discovery, err := o.getDiscovery()
+	if err != nil {
+		return "", "", err
+	}
+
+	record := &oidcState{Admin: admin}
+	values := make([]string, 3)
+	for i := range values {
+		if values[i], err = randomString(32); err != nil {
+			return "", "", errors.Newf(500, "Oidc_Error", "生成登录请求的随机值时发生了错误:%v", err)
+		}
+	}
+	state, record.Nonce, record.Verifier = values[0], values[1], values[2]
+	marshal, err := json.Marshal(record)
+	if err != nil {
+		return "", "", errors.Newf(500, "Oidc_Error", "对登录请求进行json序列化时发生了错误:%v", err)
+	}
+	err = o.repo.SaveOidcState(state, marshal, oidcStateExpiration)
+	if err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(record.Verifier))
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", o.clientID)
+	query.Set("redirect_uri", o.redirectUrl)
+	query.Set("scope", strings.Join(o.scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", record.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// Callback 使用授权码换取并校验id token，根据登录请求的类型返回租户的token、首次注册凭证或管理令牌，
+// 租户启用了二次验证时，twoFactorCode需要为有效的验证码或恢复码，为空时返回二次验证凭证
+func (o *OidcUsecase) Callback(code, state, twoFactorCode string) (*v1.OidcCallbackReply, error) {
+	if !o.enabled {
+		return nil, errors.NotFound("Oidc_Error", "服务未开启OIDC登录")
+	}
+
+	marshal, err := o.repo.TakeOidcState(state)
+	if err != nil {
+		return nil, err
+	}
+	record := new(oidcState)
+	if marshal == nil || json.Unmarshal(marshal, record) != nil {
+		return nil, errors.Forbidden("Oidc_Error", "登录请求无效或已过期")
+	}
+
+	rawIdToken, err := o.exchange(code, record.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := o.verify(rawIdToken, record.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.Forbidden("Oidc_Error", "id token中缺少subject")
+	}
+
+	if record.Admin {
+		return o.adminSession(subject, claims)
+	}
+
+	username, err := o.repo.GetOidcSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+	if username != "" {
+		return o.login(subject, username, twoFactorCode)
+	}
+
+	// 身份未关联租户时，以claim映射得到的用户名作为首次注册的用户名
+	username, err = o.username(claims)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := o.saveTicket(&oidcIdentity{Subject: subject, Username: username})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.OidcCallbackReply{
+		Success:            true,
+		Username:           username,
+		RegistrationTicket: ticket,
+		TicketExpireTime:   timestamppb.New(time.Now().Add(oidcTicketExpiration)),
+	}, nil
+}
+
+// CompleteTwoFactor 以二次验证凭证以及验证码完成已关联租户的身份的登录，返回租户的token，
+// 验证通过后凭证失效，验证失败时凭证仍可在有效期内使用
+func (o *OidcUsecase) CompleteTwoFactor(ticket, code string) (*v1.OidcCallbackReply, error) {
+	identity, err := o.getTicket(ticket, false, true)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, errors.Unauthorized("Two_Factor_Required", "需要在X-Two-Factor-Code请求头中携带验证码")
+	}
+	err = o.uc.twoFactor.Check(identity.Username, code)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := o.getTicket(ticket, true, true); err != nil {
+		return nil, err
+	}
+
+	return o.token(identity.Username)
+}
+
+// 已关联租户的身份登录，租户启用了二次验证且未携带验证码时返回二次验证凭证
+func (o *OidcUsecase) login(subject, username, code string) (*v1.OidcCallbackReply, error) {
+	record, err := o.uc.twoFactor.get(username)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.Enabled && code == "" {
+		ticket, err := o.saveTicket(&oidcIdentity{Subject: subject, Username: username, TwoFactor: true})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.OidcCallbackReply{
+			Success:          true,
+			Username:         username,
+			TwoFactorTicket:  ticket,
+			TicketExpireTime: timestamppb.New(time.Now().Add(oidcTicketExpiration)),
+		}, nil
+	}
+
+	err = o.uc.twoFactor.Check(username, code)
+	if err != nil {
+		return nil, err
+	}
+	return o.token(username)
+}
+
+// 获得租户的token，租户等待清理时不允许登录
+func (o *OidcUsecase) token(username string) (*v1.OidcCallbackReply, error) {
+	err := o.uc.checkPending(username)
+	if err != nil {
+		return nil, err
+	}
+	token, err := o.repo.GetToken(username)
+	if err != nil {
+		return nil, err
+	}
+
+	o.uc.events.Publish(EventTenantLoggedIn, username, nil, nil)
+	return &v1.OidcCallbackReply{Success: true, Username: username, Token: token}, nil
+}
+
+// 依次尝试配置的claim，将其映射为满足注册规则的用户名，与已有的用户名或保留的名称冲突时添加随机后缀
+func (o *OidcUsecase) username(claims map[string]interface{}) (string, error) {
+	var name string
+	for _, claim := range o.usernameClaims {
+		if value, _ := claims[claim].(string); value != "" {
+			name = sanitizeUsername(value)
+			break
+		}
+	}
+	if name == "" {
+		return "", errors.Forbidden("Oidc_Error", fmt.Sprintf(
+			"id token中缺少作为用户名的claim %v", strings.Join(o.usernameClaims, "、")))
+	}
+
+	// 保留的名称视为已被使用
+	candidate := name
+	for i := 0; i < oidcUsernameAttempts; i++ {
+		taken := reservedUsernames[candidate]
+		if !taken {
+			var err error
+			taken, err = o.repo.UsernameTaken(candidate)
+			if err != nil {
+				return "", err
+			}
+		}
+		if !taken {
+			return candidate, nil
+		}
+
+		suffix := make([]byte, 2)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", errors.Newf(500, "Oidc_Error", "生成用户名的随机后缀时发生了错误:%v", err)
+		}
+		base := name
+		if len(base) > maxUsernameLength-5 {
+			base = base[:maxUsernameLength-5]
+		}
+		candidate = base + "_" + hex.EncodeToString(suffix)
+	}
+	return "", errors.Conflict("Oidc_Error", fmt.Sprintf("用户名 %v 已被使用，且无法生成可用的用户名", name))
+}
+
+// 保存首次注册凭证或二次验证凭证，返回凭证
+func (o *OidcUsecase) saveTicket(identity *oidcIdentity) (string, error) {
+	ticket, err := randomString(32)
+	if err != nil {
+		return "", errors.Newf(500, "Oidc_Error", "生成凭证时发生了错误:%v", err)
+	}
+	marshal, err := json.Marshal(identity)
+	if err != nil {
+		return "", errors.Newf(500, "Oidc_Error", "对身份信息进行json序列化时发生了错误:%v", err)
+	}
+	err = o.repo.SaveOidcTicket(ticket, marshal, oidcTicketExpiration)
+	if err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// PrepareRegister 以注册凭证对应的身份填充注册请求中的用户信息，
+// 用户名由claim决定，密码随机生成，用户之后通过OIDC登录，需要时可以重置密码
+func (o *OidcUsecase) PrepareRegister(request *v1.RegisterRequest) error {
+	identity, err := o.ticket(request.OidcTicket, false)
+	if err != nil {
+		return err
+	}
+	password, err := randomPassword()
+	if err != nil {
+		return errors.Newf(500, "Oidc_Error", "生成随机密码时发生了错误:%v", err)
+	}
+
+	request.User = &utilApi.User{Id: identity.Username, Password: password}
+	return nil
+}
+
+// Register 以OIDC身份完成首次注册，身份提供方已确认用户的身份，因此不再进行邮箱验证。
+// 身份在创建资源之前关联到租户，使同一身份的并发注册只有一个能够进行，注册失败时撤销关联；
+// 注册凭证在注册成功后才被消耗，使校验失败或创建资源失败时可以以同一凭证重试
+func (o *OidcUsecase) Register(request *v1.RegisterRequest) (token string, err error) {
+	identity, err := o.ticket(request.OidcTicket, false)
+	if err != nil {
+		return "", err
+	}
+	if request.User == nil || request.User.Id != identity.Username {
+		return "", errors.BadRequest("Oidc_Error", "注册的用户名与OIDC身份不符")
+	}
+	if username, err := o.repo.GetOidcSubject(identity.Subject); err != nil {
+		return "", err
+	} else if username != "" {
+		return "", errors.Conflict("Oidc_Error", "OIDC身份已关联租户")
+	}
+
+	err = o.repo.LinkOidcSubject(identity.Subject, identity.Username)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if e := o.repo.UnlinkOidcSubject(identity.Subject, identity.Username); e != nil {
+			o.logger.Errorf("撤销OIDC身份 %v 与用户 %v 的关联时发生了错误:%v", identity.Subject, identity.Username, e)
+		}
+	}()
+
+	// 注册信息中不保存注册凭证
+	ticket := request.OidcTicket
+	request.OidcTicket = ""
+	token, err = o.uc.Register(request)
+	if err != nil {
+		return "", err
+	}
+
+	// 身份已关联租户，凭证无法再用于注册，因此删除失败时只记录日志，凭证过期后自动删除
+	if _, e := o.repo.TakeOidcTicket(ticket); e != nil {
+		o.logger.Errorf("删除用户 %v 的OIDC注册凭证时发生了错误:%v", identity.Username, e)
+	}
+	o.logger.Infof("用户 %v 以OIDC身份 %v 完成了注册", identity.Username, identity.Subject)
+	return token, nil
+}
+
+// AdminSession 管理令牌是否为通过OIDC登录获得的有效令牌，有效时同时返回管理员的subject
+func (o *OidcUsecase) AdminSession(token string) (subject string, ok bool) {
+	if !o.enabled || token == "" {
+		return "", false
+	}
+	subject, err := o.repo.GetAdminSession(resetTokenDigest(token))
+	if err != nil {
+		o.logger.Errorf("查询管理令牌时发生了错误:%v", err)
+		return "", false
+	}
+	return subject, subject != ""
+}
+
+// 确认身份满足管理员claim后生成管理令牌
+func (o *OidcUsecase) adminSession(subject string, claims map[string]interface{}) (*v1.OidcCallbackReply, error) {
+	if !claimContains(claims[o.adminClaim], o.adminValue) {
+		return nil, errors.Forbidden("Oidc_Error", "OIDC身份不具有管理员权限")
+	}
+
+	token, err := randomString(32)
+	if err != nil {
+		return nil, errors.Newf(500, "Oidc_Error", "生成管理令牌时发生了错误:%v", err)
+	}
+	err = o.repo.SaveAdminSession(resetTokenDigest(token), subject, o.adminExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	o.logger.Infof("管理员 %v 通过OIDC登录", subject)
+	return &v1.OidcCallbackReply{
+		Success:         true,
+		AdminToken:      token,
+		AdminExpireTime: timestamppb.New(time.Now().Add(o.adminExpiration)),
+	}, nil
+}
+
+// 获得注册凭证对应的身份，take为true时同时删除凭证
+func (o *OidcUsecase) ticket(ticket string, take bool) (*oidcIdentity, error) {
+	return o.getTicket(ticket, take, false)
+}
+
+// 获得凭证对应的身份，twoFactor表示凭证的类型，凭证类型不符时视为无效，take为true时同时删除凭证
+func (o *OidcUsecase) getTicket(ticket string, take, twoFactor bool) (*oidcIdentity, error) {
+	if !o.enabled {
+		return nil, errors.NotFound("Oidc_Error", "服务未开启OIDC登录")
+	}
+
+	var (
+		marshal []byte
+		err     error
+	)
+	if take {
+		marshal, err = o.repo.TakeOidcTicket(ticket)
+	} else {
+		marshal, err = o.repo.GetOidcTicket(ticket)
+	}
+	if err != nil {
+		return nil, err
+	}
+	identity := new(oidcIdentity)
+	if marshal == nil || json.Unmarshal(marshal, identity) != nil || identity.TwoFactor != twoFactor {
+		return nil, errors.Forbidden("Oidc_Error", "凭证无效或已过期")
+	}
+	return identity, nil
+}
+
+// 使用授权码以及PKCE的code verifier向身份提供方换取id token
+func (o *OidcUsecase) exchange(code, verifier string) (string, error) {
+	discovery, err := o.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", o.redirectUrl)
+	form.Set("client_id", o.clientID)
+	form.Set("code_verifier", verifier)
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Newf(500, "Oidc_Error", "创建换取token的请求时发生了错误:%v", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if o.clientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+	}
+
+	var reply struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := o.doJson(request, &reply)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK || reply.IdToken == "" {
+		return "", errors.Forbidden("Oidc_Error", fmt.Sprintf(
+			"换取token失败，状态码:%v，错误:%v %v", status, reply.Error, reply.ErrorDescription))
+	}
+	return reply.IdToken, nil
+}
+
+// 校验id token的签名、issuer、audience、有效期以及nonce，返回其中的claims
+func (o *OidcUsecase) verify(rawIdToken, nonce string) (map[string]interface{}, error) {
+	invalid := func(reason string) error {
+		return errors.Forbidden("Oidc_Error", "id token无效:"+reason)
+	}
+
+	parts := strings.Split(rawIdToken, ".")
+	if len(parts) != 3 {
+		return nil, invalid("格式错误")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJwtPart(parts[0], &header); err != nil {
+		return nil, invalid("头部格式错误")
+	}
+	if header.Alg != "RS256" {
+		return nil, invalid("不支持的签名算法" + header.Alg)
+	}
+	key, err := o.getKey(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("签名格式错误")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, invalid("签名错误")
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeJwtPart(parts[1], &claims); err != nil {
+		return nil, invalid("claims格式错误")
+	}
+	if iss, _ := claims["iss"].(string); iss != o.issuer {
+		return nil, invalid("issuer不符")
+	}
+	if !claimContains(claims["aud"], o.clientID) {
+		return nil, invalid("audience不符")
+	}
+	exp, _ := claims["exp"].(float64)
+	if time.Now().Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return nil, invalid("已过期")
+	}
+	if n, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(n), []byte(nonce)) != 1 {
+		return nil, invalid("nonce不符")
+	}
+
+	return claims, nil
+}
+
+// 获得身份提供方的配置信息，获取成功后缓存
+func (o *OidcUsecase) getDiscovery() (*oidcDiscovery, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, o.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, errors.Newf(500, "Oidc_Error", "创建获取身份提供方配置的请求时发生了错误:%v", err)
+	}
+	discovery := new(oidcDiscovery)
+	status, err := o.doJson(request, discovery)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.Newf(502, "Oidc_Error", "获取身份提供方的配置失败，状态码:%v", status)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != o.issuer {
+		return nil, errors.Newf(502, "Oidc_Error", "身份提供方的issuer %v 与配置不符", discovery.Issuer)
+	}
+
+	o.discovery = discovery
+	return discovery, nil
+}
+
+// 获得签名id token的公钥，kid未知时重新获取身份提供方的公钥，以支持密钥轮换，
+// 重新获取的间隔不小于oidcKeysRefreshInterval
+func (o *OidcUsecase) getKey(kid string) (*rsa.PublicKey, error) {
+	discovery, err := o.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if key, ok := o.keys[kid]; ok {
+		return key, nil
+	}
+	unknown := errors.Forbidden("Oidc_Error", fmt.Sprintf("id token无效:未知的签名公钥%v", kid))
+	if time.Since(o.keysFetched) < oidcKeysRefreshInterval {
+		return nil, unknown
+	}
+	o.keysFetched = time.Now()
+
+	request, err := http.NewRequest(http.MethodGet, discovery.JwksUri, nil)
+	if err != nil {
+		return nil, errors.Newf(500, "Oidc_Error", "创建获取签名公钥的请求时发生了错误:%v", err)
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	status, err := o.doJson(request, &jwks)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errors.Newf(502, "Oidc_Error", "获取身份提供方的签名公钥失败，状态码:%v", status)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	o.keys = keys
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, unknown
+	}
+	return key, nil
+}
+
+// 发送请求并将json响应解码到v中，返回响应的状态码
+func (o *OidcUsecase) doJson(request *http.Request, v interface{}) (int, error) {
+	response, err := o.client.Do(request)
+	if err != nil {
+		return 0, errors.Newf(502, "Oidc_Error", "请求身份提供方时发生了错误:%v", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, errors.Newf(502, "Oidc_Error", "读取身份提供方的响应时发生了错误:%v", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil && response.StatusCode == http.StatusOK {
+		return 0, errors.Newf(502, "Oidc_Error", "对身份提供方的响应进行json解码时发生了错误:%v", err)
+	}
+	return response.StatusCode, nil
+}
+
+// claim为字符串时判断是否相等，为数组时判断是否包含value
+func claimContains(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []interface{}:
+		for _, v := range c {
+			if s, ok := v.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeJwtPart(part string, v interface{}) error {
+	marshal, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(marshal, v)
+}
+
+// 生成n字节随机数的base64url编码
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 生成满足用户密码校验规则的随机密码，即12位的字母与数字
+func randomPassword() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// 将claim的取值映射为满足注册规则的用户名，即3到12位的小写字母、数字与下划线，
+// 取值为邮箱时只使用@之前的部分，其余不允许的字符替换为下划线或直接去除
+func sanitizeUsername(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.Index(value, "@"); i > 0 {
+		value = value[:i]
+	}
+
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		case r == '.', r == '-', r == ' ':
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" {
+		return ""
+	}
+	if len(name) < minUsernameLength {
+		name = "user_" + name
+	}
+	if len(name) > maxUsernameLength {
+		name = name[:maxUsernameLength]
+	}
+	return name
+}
@@ -0,0 +1,536 @@
+package biz
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的OIDC登录信息，不处理过期
+type memoryOidcRepo struct {
+	mutex    sync.Mutex
+	values   map[string][]byte
+	subjects map[string]string
+	tokens   map[string]string
+	taken    map[string]bool
+}
+
+func newMemoryOidcRepo() *memoryOidcRepo {
+	return &memoryOidcRepo{
+		values:   make(map[string][]byte),
+		subjects: make(map[string]string),
+		tokens:   make(map[string]string),
+		taken:    make(map[string]bool),
+	}
+}
+
+func (r *memoryOidcRepo) save(key string, value []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.values[key] = value
+	return nil
+}
+
+func (r *memoryOidcRepo) take(key string, remove bool) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	value := r.values[key]
+	if remove {
+		delete(r.values, key)
+	}
+	return value, nil
+}
+
+func (r *memoryOidcRepo) SaveOidcState(state string, record []byte, ttl time.Duration) error {
+	return r.save("state:"+state, record)
+}
+
+func (r *memoryOidcRepo) TakeOidcState(state string) ([]byte, error) {
+	return r.take("state:"+state, true)
+}
+
+func (r *memoryOidcRepo) SaveOidcTicket(ticket string, identity []byte, ttl time.Duration) error {
+	return r.save("ticket:"+ticket, identity)
+}
+
+func (r *memoryOidcRepo) GetOidcTicket(ticket string) ([]byte, error) {
+	return r.take("ticket:"+ticket, false)
+}
+
+func (r *memoryOidcRepo) TakeOidcTicket(ticket string) ([]byte, error) {
+	return r.take("ticket:"+ticket, true)
+}
+
+func (r *memoryOidcRepo) LinkOidcSubject(subject, username string) error {
+	if _, ok := r.subjects[subject]; ok {
+		return errors.Conflict("Repo_Error", "OIDC身份已关联租户")
+	}
+	r.subjects[subject] = username
+	return nil
+}
+
+func (r *memoryOidcRepo) UnlinkOidcSubject(subject, username string) error {
+	if r.subjects[subject] == username {
+		delete(r.subjects, subject)
+	}
+	return nil
+}
+
+func (r *memoryOidcRepo) GetOidcSubject(subject string) (string, error) {
+	return r.subjects[subject], nil
+}
+
+func (r *memoryOidcRepo) UsernameTaken(username string) (bool, error) {
+	return r.taken[username], nil
+}
+
+func (r *memoryOidcRepo) GetToken(username string) (string, error) {
+	return r.tokens[username], nil
+}
+
+func (r *memoryOidcRepo) SaveAdminSession(digest, subject string, ttl time.Duration) error {
+	return r.save("admin:"+digest, []byte(subject))
+}
+
+func (r *memoryOidcRepo) GetAdminSession(digest string) (string, error) {
+	value, _ := r.take("admin:"+digest, false)
+	return string(value), nil
+}
+
+// 本地的身份提供方，授权端点直接以claims签发授权码，令牌端点校验PKCE后签发RS256签名的id token
+type testIssuer struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	mutex  sync.Mutex
+	claims map[string]interface{}
+	grants map[string]url.Values
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{key: key, grants: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/authorize",
+			"token_endpoint":         issuer.URL + "/token",
+			"jwks_uri":               issuer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+			http.Error(w, "missing pkce", http.StatusBadRequest)
+			return
+		}
+		code, _ := randomString(16)
+		issuer.mutex.Lock()
+		issuer.grants[code] = query
+		issuer.mutex.Unlock()
+
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		issuer.mutex.Lock()
+		grant, ok := issuer.grants[r.PostForm.Get("code")]
+		delete(issuer.grants, r.PostForm.Get("code"))
+		issuer.mutex.Unlock()
+
+		clientID, secret, _ := r.BasicAuth()
+		challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || clientID != "client" || secret != "secret" ||
+			base64.RawURLEncoding.EncodeToString(challenge[:]) != grant.Get("code_challenge") ||
+			r.PostForm.Get("redirect_uri") != grant.Get("redirect_uri") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := map[string]interface{}{
+			"iss":   issuer.URL,
+			"aud":   grant.Get("client_id"),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": grant.Get("nonce"),
+		}
+		for k, v := range issuer.claims {
+			claims[k] = v
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": issuer.sign(claims)})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	return issuer
+}
+
+func (i *testIssuer) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signing := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signing))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	return signing + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// 以给定的claims完成授权，返回回调地址中的code与state
+func (i *testIssuer) authorize(t *testing.T, authorizationUrl string, claims map[string]interface{}) (code, state string) {
+	i.claims = claims
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Get(authorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil || response.StatusCode != http.StatusFound {
+		t.Fatalf("授权失败，状态码:%v", response.StatusCode)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func newTestOidcUsecase(issuer *testIssuer, repo OidcRepo) *OidcUsecase {
+	return &OidcUsecase{
+		uc: &UserUsecase{
+			events:    new(EventPublisher),
+			deletions: newMemoryDeletionRepo(),
+			twoFactor: &TwoFactorUsecase{repo: newMemoryTwoFactorRepo(), maxFailures: 5, logger: log.NewHelper(log.DefaultLogger)},
+		},
+		repo:            repo,
+		enabled:         true,
+		issuer:          issuer.URL,
+		clientID:        "client",
+		clientSecret:    "secret",
+		redirectUrl:     "https://service-centre.example.com/oidc/callback",
+		scopes:          []string{"openid"},
+		usernameClaims:  []string{"preferred_username", "email"},
+		adminClaim:      "groups",
+		adminValue:      "admins",
+		adminExpiration: time.Hour,
+		client:          &http.Client{Timeout: time.Second},
+		keys:            make(map[string]*rsa.PublicKey),
+		logger:          log.NewHelper(log.DefaultLogger),
+	}
+}
+
+func TestOidcUsecase_Callback(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.Close()
+	repo := newMemoryOidcRepo()
+	oidc := newTestOidcUsecase(issuer, repo)
+	login := func(admin bool, claims map[string]interface{}) (*v1.OidcCallbackReply, error) {
+		authorizationUrl, state, err := oidc.Login(admin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, returned := issuer.authorize(t, authorizationUrl, claims)
+		if returned != state {
+			t.Fatalf("回调中的state应为%v，实际为%v", state, returned)
+		}
+		return oidc.Callback(code, state, "")
+	}
+	alice := map[string]interface{}{"sub": "alice-subject", "preferred_username": "Alice"}
+
+	// 未关联租户的身份获得注册凭证，并可以不填写密码完成注册信息
+	reply, err := login(false, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Token != "" || reply.RegistrationTicket == "" || reply.Username != "alice" {
+		t.Fatalf("未关联租户的身份应获得注册凭证:%v", reply)
+	}
+	request := &v1.RegisterRequest{OidcTicket: reply.RegistrationTicket}
+	if err := oidc.PrepareRegister(request); err != nil {
+		t.Fatal(err)
+	}
+	if request.User.Id != "alice" {
+		t.Fatalf("注册的用户名应为alice，实际为%v", request.User.Id)
+	}
+	if err := request.User.Validate(); err != nil {
+		t.Fatalf("随机生成的密码应满足校验规则:%v", err)
+	}
+
+	// 已关联租户的身份直接获得租户的token
+	repo.LinkOidcSubject("alice-subject", "alice")
+	repo.tokens["alice"] = "alice-token"
+	reply, err = login(false, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Token != "alice-token" || reply.RegistrationTicket != "" {
+		t.Fatalf("已关联租户的身份应获得租户的token:%v", reply)
+	}
+
+	// 满足管理员claim的身份获得管理令牌
+	if _, err := login(true, alice); err == nil {
+		t.Fatal("不具有管理员claim的身份不应获得管理令牌")
+	}
+	reply, err = login(true, map[string]interface{}{"sub": "bob-subject", "groups": []string{"users", "admins"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, ok := oidc.AdminSession(reply.AdminToken); !ok || subject != "bob-subject" {
+		t.Fatal("管理令牌应在通过OIDC登录后有效")
+	}
+	if _, ok := oidc.AdminSession("unknown"); ok {
+		t.Fatal("未知的管理令牌不应有效")
+	}
+}
+
+func TestOidcUsecase_Register(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.Close()
+	repo := newMemoryOidcRepo()
+	oidc := newTestOidcUsecase(issuer, repo)
+	oidc.uc.plans = &retentionPlans{}
+	oidc.uc.logger = log.NewHelper(log.DefaultLogger)
+	ticket, err := oidc.saveTicket(&oidcIdentity{Subject: "alice-subject", Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := func(plan string) *v1.RegisterRequest {
+		r := &v1.RegisterRequest{OidcTicket: ticket, Plan: plan}
+		if err := oidc.PrepareRegister(r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// 用户名与身份不符时保留凭证
+	mismatched := request("")
+	mismatched.User.Id = "bob"
+	if _, err := oidc.Register(mismatched); errors.Code(err) != 400 {
+		t.Fatalf("用户名与身份不符时应返回400:%v", err)
+	}
+
+	// 注册失败时撤销关联并保留凭证，可以以同一凭证重试
+	if _, err := oidc.Register(request("premium")); errors.Code(err) != 403 {
+		t.Fatalf("注册失败时应返回注册的错误:%v", err)
+	}
+	if repo.subjects["alice-subject"] != "" {
+		t.Fatal("注册失败时应撤销OIDC身份的关联")
+	}
+	retry := request("")
+	if retry.User.Id != "alice" {
+		t.Fatalf("注册失败后凭证应仍然有效:%v", retry)
+	}
+
+	// 身份已关联租户时拒绝注册
+	repo.LinkOidcSubject("alice-subject", "alice")
+	if _, err := oidc.Register(retry); errors.Code(err) != 409 {
+		t.Fatalf("身份已关联租户时应返回409:%v", err)
+	}
+	if repo.subjects["alice-subject"] != "alice" {
+		t.Fatal("不应撤销已有的关联")
+	}
+}
+
+func TestOidcUsecase_CallbackRejected(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.Close()
+	repo := newMemoryOidcRepo()
+	oidc := newTestOidcUsecase(issuer, repo)
+	claims := map[string]interface{}{"sub": "alice-subject", "preferred_username": "alice"}
+
+	// state只能使用一次
+	authorizationUrl, state, err := oidc.Login(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := issuer.authorize(t, authorizationUrl, claims)
+	if _, err := oidc.Callback(code, state, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := oidc.Callback(code, state, ""); err == nil {
+		t.Fatal("重复使用的state不应通过")
+	}
+
+	// code verifier与授权请求中的challenge不符时无法换取token
+	authorizationUrl, state, err = oidc.Login(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ = issuer.authorize(t, authorizationUrl, claims)
+	record := new(oidcState)
+	json.Unmarshal(repo.values["state:"+state], record)
+	record.Verifier = "tampered"
+	repo.values["state:"+state], _ = json.Marshal(record)
+	if _, err := oidc.Callback(code, state, ""); err == nil {
+		t.Fatal("code verifier错误时不应通过")
+	}
+
+	// 签名被篡改的id token无法通过校验
+	token := issuer.sign(map[string]interface{}{
+		"iss": issuer.URL, "aud": "client", "exp": time.Now().Add(time.Hour).Unix(), "nonce": "nonce", "sub": "alice",
+	})
+	if _, err := oidc.verify(token, "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	forged, _ := json.Marshal(map[string]interface{}{
+		"iss": issuer.URL, "aud": "client", "exp": time.Now().Add(time.Hour).Unix(), "nonce": "nonce", "sub": "admin",
+	})
+	parts[1] = base64.RawURLEncoding.EncodeToString(forged)
+	if _, err := oidc.verify(strings.Join(parts, "."), "nonce"); err == nil {
+		t.Fatal("签名被篡改的id token不应通过校验")
+	}
+	if _, err := oidc.verify(token, "other"); err == nil {
+		t.Fatal("nonce不符的id token不应通过校验")
+	}
+}
+
+func TestOidcUsecase_CallbackTwoFactor(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.Close()
+	repo := newMemoryOidcRepo()
+	oidc := newTestOidcUsecase(issuer, repo)
+	aead, _ := newAEAD("test")
+	oidc.uc.twoFactor.aead = aead
+	repo.LinkOidcSubject("alice-subject", "alice")
+	repo.tokens["alice"] = "alice-token"
+
+	secret, _, err := oidc.uc.twoFactor.Enroll("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if _, err := oidc.uc.twoFactor.Verify("alice", totpCode(key, time.Now().Unix()/totpPeriod-1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 启用了二次验证的租户通过OIDC登录时不直接获得token
+	authorizationUrl, state, err := oidc.Login(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := issuer.authorize(t, authorizationUrl, map[string]interface{}{"sub": "alice-subject"})
+	reply, err := oidc.Callback(code, state, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Token != "" || reply.TwoFactorTicket == "" {
+		t.Fatalf("启用二次验证的租户应获得二次验证凭证而不是token:%v", reply)
+	}
+
+	// 二次验证凭证不能用于注册，验证码错误时凭证仍然有效
+	if err := oidc.PrepareRegister(&v1.RegisterRequest{OidcTicket: reply.TwoFactorTicket}); err == nil {
+		t.Fatal("二次验证凭证不应用于注册")
+	}
+	if _, err := oidc.CompleteTwoFactor(reply.TwoFactorTicket, "000000"); err == nil {
+		t.Fatal("验证码错误时不应完成登录")
+	}
+	completed, err := oidc.CompleteTwoFactor(reply.TwoFactorTicket, totpCode(key, time.Now().Unix()/totpPeriod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.Token != "alice-token" {
+		t.Fatalf("通过二次验证后应获得租户的token:%v", completed)
+	}
+	if _, err := oidc.CompleteTwoFactor(reply.TwoFactorTicket, "000000"); err == nil {
+		t.Fatal("二次验证凭证只能使用一次")
+	}
+}
+
+func TestOidcUsecase_username(t *testing.T) {
+	repo := newMemoryOidcRepo()
+	oidc := &OidcUsecase{repo: repo, usernameClaims: []string{"preferred_username", "email"}}
+	cases := []struct {
+		claims   map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"preferred_username": "Alice.Smith"}, "alice_smith"},
+		{map[string]interface{}{"email": "Bob-Jones@example.com"}, "bob_jones"},
+		{map[string]interface{}{"preferred_username": "张三", "email": "zs@example.com"}, ""},
+		{map[string]interface{}{"preferred_username": "a very long display name"}, "a_very_long_"},
+		{map[string]interface{}{"preferred_username": "jo"}, "user_jo"},
+	}
+	for _, c := range cases {
+		name, err := oidc.username(c.claims)
+		if c.expected == "" {
+			if err == nil {
+				t.Errorf("无法映射为用户名的claims %v 应返回错误，实际为%v", c.claims, name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != c.expected {
+			t.Errorf("claims %v 映射的用户名应为%v，实际为%v", c.claims, c.expected, name)
+		}
+	}
+
+	// 用户名已被使用时添加随机后缀
+	repo.taken["alice_smith"] = true
+	name, err := oidc.username(map[string]interface{}{"preferred_username": "alice.smith"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(name, "alice_") || name == "alice_smith" || len(name) > maxUsernameLength {
+		t.Fatalf("用户名冲突时应添加随机后缀，实际为%v", name)
+	}
+
+	// 保留的名称视为已被使用
+	name, err = oidc.username(map[string]interface{}{"preferred_username": "usage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(name, "usage_") {
+		t.Fatalf("保留的名称应添加随机后缀，实际为%v", name)
+	}
+}
+
+func TestOidcUsecase_getKey(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.Close()
+	oidc := newTestOidcUsecase(issuer, newMemoryOidcRepo())
+
+	if _, err := oidc.getKey("test"); err != nil {
+		t.Fatal(err)
+	}
+	// 短时间内出现未知的kid时不重新获取签名公钥
+	fetched := oidc.keysFetched
+	if _, err := oidc.getKey("unknown"); err == nil {
+		t.Fatal("未知的kid不应通过")
+	}
+	if !oidc.keysFetched.Equal(fetched) {
+		t.Fatal("短时间内不应因未知的kid重新获取签名公钥")
+	}
+	oidc.keysFetched = time.Now().Add(-oidcKeysRefreshInterval)
+	if _, err := oidc.getKey("unknown"); err == nil {
+		t.Fatal("未知的kid不应通过")
+	}
+	if oidc.keysFetched.Equal(fetched) {
+		t.Fatal("间隔足够时应重新获取签名公钥")
+	}
+}
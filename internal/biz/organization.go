@@ -0,0 +1,425 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+	"time"
+)
+
+// 邀请的有效期
+const invitationExpiration = 7 * 24 * time.Hour
+
+// OrganizationUsecase 管理组织的成员，组织即注册的用户，拥有为其创建的服务、bucket以及网关组件，
+// 注册的用户为组织的owner，其余成员通过邀请加入，每个成员拥有自己的账号密码以及api密钥，
+// 成员在网关中的consumer被加入组织的acl分组，从而只能访问组织自身的服务
+type OrganizationUsecase struct {
+	repo    OrgRepo
+	uc      *UserUsecase
+	gateway gateway.Provider
+	logger  *log.Helper
+}
+type OrgRepo interface {
+	// SaveInvitation 保存邀请，邀请在ttl后自动删除
+	SaveInvitation(code string, invitation []byte, ttl time.Duration) error
+	// GetInvitation 获得邀请但不删除，不存在或已过期时返回nil
+	GetInvitation(code string) ([]byte, error)
+	// TakeInvitation 获得并删除邀请，保证邀请只被使用一次，不存在或已过期时返回nil
+	TakeInvitation(code string) ([]byte, error)
+	// AddMember 保存组织的成员，成员的账号与已注册的用户或已有的成员重复时返回错误
+	AddMember(org, member string, record []byte) error
+	// GetMember 获得成员的信息，不存在时返回nil
+	GetMember(member string) ([]byte, error)
+	// UpdateMember 更新成员的信息
+	UpdateMember(member string, record []byte) error
+	// RemoveMember 删除组织的成员
+	RemoveMember(org, member string) error
+	// ListMembers 获得组织所有成员的信息
+	ListMembers(org string) ([][]byte, error)
+	// ListOrgACLEnabled 获得已为服务启用acl插件的所有组织
+	ListOrgACLEnabled() ([]string, error)
+	// MarkOrgACLEnabled 记录组织已为服务启用acl插件
+	MarkOrgACLEnabled(org string) error
+}
+
+// 保存在数据库中的成员信息
+type memberRecord struct {
+	ID         string    `json:"id"`
+	Org        string    `json:"org"`
+	Role       v1.Role   `json:"role"`
+	Password   string    `json:"password"`
+	Token      string    `json:"token"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// 保存在数据库中的邀请
+type invitationRecord struct {
+	Org  string  `json:"org"`
+	Role v1.Role `json:"role"`
+}
+
+func NewOrganizationUsecase(uc *UserUsecase, repo OrgRepo, logger log.Logger) *OrganizationUsecase {
+	return &OrganizationUsecase{
+		repo:    repo,
+		uc:      uc,
+		gateway: uc.gateway,
+		logger:  log.NewHelper(logger),
+	}
+}
+
+// Login 组织的成员或owner登录，返回各自的token，账号启用了二次验证时，code需要为有效的验证码或恢复码
+func (o *OrganizationUsecase) Login(username, password, code string) (string, error) {
+	member, err := o.getMember(username)
+	if err != nil {
+		return "", err
+	}
+	if member == nil {
+		return o.uc.Login(username, password, code)
+	}
+
+	if member.Password != password {
+		return "", errors.New(400, "Repo_Error", "用户账号或者密码错误")
+	}
+	err = o.uc.checkPending(member.Org)
+	if err != nil {
+		return "", err
+	}
+	err = o.uc.twoFactor.Check(username, code)
+	if err != nil {
+		return "", err
+	}
+	return member.Token, nil
+}
+
+// Authorize 确认token对应的成员在其组织中的角色不低于min，返回组织以及成员的用户名与角色，
+// 组织已注销并等待清理时返回错误
+func (o *OrganizationUsecase) Authorize(token string, min v1.Role) (org, member string, role v1.Role, err error) {
+	org, member, role, err = o.AuthorizePending(token, min)
+	if err != nil {
+		return "", "", 0, err
+	}
+	err = o.uc.checkPending(org)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return org, member, role, nil
+}
+
+// AuthorizePending 与Authorize相同，但允许已注销并等待清理的组织，用于在宽限期内导出数据
+func (o *OrganizationUsecase) AuthorizePending(token string, min v1.Role) (org, member string, role v1.Role, err error) {
+	member, org, err = o.gateway.GetConsumerOfToken(token)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	role = v1.Role_OWNER
+	if member != org {
+		record, err := o.getMember(member)
+		if err != nil {
+			return "", "", 0, err
+		}
+		if record == nil || record.Org != org {
+			return "", "", 0, errors.Forbidden("Org_Error", "token对应的成员不属于该组织")
+		}
+		role = record.Role
+	}
+
+	if role < min {
+		return "", "", 0, errors.Forbidden(
+			"Org_Error", fmt.Sprintf("该操作需要%v及以上的角色，当前角色为%v", min, role))
+	}
+	return org, member, role, nil
+}
+
+// Identify 获得token对应的成员以及其所属的组织，token无效时返回空字符串
+func (o *OrganizationUsecase) Identify(token string) (member, org string) {
+	member, org, err := o.gateway.GetConsumerOfToken(token)
+	if err != nil {
+		return "", ""
+	}
+	return member, org
+}
+
+// CreateInvitation 邀请成员加入token所属的组织，只能邀请角色低于自身的成员
+func (o *OrganizationUsecase) CreateInvitation(token string, role v1.Role) (*v1.Invitation, error) {
+	org, _, callerRole, err := o.Authorize(token, v1.Role_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+	if role >= callerRole {
+		return nil, errors.Forbidden("Org_Error", "只能邀请角色低于自身的成员")
+	}
+
+	code := make([]byte, 16)
+	if _, err := rand.Read(code); err != nil {
+		return nil, errors.Newf(500, "Org_Error", "生成邀请码时发生了错误:%v", err)
+	}
+	marshal, err := json.Marshal(&invitationRecord{Org: org, Role: role})
+	if err != nil {
+		return nil, errors.Newf(500, "Org_Error", "对邀请进行json序列化时发生了错误:%v", err)
+	}
+	invitation := &v1.Invitation{
+		Code:       hex.EncodeToString(code),
+		Org:        org,
+		Role:       role,
+		ExpireTime: timestamppb.New(time.Now().Add(invitationExpiration)),
+	}
+	err = o.repo.SaveInvitation(invitation.Code, marshal, invitationExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation 接受邀请，创建组织的成员以及其在网关中的consumer，返回成员的token
+func (o *OrganizationUsecase) AcceptInvitation(code string, user *utilApi.User) (token string, err error) {
+	if user == nil {
+		return "", errors.BadRequest("user is nil", "")
+	}
+	if err := checkReservedUsername(user.Id); err != nil {
+		return "", err
+	}
+
+	// 邀请在成员创建完成后才被消耗，使创建失败时邀请仍然可用
+	invalid := errors.Forbidden("Org_Error", "邀请码无效或已过期")
+	marshal, err := o.repo.GetInvitation(code)
+	if err != nil {
+		return "", err
+	}
+	invitation := new(invitationRecord)
+	if marshal == nil || json.Unmarshal(marshal, invitation) != nil {
+		return "", invalid
+	}
+
+	// 成员的consumer与已注册用户的consumer重名时，网关会拒绝创建
+	token, err = o.gateway.CreateMemberConsumer(invitation.Org, user.Id)
+	if err != nil {
+		return "", errors.Newf(
+			500, "Org_Error",
+			"创建成员在网关中的consumer时发生了错误:%v", err)
+	}
+	defer func() {
+		if err != nil {
+			o.gateway.DeleteConsumer(user.Id)
+		}
+	}()
+
+	err = o.gateway.SetACLGroups(invitation.Org, user.Id, invitation.Role >= v1.Role_OPERATOR)
+	if err != nil {
+		return "", errors.Newf(
+			500, "Org_Error",
+			"将成员加入组织的acl分组时发生了错误:%v", err)
+	}
+
+	err = o.saveMember(&memberRecord{
+		ID:         user.Id,
+		Org:        invitation.Org,
+		Role:       invitation.Role,
+		Password:   user.Password,
+		Token:      token,
+		CreateTime: time.Now().UTC(),
+	}, true)
+	if err != nil {
+		return "", err
+	}
+
+	// 消耗邀请，邀请已被同时接受时撤销创建的成员，保证邀请只被使用一次
+	marshal, err = o.repo.TakeInvitation(code)
+	if err == nil && marshal == nil {
+		err = invalid
+	}
+	if err != nil {
+		if rmErr := o.repo.RemoveMember(invitation.Org, user.Id); rmErr != nil {
+			o.logger.Errorf("撤销组织 %v 的成员 %v 时发生了错误:%v", invitation.Org, user.Id, rmErr)
+		}
+		return "", err
+	}
+
+	o.logger.Infof("用户 %v 以 %v 的角色加入了组织 %v", user.Id, invitation.Role, invitation.Org)
+	return token, nil
+}
+
+// ListMembers 获得token所属组织的所有成员，owner排在最前
+func (o *OrganizationUsecase) ListMembers(token string) (string, []*v1.Member, error) {
+	org, _, _, err := o.Authorize(token, v1.Role_VIEWER)
+	if err != nil {
+		return "", nil, err
+	}
+
+	records, err := o.repo.ListMembers(org)
+	if err != nil {
+		return "", nil, err
+	}
+
+	members := make([]*v1.Member, 0, len(records)+1)
+	for _, r := range records {
+		record := new(memberRecord)
+		if err := json.Unmarshal(r, record); err != nil {
+			return "", nil, errors.Newf(500, "Org_Error", "对成员信息进行json解码时发生了错误:%v", err)
+		}
+		members = append(members, &v1.Member{
+			Id:         record.ID,
+			Role:       record.Role,
+			CreateTime: timestamppb.New(record.CreateTime),
+		})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Id < members[j].Id })
+
+	return org, append([]*v1.Member{{Id: org, Role: v1.Role_OWNER}}, members...), nil
+}
+
+// UpdateMemberRole 修改成员的角色，只能管理角色低于自身的成员，且修改后的角色同样需要低于自身
+func (o *OrganizationUsecase) UpdateMemberRole(token, memberID string, role v1.Role) (*v1.Member, error) {
+	org, record, err := o.manageable(token, memberID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.gateway.SetACLGroups(org, memberID, role >= v1.Role_OPERATOR)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Org_Error",
+			"更新成员在组织中的acl分组时发生了错误:%v", err)
+	}
+	record.Role = role
+	err = o.saveMember(record, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Member{Id: memberID, Role: role, CreateTime: timestamppb.New(record.CreateTime)}, nil
+}
+
+// RemoveMember 移除组织的成员，删除成员在网关中的consumer，使其token立即失效
+func (o *OrganizationUsecase) RemoveMember(token, memberID string) error {
+	org, _, err := o.manageable(token, memberID, v1.Role_VIEWER)
+	if err != nil {
+		return err
+	}
+
+	err = o.gateway.DeleteConsumer(memberID)
+	if err != nil {
+		return errors.Newf(
+			500, "Org_Error",
+			"删除成员在网关中的consumer时发生了错误:%v", err)
+	}
+	return o.repo.RemoveMember(org, memberID)
+}
+
+// 确认token对应的成员可以管理给定的成员，并将其角色设置为role
+func (o *OrganizationUsecase) manageable(token, memberID string, role v1.Role) (string, *memberRecord, error) {
+	org, _, callerRole, err := o.Authorize(token, v1.Role_ADMIN)
+	if err != nil {
+		return "", nil, err
+	}
+
+	record, err := o.getMember(memberID)
+	if err != nil {
+		return "", nil, err
+	}
+	if record == nil || record.Org != org {
+		return "", nil, errors.NotFound("Org_Error", fmt.Sprintf("组织中不存在成员 %v", memberID))
+	}
+	if record.Role >= callerRole || role >= callerRole {
+		return "", nil, errors.Forbidden("Org_Error", "只能管理角色低于自身的成员")
+	}
+
+	return org, record, nil
+}
+
+func (o *OrganizationUsecase) getMember(member string) (*memberRecord, error) {
+	marshal, err := o.repo.GetMember(member)
+	if err != nil || marshal == nil {
+		return nil, err
+	}
+
+	record := new(memberRecord)
+	if err := json.Unmarshal(marshal, record); err != nil {
+		return nil, errors.Newf(500, "Org_Error", "对成员信息进行json解码时发生了错误:%v", err)
+	}
+	return record, nil
+}
+
+func (o *OrganizationUsecase) saveMember(record *memberRecord, create bool) error {
+	marshal, err := json.Marshal(record)
+	if err != nil {
+		return errors.Newf(500, "Org_Error", "对成员信息进行json序列化时发生了错误:%v", err)
+	}
+	if create {
+		return o.repo.AddMember(record.Org, record.ID, marshal)
+	}
+	return o.repo.UpdateMember(record.ID, marshal)
+}
+
+// GetClientCode 获得组织的客户端代码，需要operator及以上的角色，
+// 为兼容引入组织之前的调用方式，组织尚无其他成员时允许不携带token下载
+func (o *OrganizationUsecase) GetClientCode(token, username string) ([]byte, error) {
+	if token == "" {
+		members, err := o.repo.ListMembers(username)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) != 0 {
+			return nil, errors.Unauthorized("Org_Error", "组织已有其他成员，下载客户端代码需要携带token")
+		}
+		if err := o.uc.checkPending(username); err != nil {
+			return nil, err
+		}
+		return o.uc.GetClientCode(username)
+	}
+
+	org, _, _, err := o.Authorize(token, v1.Role_OPERATOR)
+	if err != nil {
+		return nil, err
+	}
+	if org != username {
+		return nil, errors.Forbidden("Org_Error", "只能下载所属组织的客户端代码")
+	}
+
+	return o.uc.GetClientCode(org)
+}
+
+// Run 为引入组织之前注册的用户补充启用acl插件，完成后退出，失败的组织在下次启动时重试
+func (o *OrganizationUsecase) Run(ctx context.Context) {
+	users, err := o.uc.repo.ListUsers()
+	if err != nil {
+		o.logger.Errorf("获得需要启用acl插件的用户时发生了错误:%v", err)
+		return
+	}
+	enabled, err := o.repo.ListOrgACLEnabled()
+	if err != nil {
+		o.logger.Errorf("获得需要启用acl插件的用户时发生了错误:%v", err)
+		return
+	}
+	migrated := make(map[string]bool, len(enabled))
+	for _, org := range enabled {
+		migrated[org] = true
+	}
+
+	for _, org := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		if migrated[org] {
+			continue
+		}
+		// 启用acl插件是幂等的，新注册的用户在注册时已启用，此处重复启用不会产生影响
+		if err := o.gateway.EnableOrgACL(org); err != nil {
+			o.logger.Errorf("为用户 %v 的服务启用acl插件时发生了错误:%v", org, err)
+			continue
+		}
+		if err := o.repo.MarkOrgACLEnabled(org); err != nil {
+			o.logger.Errorf("记录用户 %v 已启用acl插件时发生了错误:%v", org, err)
+		}
+	}
+}
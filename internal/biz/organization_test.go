@@ -0,0 +1,237 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的组织成员，不处理邀请的过期
+type memoryOrgRepo struct {
+	invitations map[string][]byte
+	members     map[string][]byte
+	acl         []string
+}
+
+func (r *memoryOrgRepo) SaveInvitation(code string, invitation []byte, ttl time.Duration) error {
+	r.invitations[code] = invitation
+	return nil
+}
+
+func (r *memoryOrgRepo) GetInvitation(code string) ([]byte, error) {
+	return r.invitations[code], nil
+}
+
+func (r *memoryOrgRepo) TakeInvitation(code string) ([]byte, error) {
+	invitation := r.invitations[code]
+	delete(r.invitations, code)
+	return invitation, nil
+}
+
+func (r *memoryOrgRepo) AddMember(org, member string, record []byte) error {
+	if _, ok := r.members[member]; ok {
+		return errors.New(400, "Repo_Error", "用户账号已经存在")
+	}
+	r.members[member] = record
+	return nil
+}
+
+func (r *memoryOrgRepo) GetMember(member string) ([]byte, error) {
+	return r.members[member], nil
+}
+
+func (r *memoryOrgRepo) UpdateMember(member string, record []byte) error {
+	r.members[member] = record
+	return nil
+}
+
+func (r *memoryOrgRepo) RemoveMember(org, member string) error {
+	delete(r.members, member)
+	return nil
+}
+
+func (r *memoryOrgRepo) ListMembers(org string) ([][]byte, error) {
+	var records [][]byte
+	for _, m := range r.members {
+		records = append(records, m)
+	}
+	return records, nil
+}
+
+func (r *memoryOrgRepo) ListOrgACLEnabled() ([]string, error) {
+	return r.acl, nil
+}
+
+func (r *memoryOrgRepo) MarkOrgACLEnabled(org string) error {
+	r.acl = append(r.acl, org)
+	return nil
+}
+
+// 只返回已注册用户的用户数据库
+type userListRepo struct {
+	UserRepo
+	users []string
+}
+
+func (r *userListRepo) ListUsers() ([]string, error) {
+	return r.users, nil
+}
+
+func (r *userListRepo) GetClientCode(username string) ([]byte, error) {
+	return []byte(username), nil
+}
+
+// 只实现组织管理所需接口的kong admin api，token即consumer的用户名
+func newOrgKong(t *testing.T, orgs map[string]string) *gateway.Manager {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/":
+			w.Write([]byte("{}"))
+		case len(path) == 3 && path[0] == "key-auths":
+			json.NewEncoder(w).Encode(map[string]string{"username": path[1], "custom_id": orgs[path[1]]})
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers":
+			consumer := make(map[string]interface{})
+			json.NewDecoder(r.Body).Decode(&consumer)
+			if _, ok := orgs[consumer["username"].(string)]; ok {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte("{}"))
+				return
+			}
+			orgs[consumer["username"].(string)] = consumer["custom_id"].(string)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("{}"))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/key-auth"):
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"key": path[1]})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	manager, err := gateway.NewManager(server.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestOrganizationUsecase(t *testing.T) {
+	repo := &memoryOrgRepo{invitations: make(map[string][]byte), members: make(map[string][]byte)}
+	deletions := newMemoryDeletionRepo()
+	orgs := &OrganizationUsecase{
+		repo:    repo,
+		uc:      &UserUsecase{repo: &userListRepo{users: []string{"org"}}, deletions: deletions},
+		gateway: newOrgKong(t, map[string]string{"org": ""}),
+		logger:  log.NewHelper(log.DefaultLogger),
+	}
+	join := func(inviter string, role v1.Role, member string) {
+		invitation, err := orgs.CreateInvitation(inviter, role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orgs.AcceptInvitation(invitation.Code, &utilApi.User{Id: member, Password: member}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 创建成员失败时不消耗邀请
+	invitation, err := orgs.CreateInvitation("org", v1.Role_ADMIN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orgs.AcceptInvitation(invitation.Code, &utilApi.User{Id: "org", Password: "org"}); err == nil {
+		t.Fatal("成员与已注册的用户重名时应接受失败")
+	}
+	if _, ok := repo.invitations[invitation.Code]; !ok {
+		t.Fatal("创建成员失败时不应消耗邀请")
+	}
+
+	// 组织尚无其他成员时允许不携带token下载客户端代码
+	if _, err := orgs.GetClientCode("", "org"); err != nil {
+		t.Fatal(err)
+	}
+
+	join("org", v1.Role_ADMIN, "admin")
+	if _, err := orgs.GetClientCode("", "org"); errors.Code(err) != 401 {
+		t.Fatalf("组织已有其他成员时下载客户端代码应携带token:%v", err)
+	}
+	if _, err := orgs.GetClientCode("admin", "org"); err != nil {
+		t.Fatal(err)
+	}
+	join("admin", v1.Role_OPERATOR, "operator")
+	if _, err := orgs.CreateInvitation("admin", v1.Role_ADMIN); err == nil {
+		t.Fatal("admin不应邀请相同角色的成员")
+	}
+	if _, err := orgs.CreateInvitation("operator", v1.Role_VIEWER); err == nil {
+		t.Fatal("operator不应邀请成员")
+	}
+
+	org, member, role, err := orgs.Authorize("operator", v1.Role_OPERATOR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if org != "org" || member != "operator" || role != v1.Role_OPERATOR {
+		t.Fatalf("成员的组织与角色错误:%v %v %v", org, member, role)
+	}
+
+	if _, err := orgs.UpdateMemberRole("admin", "operator", v1.Role_ADMIN); err == nil {
+		t.Fatal("admin不应将成员提升为相同的角色")
+	}
+	if _, err := orgs.UpdateMemberRole("admin", "operator", v1.Role_VIEWER); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := orgs.Authorize("operator", v1.Role_OPERATOR); err == nil {
+		t.Fatal("降级为viewer后不应再拥有operator的权限")
+	}
+	if err := orgs.RemoveMember("admin", "admin"); err == nil {
+		t.Fatal("admin不应移除相同角色的成员")
+	}
+	if err := orgs.RemoveMember("org", "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, members, err := orgs.ListMembers("org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || members[0].Role != v1.Role_OWNER || members[1].Id != "operator" {
+		t.Fatalf("组织的成员错误:%v", members)
+	}
+
+	// 组织注销后只允许在宽限期内导出数据
+	deletions.schedules["org"] = time.Now().Add(time.Hour)
+	if _, _, _, err := orgs.Authorize("operator", v1.Role_VIEWER); errors.Reason(err) != "Unregister_Pending" {
+		t.Fatalf("已注销的组织的成员不应通过认证:%v", err)
+	}
+	if _, _, _, err := orgs.AuthorizePending("org", v1.Role_ADMIN); err != nil {
+		t.Fatalf("宽限期内应允许导出数据:%v", err)
+	}
+}
+
+func TestOrganizationUsecase_Run(t *testing.T) {
+	repo := &memoryOrgRepo{acl: []string{"a"}}
+	orgs := &OrganizationUsecase{
+		repo:    repo,
+		uc:      &UserUsecase{repo: &userListRepo{users: []string{"a", "b"}}},
+		gateway: newOrgKong(t, map[string]string{"a": "", "b": ""}),
+		logger:  log.NewHelper(log.DefaultLogger),
+	}
+
+	// 只为尚未启用acl插件的用户补充启用
+	orgs.Run(context.Background())
+	if len(repo.acl) != 2 || repo.acl[1] != "b" {
+		t.Fatalf("应为引入组织之前注册的用户启用acl插件，实际为:%v", repo.acl)
+	}
+}
@@ -0,0 +1,117 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// 向用户提供的地址发起请求时默认拒绝访问的内部网络，包括回环、链路本地、私有网络以及运营商级NAT地址，
+// 集群内部的pod与service地址也位于其中
+var deniedNetworks = parseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+	"172.16.0.0/12", "192.168.0.0/16", "::/128", "::1/128", "fc00::/7", "fe80::/10",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// 限制向用户提供的地址发起的请求，防止借由服务中心访问集群内部的服务(SSRF)
+type outboundGuard struct {
+	// 即使属于内部网络也允许访问的地址
+	allowed []*net.IPNet
+}
+
+func newOutboundGuard(c *conf.Server_Outbound) (*outboundGuard, error) {
+	guard := new(outboundGuard)
+	for _, cidr := range c.GetAllowedCidrs() {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "服务配置中允许访问的地址 %s 不是合法的CIDR:%v", cidr, err)
+		}
+		guard.allowed = append(guard.allowed, network)
+	}
+	return guard, nil
+}
+
+// 校验地址是否允许访问
+func (g *outboundGuard) checkIP(ip net.IP) error {
+	for _, network := range g.allowed {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsMulticast() {
+		return errors.BadRequest("Outbound_Denied", "不允许访问组播地址 "+ip.String())
+	}
+	for _, network := range deniedNetworks {
+		if network.Contains(ip) {
+			return errors.BadRequest("Outbound_Denied", "不允许访问内部地址 "+ip.String())
+		}
+	}
+	return nil
+}
+
+// 校验url的协议，并校验主机名解析得到的所有地址，用于在保存用户提供的地址时尽早拒绝
+func (g *outboundGuard) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.BadRequest("Outbound_Denied", "地址 "+rawURL+" 不是合法的url:"+err.Error())
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.BadRequest("Outbound_Denied", "地址 "+rawURL+" 的协议只能为http或https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.BadRequest("Outbound_Denied", "地址 "+rawURL+" 中缺少主机名")
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return errors.BadRequest("Outbound_Denied", "无法解析主机名 "+host+":"+err.Error())
+	}
+	for _, ip := range ips {
+		if err := g.checkIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 只能访问外部地址的http客户端，在建立每个连接时校验实际连接的地址，
+// 避免校验后主机名被重新解析到内部地址，或经由重定向访问内部地址
+func (g *outboundGuard) httpClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return errors.BadRequest("Outbound_Denied", "无法解析连接的地址 "+address)
+			}
+			return g.checkIP(ip)
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// 经由代理访问时无法校验实际的地址，因此不使用环境变量中配置的代理
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,65 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOutboundGuard_checkURL(t *testing.T) {
+	guard, err := newOutboundGuard(&conf.Server_Outbound{AllowedCidrs: []string{"10.1.0.0/16"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://8.8.8.8:8086", true},
+		{"http://10.1.2.3:8086", true},
+		{"http://127.0.0.1:8086", false},
+		{"http://[::1]:8086", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://10.2.0.1", false},
+		{"http://192.168.1.1", false},
+		{"http://[::ffff:127.0.0.1]", false},
+		{"ftp://8.8.8.8", false},
+		{"8.8.8.8:8086", false},
+	}
+	for _, c := range cases {
+		err := guard.checkURL(c.url)
+		if c.allowed && err != nil {
+			t.Errorf("应允许访问 %s:%v", c.url, err)
+		}
+		if !c.allowed && errors.Code(err) != 400 {
+			t.Errorf("应拒绝访问 %s:%v", c.url, err)
+		}
+	}
+
+	if _, err := newOutboundGuard(&conf.Server_Outbound{AllowedCidrs: []string{"10.1.0.0"}}); err == nil {
+		t.Fatal("不合法的CIDR应返回错误")
+	}
+}
+
+func TestOutboundGuard_httpClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// 建立连接时校验实际的地址
+	if _, err := new(outboundGuard).httpClient(time.Second).Get(server.URL); err == nil {
+		t.Fatal("不应能连接内部地址")
+	}
+
+	guard, err := newOutboundGuard(&conf.Server_Outbound{AllowedCidrs: []string{"127.0.0.0/8"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := guard.httpClient(time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("应能连接允许访问的地址:%v", err)
+	}
+	response.Body.Close()
+}
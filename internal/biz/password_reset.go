@@ -0,0 +1,293 @@
+package biz
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/gateway"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// 重置密码审计记录中的操作与结果
+const (
+	resetActionRequest = "request"
+	resetActionConfirm = "confirm"
+
+	resetOutcomeSucceeded   = "succeeded"
+	resetOutcomeFailed      = "failed"
+	resetOutcomeRateLimited = "rate_limited"
+)
+
+// PasswordResetUsecase 负责重置忘记的密码，重置令牌只能使用一次，并在有效期后失效，
+// 申请与确认重置的次数都受到限制，每次申请与确认都会记录审计日志
+type PasswordResetUsecase struct {
+	repo     PasswordResetRepo
+	userRepo UserRepo
+	gateway  gateway.Provider
+	notifier Notifier
+	// 更换api密钥前需要通过二次验证
+	twoFactor *TwoFactorUsecase
+	// 重置令牌的有效期
+	expiration time.Duration
+	// 每个用户以及每个ip在window时长内允许申请重置的次数
+	maxRequests int64
+	window      time.Duration
+	// 重置令牌允许被错误确认的次数
+	maxFailures int64
+	// 每个ip在window时长内允许确认重置的次数
+	maxConfirms int64
+	logger      *log.Helper
+}
+type PasswordResetRepo interface {
+	// SaveResetToken 保存重置令牌的摘要，令牌在ttl后自动删除，用户已有的令牌被覆盖
+	SaveResetToken(username, digest string, ttl time.Duration) error
+	// GetResetToken 获得重置令牌的摘要，不存在或已过期时返回空字符串
+	GetResetToken(username string) (string, error)
+	// RemoveResetToken 删除重置令牌，返回false时表示令牌已被删除
+	RemoveResetToken(username string) (bool, error)
+	// IncrResetCounter 对给定的计数器加一并返回加一后的值，计数器在首次计数window时长后清零
+	IncrResetCounter(key string, window time.Duration) (int64, error)
+	// UpdatePassword 更新已注册用户的密码以及注册信息中的密码，两者一并更新
+	UpdatePassword(username, password string, registerInfo []byte) error
+	// UpdateToken 更新已注册用户的token
+	UpdateToken(username, token string) error
+	// AppendResetAudit 追加重置密码的审计记录
+	AppendResetAudit(record []byte) error
+}
+
+// Notifier 向用户发送通知的方式
+type Notifier interface {
+	// Notify 向用户发送通知，email为用户注册时填写的邮箱，可能为空
+	Notify(username, email, subject, body string) error
+}
+
+// 重置密码的审计记录
+type resetAudit struct {
+	Time     time.Time `json:"time"`
+	Username string    `json:"username"`
+	IP       string    `json:"ip"`
+	Action   string    `json:"action"`
+	Outcome  string    `json:"outcome"`
+	Message  string    `json:"message,omitempty"`
+}
+
+func NewPasswordResetUsecase(server *conf.Server, uc *UserUsecase,
+	repo PasswordResetRepo, notifier Notifier, logger log.Logger) *PasswordResetUsecase {
+	reset := &PasswordResetUsecase{
+		repo:        repo,
+		userRepo:    uc.repo,
+		gateway:     uc.gateway,
+		notifier:    notifier,
+		twoFactor:   uc.twoFactor,
+		expiration:  30 * time.Minute,
+		maxRequests: 3,
+		window:      time.Hour,
+		maxFailures: 5,
+		maxConfirms: 10,
+		logger:      log.NewHelper(logger),
+	}
+	if c := server.PasswordReset; c != nil {
+		if c.Expiration != nil && c.Expiration.AsDuration() > 0 {
+			reset.expiration = c.Expiration.AsDuration()
+		}
+		if c.MaxRequests > 0 {
+			reset.maxRequests = c.MaxRequests
+		}
+		if c.Window != nil && c.Window.AsDuration() > 0 {
+			reset.window = c.Window.AsDuration()
+		}
+		if c.MaxFailures > 0 {
+			reset.maxFailures = c.MaxFailures
+		}
+		if c.MaxConfirms > 0 {
+			reset.maxConfirms = c.MaxConfirms
+		}
+	}
+
+	return reset
+}
+
+// Request 为用户生成重置令牌并发送给用户，为避免泄露账号是否存在，
+// 除超出申请次数外，账号不存在或发送失败时都不返回错误
+func (r *PasswordResetUsecase) Request(username, ip string) error {
+	for _, key := range []string{"user:" + username, "ip:" + ip} {
+		count, err := r.repo.IncrResetCounter(key, r.window)
+		if err != nil {
+			return err
+		}
+		if count > r.maxRequests {
+			r.audit(username, ip, resetActionRequest, resetOutcomeRateLimited, key)
+			return errors.New(429, "Password_Reset_Error", "申请重置密码的次数过多，请稍后再试")
+		}
+	}
+
+	marshal, err := r.userRepo.GetRegisterInfo(username)
+	if err != nil {
+		r.audit(username, ip, resetActionRequest, resetOutcomeFailed, "用户不存在")
+		return nil
+	}
+	info := new(v1.RegisterRequest)
+	if err := proto.Unmarshal(marshal, info); err != nil {
+		r.audit(username, ip, resetActionRequest, resetOutcomeFailed, err.Error())
+		return nil
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return errors.Newf(500, "Password_Reset_Error", "生成重置令牌时发生了错误:%v", err)
+	}
+	resetToken := hex.EncodeToString(token)
+	err = r.repo.SaveResetToken(username, resetTokenDigest(resetToken), r.expiration)
+	if err != nil {
+		return err
+	}
+
+	expire := time.Now().Add(r.expiration)
+	err = r.notifier.Notify(username, info.Email, "重置密码", fmt.Sprintf(
+		"您好 %s，\n\n我们收到了重置您密码的申请，重置令牌为:\n\n%s\n\n令牌只能使用一次，并在 %s 后失效。"+
+			"如果这不是您本人的操作，请忽略此邮件。\n",
+		username, resetToken, expire.Format(time.RFC3339)))
+	if err != nil {
+		r.repo.RemoveResetToken(username)
+		r.audit(username, ip, resetActionRequest, resetOutcomeFailed, err.Error())
+		return nil
+	}
+
+	r.audit(username, ip, resetActionRequest, resetOutcomeSucceeded, "")
+	return nil
+}
+
+// Confirm 校验重置令牌并设置新的密码，rotateKey为true时同时更换用户的api密钥，返回更换后的密钥，
+// 用户启用了二次验证时，更换api密钥需要code为有效的验证码或恢复码
+func (r *PasswordResetUsecase) Confirm(
+	request *v1.ConfirmPasswordResetRequest, ip, code string) (token string, err error) {
+	if request == nil {
+		return "", errors.BadRequest("request is nil", "")
+	}
+	username := request.Username
+
+	defer func() {
+		if errors.Code(err) == 429 {
+			r.audit(username, ip, resetActionConfirm, resetOutcomeRateLimited, "ip:"+ip)
+		} else if err != nil {
+			r.audit(username, ip, resetActionConfirm, resetOutcomeFailed, err.Error())
+		} else {
+			r.audit(username, ip, resetActionConfirm, resetOutcomeSucceeded, "")
+		}
+	}()
+
+	// 限制每个ip确认重置的次数，避免以不同的用户名暴力猜测重置令牌
+	count, err := r.repo.IncrResetCounter("confirm_ip:"+ip, r.window)
+	if err != nil {
+		return "", err
+	}
+	if count > r.maxConfirms {
+		return "", errors.New(429, "Password_Reset_Error", "确认重置密码的次数过多，请稍后再试")
+	}
+
+	// 在消耗重置令牌前完成二次验证，使缺少验证码时可以携带验证码重新确认
+	if request.RotateKey {
+		err = r.twoFactor.Check(username, code)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	invalid := errors.Forbidden("Password_Reset_Error", "重置令牌无效或已过期")
+	digest, err := r.repo.GetResetToken(username)
+	if err != nil {
+		return "", err
+	}
+	if digest == "" {
+		return "", invalid
+	}
+	if subtle.ConstantTimeCompare([]byte(digest), []byte(resetTokenDigest(request.ResetToken))) != 1 {
+		// 错误确认的次数过多时令牌失效，避免令牌被暴力猜测
+		failures, err := r.repo.IncrResetCounter("failure:"+username, r.expiration)
+		if err == nil && failures >= r.maxFailures {
+			r.repo.RemoveResetToken(username)
+		}
+		return "", invalid
+	}
+
+	// 注册信息中同样保存了密码，需要与密码一并更新
+	marshal, err := r.userRepo.GetRegisterInfo(username)
+	if err != nil {
+		return "", err
+	}
+	info := new(v1.RegisterRequest)
+	if err = proto.Unmarshal(marshal, info); err != nil {
+		return "", errors.Newf(500, "Password_Reset_Error", "解析用户的注册信息时发生了错误:%v", err)
+	}
+	if info.User == nil {
+		info.User = &utilApi.User{Id: username}
+	}
+	info.User.Password = request.NewPassword
+	marshal, err = proto.Marshal(info)
+	if err != nil {
+		return "", errors.Newf(500, "Password_Reset_Error", "序列化用户的注册信息时发生了错误:%v", err)
+	}
+
+	// 删除重置令牌，保证令牌只被使用一次
+	removed, err := r.repo.RemoveResetToken(username)
+	if err != nil {
+		return "", err
+	}
+	if !removed {
+		return "", invalid
+	}
+
+	err = r.repo.UpdatePassword(username, request.NewPassword, marshal)
+	if err != nil {
+		return "", err
+	}
+
+	if request.RotateKey {
+		token, err = r.gateway.RotateKey(username)
+		if err != nil {
+			return "", errors.Newf(
+				500, "Password_Reset_Error",
+				"更换用户的api密钥时发生了错误:%v", err)
+		}
+		err = r.repo.UpdateToken(username, token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	r.logger.Infof("用户 %v 重置了密码", username)
+	return token, nil
+}
+
+// 保存重置密码的审计记录，保存失败时只记录日志
+func (r *PasswordResetUsecase) audit(username, ip, action, outcome, message string) {
+	marshal, err := json.Marshal(&resetAudit{
+		Time:     time.Now().UTC(),
+		Username: username,
+		IP:       ip,
+		Action:   action,
+		Outcome:  outcome,
+		Message:  message,
+	})
+	if err == nil {
+		err = r.repo.AppendResetAudit(marshal)
+	}
+	if err != nil {
+		r.logger.Errorf("保存用户 %v 重置密码的审计记录时发生了错误:%v", username, err)
+	}
+}
+
+// 重置令牌只保存摘要，避免数据库泄露时令牌被直接使用
+func resetTokenDigest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
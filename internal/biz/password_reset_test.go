@@ -0,0 +1,161 @@
+package biz
+
+import (
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// 只实现查询注册信息的用户数据库
+type registerInfoRepo struct {
+	UserRepo
+	infos map[string][]byte
+}
+
+func (r *registerInfoRepo) GetRegisterInfo(username string) ([]byte, error) {
+	info, ok := r.infos[username]
+	if !ok {
+		return nil, errors.NotFound("Repo_Error", "用户不存在")
+	}
+	return info, nil
+}
+
+// 保存在内存中的重置令牌，不处理过期
+type memoryPasswordResetRepo struct {
+	tokens    map[string]string
+	counters  map[string]int64
+	passwords map[string]string
+	infos     map[string][]byte
+	audits    [][]byte
+}
+
+func (r *memoryPasswordResetRepo) SaveResetToken(username, digest string, ttl time.Duration) error {
+	r.tokens[username] = digest
+	return nil
+}
+
+func (r *memoryPasswordResetRepo) GetResetToken(username string) (string, error) {
+	return r.tokens[username], nil
+}
+
+func (r *memoryPasswordResetRepo) RemoveResetToken(username string) (bool, error) {
+	_, ok := r.tokens[username]
+	delete(r.tokens, username)
+	return ok, nil
+}
+
+func (r *memoryPasswordResetRepo) IncrResetCounter(key string, window time.Duration) (int64, error) {
+	r.counters[key]++
+	return r.counters[key], nil
+}
+
+func (r *memoryPasswordResetRepo) UpdatePassword(username, password string, registerInfo []byte) error {
+	r.passwords[username] = password
+	r.infos[username] = registerInfo
+	return nil
+}
+
+func (r *memoryPasswordResetRepo) UpdateToken(username, token string) error {
+	return nil
+}
+
+func (r *memoryPasswordResetRepo) AppendResetAudit(record []byte) error {
+	r.audits = append(r.audits, record)
+	return nil
+}
+
+// 记录最后一条通知的发送对象
+type memoryNotifier struct {
+	email, body string
+}
+
+func (n *memoryNotifier) Notify(username, email, subject, body string) error {
+	n.email, n.body = email, body
+	return nil
+}
+
+func TestPasswordResetUsecase(t *testing.T) {
+	info, err := proto.Marshal(&v1.RegisterRequest{Email: "test@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &memoryPasswordResetRepo{
+		tokens:    make(map[string]string),
+		counters:  make(map[string]int64),
+		passwords: make(map[string]string),
+		infos:     make(map[string][]byte),
+	}
+	notifier := new(memoryNotifier)
+	reset := &PasswordResetUsecase{
+		repo:        repo,
+		userRepo:    &registerInfoRepo{infos: map[string][]byte{"test": info}},
+		notifier:    notifier,
+		expiration:  time.Hour,
+		maxRequests: 2,
+		window:      time.Hour,
+		maxFailures: 2,
+		maxConfirms: 5,
+		logger:      log.NewHelper(log.DefaultLogger),
+	}
+	resetToken := func() string {
+		if err := reset.Request("test", "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+		if notifier.email != "test@example.com" {
+			t.Fatalf("重置令牌应发送到用户的邮箱，实际发送到:%v", notifier.email)
+		}
+		return regexp.MustCompile(`[0-9a-f]{64}`).FindString(notifier.body)
+	}
+
+	// 错误确认的次数过多时令牌失效
+	token := resetToken()
+	request := &v1.ConfirmPasswordResetRequest{Username: "test", ResetToken: "wrong", NewPassword: "newpassword"}
+	for i := 0; i < 2; i++ {
+		if _, err := reset.Confirm(request, "127.0.0.1", ""); err == nil {
+			t.Fatal("错误的重置令牌应确认失败")
+		}
+	}
+	request.ResetToken = token
+	if _, err := reset.Confirm(request, "127.0.0.1", ""); err == nil {
+		t.Fatal("错误确认的次数过多后重置令牌应失效")
+	}
+
+	// 重置令牌只能使用一次
+	delete(repo.counters, "failure:test")
+	request.ResetToken = resetToken()
+	if _, err := reset.Confirm(request, "127.0.0.1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if repo.passwords["test"] != "newpassword" {
+		t.Fatalf("确认重置后应更新密码，实际为:%v", repo.passwords["test"])
+	}
+	updated := new(v1.RegisterRequest)
+	if err := proto.Unmarshal(repo.infos["test"], updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetUser().GetPassword() != "newpassword" || updated.Email != "test@example.com" {
+		t.Fatalf("确认重置后应更新注册信息中的密码，实际为:%v", updated)
+	}
+	if _, err := reset.Confirm(request, "127.0.0.1", ""); err == nil {
+		t.Fatal("重置令牌不应被重复使用")
+	}
+
+	// 超出申请次数时拒绝申请，不存在的账号不返回错误
+	if err := reset.Request("test", "127.0.0.1"); errors.Code(err) != 429 {
+		t.Fatalf("超出申请次数时应返回429，实际为:%v", err)
+	}
+	if err := reset.Request("nobody", "10.0.0.1"); err != nil {
+		t.Fatalf("账号不存在时不应返回错误:%v", err)
+	}
+	// 超出确认次数时拒绝同一ip的确认
+	if _, err := reset.Confirm(request, "127.0.0.1", ""); errors.Code(err) != 429 {
+		t.Fatalf("超出确认次数时应返回429，实际为:%v", err)
+	}
+	if len(repo.audits) != 10 {
+		t.Fatalf("每次申请与确认都应记录审计日志，实际记录了%v条", len(repo.audits))
+	}
+}
@@ -0,0 +1,189 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/kubecontroller"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// 放置新租户的策略
+const (
+	// 选择租户数量与容量权重之比最小的集群
+	placementLeastLoaded = "least-loaded"
+	// 在注册请求指定区域的集群中选择负载最小的集群
+	placementRegion = "region"
+	// 总是选择配置中指定的集群
+	placementPinned = "pinned"
+)
+
+// 未配置targets时，由单集群配置得到的集群名称
+const defaultClusterName = "default"
+
+// 可以放置租户服务的集群
+type cluster struct {
+	*kubecontroller.KubeController
+	name   string
+	region string
+	weight int64
+	// 网关访问该集群中服务时使用的域名后缀
+	serviceDomain string
+}
+
+// 获得网关访问集群中指定服务时使用的host
+func (c *cluster) serviceHost(service string) string {
+	if c.serviceDomain == "" {
+		return service
+	}
+	return service + "." + c.serviceDomain
+}
+
+// clusterPlacement 为新注册的租户选择集群，并将之后对租户的操作路由到其所在的集群
+type clusterPlacement struct {
+	repo UserRepo
+	// 首个集群同时作为未记录所在集群的租户的默认集群，即引入多集群前注册的租户
+	clusters []*cluster
+	policy   string
+	pinned   string
+}
+
+func newClusterPlacement(config *conf.Server_Cluster, repo UserRepo) (*clusterPlacement, error) {
+	if config == nil {
+		return nil, errors.New(500, "Config_Error", "服务配置中缺少集群配置")
+	}
+
+	targets := config.Targets
+	if len(targets) == 0 {
+		targets = []*conf.Server_Cluster_Target{
+			{Name: defaultClusterName, Namespace: config.Namespace, Weight: 1},
+		}
+	}
+
+	p := &clusterPlacement{
+		repo:   repo,
+		policy: config.Placement,
+		pinned: config.Pinned,
+	}
+	if p.policy == "" {
+		p.policy = placementLeastLoaded
+	}
+	if p.policy != placementLeastLoaded && p.policy != placementRegion && p.policy != placementPinned {
+		return nil, errors.Newf(500, "Config_Error", "不支持的集群放置策略:%s", p.policy)
+	}
+
+	names := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		if t.Name == "" || names[t.Name] {
+			return nil, errors.Newf(500, "Config_Error", "集群名称为空或重复:%q", t.Name)
+		}
+		names[t.Name] = true
+
+		controller, err := kubecontroller.NewKubeControllerWithKubeconfig(t.Kubeconfig, t.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		p.clusters = append(p.clusters, &cluster{
+			KubeController: controller,
+			name:           t.Name,
+			region:         t.Region,
+			weight:         t.Weight,
+			serviceDomain:  t.ServiceDomain,
+		})
+	}
+	if p.policy == placementPinned && !names[p.pinned] {
+		return nil, errors.Newf(500, "Config_Error", "pinned策略指定的集群不存在:%q", p.pinned)
+	}
+
+	return p, nil
+}
+
+// place 依据放置策略为新租户选择集群，并保存选择的结果
+func (p *clusterPlacement) place(username, region string) (*cluster, error) {
+	counts, err := p.countTenants()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := chooseCluster(p.clusters, counts, p.policy, p.pinned, region)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.repo.SavePlacement(username, c.name)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// clusterOf 获得租户服务所在的集群
+func (p *clusterPlacement) clusterOf(username string) (*cluster, error) {
+	name, err := p.repo.GetPlacement(username)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return p.clusters[0], nil
+	}
+
+	for _, c := range p.clusters {
+		if c.name == name {
+			return c, nil
+		}
+	}
+	return nil, errors.Newf(500, "Placement_Error", "租户 %s 所在的集群 %s 不存在于服务配置中", username, name)
+}
+
+// 统计每个集群中的租户数量，未记录所在集群的租户计入默认集群
+func (p *clusterPlacement) countTenants() (map[string]int64, error) {
+	placements, err := p.repo.ListPlacements()
+	if err != nil {
+		return nil, err
+	}
+	users, err := p.repo.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(p.clusters))
+	for _, user := range users {
+		if name, ok := placements[user]; ok {
+			counts[name]++
+		} else {
+			counts[p.clusters[0].name]++
+		}
+	}
+	return counts, nil
+}
+
+// 依据放置策略以及各个集群的租户数量选择集群，负载相同时选择配置中靠前的集群
+func chooseCluster(clusters []*cluster, counts map[string]int64, policy, pinned, region string) (*cluster, error) {
+	if policy == placementPinned {
+		for _, c := range clusters {
+			if c.name == pinned {
+				return c, nil
+			}
+		}
+		return nil, errors.Newf(500, "Placement_Error", "pinned策略指定的集群不存在:%s", pinned)
+	}
+
+	var chosen *cluster
+	for _, c := range clusters {
+		if c.weight <= 0 {
+			continue
+		}
+		if policy == placementRegion && region != "" && c.region != region {
+			continue
+		}
+		// 比较租户数量与权重之比，即counts[c]/c.weight < counts[chosen]/chosen.weight
+		if chosen == nil || counts[c.name]*chosen.weight < counts[chosen.name]*c.weight {
+			chosen = c
+		}
+	}
+	if chosen == nil {
+		if policy == placementRegion && region != "" {
+			return nil, errors.BadRequest("Placement_Error", "指定的区域中没有可以放置服务的集群")
+		}
+		return nil, errors.New(500, "Placement_Error", "没有可以放置服务的集群")
+	}
+	return chosen, nil
+}
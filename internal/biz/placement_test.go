@@ -0,0 +1,42 @@
+package biz
+
+import (
+	"testing"
+)
+
+func Test_chooseCluster(t *testing.T) {
+	clusters := []*cluster{
+		{name: "a", region: "east", weight: 1},
+		{name: "b", region: "east", weight: 3},
+		{name: "c", region: "west", weight: 1},
+		{name: "drained", region: "west", weight: 0},
+	}
+	counts := map[string]int64{"a": 2, "b": 3, "c": 2}
+
+	c, err := chooseCluster(clusters, counts, placementLeastLoaded, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.name != "b" {
+		t.Fatalf("应选择租户数量与权重之比最小的集群，实际为:%v", c.name)
+	}
+
+	c, err = chooseCluster(clusters, counts, placementRegion, "", "west")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.name != "c" {
+		t.Fatalf("应只在指定区域中选择权重不为0的集群，实际为:%v", c.name)
+	}
+	if _, err = chooseCluster(clusters, counts, placementRegion, "", "north"); err == nil {
+		t.Fatal("指定的区域中没有集群时应返回错误")
+	}
+
+	c, err = chooseCluster(clusters, counts, placementPinned, "drained", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.name != "drained" {
+		t.Fatalf("pinned策略应总是选择指定的集群，实际为:%v", c.name)
+	}
+}
@@ -0,0 +1,197 @@
+package biz
+
+import (
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"time"
+)
+
+// RetentionUsecase 修改租户已有bucket的保留策略，并记录租户切换后的套餐
+type RetentionUsecase struct {
+	uc     *UserUsecase
+	logger *log.Helper
+}
+
+// 各个套餐的bucket保留策略以及允许的保留时长范围
+type retentionPlans struct {
+	plans map[string]*timeseries.RetentionPolicy
+	// 注册时未指定套餐时使用的套餐，为空时使用默认的保留策略
+	defaultPlan string
+	// 允许的最短与最长保留时长，为0时不限制
+	min, max time.Duration
+}
+
+func NewRetentionUsecase(uc *UserUsecase, logger log.Logger) *RetentionUsecase {
+	return &RetentionUsecase{uc: uc, logger: log.NewHelper(logger)}
+}
+
+// UpdateRetention 以租户套餐的保留策略为基础，覆盖请求中设置了的字段，校验后修改租户已有bucket的保留策略，
+// 请求中指定了套餐时同时切换租户的套餐
+func (r *RetentionUsecase) UpdateRetention(request *v1.UpdateRetentionRequest) (*v1.UpdateRetentionReply, error) {
+	if request == nil {
+		return nil, errors.BadRequest("request is nil", "")
+	}
+	username := request.Username
+
+	marshal, err := r.uc.repo.GetRegisterInfo(username)
+	if err != nil {
+		return nil, err
+	}
+	info := new(v1.RegisterRequest)
+	if err := proto.Unmarshal(marshal, info); err != nil {
+		return nil, errors.Newf(
+			500, "Retention_Error",
+			"对用户注册信息进行protobuf解码时发生了错误:%v", err)
+	}
+
+	plan := request.Plan
+	if plan == "" {
+		plan = info.Plan
+	}
+	plan, policy, err := r.uc.plans.policy(plan)
+	if err != nil {
+		return nil, err
+	}
+	overrideRetention(policy, request.Data, request.WarningDetect, request.Warnings, request.ShardGroupDuration)
+	err = r.uc.plans.validate(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := r.uc.timeSeriesOf(username)
+	if err != nil {
+		return nil, err
+	}
+	// 时序数据库在修改失败时还原已修改的bucket，保留策略以绝对值设置，因此失败后可以以相同的请求重试
+	err = backend.UpdateRetention(username, policy)
+	if timeseries.IsUnsupported(err) {
+		return nil, unsupportedTimeSeries(backend, "修改租户的保留策略")
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Retention_Error",
+			"修改用户bucket的保留策略时发生了错误:%v", err)
+	}
+
+	if plan != info.Plan {
+		info.Plan = plan
+		err = r.uc.updateRegisterInfo(username, info)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Retention_Error",
+				"bucket的保留策略已修改，但记录用户的套餐时发生了错误，请以相同的请求重试:%v", err)
+		}
+	}
+
+	r.uc.events.Publish(EventTenantConfigChanged, username, nil, map[string]string{
+		"plan":                     plan,
+		"data_retention":           policy.Data.String(),
+		"warning_detect_retention": policy.WarningDetect.String(),
+		"warnings_retention":       policy.Warnings.String(),
+		"shard_group_duration":     policy.ShardGroupDuration.String(),
+	})
+	r.logger.Infof("修改了用户 %v 的bucket保留策略，当前套餐为 %q", username, plan)
+	return &v1.UpdateRetentionReply{Plan: plan, Buckets: bucketRetentions(username, policy)}, nil
+}
+
+// 解析服务配置中的套餐，套餐中未设置的字段使用默认的保留策略，所有套餐都需要满足保留时长的限制
+func newRetentionPlans(c *conf.Server_Influxdb) (*retentionPlans, error) {
+	plans := &retentionPlans{plans: make(map[string]*timeseries.RetentionPolicy)}
+	if c == nil {
+		return plans, nil
+	}
+	plans.defaultPlan = c.DefaultPlan
+	plans.min = c.MinRetention.AsDuration()
+	plans.max = c.MaxRetention.AsDuration()
+
+	for name, p := range c.Plans {
+		policy := timeseries.DefaultRetentionPolicy()
+		overrideRetention(policy, p.GetData(), p.GetWarningDetect(), p.GetWarnings(), p.GetShardGroupDuration())
+		if err := policy.Validate(plans.min, plans.max); err != nil {
+			return nil, errors.Newf(500, "Config_Error", "套餐 %s 的保留策略无效:%v", name, err)
+		}
+		plans.plans[name] = policy
+	}
+
+	if plans.defaultPlan != "" && plans.plans[plans.defaultPlan] == nil {
+		return nil, errors.Newf(500, "Config_Error", "默认套餐 %s 不存在于服务配置中", plans.defaultPlan)
+	}
+	if plans.defaultPlan == "" {
+		if err := timeseries.DefaultRetentionPolicy().Validate(plans.min, plans.max); err != nil {
+			return nil, errors.Newf(500, "Config_Error", "未配置默认套餐时，默认的保留策略需要满足保留时长的限制:%v", err)
+		}
+	}
+
+	return plans, nil
+}
+
+// 以设置了的时长覆盖保留策略中的字段，为nil的时长保持原有的值
+func overrideRetention(policy *timeseries.RetentionPolicy,
+	data, warningDetect, warnings, shardGroupDuration *durationpb.Duration) {
+	for _, o := range []struct {
+		value  *durationpb.Duration
+		result *time.Duration
+	}{
+		{data, &policy.Data},
+		{warningDetect, &policy.WarningDetect},
+		{warnings, &policy.Warnings},
+		{shardGroupDuration, &policy.ShardGroupDuration},
+	} {
+		if o.value != nil {
+			*o.result = o.value.AsDuration()
+		}
+	}
+}
+
+// 校验租户注册时选择的套餐，租户只能使用默认套餐，其余套餐需要由管理员修改保留策略时切换
+func (p *retentionPlans) checkRegisterPlan(plan string) error {
+	if plan != "" && plan != p.defaultPlan {
+		return errors.Forbidden("Retention_Error", "注册时只能使用默认套餐，其余套餐需要由管理员切换")
+	}
+	return nil
+}
+
+// 获得套餐的保留策略的副本，plan为空时使用默认套餐，返回实际使用的套餐名
+func (p *retentionPlans) policy(plan string) (string, *timeseries.RetentionPolicy, error) {
+	if plan == "" {
+		plan = p.defaultPlan
+	}
+	if plan == "" {
+		return "", timeseries.DefaultRetentionPolicy(), nil
+	}
+
+	policy, ok := p.plans[plan]
+	if !ok {
+		return "", nil, errors.BadRequest("Retention_Error", "套餐 "+plan+" 不存在")
+	}
+	copied := *policy
+	return plan, &copied, nil
+}
+
+// 校验保留策略是否满足服务配置的保留时长的限制
+func (p *retentionPlans) validate(policy *timeseries.RetentionPolicy) error {
+	if err := policy.Validate(p.min, p.max); err != nil {
+		return errors.BadRequest("Retention_Error", err.Error())
+	}
+	return nil
+}
+
+// 获得保留策略下用户各个bucket的保留时长
+func bucketRetentions(username string, policy *timeseries.RetentionPolicy) []*v1.BucketRetention {
+	names := influxdb.BucketNames(username)
+	retentions := []time.Duration{policy.Data, policy.WarningDetect, policy.Warnings}
+	buckets := make([]*v1.BucketRetention, len(names))
+	for i, name := range names {
+		buckets[i] = &v1.BucketRetention{
+			Name:                      name,
+			RetentionSeconds:          int64(retentions[i].Seconds()),
+			ShardGroupDurationSeconds: int64(policy.ShardGroupDuration.Seconds()),
+		}
+	}
+	return buckets
+}
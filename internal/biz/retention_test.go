@@ -0,0 +1,65 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"testing"
+	"time"
+)
+
+func Test_newRetentionPlans(t *testing.T) {
+	day := 24 * time.Hour
+	c := &conf.Server_Influxdb{
+		Plans: map[string]*conf.Server_Influxdb_Retention{
+			"trial":      {Data: durationpb.New(7 * day), Warnings: durationpb.New(7 * day)},
+			"compliance": {Data: durationpb.New(365 * day), Warnings: durationpb.New(365 * day)},
+		},
+		DefaultPlan:  "trial",
+		MinRetention: durationpb.New(day),
+		MaxRetention: durationpb.New(365 * day),
+	}
+	plans, err := newRetentionPlans(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, policy, err := plans.policy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan != "trial" || policy.Data != 7*day || policy.WarningDetect != day {
+		t.Fatalf("未指定套餐时应使用默认套餐，未设置的字段使用默认值:%v %+v", plan, policy)
+	}
+
+	// 修改返回的保留策略不能影响套餐本身
+	policy.Data = time.Hour
+	if _, policy, _ := plans.policy("trial"); policy.Data != 7*day {
+		t.Fatalf("套餐的保留策略被修改:%+v", policy)
+	}
+
+	if _, _, err := plans.policy("unknown"); errors.Code(err) != 400 {
+		t.Fatalf("不存在的套餐应返回400:%v", err)
+	}
+	if err := plans.validate(policy); errors.Code(err) != 400 {
+		t.Fatalf("超出限制的保留策略应返回400:%v", err)
+	}
+	if err := plans.checkRegisterPlan("compliance"); errors.Code(err) != 403 {
+		t.Fatalf("注册时不能选择默认套餐以外的套餐:%v", err)
+	}
+	for _, plan := range []string{"", "trial"} {
+		if err := plans.checkRegisterPlan(plan); err != nil {
+			t.Fatalf("注册时应可以使用默认套餐:%v", err)
+		}
+	}
+
+	c.Plans["forever"] = &conf.Server_Influxdb_Retention{Data: durationpb.New(0)}
+	if _, err := newRetentionPlans(c); err == nil {
+		t.Fatal("配置了最长保留时长时，套餐不能永久保留数据")
+	}
+	delete(c.Plans, "forever")
+	c.DefaultPlan = "unknown"
+	if _, err := newRetentionPlans(c); err == nil {
+		t.Fatal("默认套餐不存在时应返回错误")
+	}
+}
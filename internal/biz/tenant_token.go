@@ -0,0 +1,62 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"time"
+)
+
+// 轮换token时等待用户服务滚动更新完成的时长
+const tokenRotationTimeout = 5 * time.Minute
+
+// TenantTokenUsecase 管理租户只能访问其自身bucket的influxdb token
+type TenantTokenUsecase struct {
+	uc     *UserUsecase
+	logger *log.Helper
+}
+
+func NewTenantTokenUsecase(uc *UserUsecase, logger log.Logger) *TenantTokenUsecase {
+	return &TenantTokenUsecase{uc: uc, logger: log.NewHelper(logger)}
+}
+
+// Rotate 为租户创建新的influxdb token并更新到租户服务的secret中，待服务滚动更新完成后吊销租户其余的token，
+// 滚动更新失败时新旧token均保留，使服务保持可用，可以再次轮换
+func (t *TenantTokenUsecase) Rotate(username string) (time.Time, error) {
+	if _, err := t.uc.repo.GetRegisterInfo(username); err != nil {
+		return time.Time{}, errors.NotFound("Token_Error", "用户 "+username+" 不存在")
+	}
+	cluster, err := t.uc.clusters.clusterOf(username)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	backend, err := t.uc.timeSeriesOf(username)
+	if err != nil {
+		return time.Time{}, err
+	}
+	token, err := backend.CreateCredential(username)
+	if timeseries.IsUnsupported(err) {
+		return time.Time{}, unsupportedTimeSeries(backend, "租户凭证")
+	} else if err != nil {
+		return time.Time{}, errors.Newf(
+			500, "Token_Error",
+			"创建用户的influxdb token时发生了错误:%v", err)
+	}
+	err = cluster.RotateInfluxdbToken(username, token.Token, tokenRotationTimeout)
+	if err != nil {
+		return time.Time{}, errors.Newf(
+			500, "Token_Error",
+			"更新用户服务使用的influxdb token时发生了错误:%v", err,
+		).WithMetadata(errors.FromError(err).Metadata)
+	}
+	err = backend.RevokeCredentials(username, token.ID)
+	if err != nil {
+		return time.Time{}, errors.Newf(
+			500, "Token_Error",
+			"吊销用户旧的influxdb token时发生了错误:%v", err)
+	}
+
+	t.logger.Infof("轮换了用户 %v 的influxdb token", username)
+	return time.Now(), nil
+}
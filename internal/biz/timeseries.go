@@ -0,0 +1,160 @@
+package biz
+
+import (
+	"context"
+	"crypto/subtle"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"gitee.com/moyusir/service-centre/internal/biz/victoriametrics"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	"io"
+	"time"
+)
+
+// TimeSeriesBackend 保存租户设备状态信息、下采样数据与警告信息的时序数据库，
+// 负责租户存储的创建与清理、保留策略以及只能访问租户自身数据的凭证，
+// 不支持的操作返回timeseries.ErrUnsupported
+type TimeSeriesBackend interface {
+	// Type 时序数据库的类型，与服务配置中的time_series.type一致
+	Type() string
+	// CreateStorage 为租户创建存储，policy为nil时使用默认的保留策略
+	CreateStorage(username string, policy *timeseries.RetentionPolicy) error
+	// UpdateRetention 修改租户已有存储的保留策略
+	UpdateRetention(username string, policy *timeseries.RetentionPolicy) error
+	// CreateCredential 为租户创建只能访问其自身数据的凭证
+	CreateCredential(username string) (*timeseries.Credential, error)
+	// RevokeCredentials 吊销租户除keep以外的所有凭证，keep为空时吊销全部凭证
+	RevokeCredentials(username, keep string) error
+	// ClearStorage 删除租户的存储及其中的数据
+	ClearStorage(username string) error
+	// ConnectionEnv 租户服务连接时序数据库所需的非敏感信息，以环境变量名为键，凭证通过secret另行注入
+	ConnectionEnv(username string) (map[string]string, error)
+}
+
+// TimeSeriesAccountRepo 保存租户在victoriametrics集群中的租户id，以及租户经由vmauth访问其数据的凭证
+type TimeSeriesAccountRepo interface {
+	// GetAccountID 获得用户的租户id，未分配时返回0
+	GetAccountID(username string) (uint32, error)
+	// AllocateAccountID 为用户分配租户id，已分配时返回已有的id
+	AllocateAccountID(username string) (uint32, error)
+	// DeleteAccountID 删除用户的租户id
+	DeleteAccountID(username string) error
+	// ListAccountIDs 获得所有已分配的租户id，以用户名为键
+	ListAccountIDs() (map[string]uint32, error)
+	// SaveCredential 保存用户经由vmauth访问其租户数据的凭证
+	SaveCredential(username, id, token string) error
+	// ListCredentials 获得用户所有的凭证，以凭证id为键
+	ListCredentials(username string) (map[string]string, error)
+	// DeleteCredentials 删除用户的指定凭证，ids为空时删除用户所有的凭证
+	DeleteCredentials(username string, ids ...string) error
+}
+
+// 以下为只有部分时序数据库支持的能力，通过类型断言判断
+
+// 依据预警规则维护下采样任务
+type downsampleSyncer interface {
+	SyncDownsampleTasks(username string, states []*utilApi.DeviceStateRegisterInfo) error
+}
+
+// 查询租户各个bucket的状态
+type bucketStatusReader interface {
+	GetBucketStatus(username string) ([]influxdb.BucketStatus, error)
+}
+
+// 导出与导入bucket中的数据，用于数据导出与备份
+type bucketExporter interface {
+	ExportBucket(ctx context.Context, bucket, format string, w io.Writer, progress func(points int64)) (int64, error)
+}
+type bucketImporter interface {
+	ImportBucket(ctx context.Context, bucket string, r io.Reader) (int64, error)
+}
+
+// 统计bucket在时间范围内写入的数据点数量，用于计量
+type pointCounter interface {
+	CountPoints(bucket string, start, stop time.Time) (int64, error)
+}
+
+// 生成代理租户访问的鉴权配置，如victoriametrics的vmauth配置
+type authConfigRenderer interface {
+	AuthConfig() ([]byte, error)
+}
+
+// 依据服务配置实例化保存租户数据的时序数据库，未配置时使用influxdb
+func newTimeSeriesBackend(server *conf.Server, accounts TimeSeriesAccountRepo,
+	recorder audit.Recorder) (TimeSeriesBackend, error) {
+	config := server.TimeSeries
+	if config == nil || config.Type == "" || config.Type == influxdb.Type {
+		if server.Influxdb == nil {
+			return nil, errors.New(500, "Config_Error", "服务配置中缺少influxdb的连接信息")
+		}
+		client, err := influxdb.NewInfluxdbClient(
+			server.Influxdb.ServerUrl, server.Influxdb.AuthToken, server.Influxdb.Org)
+		if err != nil {
+			return nil, err
+		}
+		client.Recorder = recorder
+		return client, nil
+	}
+
+	switch config.Type {
+	case victoriametrics.Type:
+		vm := config.VictoriaMetrics
+		if vm == nil {
+			return nil, errors.New(500, "Config_Error", "服务配置中缺少victoriametrics的连接信息")
+		}
+		// 开源版本的victoriametrics不提供鉴权，未配置vmauth时租户可以访问其他租户的数据
+		if vm.AuthUrl == "" || vm.AuthConfigKey == "" {
+			return nil, errors.New(500, "Config_Error",
+				"使用victoriametrics时需要配置租户服务访问的vmauth地址及vmauth获取鉴权配置的密钥")
+		}
+		client, err := victoriametrics.NewClient(vm.InsertUrl, vm.SelectUrl, vm.AuthUrl, accounts)
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "%v", err)
+		}
+		client.Recorder = recorder
+		return client, nil
+	default:
+		return nil, errors.Newf(500, "Config_Error", "不支持的时序数据库:%s", config.Type)
+	}
+}
+
+// 获得保存用户数据的时序数据库，用户自带influxdb时为以其连接信息创建的客户端
+func (u *UserUsecase) timeSeriesOf(username string) (TimeSeriesBackend, error) {
+	if u.external != nil {
+		client, err := u.external.client(username)
+		if err != nil {
+			return nil, err
+		}
+		if client != nil {
+			return client, nil
+		}
+	}
+	return u.timeSeries, nil
+}
+
+// TimeSeriesAuthConfig 获得代理租户访问时序数据库的鉴权配置，如vmauth的配置，其中包括所有租户的凭证，
+// 因此key需要与服务配置中的密钥一致
+func (u *UserUsecase) TimeSeriesAuthConfig(key string) ([]byte, error) {
+	if u.timeSeriesAuthKey == "" ||
+		subtle.ConstantTimeCompare([]byte(key), []byte(u.timeSeriesAuthKey)) != 1 {
+		return nil, errors.Forbidden("TimeSeries_Auth_Error", "获取鉴权配置的密钥错误")
+	}
+	renderer, ok := u.timeSeries.(authConfigRenderer)
+	if !ok {
+		return nil, unsupportedTimeSeries(u.timeSeries, "生成鉴权配置")
+	}
+	config, err := renderer.AuthConfig()
+	if err != nil {
+		return nil, errors.Newf(500, "TimeSeries_Auth_Error", "生成时序数据库的鉴权配置时发生了错误:%v", err)
+	}
+	return config, nil
+}
+
+// 时序数据库不支持某一操作时返回给调用方的错误
+func unsupportedTimeSeries(backend TimeSeriesBackend, operation string) error {
+	return errors.BadRequest("TimeSeries_Unsupported",
+		"当前使用的时序数据库 "+backend.Type()+" 不支持"+operation)
+}
@@ -0,0 +1,70 @@
+package timeseries
+
+import (
+	"fmt"
+	"time"
+)
+
+// 允许的最短保留时长，即influxdb允许的最短保留时长
+const minRetention = time.Hour
+
+// RetentionPolicy 租户数据的保留策略，依次对应设备状态信息、下采样数据与警告信息，保留时长为0时数据永久保留
+type RetentionPolicy struct {
+	// 设备状态信息的保留时长
+	Data time.Duration
+	// 下采样数据的保留时长
+	WarningDetect time.Duration
+	// 警告信息的保留时长
+	Warnings time.Duration
+	// 数据分片的时长，为0时由时序数据库依据保留时长决定
+	ShardGroupDuration time.Duration
+}
+
+// DefaultRetentionPolicy 默认的保留策略，下采样的数据是临时的，因此只保留一天，
+// 设备状态和警告信息的信息需要提供给前端查询，因此保留一个月
+func DefaultRetentionPolicy() *RetentionPolicy {
+	return &RetentionPolicy{
+		Data:          720 * time.Hour,
+		WarningDetect: 24 * time.Hour,
+		Warnings:      720 * time.Hour,
+	}
+}
+
+// Validate 校验保留策略，非零的保留时长不能短于允许的最短时长，且需要在[min, max]范围内，
+// max不为0时不允许永久保留，shard group时长不能超过任何一个非零的保留时长
+func (p *RetentionPolicy) Validate(min, max time.Duration) error {
+	for _, r := range []struct {
+		name      string
+		retention time.Duration
+	}{{"data", p.Data}, {"warning_detect", p.WarningDetect}, {"warnings", p.Warnings}} {
+		switch {
+		case r.retention < 0:
+			return fmt.Errorf("%s的保留时长不能为负数", r.name)
+		case r.retention == 0 && max > 0:
+			return fmt.Errorf("%s的保留时长不能为永久，最长为%v", r.name, max)
+		case r.retention == 0:
+			continue
+		case r.retention < minRetention || r.retention < min:
+			return fmt.Errorf("%s的保留时长%v过短，最短为%v", r.name, r.retention, maxDuration(min, minRetention))
+		case max > 0 && r.retention > max:
+			return fmt.Errorf("%s的保留时长%v过长，最长为%v", r.name, r.retention, max)
+		case p.ShardGroupDuration > r.retention:
+			return fmt.Errorf("shard group时长%v不能超过%s的保留时长%v", p.ShardGroupDuration, r.name, r.retention)
+		}
+	}
+	if p.ShardGroupDuration < 0 {
+		return fmt.Errorf("shard group时长不能为负数")
+	}
+	if p.ShardGroupDuration > 0 && p.ShardGroupDuration < minRetention {
+		return fmt.Errorf("shard group时长%v过短，最短为%v", p.ShardGroupDuration, minRetention)
+	}
+
+	return nil
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
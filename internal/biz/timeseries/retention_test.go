@@ -0,0 +1,43 @@
+package timeseries
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	day := 24 * time.Hour
+	if err := DefaultRetentionPolicy().Validate(0, 0); err != nil {
+		t.Fatalf("默认的保留策略应有效:%v", err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		min, max time.Duration
+		valid    bool
+	}{
+		{"不限制时允许永久保留", RetentionPolicy{Data: 0, WarningDetect: day, Warnings: 0}, 0, 0, true},
+		{"配置了最长时长时不允许永久保留", RetentionPolicy{Data: 0, WarningDetect: day, Warnings: day}, 0, 365 * day, false},
+		{"短于influxdb允许的最短时长", RetentionPolicy{Data: time.Minute, WarningDetect: day, Warnings: day}, 0, 0, false},
+		{"短于配置的最短时长", RetentionPolicy{Data: day, WarningDetect: day, Warnings: 3 * day}, 7 * day, 0, false},
+		{"超过配置的最长时长", RetentionPolicy{Data: 400 * day, WarningDetect: day, Warnings: day}, 0, 365 * day, false},
+		{"shard group时长超过保留时长", RetentionPolicy{Data: 30 * day, WarningDetect: day, Warnings: 30 * day,
+			ShardGroupDuration: 7 * day}, 0, 0, false},
+		{"shard group时长不超过保留时长", RetentionPolicy{Data: 365 * day, WarningDetect: 7 * day, Warnings: 365 * day,
+			ShardGroupDuration: 7 * day}, day, 365 * day, true},
+		{"shard group时长过短", RetentionPolicy{Data: day, WarningDetect: day, Warnings: day,
+			ShardGroupDuration: time.Minute}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.min, tt.max)
+			if tt.valid && err != nil {
+				t.Fatalf("保留策略应有效:%v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("保留策略应无效")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package timeseries 包括与具体时序数据库无关的租户数据存储的类型
+package timeseries
+
+import "errors"
+
+// ErrUnsupported 时序数据库不支持的操作返回的错误
+var ErrUnsupported = errors.New("当前的时序数据库不支持该操作")
+
+// IsUnsupported 判断错误是否由时序数据库不支持的操作引起
+func IsUnsupported(err error) bool {
+	return errors.Is(err, ErrUnsupported)
+}
+
+// Credential 只能访问租户自身数据的凭证
+type Credential struct {
+	// 凭证的id，用于吊销凭证
+	ID    string
+	Token string
+}
+
+// BackendEnv 租户服务中保存时序数据库类型的环境变量，其余连接信息的环境变量由各个时序数据库决定
+const BackendEnv = "TIMESERIES_BACKEND"
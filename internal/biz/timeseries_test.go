@@ -0,0 +1,146 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz/victoriametrics"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"strings"
+	"testing"
+)
+
+type memoryAccountRepo struct {
+	ids         map[string]uint32
+	credentials map[string]map[string]string
+}
+
+func newMemoryAccountRepo() *memoryAccountRepo {
+	return &memoryAccountRepo{ids: map[string]uint32{}, credentials: map[string]map[string]string{}}
+}
+
+func (r *memoryAccountRepo) GetAccountID(username string) (uint32, error) {
+	return r.ids[username], nil
+}
+
+func (r *memoryAccountRepo) AllocateAccountID(username string) (uint32, error) {
+	if _, ok := r.ids[username]; !ok {
+		r.ids[username] = uint32(len(r.ids) + 1)
+	}
+	return r.ids[username], nil
+}
+
+func (r *memoryAccountRepo) DeleteAccountID(username string) error {
+	delete(r.ids, username)
+	return nil
+}
+
+func (r *memoryAccountRepo) ListAccountIDs() (map[string]uint32, error) {
+	ids := make(map[string]uint32, len(r.ids))
+	for username, id := range r.ids {
+		ids[username] = id
+	}
+	return ids, nil
+}
+
+func (r *memoryAccountRepo) SaveCredential(username, id, token string) error {
+	if r.credentials[username] == nil {
+		r.credentials[username] = map[string]string{}
+	}
+	r.credentials[username][id] = token
+	return nil
+}
+
+func (r *memoryAccountRepo) ListCredentials(username string) (map[string]string, error) {
+	credentials := make(map[string]string)
+	for id, token := range r.credentials[username] {
+		credentials[id] = token
+	}
+	return credentials, nil
+}
+
+func (r *memoryAccountRepo) DeleteCredentials(username string, ids ...string) error {
+	if len(ids) == 0 {
+		delete(r.credentials, username)
+	}
+	for _, id := range ids {
+		delete(r.credentials[username], id)
+	}
+	return nil
+}
+
+func Test_newTimeSeriesBackend(t *testing.T) {
+	backend, err := newTimeSeriesBackend(&conf.Server{TimeSeries: &conf.Server_TimeSeries{
+		Type: victoriametrics.Type,
+		VictoriaMetrics: &conf.Server_TimeSeries_VictoriaMetrics{
+			InsertUrl:     "http://vminsert:8480",
+			SelectUrl:     "http://vmselect:8481",
+			AuthUrl:       "http://vmauth:8427",
+			AuthConfigKey: "key",
+		},
+	}}, newMemoryAccountRepo(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Type() != victoriametrics.Type {
+		t.Fatalf("时序数据库类型错误:%v", backend.Type())
+	}
+	// victoriametrics不支持下采样任务与数据导出
+	if _, ok := backend.(downsampleSyncer); ok {
+		t.Fatal("victoriametrics不应支持下采样任务")
+	}
+	if _, ok := backend.(bucketExporter); ok {
+		t.Fatal("victoriametrics不应支持导出数据")
+	}
+	if err := unsupportedTimeSeries(backend, "导出数据"); errors.Code(err) != 400 {
+		t.Fatalf("不支持的操作应返回400:%v", err)
+	}
+
+	for _, c := range []*conf.Server{
+		{TimeSeries: &conf.Server_TimeSeries{Type: "timescaledb"}},
+		{TimeSeries: &conf.Server_TimeSeries{Type: victoriametrics.Type}},
+		// 未配置vmauth时租户之间无法隔离
+		{TimeSeries: &conf.Server_TimeSeries{Type: victoriametrics.Type,
+			VictoriaMetrics: &conf.Server_TimeSeries_VictoriaMetrics{
+				InsertUrl: "http://vminsert:8480", SelectUrl: "http://vmselect:8481"}}},
+		{TimeSeries: &conf.Server_TimeSeries{Type: "influxdb"}},
+	} {
+		if _, err := newTimeSeriesBackend(c, newMemoryAccountRepo(), nil); errors.Reason(err) != "Config_Error" {
+			t.Fatalf("无效的配置%v应返回配置错误:%v", c.TimeSeries, err)
+		}
+	}
+}
+
+func TestUserUsecase_TimeSeriesAuthConfig(t *testing.T) {
+	accounts := newMemoryAccountRepo()
+	backend, err := victoriametrics.NewClient(
+		"http://vminsert:8480", "http://vmselect:8481", "http://vmauth:8427", accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.CreateStorage("alice", nil); err != nil {
+		t.Fatal(err)
+	}
+	credential, err := backend.CreateCredential("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := &UserUsecase{timeSeries: backend, timeSeriesAuthKey: "key"}
+
+	for _, key := range []string{"", "other"} {
+		if _, err := uc.TimeSeriesAuthConfig(key); errors.Code(err) != 403 {
+			t.Fatalf("密钥%q错误时应返回403:%v", key, err)
+		}
+	}
+	config, err := uc.TimeSeriesAuthConfig("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), credential.Token) {
+		t.Fatalf("鉴权配置中缺少用户的凭证:%s", config)
+	}
+
+	// 未配置密钥时不允许获取
+	uc.timeSeriesAuthKey = ""
+	if _, err := uc.TimeSeriesAuthConfig(""); errors.Code(err) != 403 {
+		t.Fatalf("未配置密钥时应返回403:%v", err)
+	}
+}
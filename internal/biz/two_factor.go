@@ -0,0 +1,385 @@
+package biz
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTP的时间步长、验证码位数以及允许的时间偏差步数，参考RFC 6238
+	totpPeriod = 30
+	totpDigits = 6
+	totpSkew   = 1
+	// 启用二次验证时生成的恢复码数量
+	recoveryCodeCount = 10
+	// 未配置发行方名称时使用的默认值
+	defaultTwoFactorIssuer = "service-centre"
+)
+
+// TwoFactorUsecase 负责基于TOTP的二次验证，密钥加密后保存，恢复码只保存摘要，
+// 账号启用二次验证后，登录、注销以及更换api密钥时需要提供验证码或恢复码，
+// 连续错误的次数过多时锁定账号的二次验证，避免验证码被暴力猜测
+type TwoFactorUsecase struct {
+	repo TwoFactorRepo
+	// 加密TOTP密钥使用的AES-GCM，未配置加密密钥时为nil，此时无法启用二次验证
+	aead   cipher.AEAD
+	issuer string
+	// 允许连续错误的次数以及超出后锁定的时长
+	maxFailures int64
+	lockout     time.Duration
+	logger      *log.Helper
+}
+type TwoFactorRepo interface {
+	// SaveTwoFactor 保存账号的二次验证信息
+	SaveTwoFactor(username string, record []byte) error
+	// GetTwoFactor 获得账号的二次验证信息，不存在时返回nil
+	GetTwoFactor(username string) ([]byte, error)
+	// UpdateTwoFactor 账号的二次验证信息仍为previous时将其更新为record，返回false时表示已被并发修改
+	UpdateTwoFactor(username string, previous, record []byte) (bool, error)
+	// RemoveTwoFactor 删除账号的二次验证信息
+	RemoveTwoFactor(username string) error
+	// IncrTwoFactorFailures 对账号连续验证失败的次数加一并返回加一后的值，计数在首次失败lockout时长后清零
+	IncrTwoFactorFailures(username string, lockout time.Duration) (int64, error)
+	// GetTwoFactorFailures 获得账号连续验证失败的次数
+	GetTwoFactorFailures(username string) (int64, error)
+	// ResetTwoFactorFailures 验证通过后清零账号连续验证失败的次数
+	ResetTwoFactorFailures(username string) error
+}
+
+// 保存在数据库中的二次验证信息
+type twoFactorRecord struct {
+	// 加密后的TOTP密钥
+	Secret []byte `json:"secret"`
+	// 是否已通过验证并启用
+	Enabled bool `json:"enabled"`
+	// 未使用的恢复码的摘要
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	// 最近一次使用的验证码对应的时间步，用于防止验证码被重放
+	LastCounter int64 `json:"last_counter"`
+}
+
+func NewTwoFactorUsecase(server *conf.Server, repo TwoFactorRepo, logger log.Logger) (*TwoFactorUsecase, error) {
+	twoFactor := &TwoFactorUsecase{
+		repo:        repo,
+		issuer:      defaultTwoFactorIssuer,
+		maxFailures: 5,
+		lockout:     15 * time.Minute,
+		logger:      log.NewHelper(logger),
+	}
+
+	c := server.TwoFactor
+	if c == nil {
+		return twoFactor, nil
+	}
+	if c.Issuer != "" {
+		twoFactor.issuer = c.Issuer
+	}
+	if c.MaxFailures > 0 {
+		twoFactor.maxFailures = c.MaxFailures
+	}
+	if c.Lockout != nil && c.Lockout.AsDuration() > 0 {
+		twoFactor.lockout = c.Lockout.AsDuration()
+	}
+	if c.EncryptionKey != "" {
+		aead, err := newAEAD(c.EncryptionKey)
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "初始化二次验证的加密密钥时发生了错误:%v", err)
+		}
+		twoFactor.aead = aead
+	}
+
+	return twoFactor, nil
+}
+
+// Enroll 为账号生成新的TOTP密钥，返回base32编码的密钥以及otpauth链接，账号已启用二次验证时返回错误
+func (t *TwoFactorUsecase) Enroll(username string) (secret, otpauthUrl string, err error) {
+	if t.aead == nil {
+		return "", "", errors.New(500, "Two_Factor_Error", "服务未配置二次验证的加密密钥")
+	}
+
+	record, err := t.get(username)
+	if err != nil {
+		return "", "", err
+	}
+	if record != nil && record.Enabled {
+		return "", "", errors.Conflict("Two_Factor_Error", "账号已启用二次验证，需要先关闭")
+	}
+
+	key := make([]byte, 20)
+	if _, err := rand.Read(key); err != nil {
+		return "", "", errors.Newf(500, "Two_Factor_Error", "生成TOTP密钥时发生了错误:%v", err)
+	}
+	encrypted, err := t.encrypt(key)
+	if err != nil {
+		return "", "", err
+	}
+	err = t.save(username, &twoFactorRecord{Secret: encrypted})
+	if err != nil {
+		return "", "", err
+	}
+
+	secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(key)
+	return secret, totpUrl(t.issuer, username, secret), nil
+}
+
+// Verify 校验验证码，通过后启用二次验证，返回一次性的恢复码
+func (t *TwoFactorUsecase) Verify(username, code string) ([]string, error) {
+	record, previous, err := t.load(username)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errors.BadRequest("Two_Factor_Error", "账号未生成二次验证的密钥")
+	}
+	if record.Enabled {
+		return nil, errors.Conflict("Two_Factor_Error", "账号已启用二次验证")
+	}
+	if err := t.checkLockout(username); err != nil {
+		return nil, err
+	}
+	if ok, err := t.checkTotp(record, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, t.fail(username)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	record.RecoveryCodes = make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.Newf(500, "Two_Factor_Error", "生成恢复码时发生了错误:%v", err)
+		}
+		codes[i] = hex.EncodeToString(b)
+		record.RecoveryCodes[i] = recoveryCodeDigest(codes[i])
+	}
+	record.Enabled = true
+	err = t.update(username, previous, record)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logger.Infof("账号 %v 启用了二次验证", username)
+	return codes, nil
+}
+
+// Disable 校验验证码或恢复码，通过后关闭二次验证
+func (t *TwoFactorUsecase) Disable(username, code string) error {
+	record, previous, err := t.load(username)
+	if err != nil {
+		return err
+	}
+	if record == nil || !record.Enabled {
+		return errors.BadRequest("Two_Factor_Error", "账号未启用二次验证")
+	}
+	if err := t.check(username, record, previous, code); err != nil {
+		return err
+	}
+
+	t.logger.Infof("账号 %v 关闭了二次验证", username)
+	return t.repo.RemoveTwoFactor(username)
+}
+
+// Check 账号启用了二次验证时，校验验证码或恢复码，恢复码在使用后失效，账号未启用二次验证时直接通过
+func (t *TwoFactorUsecase) Check(username, code string) error {
+	record, previous, err := t.load(username)
+	if err != nil {
+		return err
+	}
+	if record == nil || !record.Enabled {
+		return nil
+	}
+	if code == "" {
+		return errors.Unauthorized("Two_Factor_Required", "账号已启用二次验证，需要在X-Two-Factor-Code请求头中携带验证码")
+	}
+
+	return t.check(username, record, previous, code)
+}
+
+// 校验验证码或恢复码，并保存验证码的时间步或删除已使用的恢复码，
+// previous为读取时保存的二次验证信息，只有其未被并发修改时校验才会通过，保证验证码与恢复码只被使用一次
+func (t *TwoFactorUsecase) check(username string, record *twoFactorRecord, previous []byte, code string) error {
+	if err := t.checkLockout(username); err != nil {
+		return err
+	}
+
+	ok, err := t.checkTotp(record, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		digest := recoveryCodeDigest(code)
+		for i, c := range record.RecoveryCodes {
+			if subtle.ConstantTimeCompare([]byte(c), []byte(digest)) == 1 {
+				record.RecoveryCodes = append(record.RecoveryCodes[:i], record.RecoveryCodes[i+1:]...)
+				ok = true
+				t.logger.Infof("账号 %v 使用了恢复码，剩余%v个", username, len(record.RecoveryCodes))
+				break
+			}
+		}
+	}
+	if !ok {
+		return t.fail(username)
+	}
+
+	return t.update(username, previous, record)
+}
+
+// 账号连续验证失败的次数过多时拒绝验证
+func (t *TwoFactorUsecase) checkLockout(username string) error {
+	failures, err := t.repo.GetTwoFactorFailures(username)
+	if err != nil {
+		return err
+	}
+	if failures >= t.maxFailures {
+		return errors.New(429, "Two_Factor_Locked", "验证码错误的次数过多，请稍后再试")
+	}
+	return nil
+}
+
+// 记录一次验证失败，返回验证码错误
+func (t *TwoFactorUsecase) fail(username string) error {
+	failures, err := t.repo.IncrTwoFactorFailures(username, t.lockout)
+	if err != nil {
+		return err
+	}
+	if failures == t.maxFailures {
+		t.logger.Warnf("账号 %v 的验证码连续错误%v次，锁定二次验证%v", username, failures, t.lockout)
+	}
+	return errors.Unauthorized("Two_Factor_Error", "验证码错误")
+}
+
+// 以比较并交换的方式保存验证通过后的二次验证信息，并清零连续验证失败的次数，
+// 二次验证信息已被并发修改时，说明验证码或恢复码已被同时使用，此次验证不通过
+func (t *TwoFactorUsecase) update(username string, previous []byte, record *twoFactorRecord) error {
+	marshal, err := json.Marshal(record)
+	if err != nil {
+		return errors.Newf(500, "Two_Factor_Error", "对二次验证信息进行json序列化时发生了错误:%v", err)
+	}
+	updated, err := t.repo.UpdateTwoFactor(username, previous, marshal)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.Unauthorized("Two_Factor_Error", "验证码已被使用")
+	}
+
+	if err := t.repo.ResetTwoFactorFailures(username); err != nil {
+		t.logger.Errorf("清零账号 %v 验证失败的次数时发生了错误:%v", username, err)
+	}
+	return nil
+}
+
+// 校验TOTP验证码，通过时记录验证码的时间步，已使用过的时间步不能再次通过
+func (t *TwoFactorUsecase) checkTotp(record *twoFactorRecord, code string) (bool, error) {
+	if len(code) != totpDigits {
+		return false, nil
+	}
+	key, err := t.decrypt(record.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	counter := time.Now().Unix() / totpPeriod
+	for c := counter - totpSkew; c <= counter+totpSkew; c++ {
+		if c <= record.LastCounter {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, c)), []byte(code)) == 1 {
+			record.LastCounter = c
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t *TwoFactorUsecase) encrypt(plaintext []byte) ([]byte, error) {
+	ciphertext, err := sealAEAD(t.aead, plaintext)
+	if err != nil {
+		return nil, errors.Newf(500, "Two_Factor_Error", "加密TOTP密钥时发生了错误:%v", err)
+	}
+	return ciphertext, nil
+}
+
+func (t *TwoFactorUsecase) decrypt(ciphertext []byte) ([]byte, error) {
+	if t.aead == nil {
+		return nil, errors.New(500, "Two_Factor_Error", "服务未配置二次验证的加密密钥")
+	}
+	plaintext, err := openAEAD(t.aead, ciphertext)
+	if err != nil {
+		return nil, errors.Newf(500, "Two_Factor_Error", "解密TOTP密钥时发生了错误:%v", err)
+	}
+	return plaintext, nil
+}
+
+func (t *TwoFactorUsecase) get(username string) (*twoFactorRecord, error) {
+	record, _, err := t.load(username)
+	return record, err
+}
+
+// 获得账号的二次验证信息以及其序列化后的内容，后者用于比较并交换
+func (t *TwoFactorUsecase) load(username string) (*twoFactorRecord, []byte, error) {
+	marshal, err := t.repo.GetTwoFactor(username)
+	if err != nil || marshal == nil {
+		return nil, nil, err
+	}
+
+	record := new(twoFactorRecord)
+	if err := json.Unmarshal(marshal, record); err != nil {
+		return nil, nil, errors.Newf(500, "Two_Factor_Error", "对二次验证信息进行json解码时发生了错误:%v", err)
+	}
+	return record, marshal, nil
+}
+
+func (t *TwoFactorUsecase) save(username string, record *twoFactorRecord) error {
+	marshal, err := json.Marshal(record)
+	if err != nil {
+		return errors.Newf(500, "Two_Factor_Error", "对二次验证信息进行json序列化时发生了错误:%v", err)
+	}
+	return t.repo.SaveTwoFactor(username, marshal)
+}
+
+// 计算给定时间步的TOTP验证码，即以时间步为计数器的HOTP(RFC 4226)
+func totpCode(key []byte, counter int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// 生成认证器应用可以识别的otpauth链接
+func totpUrl(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func recoveryCodeDigest(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,198 @@
+package biz
+
+import (
+	"bytes"
+	"encoding/base32"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的二次验证信息，不处理失败次数的过期
+type memoryTwoFactorRepo struct {
+	records  map[string][]byte
+	failures map[string]int64
+}
+
+func newMemoryTwoFactorRepo() *memoryTwoFactorRepo {
+	return &memoryTwoFactorRepo{records: make(map[string][]byte), failures: make(map[string]int64)}
+}
+
+func (r *memoryTwoFactorRepo) SaveTwoFactor(username string, record []byte) error {
+	r.records[username] = record
+	return nil
+}
+
+func (r *memoryTwoFactorRepo) GetTwoFactor(username string) ([]byte, error) {
+	return r.records[username], nil
+}
+
+func (r *memoryTwoFactorRepo) UpdateTwoFactor(username string, previous, record []byte) (bool, error) {
+	if !bytes.Equal(r.records[username], previous) {
+		return false, nil
+	}
+	r.records[username] = record
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepo) RemoveTwoFactor(username string) error {
+	delete(r.records, username)
+	delete(r.failures, username)
+	return nil
+}
+
+func (r *memoryTwoFactorRepo) IncrTwoFactorFailures(username string, lockout time.Duration) (int64, error) {
+	r.failures[username]++
+	return r.failures[username], nil
+}
+
+func (r *memoryTwoFactorRepo) GetTwoFactorFailures(username string) (int64, error) {
+	return r.failures[username], nil
+}
+
+func (r *memoryTwoFactorRepo) ResetTwoFactorFailures(username string) error {
+	delete(r.failures, username)
+	return nil
+}
+
+func TestTotpCode(t *testing.T) {
+	// RFC 6238 附录B中SHA1的测试向量，取后六位
+	key := []byte("12345678901234567890")
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		if code := totpCode(key, unix/totpPeriod); code != expected {
+			t.Errorf("时间%v的验证码应为%v，实际为%v", unix, expected, code)
+		}
+	}
+}
+
+func TestTwoFactorUsecase(t *testing.T) {
+	repo := newMemoryTwoFactorRepo()
+	twoFactor, err := NewTwoFactorUsecase(
+		&conf.Server{TwoFactor: &conf.Server_TwoFactor{EncryptionKey: "test"}}, repo, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未启用二次验证时直接通过
+	if err := twoFactor.Check("test", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, _, err := twoFactor.Enroll("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := totpCode(key, time.Now().Unix()/totpPeriod)
+	// 验证通过前不要求二次验证
+	if err := twoFactor.Check("test", ""); err != nil {
+		t.Fatal(err)
+	}
+	codes, err := twoFactor.Verify("test", code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("应返回%v个恢复码，实际为%v", recoveryCodeCount, len(codes))
+	}
+
+	if err := twoFactor.Check("test", ""); errors.Reason(err) != "Two_Factor_Required" {
+		t.Fatalf("启用二次验证后缺少验证码时应返回Two_Factor_Required，实际为:%v", err)
+	}
+	if err := twoFactor.Check("test", code); err == nil {
+		t.Fatal("已使用的验证码不应再次通过")
+	}
+	if err := twoFactor.Check("test", codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := twoFactor.Check("test", codes[0]); err == nil {
+		t.Fatal("已使用的恢复码不应再次通过")
+	}
+
+	// 保存的密钥经过加密
+	record, err := twoFactor.get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(record.Secret, key) {
+		t.Fatal("保存的TOTP密钥应经过加密")
+	}
+
+	if err := twoFactor.Disable("test", codes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := twoFactor.Check("test", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTwoFactorUsecase_check(t *testing.T) {
+	repo := newMemoryTwoFactorRepo()
+	twoFactor, err := NewTwoFactorUsecase(&conf.Server{TwoFactor: &conf.Server_TwoFactor{
+		EncryptionKey: "test", MaxFailures: 3}}, repo, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, _, err := twoFactor.Enroll("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := twoFactor.Verify("test", totpCode(key, time.Now().Unix()/totpPeriod-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同时使用同一个验证码时只有一次通过
+	code := totpCode(key, time.Now().Unix()/totpPeriod)
+	record, previous, err := twoFactor.load("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent, _, err := twoFactor.load("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := twoFactor.check("test", record, previous, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := twoFactor.check("test", concurrent, previous, code); err == nil {
+		t.Fatal("并发使用的验证码不应再次通过")
+	}
+
+	// 连续错误的次数过多时锁定，正确的恢复码也无法通过
+	for i := 0; i < 3; i++ {
+		if err := twoFactor.Check("test", "000000"); errors.Code(err) != 401 {
+			t.Fatalf("错误的验证码应返回401，实际为:%v", err)
+		}
+	}
+	if err := twoFactor.Check("test", codes[0]); errors.Reason(err) != "Two_Factor_Locked" {
+		t.Fatalf("锁定后应拒绝验证，实际为:%v", err)
+	}
+
+	// 锁定解除后验证通过，并清零失败的次数
+	delete(repo.failures, "test")
+	if err := twoFactor.Check("test", "000000"); err == nil {
+		t.Fatal("错误的验证码不应通过")
+	}
+	if err := twoFactor.Check("test", codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if repo.failures["test"] != 0 {
+		t.Fatalf("验证通过后应清零失败的次数，实际为:%v", repo.failures["test"])
+	}
+}
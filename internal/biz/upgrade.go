@@ -0,0 +1,392 @@
+package biz
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/kubecontroller"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UpgradeUsecase 负责将所有租户的服务分批次升级到新的镜像版本
+type UpgradeUsecase struct {
+	repo     UpgradeRepo
+	userRepo UserRepo
+	clusters *clusterPlacement
+	events   *EventPublisher
+	webhooks *WebhookUsecase
+	// 服务配置中的镜像，未执行过成功的升级任务时作为默认的升级目标镜像
+	images *conf.Server_Images
+	// 默认的批次大小以及等待单个租户完成滚动更新的超时时长
+	batchSize  int64
+	canarySize int64
+	timeout    time.Duration
+	// 标识当前是否有正在执行的升级任务，同一时间只允许执行一个升级任务
+	running int32
+	logger  *log.Helper
+}
+type UpgradeRepo interface {
+	// SaveUpgradeStatus 保存升级任务的状态
+	SaveUpgradeStatus(id string, status []byte) error
+	// GetUpgradeStatus 获得升级任务的状态
+	GetUpgradeStatus(id string) ([]byte, error)
+}
+
+// 记录执行过更新的租户以及其升级前使用的镜像，用于升级失败时回滚
+type upgradedTenant struct {
+	index    int
+	previous *kubecontroller.ServiceImages
+}
+
+func NewUpgradeUsecase(server *conf.Server, uc *UserUsecase, repo UpgradeRepo, logger log.Logger) *UpgradeUsecase {
+	upgrade := &UpgradeUsecase{
+		repo:       repo,
+		userRepo:   uc.repo,
+		clusters:   uc.clusters,
+		events:     uc.events,
+		webhooks:   uc.webhooks,
+		images:     server.Images,
+		batchSize:  5,
+		canarySize: 1,
+		timeout:    5 * time.Minute,
+		logger:     log.NewHelper(logger),
+	}
+	if c := server.Upgrade; c != nil {
+		if c.BatchSize > 0 {
+			upgrade.batchSize = c.BatchSize
+		}
+		if c.CanarySize > 0 {
+			upgrade.canarySize = c.CanarySize
+		}
+		if c.Timeout != nil {
+			upgrade.timeout = c.Timeout.AsDuration()
+		}
+	}
+
+	return upgrade
+}
+
+// UpgradeFleet 创建升级任务，在后台先升级金丝雀批次的租户，然后分批次升级剩余的租户，
+// 任意租户升级失败时停止升级，并将本次升级过的所有租户回滚到升级前的镜像
+func (u *UpgradeUsecase) UpgradeFleet(request *v1.UpgradeFleetRequest) (id string, err error) {
+	if request == nil {
+		return "", errors.BadRequest("request is nil", "")
+	}
+
+	// 补全请求中未指定的目标镜像以及批次大小
+	target := &v1.UpgradeFleetRequest{
+		DataCollectionImage:    request.DataCollectionImage,
+		DataProcessingImage:    request.DataProcessingImage,
+		CompilationClientImage: request.CompilationClientImage,
+		BatchSize:              request.BatchSize,
+		CanarySize:             request.CanarySize,
+	}
+	images, err := serviceImages(u.userRepo, u.images)
+	if err != nil {
+		return "", err
+	}
+	if target.DataCollectionImage == "" {
+		target.DataCollectionImage = images.DataCollection
+	}
+	if target.DataProcessingImage == "" {
+		target.DataProcessingImage = images.DataProcessing
+	}
+	if target.CompilationClientImage == "" {
+		target.CompilationClientImage = images.CompilationClient
+	}
+	if target.BatchSize <= 0 {
+		target.BatchSize = u.batchSize
+	}
+	if target.CanarySize <= 0 {
+		target.CanarySize = u.canarySize
+	}
+
+	if !atomic.CompareAndSwapInt32(&u.running, 0, 1) {
+		return "", errors.Conflict("Upgrade_Error", "已有正在执行的升级任务")
+	}
+	defer func() {
+		if err != nil {
+			atomic.StoreInt32(&u.running, 0)
+		}
+	}()
+
+	users, err := u.userRepo.ListUsers()
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(users)
+
+	// 依据金丝雀批次大小和批次大小为每个租户划分批次，
+	// id中附加随机的后缀，避免同一秒内创建的升级任务覆盖彼此的状态
+	suffix := make([]byte, 4)
+	if _, err = rand.Read(suffix); err != nil {
+		return "", errors.Newf(500, "Upgrade_Error", "生成升级任务的id时发生了错误:%v", err)
+	}
+	id = fmt.Sprintf("upgrade-%s-%s", time.Now().Format("20060102150405"), hex.EncodeToString(suffix))
+	status := &v1.UpgradeStatus{
+		Id:        id,
+		State:     v1.UpgradeStatus_RUNNING,
+		Target:    target,
+		Tenants:   make([]*v1.TenantUpgradeStatus, len(users)),
+		StartTime: timestamppb.Now(),
+	}
+	for i, user := range users {
+		var batch int64
+		if int64(i) >= target.CanarySize {
+			batch = (int64(i)-target.CanarySize)/target.BatchSize + 1
+		}
+		status.Tenants[i] = &v1.TenantUpgradeStatus{
+			Username: user,
+			Batch:    batch,
+			State:    v1.UpgradeStatus_PENDING,
+		}
+	}
+	err = u.saveStatus(status)
+	if err != nil {
+		return "", err
+	}
+
+	u.logger.Infof("开始执行升级任务 %v，共 %v 个租户", id, len(users))
+	go u.upgrade(status)
+
+	return id, nil
+}
+
+// GetUpgradeStatus 获得升级任务的执行状态
+func (u *UpgradeUsecase) GetUpgradeStatus(id string) (*v1.UpgradeStatus, error) {
+	data, err := u.repo.GetUpgradeStatus(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := new(v1.UpgradeStatus)
+	err = proto.Unmarshal(data, status)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Upgrade_Error",
+			"对升级任务状态进行protobuf解码时发生了错误:%v", err)
+	}
+
+	return status, nil
+}
+
+// 按批次依次升级租户，每个批次内的租户并发升级
+func (u *UpgradeUsecase) upgrade(status *v1.UpgradeStatus) {
+	defer atomic.StoreInt32(&u.running, 0)
+
+	target := &kubecontroller.ServiceImages{
+		DataCollection:    status.Target.DataCollectionImage,
+		DataProcessing:    status.Target.DataProcessingImage,
+		CompilationClient: status.Target.CompilationClientImage,
+	}
+
+	var (
+		// 保护status以及upgraded在协程间的并发修改
+		mutex    sync.Mutex
+		upgraded []upgradedTenant
+	)
+	setTenantState := func(i int, state v1.UpgradeStatus_State, message string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		status.Tenants[i].State = state
+		status.Tenants[i].Message = message
+		u.saveStatus(status)
+	}
+
+	for start := 0; start < len(status.Tenants); {
+		batch := status.Tenants[start].Batch
+		end := start
+		for end < len(status.Tenants) && status.Tenants[end].Batch == batch {
+			end++
+		}
+
+		eg := &errgroup.Group{}
+		for i := start; i < end; i++ {
+			i := i
+			username := status.Tenants[i].Username
+			eg.Go(func() error {
+				cluster, err := u.clusters.clusterOf(username)
+				if err != nil {
+					setTenantState(i, v1.UpgradeStatus_FAILED,
+						fmt.Sprintf("查询租户服务所在的集群时发生了错误:%v", err))
+					return err
+				}
+				previous, err := cluster.GetServiceImages(username)
+				if err != nil {
+					setTenantState(i, v1.UpgradeStatus_FAILED,
+						fmt.Sprintf("查询租户当前使用的镜像时发生了错误:%v", err))
+					return err
+				}
+				if *previous == *target {
+					setTenantState(i, v1.UpgradeStatus_SUCCEEDED, "租户已在使用目标镜像")
+					return nil
+				}
+
+				mutex.Lock()
+				upgraded = append(upgraded, upgradedTenant{index: i, previous: previous})
+				mutex.Unlock()
+
+				setTenantState(i, v1.UpgradeStatus_RUNNING, "")
+				err = cluster.UpdateServiceImages(username, target, u.timeout)
+				saveRolloutOutcome(u.userRepo, u.logger, username, "upgrade", err)
+				if err != nil {
+					u.webhooks.Notify(WebhookUpgradeFailed, username, u.webhooks.Subscriptions(username), err)
+					setTenantState(i, v1.UpgradeStatus_FAILED,
+						fmt.Sprintf("更新租户服务的镜像时发生了错误:%v", err))
+					return err
+				}
+				u.events.Publish(EventTenantUpgraded, username,
+					tenantResources(username, cluster), imageDetails(status.Id, target))
+				u.webhooks.Notify(WebhookUpgradeSucceeded, username, u.webhooks.Subscriptions(username), nil)
+				setTenantState(i, v1.UpgradeStatus_SUCCEEDED, "")
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			u.logger.Errorf("升级任务 %v 在第 %v 批次失败，开始回滚:%v", status.Id, batch, err)
+			u.rollback(status, upgraded, &mutex)
+
+			mutex.Lock()
+			status.Message = fmt.Sprintf("第 %v 批次的租户升级失败，已停止升级:%v", batch, err)
+			status.EndTime = timestamppb.Now()
+			u.saveStatus(status)
+			mutex.Unlock()
+			return
+		}
+		start = end
+	}
+
+	// 所有租户升级成功后，新注册的用户同样使用目标镜像
+	images := &conf.Server_Images{
+		DataCollection:    target.DataCollection,
+		DataProcessing:    target.DataProcessing,
+		CompilationClient: target.CompilationClient,
+	}
+	if err := saveServiceImages(u.userRepo, images); err != nil {
+		u.logger.Errorf("保存升级任务 %v 的目标镜像时发生了错误，新注册的用户仍将使用原有的镜像:%v", status.Id, err)
+	}
+
+	mutex.Lock()
+	status.State = v1.UpgradeStatus_SUCCEEDED
+	status.EndTime = timestamppb.Now()
+	u.saveStatus(status)
+	mutex.Unlock()
+	u.logger.Infof("完成了升级任务 %v", status.Id)
+}
+
+// 将本次升级任务中执行过更新的租户回滚到升级前的镜像
+func (u *UpgradeUsecase) rollback(status *v1.UpgradeStatus, upgraded []upgradedTenant, mutex *sync.Mutex) {
+	var rollbackFailed int32
+	eg := &errgroup.Group{}
+	for _, tenant := range upgraded {
+		tenant := tenant
+		eg.Go(func() error {
+			username := status.Tenants[tenant.index].Username
+			cluster, err := u.clusters.clusterOf(username)
+			if err == nil {
+				err = cluster.UpdateServiceImages(username, tenant.previous, u.timeout)
+			}
+			saveRolloutOutcome(u.userRepo, u.logger, username, "rollback", err)
+			if err == nil {
+				u.events.Publish(EventTenantUpgraded, username,
+					tenantResources(username, cluster), imageDetails(status.Id, tenant.previous))
+				u.webhooks.Notify(WebhookUpgradeRolledBack, username, u.webhooks.Subscriptions(username), nil)
+			} else {
+				u.webhooks.Notify(WebhookUpgradeFailed, username, u.webhooks.Subscriptions(username), err)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			t := status.Tenants[tenant.index]
+			if err != nil {
+				atomic.StoreInt32(&rollbackFailed, 1)
+				t.State = v1.UpgradeStatus_FAILED
+				t.Message = fmt.Sprintf("%s 回滚时发生了错误:%v", t.Message, err)
+			} else {
+				t.State = v1.UpgradeStatus_ROLLED_BACK
+			}
+			u.saveStatus(status)
+			return err
+		})
+	}
+	eg.Wait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if atomic.LoadInt32(&rollbackFailed) == 0 {
+		status.State = v1.UpgradeStatus_ROLLED_BACK
+	} else {
+		status.State = v1.UpgradeStatus_FAILED
+	}
+}
+
+// 保存升级任务的状态
+func (u *UpgradeUsecase) saveStatus(status *v1.UpgradeStatus) error {
+	marshal, err := proto.Marshal(status)
+	if err != nil {
+		return errors.Newf(
+			500, "Upgrade_Error",
+			"对升级任务状态进行protobuf序列化时发生了错误:%v", err)
+	}
+
+	err = u.repo.SaveUpgradeStatus(status.Id, marshal)
+	if err != nil {
+		u.logger.Errorf("保存升级任务 %v 的状态时发生了错误:%v", status.Id, err)
+	}
+	return err
+}
+
+// 将升级任务以及租户服务当前使用的镜像转换为事件的附加信息
+func imageDetails(id string, images *kubecontroller.ServiceImages) map[string]string {
+	return map[string]string{
+		"upgrade_id":               id,
+		"data_collection_image":    images.DataCollection,
+		"data_processing_image":    images.DataProcessing,
+		"compilation_client_image": images.CompilationClient,
+	}
+}
+
+// 获得新注册的用户使用的镜像，即最近一次成功的升级任务的目标镜像，未执行过升级时为服务配置中的镜像
+func serviceImages(repo UserRepo, configured *conf.Server_Images) (*conf.Server_Images, error) {
+	data, err := repo.GetServiceImages()
+	if err != nil || data == nil {
+		return configured, err
+	}
+
+	images := new(conf.Server_Images)
+	if err := proto.Unmarshal(data, images); err != nil {
+		return nil, errors.Newf(500, "Upgrade_Error", "对用户服务使用的镜像进行protobuf解码时发生了错误:%v", err)
+	}
+	if !validImages(images) {
+		return configured, nil
+	}
+	return images, nil
+}
+
+// 保存新注册的用户使用的镜像
+func saveServiceImages(repo UserRepo, images *conf.Server_Images) error {
+	data, err := proto.Marshal(images)
+	if err != nil {
+		return errors.Newf(500, "Upgrade_Error", "对用户服务使用的镜像进行protobuf序列化时发生了错误:%v", err)
+	}
+	return repo.SaveServiceImages(data)
+}
+
+// 检查配置的镜像是否完整
+func validImages(images *conf.Server_Images) bool {
+	return images != nil &&
+		images.DataCollection != "" &&
+		images.DataProcessing != "" &&
+		images.CompilationClient != ""
+}
@@ -0,0 +1,41 @@
+package biz
+
+import (
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// 只实现保存镜像的用户数据库
+type imagesRepo struct {
+	UserRepo
+	images []byte
+}
+
+func (r *imagesRepo) SaveServiceImages(images []byte) error {
+	r.images = images
+	return nil
+}
+
+func (r *imagesRepo) GetServiceImages() ([]byte, error) {
+	return r.images, nil
+}
+
+func Test_serviceImages(t *testing.T) {
+	repo := new(imagesRepo)
+	configured := &conf.Server_Images{DataCollection: "dc:v1", DataProcessing: "dp:v1", CompilationClient: "cc:v1"}
+
+	images, err := serviceImages(repo, configured)
+	if err != nil || images != configured {
+		t.Fatalf("未执行过升级时应使用服务配置中的镜像:%v %v", images, err)
+	}
+
+	upgraded := &conf.Server_Images{DataCollection: "dc:v2", DataProcessing: "dp:v2", CompilationClient: "cc:v2"}
+	if err := saveServiceImages(repo, upgraded); err != nil {
+		t.Fatal(err)
+	}
+	images, err = serviceImages(repo, configured)
+	if err != nil || !proto.Equal(images, upgraded) {
+		t.Fatalf("升级后应使用升级的目标镜像:%v %v", images, err)
+	}
+}
@@ -1,26 +1,42 @@
 package biz
 
 import (
+	"context"
+	"fmt"
 	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
 	"gitee.com/moyusir/service-centre/internal/biz/gateway"
 	"gitee.com/moyusir/service-centre/internal/biz/influxdb"
 	"gitee.com/moyusir/service-centre/internal/biz/kubecontroller"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
 	"gitee.com/moyusir/service-centre/internal/conf"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	corev1 "k8s.io/api/core/v1"
+	"strings"
 	"time"
 )
 
 type UserUsecase struct {
 	repo                     UserRepo
-	controller               *kubecontroller.KubeController
-	gateway                  *gateway.Manager
+	deletions                DeletionRepo
+	gracePeriod              time.Duration
+	clusters                 *clusterPlacement
+	gateway                  gateway.Provider
+	appDomainName            string
 	compilationCenterAddress string
-	influxdbClient           *influxdb.Client
-	logger                   *log.Helper
+	timeSeries               TimeSeriesBackend
+	// 获取时序数据库鉴权配置使用的密钥
+	timeSeriesAuthKey string
+	external          *externalInfluxdbs
+	plans             *retentionPlans
+	images            *conf.Server_Images
+	events            *EventPublisher
+	webhooks          *WebhookUsecase
+	twoFactor         *TwoFactorUsecase
+	logger            *log.Helper
 }
 type UserRepo interface {
 	// Login 用户登录
@@ -29,41 +45,112 @@ type UserRepo interface {
 	Register(username, password, token string, registerInfo []byte) error
 	// GetRegisterInfo 获取用户注册信息
 	GetRegisterInfo(username string) ([]byte, error)
+	// UpdateRegisterInfo 更新已注册用户的注册信息
+	UpdateRegisterInfo(username string, registerInfo []byte) error
 	// UnRegister 用户注销
 	UnRegister(username string) error
 	// GetClientCode 获得生成的客户端代码
 	GetClientCode(username string) ([]byte, error)
+	// ListUsers 获得所有已注册用户的用户名
+	ListUsers() ([]string, error)
+	// SaveRolloutOutcome 保存用户服务最近一次部署或升级的结果
+	SaveRolloutOutcome(username string, outcome []byte) error
+	// GetRolloutOutcome 获得用户服务最近一次部署或升级的结果，不存在时返回nil
+	GetRolloutOutcome(username string) ([]byte, error)
+	// SavePlacement 保存用户服务所在的集群
+	SavePlacement(username, cluster string) error
+	// GetPlacement 获得用户服务所在的集群，不存在记录时返回空字符串
+	GetPlacement(username string) (string, error)
+	// ListPlacements 获得所有用户服务所在的集群，以用户名为键
+	ListPlacements() (map[string]string, error)
+	// SaveServiceImages 保存新注册的用户使用的镜像
+	SaveServiceImages(images []byte) error
+	// GetServiceImages 获得新注册的用户使用的镜像，不存在时返回nil
+	GetServiceImages() ([]byte, error)
 }
 
-func NewUserUsecase(server *conf.Server, repo UserRepo, logger log.Logger) (*UserUsecase, error) {
-	controller, err := kubecontroller.NewKubeController(server.Cluster.Namespace)
+func NewUserUsecase(server *conf.Server, repo UserRepo, deletions DeletionRepo, accounts TimeSeriesAccountRepo,
+	externals ExternalInfluxdbRepo, events *EventPublisher, webhooks *WebhookUsecase, twoFactor *TwoFactorUsecase,
+	audits *AuditUsecase, logger log.Logger) (*UserUsecase, error) {
+	if !validImages(server.Images) {
+		return nil, errors.New(500, "Config_Error", "服务配置中缺少用户服务使用的镜像")
+	}
+
+	clusters, err := newClusterPlacement(server.Cluster, repo)
 	if err != nil {
 		return nil, err
 	}
 
-	manager, err := gateway.NewManager(server.Gateway.Address, server.AppDomainName)
+	provider, err := newGatewayProvider(server, audits)
 	if err != nil {
 		return nil, err
 	}
 
-	influxdbClient, err := influxdb.NewInfluxdbClient(
-		server.Influxdb.ServerUrl, server.Influxdb.AuthToken, server.Influxdb.Org)
+	timeSeries, err := newTimeSeriesBackend(server, accounts, audits)
+	if err != nil {
+		return nil, err
+	}
+	guard, err := newOutboundGuard(server.Outbound)
+	if err != nil {
+		return nil, err
+	}
+	external, err := newExternalInfluxdbs(server.TimeSeries, guard, externals, audits)
 	if err != nil {
 		return nil, err
 	}
 
+	plans, err := newRetentionPlans(server.Influxdb)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录服务对各个集群的每次修改，网关与时序数据库的修改在创建时即已记录
+	for _, c := range clusters.clusters {
+		c.Recorder = audits
+	}
+
+	gracePeriod := defaultGracePeriod
+	if c := server.Deletion; c != nil && c.GracePeriod != nil && c.GracePeriod.AsDuration() > 0 {
+		gracePeriod = c.GracePeriod.AsDuration()
+	}
+
 	return &UserUsecase{
 		repo:                     repo,
-		controller:               controller,
-		gateway:                  manager,
+		deletions:                deletions,
+		gracePeriod:              gracePeriod,
+		clusters:                 clusters,
+		gateway:                  provider,
+		appDomainName:            server.AppDomainName,
 		compilationCenterAddress: server.CompilationCenter.Address,
-		influxdbClient:           influxdbClient,
+		timeSeries:               timeSeries,
+		timeSeriesAuthKey:        server.GetTimeSeries().GetVictoriaMetrics().GetAuthConfigKey(),
+		external:                 external,
+		plans:                    plans,
+		images:                   server.Images,
+		events:                   events,
+		webhooks:                 webhooks,
+		twoFactor:                twoFactor,
 		logger:                   log.NewHelper(logger),
 	}, nil
 }
 
-func (u *UserUsecase) Login(username, password string) (token string, err error) {
-	return u.repo.Login(username, password)
+// Login 用户登录，用户启用了二次验证时，code需要为有效的验证码或恢复码
+func (u *UserUsecase) Login(username, password, code string) (token string, err error) {
+	token, err = u.repo.Login(username, password)
+	if err != nil {
+		return "", err
+	}
+	err = u.checkPending(username)
+	if err != nil {
+		return "", err
+	}
+	err = u.twoFactor.Check(username, code)
+	if err != nil {
+		return "", err
+	}
+
+	u.events.Publish(EventTenantLoggedIn, username, nil, nil)
+	return token, nil
 }
 
 // Register 完成用户注册
@@ -78,12 +165,36 @@ func (u *UserUsecase) Register(request *v1.RegisterRequest) (token string, err e
 	}
 	username := request.User.Id
 
+	// 在创建任何资源之前确认用户名、用户的套餐、订阅的webhook，以及用户自带的influxdb可以使用
+	if err := checkReservedUsername(username); err != nil {
+		return "", err
+	}
+	if err := u.plans.checkRegisterPlan(request.Plan); err != nil {
+		return "", err
+	}
+	plan, retention, err := u.plans.policy(request.Plan)
+	if err != nil {
+		return "", err
+	}
+	if err := u.webhooks.ValidateSubscriptions(request.Webhooks); err != nil {
+		return "", err
+	}
+	if request.ExternalInfluxdb != nil {
+		client, err := u.external.connect(request.ExternalInfluxdb)
+		if err != nil {
+			return "", err
+		}
+		client.Close()
+	}
+
 	defer func() {
 		if err != nil {
 			// 注册失败时，需要清理掉创建的无效资源
 			u.clear(username)
+			u.webhooks.Notify(WebhookRegisterFailed, username, request.Webhooks, err)
 		} else {
 			u.logger.Infof("完成了用户 %v 的注册请求", request.User.Id)
+			u.webhooks.Notify(WebhookRegisterSucceeded, username, request.Webhooks, nil)
 		}
 	}()
 	// 在网关创建用户对应的consumer，从而创建token
@@ -95,100 +206,237 @@ func (u *UserUsecase) Register(request *v1.RegisterRequest) (token string, err e
 		)
 	}
 
-	// 创建保存用户设备状态信息的influxdb bucket
-	err = u.influxdbClient.CreateBucket(username)
+	// 创建用户的bucket、k8s中的服务以及网关中的路由
+	cluster, err := u.provision(username, request, retention)
+	if err != nil {
+		return "", err
+	}
+
+	// 最后往数据库中保存用户信息，避免出现服务还未初始化用户就可以登录网页，
+	// webhook的密钥与自带influxdb的连接信息单独保存，不随注册信息返回给用户
+	info := proto.Clone(request).(*v1.RegisterRequest)
+	info.Webhooks = nil
+	info.ExternalInfluxdb = nil
+	info.Plan = plan
+	marshal, err := proto.Marshal(info)
 	if err != nil {
 		return "", errors.Newf(
 			500, "Register_Error",
-			"创建用户对应的influx bucket时发生了错误:%v", err,
+			"对用户注册信息进行protobuf序列化时发生了错误:%v", err,
+		)
+	}
+	err = u.repo.Register(username, request.User.Password, token, marshal)
+	if err != nil {
+		return "", err
+	}
+	err = u.webhooks.SaveSubscriptions(username, request.Webhooks)
+	if err != nil {
+		return "", err
+	}
+	saveRolloutOutcome(u.repo, u.logger, username, "register", nil)
+	u.events.Publish(EventTenantRegistered, username, tenantResources(username, cluster), nil)
+
+	return
+}
+
+// 为用户创建时序数据的存储，然后为服务代码创建相应的configMap，并在选择的集群中启动相应的service和deployment，
+// 最后在网关创建服务、路由以及认证插件，返回部署用户服务的集群，用户在网关中的consumer需要已经存在
+func (u *UserUsecase) provision(username string, request *v1.RegisterRequest,
+	retention *timeseries.RetentionPolicy) (cluster *cluster, err error) {
+	// 用户自带influxdb时保存加密后的连接信息，之后用户的bucket均创建在其自带的influxdb中
+	if request.ExternalInfluxdb != nil {
+		err = u.external.save(username, request.ExternalInfluxdb)
+		if err != nil {
+			return nil, err
+		}
+	}
+	backend, err := u.timeSeriesOf(username)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建保存用户设备状态信息的时序数据存储
+	err = backend.CreateStorage(username, retention)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Register_Error",
+			"创建用户的时序数据存储时发生了错误:%v", err,
+		)
+	}
+	// 依据预警规则创建写入下采样数据的task
+	if syncer, ok := backend.(downsampleSyncer); ok {
+		err = syncer.SyncDownsampleTasks(username, request.DeviceStateRegisterInfos)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Register_Error",
+				"创建用户预警规则对应的下采样task时发生了错误:%v", err,
+			)
+		}
+	}
+	connection, err := backend.ConnectionEnv(username)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Register_Error",
+			"获得用户服务连接时序数据库的信息时发生了错误:%v", err,
+		)
+	}
+
+	// 依据放置策略选择部署用户服务的集群
+	cluster, err = u.clusters.place(username, request.Region)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Register_Error",
+			"为用户选择部署服务的集群时发生了错误:%v", err,
+		)
+	}
+
+	// 新注册的用户使用最近一次升级的目标镜像，使其与已升级的租户保持一致
+	images, err := serviceImages(u.repo, u.images)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Register_Error",
+			"获得用户服务使用的镜像时发生了错误:%v", err,
 		)
 	}
 
 	// 为用户创建服务运行所需的k8s资源
 	// 创建用户注册信息对应的configMap，用于初始容器向编译中心发起编译请求使用
-	registerInfo, err := u.controller.CreateConfigMapOfRegisterInfo(
+	registerInfo, err := cluster.CreateConfigMapOfRegisterInfo(
 		username, request.DeviceStateRegisterInfos, request.DeviceConfigRegisterInfos)
 	if err != nil {
-		return "", errors.Newf(
+		return nil, errors.Newf(
 			500, "Register_Error",
 			"创建用户对应的k8s资源时发生了错误:%v", err,
 		)
 	}
 
+	// 为用户创建只能访问其自身数据的凭证，保存在secret中并注入用户的服务，时序数据库不提供凭证时不创建secret
+	var secretName string
+	token, err := backend.CreateCredential(username)
+	if err != nil && !timeseries.IsUnsupported(err) {
+		return nil, errors.Newf(
+			500, "Register_Error",
+			"创建用户的时序数据库凭证时发生了错误:%v", err,
+		)
+	}
+	if err == nil {
+		secret, err := cluster.CreateInfluxdbSecret(username, token.Token)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Register_Error",
+				"创建用户对应的k8s资源时发生了错误:%v", err,
+			)
+		}
+		secretName = secret.Name
+	}
+
 	// 利用协程部署数据收集和数据处理服务
 	var dcService, dpService *corev1.Service
 	eg := &errgroup.Group{}
 	eg.Go(func() error {
-		dcService, err = u.controller.DeployDataCollectionService(&kubecontroller.DataCollectionDeployOption{
+		dcService, err = cluster.DeployDataCollectionService(&kubecontroller.DataCollectionDeployOption{
 			BaseDeployOption: kubecontroller.BaseDeployOption{
 				Username:                 username,
 				Replica:                  2,
 				Timeout:                  5 * time.Minute,
 				CompilationCenterAddress: u.compilationCenterAddress,
 				RegisterInfo:             registerInfo,
-				Image:                    "moyusir233/graduation-design:data-collection",
+				Image:                    images.DataCollection,
+				BuildImage:               images.CompilationClient,
+				InfluxdbSecret:           secretName,
+				TimeSeriesEnv:            connection,
 			},
-			AppDomainName: u.gateway.AppDomainName,
+			AppDomainName: u.appDomainName,
 		})
 		return err
 	})
 	eg.Go(func() error {
-		dpService, err = u.controller.DeployDataProcessingService(&kubecontroller.DataProcessingDeployOption{
+		dpService, err = cluster.DeployDataProcessingService(&kubecontroller.DataProcessingDeployOption{
 			BaseDeployOption: kubecontroller.BaseDeployOption{
 				Username:                 username,
 				Replica:                  1,
 				Timeout:                  5 * time.Minute,
 				CompilationCenterAddress: u.compilationCenterAddress,
 				RegisterInfo:             registerInfo,
-				Image:                    "moyusir233/graduation-design:data-processing",
+				Image:                    images.DataProcessing,
+				BuildImage:               images.CompilationClient,
+				InfluxdbSecret:           secretName,
+				TimeSeriesEnv:            connection,
 			},
 		})
 		return err
 	})
 	err = eg.Wait()
 	if err != nil {
-		return "", errors.Newf(
+		// 保留滚动更新失败时对pod的诊断信息
+		return nil, errors.Newf(
 			500, "Register_Error",
 			"创建用户服务相应的运行容器时发生了错误:%v", err,
-		)
+		).WithMetadata(errors.FromError(err).Metadata)
 	}
 
 	// 为部署的服务向网关创建外部的路由
-	err = u.gateway.CreateDcServiceRoute(username, dcService)
+	// 网关经由集群可访问的地址访问服务
+	err = u.gateway.CreateDcServiceRoute(username, dcService, cluster.serviceHost(dcService.Name))
 	if err != nil {
-		return "", errors.Newf(
+		return nil, errors.Newf(
 			500, "Register_Error",
 			"创建用户服务相应的路由时发生了错误:%v", err,
 		)
 	}
-	err = u.gateway.CreateDpServiceRoute(username, dpService)
+	err = u.gateway.CreateDpServiceRoute(username, dpService, cluster.serviceHost(dpService.Name))
 	if err != nil {
-		return "", errors.Newf(
+		return nil, errors.Newf(
 			500, "Register_Error",
 			"创建用户服务相应的路由时发生了错误:%v", err,
 		)
 	}
-
-	// 最后往数据库中保存用户信息，避免出现服务还未初始化用户就可以登录网页
-	marshal, err := proto.Marshal(request)
+	// 用户即组织的owner，只允许组织acl分组中的consumer访问组织的服务
+	err = u.gateway.EnableOrgACL(username)
 	if err != nil {
-		return "", errors.Newf(
+		return nil, errors.Newf(
 			500, "Register_Error",
-			"对用户注册信息进行protobuf序列化时发生了错误:%v", err,
+			"为用户服务启用acl插件时发生了错误:%v", err,
 		)
 	}
-	err = u.repo.Register(username, request.User.Password, token, marshal)
+
+	return cluster, nil
+}
+
+// 保存修改后的用户注册信息，并使下采样task与其中的预警规则保持一致。
+// 设备状态注册信息仅在注册(以及由备份恢复)时经由provision写入，目前没有修改它的接口，
+// 之后修改注册信息的路径均应经由此方法保存，使下采样task随预警规则同步
+func (u *UserUsecase) updateRegisterInfo(username string, info *v1.RegisterRequest) error {
+	marshal, err := proto.Marshal(info)
 	if err != nil {
-		return "", err
+		return errors.Newf(
+			500, "Update_RegisterInfo_Error",
+			"对用户注册信息进行protobuf序列化时发生了错误:%v", err)
+	}
+	err = u.repo.UpdateRegisterInfo(username, marshal)
+	if err != nil {
+		return err
 	}
 
-	return
+	backend, err := u.timeSeriesOf(username)
+	if err != nil {
+		return err
+	}
+	if syncer, ok := backend.(downsampleSyncer); ok {
+		err = syncer.SyncDownsampleTasks(username, info.DeviceStateRegisterInfos)
+		if err != nil {
+			return errors.Newf(
+				500, "Update_RegisterInfo_Error",
+				"同步用户预警规则对应的下采样task时发生了错误:%v", err)
+		}
+	}
+	return nil
 }
 
 // GetUserRegisterInfo 获得用户注册信息，并解码到给定的proto message中
 func (u *UserUsecase) GetUserRegisterInfo(token string, message proto.Message) error {
 	// 由token信息先获得用户id，再利用用户id查询用户注册信息
-	username, err := u.gateway.GetUsernameOfToken(token)
+	username, err := u.tenantOfToken(token)
 	if err != nil {
 		return err
 	}
@@ -208,40 +456,399 @@ func (u *UserUsecase) GetUserRegisterInfo(token string, message proto.Message) e
 	return nil
 }
 
-// Unregister 注销用户，清理用户相关的资源，包括网关组件、k8s资源以及数据库的记录
-func (u *UserUsecase) Unregister(username, password string) error {
+// Unregister 注销用户，暂停用户的服务与路由，并在宽限期后由后台任务清理用户相关的资源，
+// 返回清理的时间，用户启用了二次验证时，code需要为有效的验证码或恢复码
+func (u *UserUsecase) Unregister(username, password, code string) (purgeTime time.Time, err error) {
 	u.logger.Infof("接收到了用户 %v 的注销请求", username)
 
-	// 确认密码是否正确，确保是用户本人操作的注销
+	// 确认密码是否正确，确保是用户本人操作的注销，
+	// 组织的成员不保存在用户的账号中，因此只有组织的owner可以注销
+	err = u.verifyOwner(username, password, code, "UnRegister_Error")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	purgeTime, err = u.deletions.GetDeletion(username)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !purgeTime.IsZero() {
+		return time.Time{}, errors.Conflict(
+			"UnRegister_Error", fmt.Sprintf("账号已注销，将在 %v 被清理", purgeTime.Format(time.RFC3339)))
+	}
+
+	cluster, err := u.clusters.clusterOf(username)
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = u.suspend(username, cluster)
+	if err != nil {
+		// 暂停失败时恢复已暂停的部分，使账号保持可用
+		u.resume(username, cluster)
+		return time.Time{}, errors.Newf(
+			500, "UnRegister_Error",
+			"暂停用户的服务与路由时发生了错误:%v", err)
+	}
+
+	purgeTime = time.Now().Add(u.gracePeriod)
+	err = u.deletions.ScheduleDeletion(username, purgeTime)
+	if err != nil {
+		u.resume(username, cluster)
+		return time.Time{}, err
+	}
+
+	u.events.Publish(EventTenantSuspended, username, tenantResources(username, cluster),
+		map[string]string{"purge_time": purgeTime.UTC().Format(time.RFC3339)})
+	u.logger.Infof("用户 %v 已注销，将在 %v 清理用户的资源", username, purgeTime.Format(time.RFC3339))
+	return purgeTime, nil
+}
+
+// Restore 在宽限期内恢复已注销的账号，恢复用户的服务与路由，
+// 用户启用了二次验证时，code需要为有效的验证码或恢复码
+func (u *UserUsecase) Restore(username, password, code string) error {
+	err := u.verifyOwner(username, password, code, "Restore_Error")
+	if err != nil {
+		return err
+	}
+
+	notPending := errors.BadRequest("Restore_Error", "账号未注销或已开始清理")
+	purgeTime, err := u.deletions.GetDeletion(username)
+	if err != nil {
+		return err
+	}
+	if purgeTime.IsZero() {
+		return notPending
+	}
+	// 取消清理计划，避免恢复的同时后台任务开始清理
+	canceled, err := u.deletions.CancelDeletion(username)
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return notPending
+	}
+
+	cluster, err := u.clusters.clusterOf(username)
+	if err == nil {
+		err = u.resume(username, cluster)
+	}
+	if err != nil {
+		// 恢复失败时保留原有的清理计划，用户可以再次尝试恢复
+		if e := u.deletions.ScheduleDeletion(username, purgeTime); e != nil {
+			u.logger.Errorf("还原用户 %v 的清理计划时发生了错误:%v", username, e)
+		}
+		return errors.Newf(
+			500, "Restore_Error",
+			"恢复用户的服务与路由时发生了错误:%v", err)
+	}
+
+	u.events.Publish(EventTenantRestored, username, tenantResources(username, cluster), nil)
+	u.logger.Infof("恢复了已注销的用户 %v", username)
+	return nil
+}
+
+// GetStatus 获得用户服务的运行状态，包括数据收集和数据处理服务的副本与编译情况、
+// 最近一次部署或升级的结果、网关中的路由与认证插件以及influxdb bucket的状态
+func (u *UserUsecase) GetStatus(token string) (*v1.GetStatusReply, error) {
+	username, err := u.tenantOfToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := u.clusters.clusterOf(username)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		services []kubecontroller.ServiceStatus
+		objects  []gateway.ObjectStatus
+		buckets  []influxdb.BucketStatus
+		outcome  []byte
+	)
+	eg := &errgroup.Group{}
+	eg.Go(func() (err error) {
+		services, err = cluster.GetServiceStatus(username)
+		return
+	})
+	eg.Go(func() (err error) {
+		objects, err = u.gateway.GetStatus(username)
+		return
+	})
+	eg.Go(func() (err error) {
+		backend, err := u.timeSeriesOf(username)
+		if err != nil {
+			return err
+		}
+		if reader, ok := backend.(bucketStatusReader); ok {
+			buckets, err = reader.GetBucketStatus(username)
+		}
+		return
+	})
+	eg.Go(func() (err error) {
+		outcome, err = u.repo.GetRolloutOutcome(username)
+		return
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, errors.Newf(
+			500, "Get_Status_Error",
+			"查询用户服务的运行状态时发生了错误:%v", err)
+	}
+
+	reply := &v1.GetStatusReply{
+		Services:       make([]*v1.ServiceStatus, 0, len(services)),
+		GatewayObjects: make([]*v1.GatewayObjectStatus, 0, len(objects)),
+		Buckets:        make([]*v1.BucketStatus, 0, len(buckets)),
+	}
+	for _, s := range services {
+		compile := &v1.CompileOutcome{State: s.Compile.State, Message: s.Compile.Message}
+		if !s.Compile.FinishTime.IsZero() {
+			compile.FinishTime = timestamppb.New(s.Compile.FinishTime)
+		}
+		reply.Services = append(reply.Services, &v1.ServiceStatus{
+			Name:            s.Name,
+			Exists:          s.Exists,
+			DesiredReplicas: s.DesiredReplicas,
+			ReadyReplicas:   s.ReadyReplicas,
+			RestartCount:    s.RestartCount,
+			Compile:         compile,
+		})
+	}
+	for _, o := range objects {
+		reply.GatewayObjects = append(reply.GatewayObjects, &v1.GatewayObjectStatus{
+			Kind:    o.Kind,
+			Name:    o.Name,
+			Present: o.Present,
+		})
+	}
+	for _, b := range buckets {
+		reply.Buckets = append(reply.Buckets, &v1.BucketStatus{
+			Name:             b.Name,
+			Exists:           b.Exists,
+			RetentionSeconds: b.RetentionSeconds,
+			SizeBytes:        b.SizeBytes,
+		})
+	}
+	if outcome != nil {
+		reply.LastRollout = new(v1.RolloutOutcome)
+		err = proto.Unmarshal(outcome, reply.LastRollout)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Get_Status_Error",
+				"对用户服务的部署结果进行protobuf解码时发生了错误:%v", err)
+		}
+	}
+
+	return reply, nil
+}
+
+// StreamLogs 获得token对应用户服务的日志流，只允许获取用户自身服务的日志
+func (u *UserUsecase) StreamLogs(
+	ctx context.Context, token string, option *kubecontroller.LogStreamOption) (<-chan kubecontroller.LogLine, error) {
+	if option == nil {
+		return nil, errors.BadRequest("Stream_Logs_Error", "option is nil")
+	}
+	if option.Service != "" && option.Service != "dc" && option.Service != "dp" {
+		return nil, errors.BadRequest("Stream_Logs_Error", "服务类型只能为dc或dp")
+	}
+	if option.Container != "" && option.Container != "app" && option.Container != "build" {
+		return nil, errors.BadRequest("Stream_Logs_Error", "容器类型只能为app或build")
+	}
+
+	username, err := u.tenantOfToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := u.clusters.clusterOf(username)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := cluster.StreamLogs(ctx, username, option)
+	if err != nil {
+		if e := errors.FromError(err); e.Code != 500 {
+			return nil, e
+		}
+		return nil, errors.Newf(
+			500, "Stream_Logs_Error",
+			"获取用户服务的日志时发生了错误:%v", err)
+	}
+
+	return lines, nil
+}
+
+func (u *UserUsecase) GetClientCode(username string) ([]byte, error) {
+	return u.repo.GetClientCode(username)
+}
+
+// 获得租户占用的系统资源，作为生命周期事件的数据
+func tenantResources(username string, c *cluster) *TenantResources {
+	return &TenantResources{
+		Cluster:         c.name,
+		Workloads:       []string{username + "-dc", username + "-dp"},
+		GatewayServices: gateway.ServiceNames(username),
+		Buckets:         influxdb.BucketNames(username),
+	}
+}
+
+// 保存用户服务最近一次部署或升级的结果，err为nil时视为成功，保存失败时只记录日志
+func saveRolloutOutcome(repo UserRepo, logger *log.Helper, username, operation string, err error) {
+	outcome := &v1.RolloutOutcome{
+		Operation: operation,
+		Success:   err == nil,
+		Time:      timestamppb.Now(),
+	}
+	if err != nil {
+		outcome.Message = err.Error()
+	}
+
+	marshal, e := proto.Marshal(outcome)
+	if e == nil {
+		e = repo.SaveRolloutOutcome(username, marshal)
+	}
+	if e != nil {
+		logger.Errorf("保存用户 %v 服务的部署结果时发生了错误:%v", username, e)
+	}
+}
+
+// 确认账号密码以及二次验证的验证码，确保是用户本人的操作
+func (u *UserUsecase) verifyOwner(username, password, code, reason string) error {
 	_, err := u.repo.Login(username, password)
 	if err != nil {
-		return errors.Forbidden(
-			"UnRegister_Error", "账号或密码错误，无法执行注销操作")
+		return errors.Forbidden(reason, "账号或密码错误，无法执行该操作")
+	}
+	return u.twoFactor.Check(username, code)
+}
+
+// 获得token对应的租户，token属于组织的成员时返回所属的组织，租户已注销并等待清理时返回错误
+func (u *UserUsecase) tenantOfToken(token string) (string, error) {
+	username, err := u.gateway.GetUsernameOfToken(token)
+	if err != nil {
+		return "", err
+	}
+	if err := u.checkPending(username); err != nil {
+		return "", err
+	}
+	return username, nil
+}
+
+// 用户已注销并等待清理时返回错误
+func (u *UserUsecase) checkPending(username string) error {
+	purgeTime, err := u.deletions.GetDeletion(username)
+	if err != nil {
+		return err
+	}
+	if !purgeTime.IsZero() {
+		return errors.Forbidden("Unregister_Pending", fmt.Sprintf(
+			"账号已注销，将在 %v 被清理，在此之前可以恢复账号", purgeTime.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// 暂停用户的服务与路由
+func (u *UserUsecase) suspend(username string, cluster *cluster) error {
+	err := u.gateway.SuspendRoutes(username)
+	if err != nil {
+		return err
 	}
+	return cluster.SuspendServices(username)
+}
+
+// 恢复用户的服务与路由
+func (u *UserUsecase) resume(username string, cluster *cluster) error {
+	err := cluster.ResumeServices(username)
+	if err != nil {
+		return err
+	}
+	return u.gateway.ResumeRoutes(username)
+}
+
+// 清理已注销用户的所有资源，并通知用户订阅的webhook
+func (u *UserUsecase) purge(username string) error {
+	// 事件中需要携带被清理的资源，因此在清理前查询用户服务所在的集群，
+	// 用户订阅的webhook也会随用户信息一同删除，同样需要提前查询
+	cluster, err := u.clusters.clusterOf(username)
+	if err != nil {
+		return err
+	}
+	webhooks := u.webhooks.Subscriptions(username)
 
 	err = u.clear(username)
 	if err != nil {
-		return errors.Newf(
+		err = errors.Newf(
 			500,
 			"UnRegister_Error",
 			"为用户清理使用的系统资源时发生了错误:%v", err)
+		u.webhooks.Notify(WebhookUnregisterFailed, username, webhooks, err)
+		return err
 	}
 
-	u.logger.Infof("完成了用户 %v 的注销请求", username)
+	u.webhooks.Notify(WebhookUnregisterSucceeded, username, webhooks, nil)
+	u.events.Publish(EventTenantUnregistered, username, tenantResources(username, cluster), nil)
+	u.logger.Infof("清理了已注销用户 %v 的资源", username)
 	return nil
 }
 
-func (u *UserUsecase) GetClientCode(username string) ([]byte, error) {
-	return u.repo.GetClientCode(username)
-}
+// 清理用户相关的资源，先清理网关、时序数据库以及k8s中的资源，全部成功后再删除数据库中的记录，
+// 使清理失败时可以依据保留的记录重试
+func (u *UserUsecase) clear(username string) error {
+	// 某一步骤失败时继续清理其余的资源，最后汇总所有的错误
+	var failures []string
+	fail := func(step string, err error) {
+		failures = append(failures, fmt.Sprintf("%s:%v", step, err))
+	}
 
-// 清理用户相关的资源
-func (u *UserUsecase) clear(username string) (err error) {
-	// TODO 错误处理
-	err = u.repo.UnRegister(username)
-	err = u.gateway.Unregister(username)
-	err = u.influxdbClient.ClearBucket(username)
-	err = u.controller.Unregister(username)
+	// 用户服务所在集群的记录会随用户信息一同删除，因此需要提前查询
+	cluster, err := u.clusters.clusterOf(username)
+	if err != nil {
+		fail("查询用户服务所在的集群", err)
+	}
+
+	if err := u.gateway.Unregister(username); err != nil {
+		fail("清理网关", err)
+	}
 
-	return err
+	// 用户自带的influxdb由用户自行维护，其中的数据只尽力清理，无法连接或清理失败时只记录日志，
+	// 避免用户的influxdb不可用时无法清理k8s与网关中的资源
+	shared := true
+	if u.external != nil {
+		client, err := u.external.client(username)
+		if err != nil || client != nil {
+			shared = false
+			if err == nil {
+				err = clearTimeSeries(client, username)
+			}
+			if err != nil {
+				u.logger.Warnf("清理用户 %v 自带的influxdb时发生了错误，已跳过:%v", username, err)
+			}
+			if err := u.external.remove(username); err != nil {
+				fail("删除自带的influxdb的连接信息", err)
+			}
+		}
+	}
+	if shared {
+		if err := clearTimeSeries(u.timeSeries, username); err != nil {
+			fail("清理时序数据库", err)
+		}
+	}
+	if cluster != nil {
+		if err := cluster.Unregister(username); err != nil {
+			fail("清理k8s资源", err)
+		}
+	}
+
+	if len(failures) != 0 {
+		return errors.Newf(500, "Clear_Error", "清理用户 %s 的资源时发生了错误: %s",
+			username, strings.Join(failures, "; "))
+	}
+	return u.repo.UnRegister(username)
+}
+
+// 吊销用户在时序数据库中的所有凭证，并删除用户的存储
+func clearTimeSeries(backend TimeSeriesBackend, username string) error {
+	if err := backend.RevokeCredentials(username, ""); err != nil {
+		return err
+	}
+	return backend.ClearStorage(username)
 }
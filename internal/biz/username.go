@@ -0,0 +1,22 @@
+package biz
+
+import "github.com/go-kratos/kratos/v2/errors"
+
+// 不允许作为用户名的名称，即服务全局的key使用或曾经使用的名称与前缀。
+// 用户的key以"<用户名>:"为前缀，清理与备份用户时以该前缀匹配，用户名与全局的key的前缀相同时会匹配到其他用户的数据，
+// 全局的key已移入用户名无法匹配的命名空间，此处作为额外的保护，data中新增全局的key时需要同时补充
+var reservedUsernames = map[string]bool{
+	"audit_log": true, "blob_chunks": true, "blobs": true, "client_code": true, "event_outbox": true,
+	"export_job": true, "export_jobs": true, "invitation": true, "members": true, "oidc_state": true,
+	"oidc_ticket": true, "oidc_users": true, "org_acl": true, "org_members": true, "passwords": true,
+	"placements": true, "rollouts": true, "tokens": true, "two_factor": true, "upgrades": true,
+	"usage": true, "usage_users": true, "vm_accounts": true, "webhooks": true,
+}
+
+// 校验用户名不是保留的名称，用于注册、接受邀请等创建账号的操作
+func checkReservedUsername(username string) error {
+	if reservedUsernames[username] {
+		return errors.BadRequest("Register_Error", "用户名 "+username+" 为保留的名称，请使用其他用户名")
+	}
+	return nil
+}
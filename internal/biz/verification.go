@@ -0,0 +1,281 @@
+package biz
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VerificationUsecase 在为用户创建服务前验证用户的邮箱，注册请求先保存为等待验证的账号，
+// 用户点击验证邮件中带签名的链接后才开始创建网关、influxdb以及k8s中的资源
+type VerificationUsecase struct {
+	uc      *UserUsecase
+	repo    VerificationRepo
+	mail    MailSender
+	enabled bool
+	// 等待验证的账号的有效期
+	expiration time.Duration
+	secret     []byte
+	// 加密等待验证的账号中的注册信息使用的AES-GCM，注册信息中包含密码、webhook的密钥等敏感信息
+	aead        cipher.AEAD
+	linkBaseUrl string
+	logger      *log.Helper
+}
+type VerificationRepo interface {
+	// SavePendingAccount 保存等待验证的账号，账号在ttl后自动删除，
+	// 用户已注册或已存在等待验证的账号时返回错误
+	SavePendingAccount(username string, account []byte, ttl time.Duration) error
+	// GetPendingAccount 获得等待验证的账号，不存在或已过期时返回nil
+	GetPendingAccount(username string) ([]byte, error)
+	// RemovePendingAccount 删除等待验证的账号，返回false时表示账号已被删除
+	RemovePendingAccount(username string) (bool, error)
+	// ClaimPendingAccount 认领等待验证的账号，认领在ttl后自动失效，返回false时表示账号已被认领
+	ClaimPendingAccount(username string, ttl time.Duration) (bool, error)
+	// ReleasePendingAccount 释放对等待验证的账号的认领
+	ReleasePendingAccount(username string) error
+}
+
+// MailSender 发送邮件的方式
+type MailSender interface {
+	Send(to, subject, body string) error
+}
+
+// 通过验证后为账号创建服务的最长时间，超过该时间仍未完成时，允许再次使用验证链接
+const verificationClaimTimeout = 15 * time.Minute
+
+// 等待验证的账号
+type pendingAccount struct {
+	// 随机生成的验证码，使账号过期后重新注册时，旧的验证链接失效
+	Nonce string `json:"nonce"`
+	// 加密后的注册信息
+	Request []byte `json:"request"`
+}
+
+// 验证链接中携带的信息
+type verificationClaims struct {
+	Username string `json:"u"`
+	Nonce    string `json:"n"`
+	Expire   int64  `json:"e"`
+}
+
+func NewVerificationUsecase(server *conf.Server, uc *UserUsecase,
+	repo VerificationRepo, mail MailSender, logger log.Logger) (*VerificationUsecase, error) {
+	verification := &VerificationUsecase{
+		uc:         uc,
+		repo:       repo,
+		mail:       mail,
+		expiration: 24 * time.Hour,
+		logger:     log.NewHelper(logger),
+	}
+
+	c := server.Verification
+	if c == nil || !c.Enabled {
+		return verification, nil
+	}
+	if c.Secret == "" || c.LinkBaseUrl == "" {
+		return nil, errors.New(500, "Config_Error", "开启邮箱验证时需要配置签名密钥以及验证链接的地址")
+	}
+	// 以签名密钥派生加密注册信息使用的密钥，使两者互不相同
+	aead, err := newAEAD("pending_account:" + c.Secret)
+	if err != nil {
+		return nil, errors.Newf(500, "Config_Error", "初始化等待验证账号的加密密钥时发生了错误:%v", err)
+	}
+	verification.enabled = true
+	verification.secret = []byte(c.Secret)
+	verification.aead = aead
+	verification.linkBaseUrl = c.LinkBaseUrl
+	if c.Expiration != nil && c.Expiration.AsDuration() > 0 {
+		verification.expiration = c.Expiration.AsDuration()
+	}
+
+	return verification, nil
+}
+
+// Enabled 是否需要在创建服务前验证用户的邮箱
+func (v *VerificationUsecase) Enabled() bool {
+	return v.enabled
+}
+
+// Register 保存等待验证的账号，并向用户的邮箱发送验证链接
+func (v *VerificationUsecase) Register(request *v1.RegisterRequest) error {
+	if request == nil {
+		return errors.BadRequest("request is nil", "")
+	}
+	if request.Email == "" {
+		return errors.BadRequest("Register_Error", "需要填写用于验证的邮箱")
+	}
+	// 在发送验证邮件之前拒绝保留的用户名与租户无法使用的套餐
+	username := request.User.GetId()
+	if err := checkReservedUsername(username); err != nil {
+		return err
+	}
+	if err := v.uc.plans.checkRegisterPlan(request.Plan); err != nil {
+		return err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Newf(500, "Register_Error", "生成邮箱验证码时发生了错误:%v", err)
+	}
+	marshal, err := proto.Marshal(request)
+	if err == nil {
+		marshal, err = sealAEAD(v.aead, marshal)
+	}
+	if err == nil {
+		marshal, err = json.Marshal(&pendingAccount{Nonce: hex.EncodeToString(nonce), Request: marshal})
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Register_Error",
+			"对用户注册信息进行序列化时发生了错误:%v", err)
+	}
+
+	err = v.repo.SavePendingAccount(username, marshal, v.expiration)
+	if err != nil {
+		return err
+	}
+
+	expire := time.Now().Add(v.expiration)
+	token, err := v.sign(&verificationClaims{
+		Username: username,
+		Nonce:    hex.EncodeToString(nonce),
+		Expire:   expire.Unix(),
+	})
+	if err == nil {
+		err = v.mail.Send(request.Email, "请验证您的邮箱", fmt.Sprintf(
+			"您好 %s，\n\n请在 %s 前访问以下链接完成邮箱验证，验证通过后将开始为您创建服务:\n\n%s\n",
+			username, expire.Format(time.RFC3339), v.link(token)))
+	}
+	if err != nil {
+		// 验证邮件发送失败时删除等待验证的账号，使用户可以重新注册
+		v.repo.RemovePendingAccount(username)
+		return errors.Newf(
+			500, "Register_Error",
+			"发送验证邮件时发生了错误:%v", err)
+	}
+
+	v.logger.Infof("用户 %v 的账号正在等待邮箱验证", username)
+	return nil
+}
+
+// Verify 校验验证链接中的签名，通过后为用户创建服务，返回用户的token
+func (v *VerificationUsecase) Verify(token string) (string, error) {
+	if !v.enabled {
+		return "", errors.NotFound("Verify_Error", "服务未开启邮箱验证")
+	}
+
+	claims, err := v.parse(token)
+	if err != nil {
+		return "", err
+	}
+
+	marshal, err := v.repo.GetPendingAccount(claims.Username)
+	if err != nil {
+		return "", err
+	}
+	account := new(pendingAccount)
+	if marshal == nil || json.Unmarshal(marshal, account) != nil || account.Nonce != claims.Nonce {
+		return "", errors.Forbidden("Verify_Error", "验证链接无效或已过期")
+	}
+
+	// 认领等待验证的账号，保证验证链接同时只被使用一次
+	claimed, err := v.repo.ClaimPendingAccount(claims.Username, verificationClaimTimeout)
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return "", errors.Conflict("Verify_Error", "正在为账号创建服务，请勿重复验证")
+	}
+
+	request := new(v1.RegisterRequest)
+	plaintext, err := openAEAD(v.aead, account.Request)
+	if err == nil {
+		err = proto.Unmarshal(plaintext, request)
+	}
+	if err != nil {
+		v.repo.ReleasePendingAccount(claims.Username)
+		return "", errors.Newf(
+			500, "Verify_Error",
+			"对用户注册信息进行解码时发生了错误:%v", err)
+	}
+
+	// 注册成功后才删除等待验证的账号，注册失败时用户可以再次使用验证链接
+	userToken, err := v.uc.Register(request)
+	if err != nil {
+		if e := v.repo.ReleasePendingAccount(claims.Username); e != nil {
+			v.logger.Errorf("释放用户 %v 等待验证的账号时发生了错误:%v", claims.Username, e)
+		}
+		return "", err
+	}
+	if _, err := v.repo.RemovePendingAccount(claims.Username); err != nil {
+		v.logger.Errorf("删除用户 %v 等待验证的账号时发生了错误:%v", claims.Username, err)
+	}
+	return userToken, nil
+}
+
+// 生成带签名的验证token，格式为<base64编码的信息>.<base64编码的HMAC-SHA256签名>
+func (v *VerificationUsecase) sign(claims *verificationClaims) (string, error) {
+	marshal, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(marshal)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(v.mac(payload)), nil
+}
+
+// 校验验证token的签名以及有效期
+func (v *VerificationUsecase) parse(token string) (*verificationClaims, error) {
+	invalid := errors.Forbidden("Verify_Error", "验证链接无效或已过期")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, v.mac(parts[0])) {
+		return nil, invalid
+	}
+
+	marshal, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, invalid
+	}
+	claims := new(verificationClaims)
+	if err := json.Unmarshal(marshal, claims); err != nil || claims.Username == "" {
+		return nil, invalid
+	}
+	if time.Now().Unix() > claims.Expire {
+		return nil, invalid
+	}
+
+	return claims, nil
+}
+
+func (v *VerificationUsecase) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// 获得携带验证token的链接
+func (v *VerificationUsecase) link(token string) string {
+	separator := "?"
+	if strings.Contains(v.linkBaseUrl, "?") {
+		separator = "&"
+	}
+	return v.linkBaseUrl + separator + "token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,191 @@
+package biz
+
+import (
+	"errors"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的等待验证账号，不处理过期
+type memoryVerificationRepo struct {
+	accounts map[string][]byte
+	claims   map[string]bool
+}
+
+func (r *memoryVerificationRepo) SavePendingAccount(username string, account []byte, ttl time.Duration) error {
+	if _, ok := r.accounts[username]; ok {
+		return errors.New("用户账号正在等待邮箱验证")
+	}
+	r.accounts[username] = account
+	return nil
+}
+
+func (r *memoryVerificationRepo) GetPendingAccount(username string) ([]byte, error) {
+	return r.accounts[username], nil
+}
+
+func (r *memoryVerificationRepo) RemovePendingAccount(username string) (bool, error) {
+	_, ok := r.accounts[username]
+	delete(r.accounts, username)
+	delete(r.claims, username)
+	return ok, nil
+}
+
+func (r *memoryVerificationRepo) ClaimPendingAccount(username string, ttl time.Duration) (bool, error) {
+	if r.claims[username] {
+		return false, nil
+	}
+	r.claims[username] = true
+	return true, nil
+}
+
+func (r *memoryVerificationRepo) ReleasePendingAccount(username string) error {
+	delete(r.claims, username)
+	return nil
+}
+
+// 记录最后一封邮件的发送对象
+type memoryMailSender struct {
+	to, subject, body string
+}
+
+func (s *memoryMailSender) Send(to, subject, body string) error {
+	s.to, s.subject, s.body = to, subject, body
+	return nil
+}
+
+func newTestVerificationUsecase(repo VerificationRepo, mail MailSender) *VerificationUsecase {
+	aead, _ := newAEAD("pending_account:secret")
+	return &VerificationUsecase{
+		repo:        repo,
+		uc:          &UserUsecase{plans: &retentionPlans{}, logger: log.NewHelper(log.DefaultLogger)},
+		mail:        mail,
+		enabled:     true,
+		expiration:  time.Hour,
+		secret:      []byte("secret"),
+		aead:        aead,
+		linkBaseUrl: "http://localhost/users/verify",
+		logger:      log.NewHelper(log.DefaultLogger),
+	}
+}
+
+// 获得验证邮件中的验证token
+func verificationToken(t *testing.T, mail *memoryMailSender) string {
+	link := regexp.MustCompile(`http://localhost/users/verify\?token=\S+`).FindString(mail.body)
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("验证邮件中缺少验证链接:%v", mail.body)
+	}
+	return u.Query().Get("token")
+}
+
+func TestVerificationUsecase_Register(t *testing.T) {
+	repo := &memoryVerificationRepo{accounts: make(map[string][]byte), claims: make(map[string]bool)}
+	mail := new(memoryMailSender)
+	verification := newTestVerificationUsecase(repo, mail)
+
+	request := &v1.RegisterRequest{User: &utilApi.User{Id: "test", Password: "plain-password"}, Email: "test@example.com"}
+	if err := verification.Register(request); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(repo.accounts["test"]), "plain-password") {
+		t.Fatal("等待验证的账号中的注册信息应加密保存")
+	}
+	if err := verification.Register(request); err == nil {
+		t.Fatal("存在等待验证的账号时不应重复注册")
+	}
+	if mail.to != request.Email {
+		t.Fatalf("验证邮件应发送到用户的邮箱，实际发送到:%v", mail.to)
+	}
+
+	claims, err := verification.parse(verificationToken(t, mail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Username != "test" {
+		t.Fatalf("验证链接中的用户名应为test，实际为:%v", claims.Username)
+	}
+
+	// 保留的名称不能注册
+	reserved := &v1.RegisterRequest{User: &utilApi.User{Id: "usage", Password: "test"}, Email: "usage@example.com"}
+	if err := verification.Register(reserved); kerrors.Code(err) != 400 {
+		t.Fatalf("保留的名称不能注册:%v", err)
+	}
+	if _, ok := repo.accounts["usage"]; ok {
+		t.Fatal("保留的名称不应保存等待验证的账号")
+	}
+}
+
+func TestVerificationUsecase_Verify(t *testing.T) {
+	repo := &memoryVerificationRepo{accounts: make(map[string][]byte), claims: make(map[string]bool)}
+	mail := new(memoryMailSender)
+	verification := newTestVerificationUsecase(repo, mail)
+	// 默认套餐已从服务配置中移除，使注册在创建任何资源之前失败
+	verification.uc = &UserUsecase{
+		plans:  &retentionPlans{plans: make(map[string]*timeseries.RetentionPolicy), defaultPlan: "removed"},
+		logger: log.NewHelper(log.DefaultLogger),
+	}
+
+	request := &v1.RegisterRequest{User: &utilApi.User{Id: "test", Password: "test"}, Email: "test@example.com", Plan: "compliance"}
+	if err := verification.Register(request); kerrors.Code(err) != 403 {
+		t.Fatalf("租户注册时不能选择默认套餐以外的套餐:%v", err)
+	}
+	request.Plan = ""
+	if err := verification.Register(request); err != nil {
+		t.Fatal(err)
+	}
+	token := verificationToken(t, mail)
+
+	// 注册失败时保留等待验证的账号，使用户可以再次使用验证链接
+	for i := 0; i < 2; i++ {
+		if _, err := verification.Verify(token); kerrors.Reason(err) != "Retention_Error" {
+			t.Fatalf("应返回注册失败的原因:%v", err)
+		}
+		if _, ok := repo.accounts["test"]; !ok || repo.claims["test"] {
+			t.Fatal("注册失败后应保留等待验证的账号并释放认领")
+		}
+	}
+
+	// 正在为账号创建服务时不能再次使用验证链接
+	repo.claims["test"] = true
+	if _, err := verification.Verify(token); kerrors.Code(err) != 409 {
+		t.Fatalf("账号已被认领时应返回冲突:%v", err)
+	}
+}
+
+func TestVerificationUsecase_parse(t *testing.T) {
+	verification := &VerificationUsecase{secret: []byte("secret")}
+	valid, err := verification.sign(&verificationClaims{
+		Username: "test", Nonce: "nonce", Expire: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := verification.sign(&verificationClaims{
+		Username: "test", Nonce: "nonce", Expire: time.Now().Add(-time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &VerificationUsecase{secret: []byte("other")}
+	forged, err := other.sign(&verificationClaims{
+		Username: "test", Nonce: "nonce", Expire: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verification.parse(valid); err != nil {
+		t.Fatalf("有效的验证token校验失败:%v", err)
+	}
+	for name, token := range map[string]string{"过期": expired, "伪造": forged, "格式错误": "token"} {
+		if _, err := verification.parse(token); err == nil {
+			t.Errorf("%s的验证token应校验失败", name)
+		}
+	}
+}
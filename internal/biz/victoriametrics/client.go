@@ -0,0 +1,276 @@
+package victoriametrics
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type 服务配置中victoriametrics对应的时序数据库类型
+const Type = "victoriametrics"
+
+// 匹配租户所有时间序列的选择器
+const allSeries = `{__name__!=""}`
+
+// AccountStore 保存租户在victoriametrics集群中的租户id(accountID)，id为0的默认租户不分配给任何用户
+type AccountStore interface {
+	// GetAccountID 获得用户的租户id，未分配时返回0
+	GetAccountID(username string) (uint32, error)
+	// AllocateAccountID 为用户分配租户id，已分配时返回已有的id
+	AllocateAccountID(username string) (uint32, error)
+	// DeleteAccountID 删除用户的租户id
+	DeleteAccountID(username string) error
+	// ListAccountIDs 获得所有已分配的租户id，以用户名为键
+	ListAccountIDs() (map[string]uint32, error)
+	// SaveCredential 保存用户经由vmauth访问其租户数据的凭证
+	SaveCredential(username, id, token string) error
+	// ListCredentials 获得用户所有的凭证，以凭证id为键
+	ListCredentials(username string) (map[string]string, error)
+	// DeleteCredentials 删除用户的指定凭证，ids为空时删除用户所有的凭证
+	DeleteCredentials(username string, ids ...string) error
+}
+
+// Client 以victoriametrics集群的多租户能力保存用户数据，每个用户对应一个租户id，
+// 租户在首次写入数据时由victoriametrics隐式创建，因此只需要分配并记录租户id。
+// 开源版本的victoriametrics不提供鉴权，租户服务经由vmauth访问，由vmauth依据凭证将请求转发到凭证所属用户的租户id
+type Client struct {
+	// vminsert与vmselect的地址
+	insertUrl string
+	selectUrl string
+	// 租户服务访问的vmauth地址
+	authUrl  string
+	accounts AccountStore
+	client   *http.Client
+	// 记录对victoriametrics的每次修改，为nil时不记录
+	Recorder audit.Recorder
+}
+
+func NewClient(insertUrl, selectUrl, authUrl string, accounts AccountStore) (*Client, error) {
+	for _, u := range []string{insertUrl, selectUrl, authUrl} {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return nil, fmt.Errorf("无效的victoriametrics地址%q: %w", u, err)
+		}
+	}
+	return &Client{
+		insertUrl: strings.TrimSuffix(insertUrl, "/"),
+		selectUrl: strings.TrimSuffix(selectUrl, "/"),
+		authUrl:   strings.TrimSuffix(authUrl, "/"),
+		accounts:  accounts,
+		client:    &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+// Type 时序数据库的类型
+func (c *Client) Type() string {
+	return Type
+}
+
+// CreateStorage 为用户分配租户id，victoriametrics的保留时长由vmstorage的-retentionPeriod统一决定，
+// 因此忽略用户套餐的保留策略
+func (c *Client) CreateStorage(username string, policy *timeseries.RetentionPolicy) (err error) {
+	var id uint32
+	defer func() {
+		audit.Record(c.Recorder, "victoriametrics.create_tenant", username, []string{tenantTarget(id)}, err)
+	}()
+
+	id, err = c.accounts.AllocateAccountID(username)
+	if err != nil {
+		return fmt.Errorf("为用户 %s 分配victoriametrics租户id时发生了错误: %w", username, err)
+	}
+	return nil
+}
+
+// UpdateRetention victoriametrics不支持为租户单独设置保留时长
+func (c *Client) UpdateRetention(username string, policy *timeseries.RetentionPolicy) error {
+	return timeseries.ErrUnsupported
+}
+
+// CreateCredential 为用户创建经由vmauth访问其租户数据的bearer token，token保存后在vmauth下次获取鉴权配置时生效
+func (c *Client) CreateCredential(username string) (credential *timeseries.Credential, err error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		audit.Record(c.Recorder, "victoriametrics.create_credential", username,
+			[]string{credentialTarget(username, id)}, err)
+	}()
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	err = c.accounts.SaveCredential(username, id, token)
+	if err != nil {
+		return nil, fmt.Errorf("保存用户 %s 的victoriametrics凭证时发生了错误: %w", username, err)
+	}
+	return &timeseries.Credential{ID: id, Token: token}, nil
+}
+
+// RevokeCredentials 删除用户除keep以外的所有凭证，keep为空时删除全部凭证，
+// 凭证在vmauth下次获取鉴权配置后失效
+func (c *Client) RevokeCredentials(username, keep string) (err error) {
+	credentials, err := c.accounts.ListCredentials(username)
+	if err != nil {
+		return fmt.Errorf("查询用户 %s 的victoriametrics凭证时发生了错误: %w", username, err)
+	}
+	var ids, targets []string
+	for id := range credentials {
+		if id != keep {
+			ids = append(ids, id)
+			targets = append(targets, credentialTarget(username, id))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Strings(targets)
+	defer func() {
+		audit.Record(c.Recorder, "victoriametrics.revoke_credentials", username, targets, err)
+	}()
+
+	err = c.accounts.DeleteCredentials(username, ids...)
+	if err != nil {
+		return fmt.Errorf("删除用户 %s 的victoriametrics凭证时发生了错误: %w", username, err)
+	}
+	return nil
+}
+
+// AuthConfig 生成vmauth的鉴权配置，每个凭证只能写入与查询其所属用户的租户，
+// 租户服务以/insert/...与/select/...访问vmauth，由vmauth去掉路径的第一部分后转发到vminsert与vmselect中用户的租户，
+// 未携带有效凭证的请求以及删除数据等其余请求均被拒绝。配置以json格式生成，json同样是合法的yaml
+func (c *Client) AuthConfig() ([]byte, error) {
+	accounts, err := c.accounts.ListAccountIDs()
+	if err != nil {
+		return nil, fmt.Errorf("查询victoriametrics租户id时发生了错误: %w", err)
+	}
+	usernames := make([]string, 0, len(accounts))
+	for username := range accounts {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	config := authConfig{Users: []authUser{}}
+	for _, username := range usernames {
+		credentials, err := c.accounts.ListCredentials(username)
+		if err != nil {
+			return nil, fmt.Errorf("查询用户 %s 的victoriametrics凭证时发生了错误: %w", username, err)
+		}
+		ids := make([]string, 0, len(credentials))
+		for id := range credentials {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		account := strconv.FormatUint(uint64(accounts[username]), 10)
+		for _, id := range ids {
+			config.Users = append(config.Users, authUser{
+				Name:        username + "/" + id,
+				BearerToken: credentials[id],
+				URLMap: []authRoute{
+					{SrcPaths: []string{"/insert/.+"}, URLPrefix: c.insertUrl + "/insert/" + account + "/", DropPrefixParts: 1},
+					{SrcPaths: []string{"/select/.+"}, URLPrefix: c.selectUrl + "/select/" + account + "/", DropPrefixParts: 1},
+				},
+			})
+		}
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// vmauth的鉴权配置
+type authConfig struct {
+	Users []authUser `json:"users"`
+}
+type authUser struct {
+	Name        string      `json:"name"`
+	BearerToken string      `json:"bearer_token"`
+	URLMap      []authRoute `json:"url_map"`
+}
+type authRoute struct {
+	SrcPaths        []string `json:"src_paths"`
+	URLPrefix       string   `json:"url_prefix"`
+	DropPrefixParts int      `json:"drop_src_path_prefix_parts"`
+}
+
+// ClearStorage 吊销用户所有的凭证并删除用户租户下的所有时间序列，然后删除用户的租户id，
+// 用户未分配租户id时不删除时间序列
+func (c *Client) ClearStorage(username string) (err error) {
+	// 先吊销凭证，使用户服务无法继续写入
+	err = c.RevokeCredentials(username, "")
+	if err != nil {
+		return err
+	}
+	id, err := c.accounts.GetAccountID(username)
+	if err != nil {
+		return fmt.Errorf("查询用户 %s 的victoriametrics租户id时发生了错误: %w", username, err)
+	}
+	if id == 0 {
+		return nil
+	}
+	defer func() {
+		audit.Record(c.Recorder, "victoriametrics.delete_tenant", username, []string{tenantTarget(id)}, err)
+	}()
+
+	response, err := c.client.PostForm(
+		fmt.Sprintf("%s/delete/%d/prometheus/api/v1/admin/tsdb/delete_series", c.selectUrl, id),
+		url.Values{"match[]": {allSeries}})
+	if err != nil {
+		return fmt.Errorf("删除用户 %s 的时间序列时发生了错误: %w", username, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("删除用户 %s 的时间序列时发生了错误: %s %s", username, response.Status, body)
+	}
+
+	err = c.accounts.DeleteAccountID(username)
+	if err != nil {
+		return fmt.Errorf("删除用户 %s 的victoriametrics租户id时发生了错误: %w", username, err)
+	}
+	return nil
+}
+
+// ConnectionEnv 用户服务经由vmauth写入与查询其租户数据所需的地址，以环境变量名为键，
+// 写入地址支持influx行协议(/influx/write)与prometheus remote write(/prometheus/api/v1/write)，
+// 查询地址兼容prometheus的查询api，访问时以secret中注入的凭证作为bearer token
+func (c *Client) ConnectionEnv(username string) (map[string]string, error) {
+	id, err := c.accounts.GetAccountID(username)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户 %s 的victoriametrics租户id时发生了错误: %w", username, err)
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("用户 %s 未分配victoriametrics租户id", username)
+	}
+
+	return map[string]string{
+		timeseries.BackendEnv:        Type,
+		"VICTORIAMETRICS_INSERT_URL": c.authUrl + "/insert",
+		"VICTORIAMETRICS_SELECT_URL": c.authUrl + "/select/prometheus",
+	}, nil
+}
+
+func tenantTarget(id uint32) string {
+	return "tenants/" + strconv.FormatUint(uint64(id), 10)
+}
+
+func credentialTarget(username, id string) string {
+	return "credentials/" + username + "/" + id
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成随机数时发生了错误: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,187 @@
+package victoriametrics
+
+import (
+	"encoding/json"
+	"gitee.com/moyusir/service-centre/internal/biz/timeseries"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type memoryAccountStore struct {
+	ids         map[string]uint32
+	credentials map[string]map[string]string
+}
+
+func newMemoryAccountStore() *memoryAccountStore {
+	return &memoryAccountStore{ids: map[string]uint32{}, credentials: map[string]map[string]string{}}
+}
+
+func (s *memoryAccountStore) GetAccountID(username string) (uint32, error) {
+	return s.ids[username], nil
+}
+
+func (s *memoryAccountStore) AllocateAccountID(username string) (uint32, error) {
+	if id, ok := s.ids[username]; ok {
+		return id, nil
+	}
+	s.ids[username] = uint32(len(s.ids) + 1)
+	return s.ids[username], nil
+}
+
+func (s *memoryAccountStore) DeleteAccountID(username string) error {
+	delete(s.ids, username)
+	return nil
+}
+
+func (s *memoryAccountStore) ListAccountIDs() (map[string]uint32, error) {
+	ids := make(map[string]uint32, len(s.ids))
+	for username, id := range s.ids {
+		ids[username] = id
+	}
+	return ids, nil
+}
+
+func (s *memoryAccountStore) SaveCredential(username, id, token string) error {
+	if s.credentials[username] == nil {
+		s.credentials[username] = map[string]string{}
+	}
+	s.credentials[username][id] = token
+	return nil
+}
+
+func (s *memoryAccountStore) ListCredentials(username string) (map[string]string, error) {
+	credentials := make(map[string]string)
+	for id, token := range s.credentials[username] {
+		credentials[id] = token
+	}
+	return credentials, nil
+}
+
+func (s *memoryAccountStore) DeleteCredentials(username string, ids ...string) error {
+	if len(ids) == 0 {
+		delete(s.credentials, username)
+	}
+	for _, id := range ids {
+		delete(s.credentials[username], id)
+	}
+	return nil
+}
+
+func TestClient(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		deleted = append(deleted, r.URL.Path+" "+r.PostForm.Get("match[]"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	accounts := newMemoryAccountStore()
+	client, err := NewClient("http://vminsert:8480/", server.URL, "http://vmauth:8427/", accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, username := range []string{"alice", "bob", "alice"} {
+		if err := client.CreateStorage(username, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if accounts.ids["alice"] != 1 || accounts.ids["bob"] != 2 {
+		t.Fatalf("租户id分配错误:%v", accounts)
+	}
+
+	env, err := client.ConnectionEnv("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env[timeseries.BackendEnv] != Type ||
+		env["VICTORIAMETRICS_INSERT_URL"] != "http://vmauth:8427/insert" ||
+		env["VICTORIAMETRICS_SELECT_URL"] != "http://vmauth:8427/select/prometheus" {
+		t.Fatalf("连接信息错误:%v", env)
+	}
+
+	alice, err := client.CreateCredential("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := client.CreateCredential("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := client.CreateCredential("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.Token == bob.Token || old.ID == bob.ID {
+		t.Fatal("每次创建的凭证应不同")
+	}
+	if err := client.RevokeCredentials("bob", bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := accounts.credentials["bob"][old.ID]; ok || accounts.credentials["bob"][bob.ID] != bob.Token {
+		t.Fatalf("应只吊销keep以外的凭证:%v", accounts.credentials["bob"])
+	}
+
+	// 每个凭证只能访问其所属用户的租户
+	marshal, err := client.AuthConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config authConfig
+	if err := json.Unmarshal(marshal, &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Users) != 2 {
+		t.Fatalf("鉴权配置中的凭证数量错误:%s", marshal)
+	}
+	for _, user := range config.Users {
+		var account, token string
+		switch user.Name {
+		case "alice/" + alice.ID:
+			account, token = "1", alice.Token
+		case "bob/" + bob.ID:
+			account, token = "2", bob.Token
+		default:
+			t.Fatalf("鉴权配置中存在未知的凭证:%v", user.Name)
+		}
+		want := []authRoute{
+			{SrcPaths: []string{"/insert/.+"}, URLPrefix: "http://vminsert:8480/insert/" + account + "/", DropPrefixParts: 1},
+			{SrcPaths: []string{"/select/.+"}, URLPrefix: server.URL + "/select/" + account + "/", DropPrefixParts: 1},
+		}
+		if user.BearerToken != token || !reflect.DeepEqual(user.URLMap, want) {
+			t.Fatalf("凭证 %v 的配置错误:%+v", user.Name, user)
+		}
+	}
+	if err := client.UpdateRetention("bob", timeseries.DefaultRetentionPolicy()); !timeseries.IsUnsupported(err) {
+		t.Fatalf("victoriametrics不应支持修改保留策略:%v", err)
+	}
+
+	if err := client.ClearStorage("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/delete/2/prometheus/api/v1/admin/tsdb/delete_series "+allSeries {
+		t.Fatalf("删除时间序列的请求错误:%v", deleted)
+	}
+	if _, ok := accounts.ids["bob"]; ok {
+		t.Fatal("清理后应删除租户id")
+	}
+	if len(accounts.credentials["bob"]) != 0 {
+		t.Fatal("清理后应吊销用户所有的凭证")
+	}
+	// 未分配租户id的用户不需要清理
+	if err := client.ClearStorage("bob"); err != nil || len(deleted) != 1 {
+		t.Fatalf("重复清理时不应发起请求:%v %v", err, deleted)
+	}
+	if _, err := client.ConnectionEnv("bob"); err == nil {
+		t.Fatal("未分配租户id时应返回错误")
+	}
+}
@@ -0,0 +1,588 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// webhook回调的事件
+const (
+	WebhookRegisterSucceeded   = "register.succeeded"
+	WebhookRegisterFailed      = "register.failed"
+	WebhookUnregisterSucceeded = "unregister.succeeded"
+	WebhookUnregisterFailed    = "unregister.failed"
+	WebhookUpgradeSucceeded    = "upgrade.succeeded"
+	WebhookUpgradeFailed       = "upgrade.failed"
+	WebhookUpgradeRolledBack   = "upgrade.rolled_back"
+)
+
+const (
+	// 回调请求中携带投递id、时间戳以及签名的请求头
+	webhookIdHeader        = "X-Webhook-Id"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+	// 检查是否存在到期投递的间隔
+	webhookPollInterval = 5 * time.Second
+	// 单次从队列中取出的到期投递数量
+	webhookBatch = 100
+	// 查询投递记录时默认以及最多返回的记录数量
+	defaultWebhookDeliveries = 100
+	maxWebhookDeliveries     = 1000
+)
+
+// WebhookUsecase 在注册、注销以及服务升级完成时向订阅的webhook发送带签名的回调，
+// 投递失败时以指数退避的方式重试，并保存每次投递的记录
+type WebhookUsecase struct {
+	repo WebhookRepo
+	// 服务配置中的全局订阅，所有用户的操作结果都会回调
+	global []*v1.Webhook
+	// 加密用户订阅的webhook的签名密钥使用的AES-GCM，未配置加密密钥时为nil，此时用户无法订阅webhook
+	aead cipher.AEAD
+	// 限制用户订阅的webhook的地址，防止用户借由回调访问集群内部的服务
+	guard *outboundGuard
+	// 回调用户订阅的webhook使用的客户端只能访问外部地址，全局webhook由服务配置指定，不受此限制
+	client       *http.Client
+	globalClient *http.Client
+	// 投递的租约时长，取出投递后在租约时长内未记录结果时，视为投递的实例已退出，由其余实例重新投递
+	lease          time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// 有新的投递时唤醒后台任务，使回调尽快发出
+	wake   chan struct{}
+	logger *log.Helper
+}
+type WebhookRepo interface {
+	// SaveWebhooks 保存用户注册时订阅的webhook
+	SaveWebhooks(username string, webhooks []byte) error
+	// GetWebhooks 获得用户订阅的webhook，不存在时返回nil
+	GetWebhooks(username string) ([]byte, error)
+	// SaveDelivery 保存投递记录，createTime用于按创建时间排序投递记录
+	SaveDelivery(id string, createTime time.Time, delivery []byte) error
+	// GetDelivery 获得投递记录，不存在时返回nil
+	GetDelivery(id string) ([]byte, error)
+	// ListDeliveries 按创建时间倒序获得从offset开始的至多count条投递记录
+	ListDeliveries(offset, count int64) ([][]byte, error)
+	// ScheduleDelivery 将投递加入待投递队列，在at之后投递
+	ScheduleDelivery(id string, at time.Time) error
+	// DueDeliveries 获得待投递队列中至多limit个到期的投递的id
+	DueDeliveries(now time.Time, limit int64) ([]string, error)
+	// LeaseDelivery 投递仍到期时，将其下一次投递时间推迟到until，作为取出投递的租约，
+	// 返回false时表示投递已被其他实例取走
+	LeaseDelivery(id string, now, until time.Time) (bool, error)
+	// CompleteDelivery 将投递从待投递队列中移除
+	CompleteDelivery(id string) error
+}
+
+// 保存在数据库中的投递记录，签名使用的密钥不通过接口返回
+type webhookRecord struct {
+	Delivery []byte `json:"delivery"`
+	// 旧版本以明文保存的签名密钥，只用于读取已有的记录
+	Secret string `json:"secret,omitempty"`
+	// 加密后的签名密钥
+	EncryptedSecret []byte `json:"encrypted_secret,omitempty"`
+	// 是否为服务配置中的全局订阅，全局订阅的密钥不保存，投递时从服务配置中读取
+	Global bool `json:"global,omitempty"`
+}
+
+// 回调请求的请求体
+type webhookPayload struct {
+	ID       string    `json:"id"`
+	Event    string    `json:"event"`
+	Username string    `json:"username"`
+	Success  bool      `json:"success"`
+	Message  string    `json:"message,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+func NewWebhookUsecase(server *conf.Server, repo WebhookRepo, logger log.Logger) (*WebhookUsecase, error) {
+	guard, err := newOutboundGuard(server.Outbound)
+	if err != nil {
+		return nil, err
+	}
+	timeout := 10 * time.Second
+	if c := server.Webhook; c != nil && c.Timeout != nil {
+		timeout = c.Timeout.AsDuration()
+	}
+
+	webhook := &WebhookUsecase{
+		repo:           repo,
+		guard:          guard,
+		client:         guard.httpClient(timeout),
+		globalClient:   &http.Client{Timeout: timeout},
+		lease:          2*timeout + webhookPollInterval,
+		maxAttempts:    8,
+		initialBackoff: 10 * time.Second,
+		maxBackoff:     time.Hour,
+		wake:           make(chan struct{}, 1),
+		logger:         log.NewHelper(logger),
+	}
+	if c := server.Webhook; c != nil {
+		for _, s := range c.Subscriptions {
+			webhook.global = append(webhook.global, &v1.Webhook{Url: s.Url, Secret: s.Secret})
+		}
+		if c.MaxAttempts > 0 {
+			webhook.maxAttempts = int(c.MaxAttempts)
+		}
+		if c.InitialBackoff != nil {
+			webhook.initialBackoff = c.InitialBackoff.AsDuration()
+		}
+		if c.MaxBackoff != nil {
+			webhook.maxBackoff = c.MaxBackoff.AsDuration()
+		}
+		if c.EncryptionKey != "" {
+			aead, err := newAEAD(c.EncryptionKey)
+			if err != nil {
+				return nil, errors.Newf(500, "Config_Error", "初始化webhook的加密密钥时发生了错误:%v", err)
+			}
+			webhook.aead = aead
+		}
+	}
+
+	return webhook, nil
+}
+
+// ValidateSubscriptions 校验用户订阅的webhook，在创建任何资源之前拒绝无法安全保存或指向内部地址的webhook
+func (w *WebhookUsecase) ValidateSubscriptions(webhooks []*v1.Webhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	if w.aead == nil {
+		return errors.BadRequest("Webhook_Error", "服务未配置webhook的加密密钥，不允许订阅webhook")
+	}
+	for _, webhook := range webhooks {
+		if err := w.guard.checkURL(webhook.Url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveSubscriptions 加密后保存用户订阅的webhook
+func (w *WebhookUsecase) SaveSubscriptions(username string, webhooks []*v1.Webhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	marshal, err := json.Marshal(webhooks)
+	if err != nil {
+		return errors.Newf(
+			500, "Webhook_Error",
+			"对用户订阅的webhook进行json序列化时发生了错误:%v", err)
+	}
+	encrypted, err := w.encrypt(marshal)
+	if err != nil {
+		return err
+	}
+	return w.repo.SaveWebhooks(username, encrypted)
+}
+
+// Subscriptions 获得用户订阅的webhook，查询失败时只记录日志，不影响用户的操作
+func (w *WebhookUsecase) Subscriptions(username string) []*v1.Webhook {
+	marshal, err := w.repo.GetWebhooks(username)
+	if err != nil || marshal == nil {
+		if err != nil {
+			w.logger.Errorf("查询用户 %v 订阅的webhook时发生了错误:%v", username, err)
+		}
+		return nil
+	}
+
+	// 旧版本以明文保存订阅的webhook，无法解密时按明文解码
+	if plaintext, err := w.decrypt(marshal); err == nil {
+		marshal = plaintext
+	}
+	var webhooks []*v1.Webhook
+	if err := json.Unmarshal(marshal, &webhooks); err != nil {
+		w.logger.Errorf("对用户 %v 订阅的webhook进行json解码时发生了错误:%v", username, err)
+		return nil
+	}
+	return webhooks
+}
+
+// Notify 为用户订阅的webhook以及全局的webhook创建投递，err为nil时视为操作成功，
+// 创建失败时只记录日志，不影响用户的操作
+func (w *WebhookUsecase) Notify(event, username string, webhooks []*v1.Webhook, err error) {
+	now := time.Now().UTC()
+	payload := &webhookPayload{
+		Event:    event,
+		Username: username,
+		Success:  err == nil,
+		Time:     now,
+	}
+	if err != nil {
+		payload.Message = err.Error()
+	}
+
+	subscriptions := make([]*v1.Webhook, 0, len(webhooks)+len(w.global))
+	subscriptions = append(append(subscriptions, webhooks...), w.global...)
+
+	var created bool
+	for i, webhook := range subscriptions {
+		payload.ID = uuid.NewString()
+		marshal, e := json.Marshal(payload)
+		if e != nil {
+			w.logger.Errorf("对用户 %v 的 %v 回调进行json序列化时发生了错误:%v", username, event, e)
+			return
+		}
+
+		delivery := &v1.WebhookDelivery{
+			Id:              payload.ID,
+			Url:             webhook.Url,
+			Event:           event,
+			Username:        username,
+			State:           v1.WebhookDelivery_PENDING,
+			Payload:         string(marshal),
+			CreateTime:      timestamppb.New(now),
+			NextAttemptTime: timestamppb.New(now),
+		}
+		record := &webhookRecord{Global: i >= len(webhooks)}
+		if !record.Global {
+			record.EncryptedSecret, e = w.encrypt([]byte(webhook.Secret))
+		}
+		if e == nil {
+			e = w.saveDelivery(delivery, record)
+		}
+		if e == nil {
+			e = w.repo.ScheduleDelivery(delivery.Id, now)
+		}
+		if e != nil {
+			w.logger.Errorf("创建用户 %v 的 %v 回调时发生了错误:%v", username, event, e)
+			continue
+		}
+		created = true
+	}
+
+	if created {
+		w.notifyWorker()
+	}
+}
+
+// ListDeliveries 按创建时间倒序查询投递记录，username为空或state为nil时不进行相应的过滤
+func (w *WebhookUsecase) ListDeliveries(
+	username string, state *v1.WebhookDelivery_State, limit int64) ([]*v1.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = defaultWebhookDeliveries
+	} else if limit > maxWebhookDeliveries {
+		limit = maxWebhookDeliveries
+	}
+
+	deliveries := make([]*v1.WebhookDelivery, 0, limit)
+	for offset := int64(0); int64(len(deliveries)) < limit; offset += webhookBatch {
+		records, err := w.repo.ListDeliveries(offset, webhookBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range records {
+			delivery, _, err := unmarshalWebhookRecord(r)
+			if err != nil {
+				return nil, err
+			}
+			if username != "" && delivery.Username != username {
+				continue
+			}
+			if state != nil && delivery.State != *state {
+				continue
+			}
+			deliveries = append(deliveries, delivery)
+			if int64(len(deliveries)) == limit {
+				break
+			}
+		}
+		if len(records) < webhookBatch {
+			break
+		}
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver 重置投递的尝试次数，并重新投递
+func (w *WebhookUsecase) Redeliver(id string) (*v1.WebhookDelivery, error) {
+	delivery, record, err := w.getDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery.State = v1.WebhookDelivery_PENDING
+	delivery.Attempts = nil
+	delivery.NextAttemptTime = timestamppb.New(now)
+	err = w.saveDelivery(delivery, record)
+	if err != nil {
+		return nil, err
+	}
+	err = w.repo.ScheduleDelivery(id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	w.notifyWorker()
+	return delivery, nil
+}
+
+// Run 定期投递到期的回调，直到ctx被取消
+func (w *WebhookUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+
+		if err := w.deliverDue(ctx); err != nil {
+			w.logger.Warnf("投递到期的webhook回调时发生了错误，将在稍后重试:%v", err)
+		}
+	}
+}
+
+// 投递所有到期的回调，同一批次的回调并发投递。
+// 取出投递时只推迟其下一次投递时间作为租约，记录投递结果后才将其移出或重新加入待投递队列，
+// 使实例在投递过程中退出时，投递在租约到期后由其余实例重新投递
+func (w *WebhookUsecase) deliverDue(ctx context.Context) error {
+	for {
+		now := time.Now()
+		ids, err := w.repo.DueDeliveries(now, webhookBatch)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		wg := new(sync.WaitGroup)
+		for _, id := range ids {
+			leased, err := w.repo.LeaseDelivery(id, now, now.Add(w.lease))
+			if err != nil {
+				return err
+			}
+			if !leased {
+				continue
+			}
+
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				if err := w.attempt(ctx, id); err != nil {
+					w.logger.Errorf("投递webhook回调 %v 时发生了错误:%v", id, err)
+				}
+			}(id)
+		}
+		wg.Wait()
+
+		if len(ids) < webhookBatch || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// 对投递进行一次尝试，并依据结果保存投递记录以及安排下一次重试
+func (w *WebhookUsecase) attempt(ctx context.Context, id string) error {
+	delivery, record, err := w.getDelivery(id)
+	if errors.IsNotFound(err) {
+		// 投递记录已被淘汰
+		return w.repo.CompleteDelivery(id)
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	statusCode, err := w.send(ctx, delivery, record, now)
+	attempt := &v1.WebhookDelivery_Attempt{
+		Time:       timestamppb.New(now),
+		StatusCode: int32(statusCode),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	var next time.Time
+	switch {
+	case err == nil:
+		delivery.State = v1.WebhookDelivery_SUCCEEDED
+		delivery.NextAttemptTime = nil
+	case len(delivery.Attempts) >= w.maxAttempts:
+		delivery.State = v1.WebhookDelivery_FAILED
+		delivery.NextAttemptTime = nil
+	default:
+		next = now.Add(webhookBackoff(w.initialBackoff, w.maxBackoff, len(delivery.Attempts)))
+		delivery.NextAttemptTime = timestamppb.New(next)
+	}
+
+	if err := w.saveDelivery(delivery, record); err != nil {
+		return err
+	}
+	if !next.IsZero() {
+		return w.repo.ScheduleDelivery(id, next)
+	}
+	return w.repo.CompleteDelivery(id)
+}
+
+// 发送回调请求，响应状态码不为2xx时视为投递失败
+func (w *WebhookUsecase) send(
+	ctx context.Context, delivery *v1.WebhookDelivery, record *webhookRecord, now time.Time) (int, error) {
+	secret, err := w.secretOf(delivery, record)
+	if err != nil {
+		return 0, err
+	}
+	client := w.client
+	if record.Global {
+		client = w.globalClient
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, delivery.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhookIdHeader, delivery.Id)
+	request.Header.Set(webhookTimestampHeader, timestamp)
+	request.Header.Set(webhookSignatureHeader, signWebhook(secret, timestamp, []byte(delivery.Payload)))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("回调响应的状态码为%d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+func (w *WebhookUsecase) getDelivery(id string) (*v1.WebhookDelivery, *webhookRecord, error) {
+	record, err := w.repo.GetDelivery(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record == nil {
+		return nil, nil, errors.NotFound("Webhook_Error", fmt.Sprintf("投递记录 %v 不存在", id))
+	}
+	return unmarshalWebhookRecord(record)
+}
+
+// 保存投递记录，record中的签名密钥随投递记录一同保存
+func (w *WebhookUsecase) saveDelivery(delivery *v1.WebhookDelivery, record *webhookRecord) error {
+	marshal, err := proto.Marshal(delivery)
+	if err == nil {
+		r := *record
+		r.Delivery = marshal
+		marshal, err = json.Marshal(&r)
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Webhook_Error",
+			"对投递记录进行序列化时发生了错误:%v", err)
+	}
+	return w.repo.SaveDelivery(delivery.Id, delivery.CreateTime.AsTime(), marshal)
+}
+
+// 唤醒后台任务，后台任务已被唤醒时不阻塞
+func (w *WebhookUsecase) notifyWorker() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// 获得投递使用的签名密钥，全局订阅的密钥从服务配置中读取
+func (w *WebhookUsecase) secretOf(delivery *v1.WebhookDelivery, record *webhookRecord) (string, error) {
+	if record.Global {
+		for _, webhook := range w.global {
+			if webhook.Url == delivery.Url {
+				return webhook.Secret, nil
+			}
+		}
+		return "", fmt.Errorf("全局webhook %s 已从服务配置中移除", delivery.Url)
+	}
+	if record.EncryptedSecret == nil {
+		return record.Secret, nil
+	}
+	secret, err := w.decrypt(record.EncryptedSecret)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func (w *WebhookUsecase) encrypt(plaintext []byte) ([]byte, error) {
+	if w.aead == nil {
+		return nil, errors.New(500, "Webhook_Error", "服务未配置webhook的加密密钥")
+	}
+	ciphertext, err := sealAEAD(w.aead, plaintext)
+	if err != nil {
+		return nil, errors.Newf(500, "Webhook_Error", "加密webhook的签名密钥时发生了错误:%v", err)
+	}
+	return ciphertext, nil
+}
+
+func (w *WebhookUsecase) decrypt(ciphertext []byte) ([]byte, error) {
+	if w.aead == nil {
+		return nil, errors.New(500, "Webhook_Error", "服务未配置webhook的加密密钥")
+	}
+	plaintext, err := openAEAD(w.aead, ciphertext)
+	if err != nil {
+		return nil, errors.Newf(500, "Webhook_Error", "解密webhook的签名密钥时发生了错误:%v", err)
+	}
+	return plaintext, nil
+}
+
+func unmarshalWebhookRecord(record []byte) (*v1.WebhookDelivery, *webhookRecord, error) {
+	r := new(webhookRecord)
+	delivery := new(v1.WebhookDelivery)
+	err := json.Unmarshal(record, r)
+	if err == nil {
+		err = proto.Unmarshal(r.Delivery, delivery)
+	}
+	if err != nil {
+		return nil, nil, errors.Newf(
+			500, "Webhook_Error",
+			"对投递记录进行解码时发生了错误:%v", err)
+	}
+	return delivery, r, nil
+}
+
+// 计算回调请求的签名，即以secret为密钥对"<timestamp>.<body>"计算的HMAC-SHA256
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// 计算第attempts次尝试失败后距离下一次重试的等待时长，每次失败后翻倍，最大为max
+func webhookBackoff(initial, max time.Duration, attempts int) time.Duration {
+	backoff := initial
+	for i := 1; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
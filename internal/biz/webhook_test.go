@@ -0,0 +1,291 @@
+package biz
+
+import (
+	"context"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"github.com/go-kratos/kratos/v2/log"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 保存在内存中的webhook数据
+type memoryWebhookRepo struct {
+	mutex      sync.Mutex
+	webhooks   map[string][]byte
+	deliveries map[string][]byte
+	created    map[string]time.Time
+	queue      map[string]time.Time
+}
+
+func newMemoryWebhookRepo() *memoryWebhookRepo {
+	return &memoryWebhookRepo{
+		webhooks:   make(map[string][]byte),
+		deliveries: make(map[string][]byte),
+		created:    make(map[string]time.Time),
+		queue:      make(map[string]time.Time),
+	}
+}
+
+func (r *memoryWebhookRepo) SaveWebhooks(username string, webhooks []byte) error {
+	r.webhooks[username] = webhooks
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetWebhooks(username string) ([]byte, error) {
+	return r.webhooks[username], nil
+}
+
+func (r *memoryWebhookRepo) SaveDelivery(id string, createTime time.Time, delivery []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deliveries[id] = delivery
+	r.created[id] = createTime
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetDelivery(id string) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.deliveries[id], nil
+}
+
+func (r *memoryWebhookRepo) ListDeliveries(offset, count int64) ([][]byte, error) {
+	ids := make([]string, 0, len(r.created))
+	for id := range r.created {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return r.created[ids[i]].After(r.created[ids[j]]) })
+
+	var deliveries [][]byte
+	for i := offset; i < int64(len(ids)) && i < offset+count; i++ {
+		deliveries = append(deliveries, r.deliveries[ids[i]])
+	}
+	return deliveries, nil
+}
+
+func (r *memoryWebhookRepo) ScheduleDelivery(id string, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.queue[id] = at
+	return nil
+}
+
+func (r *memoryWebhookRepo) DueDeliveries(now time.Time, limit int64) ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var ids []string
+	for id, at := range r.queue {
+		if !at.After(now) && int64(len(ids)) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryWebhookRepo) LeaseDelivery(id string, now, until time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	at, ok := r.queue[id]
+	if !ok || at.After(now) {
+		return false, nil
+	}
+	r.queue[id] = until
+	return true, nil
+}
+
+func (r *memoryWebhookRepo) CompleteDelivery(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.queue, id)
+	return nil
+}
+
+func newTestWebhookUsecase(repo WebhookRepo) *WebhookUsecase {
+	aead, _ := newAEAD("test")
+	return &WebhookUsecase{
+		repo:           repo,
+		aead:           aead,
+		guard:          new(outboundGuard),
+		client:         &http.Client{Timeout: time.Second},
+		globalClient:   &http.Client{Timeout: time.Second},
+		lease:          time.Minute,
+		maxAttempts:    2,
+		initialBackoff: time.Hour,
+		maxBackoff:     time.Hour,
+		wake:           make(chan struct{}, 1),
+		logger:         log.NewHelper(log.DefaultLogger),
+	}
+}
+
+func TestWebhookUsecase_deliverDue(t *testing.T) {
+	var (
+		mutex      sync.Mutex
+		signatures []string
+		statusCode = http.StatusInternalServerError
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		expected := signWebhook("0123456789abcdef", r.Header.Get(webhookTimestampHeader), body)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		signatures = append(signatures, r.Header.Get(webhookSignatureHeader))
+		if r.Header.Get(webhookSignatureHeader) != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	repo := newMemoryWebhookRepo()
+	webhooks := newTestWebhookUsecase(repo)
+	webhooks.global = []*v1.Webhook{{Url: server.URL, Secret: "0123456789abcdef"}}
+	webhooks.Notify(WebhookRegisterSucceeded, "test", nil, nil)
+
+	// 首次投递失败后，需要等待退避时长后重试
+	if err := webhooks.deliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, err := webhooks.ListDeliveries("test", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || len(signatures) != 1 {
+		t.Fatalf("应创建并投递一次回调，投递记录:%v，请求次数:%v", len(deliveries), len(signatures))
+	}
+	delivery := deliveries[0]
+	if delivery.State != v1.WebhookDelivery_PENDING || delivery.Attempts[0].StatusCode != 500 {
+		t.Fatalf("投递失败后应等待重试:%v", delivery)
+	}
+	if delay := delivery.NextAttemptTime.AsTime().Sub(delivery.Attempts[0].Time.AsTime()); delay != time.Hour {
+		t.Fatalf("重试的等待时长应为初始退避时长，实际为:%v", delay)
+	}
+	if err := webhooks.deliverDue(context.Background()); err != nil || len(signatures) != 1 {
+		t.Fatal("未到重试时间时不应重新投递")
+	}
+
+	// 手动重新投递时重置尝试次数
+	statusCode = http.StatusNoContent
+	if _, err := webhooks.Redeliver(delivery.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := webhooks.deliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	succeeded := v1.WebhookDelivery_SUCCEEDED
+	deliveries, err = webhooks.ListDeliveries("", &succeeded, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || len(deliveries[0].Attempts) != 1 || deliveries[0].NextAttemptTime != nil {
+		t.Fatalf("重新投递成功后投递记录应只包含一次尝试:%v", deliveries)
+	}
+}
+
+func TestWebhookUsecase_lease(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	repo := newMemoryWebhookRepo()
+	webhooks := newTestWebhookUsecase(repo)
+	webhooks.global = []*v1.Webhook{{Url: server.URL, Secret: "secret"}}
+	webhooks.Notify(WebhookRegisterSucceeded, "test", nil, nil)
+
+	// 取出投递的实例在记录结果前退出，租约到期前投递不会被其余实例取出
+	now := time.Now()
+	ids, _ := repo.DueDeliveries(now, webhookBatch)
+	if leased, err := repo.LeaseDelivery(ids[0], now, now.Add(webhooks.lease)); err != nil || !leased {
+		t.Fatalf("应取得投递的租约:%v", err)
+	}
+	if err := webhooks.deliverDue(context.Background()); err != nil || requests != 0 {
+		t.Fatal("租约到期前不应重复投递")
+	}
+
+	// 租约到期后重新投递，成功后移出待投递队列
+	repo.queue[ids[0]] = now
+	if err := webhooks.deliverDue(context.Background()); err != nil || requests != 1 {
+		t.Fatalf("租约到期后应重新投递:%v", err)
+	}
+	if len(repo.queue) != 0 {
+		t.Fatal("投递成功后应移出待投递队列")
+	}
+}
+
+func TestWebhookUsecase_subscriptions(t *testing.T) {
+	repo := newMemoryWebhookRepo()
+	webhooks := newTestWebhookUsecase(repo)
+	subscriptions := []*v1.Webhook{{Url: "https://8.8.8.8/hook", Secret: "tenant-secret"}}
+
+	if err := webhooks.ValidateSubscriptions([]*v1.Webhook{{Url: "http://127.0.0.1:8080"}}); err == nil {
+		t.Fatal("不应允许订阅内部地址")
+	}
+	if err := webhooks.ValidateSubscriptions(subscriptions); err != nil {
+		t.Fatal(err)
+	}
+	disabled := newTestWebhookUsecase(repo)
+	disabled.aead = nil
+	if err := disabled.ValidateSubscriptions(subscriptions); err == nil {
+		t.Fatal("未配置加密密钥时不应允许订阅webhook")
+	}
+
+	// 订阅与投递记录中的签名密钥都应加密保存
+	if err := webhooks.SaveSubscriptions("test", subscriptions); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(repo.webhooks["test"]), "tenant-secret") {
+		t.Fatal("订阅的webhook中的密钥应加密保存")
+	}
+	saved := webhooks.Subscriptions("test")
+	if len(saved) != 1 || saved[0].Secret != "tenant-secret" {
+		t.Fatalf("应能解密订阅的webhook:%v", saved)
+	}
+	webhooks.Notify(WebhookRegisterSucceeded, "test", saved, nil)
+	for id, record := range repo.deliveries {
+		if strings.Contains(string(record), "tenant-secret") {
+			t.Fatal("投递记录中的密钥应加密保存")
+		}
+		delivery, r, err := webhooks.getDelivery(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret, err := webhooks.secretOf(delivery, r); err != nil || secret != "tenant-secret" {
+			t.Fatalf("应能解密投递使用的密钥:%v %v", secret, err)
+		}
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{20, time.Minute * 2},
+	}
+	for _, c := range cases {
+		if b := webhookBackoff(10*time.Second, 2*time.Minute, c.attempts); b != c.expected {
+			t.Errorf("第%v次尝试后的退避时长应为%v，实际为%v", c.attempts, c.expected, b)
+		}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// 以openssl计算的签名作为对照:
+	// echo -n '1650000000.{}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=7934fbfb5bc734764f8a72b6bceaaaca0c87c61c75dbe313c4f11455570d3d40"
+	if s := signWebhook("secret", "1650000000", []byte("{}")); s != expected {
+		t.Fatalf("签名应为%v，实际为%v", expected, s)
+	}
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// AUDIT_STREAM_KEY 审计记录stream的key，记录以entry为字段保存json，stream的id即记录的写入时间，
+// 追加记录时以MINID近似删除超出保留时长的记录，需要redis 6.2及以上的版本
+const AUDIT_STREAM_KEY = "audit_log"
+
+// NewAuditRepo 实例化保存审计记录的redis数据库操作对象
+func NewAuditRepo(data *Data) biz.AuditRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// AppendAudit 追加审计记录，并删除早于minTime的记录
+func (r *RedisRepo) AppendAudit(record []byte, minTime time.Time) error {
+	err := r.client.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: AUDIT_STREAM_KEY,
+		MinID:  fmt.Sprint(minTime.UnixMilli()),
+		Approx: true,
+		Values: map[string]interface{}{"entry": record},
+	}).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存审计记录时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// RangeAudit 按时间顺序获得[start, end]时间范围内，id大于after的至多count条记录
+func (r *RedisRepo) RangeAudit(start, end time.Time, after string, count int64) ([]string, [][]byte, error) {
+	from, to := "-", "+"
+	if after != "" {
+		// 以(开头表示不包含after本身
+		from = "(" + after
+	} else if !start.IsZero() {
+		from = fmt.Sprint(start.UnixMilli())
+	}
+	if !end.IsZero() {
+		to = fmt.Sprint(end.UnixMilli())
+	}
+
+	messages, err := r.client.XRangeN(context.Background(), AUDIT_STREAM_KEY, from, to, count).Result()
+	if err != nil {
+		return nil, nil, errors.Newf(
+			500, "Repo_Error",
+			"查询审计记录时发生了错误:%v", err)
+	}
+
+	ids := make([]string, len(messages))
+	records := make([][]byte, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+		if entry, ok := m.Values["entry"].(string); ok {
+			records[i] = []byte(entry)
+		}
+	}
+	return ids, records, nil
+}
@@ -0,0 +1,458 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// BLOB_KEY_PREFIX redis方式保存备份归档时的键前缀，
+// BLOB_CHUNKS_KEY_PREFIX redis方式以流的形式写入数据时，按块保存数据的list的键前缀，
+// 均位于全局的命名空间中，避免清理或备份用户时被当作用户的key
+const (
+	BLOB_KEY_PREFIX        = GLOBAL_KEY_NAMESPACE + "blobs:"
+	BLOB_CHUNKS_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "blob_chunks:"
+)
+
+// redis方式以流的形式写入数据时每块的大小，避免在内存与单个redis值中保存完整的数据
+const blobChunkSize = 1 << 20
+
+// 备份中包括的以用户名为字段的hash
+var tenantHashKeys = []string{
+	PSWS_KEY, TOKENS_KEY, REGISTER_INFO_KEY, CLIENT_CODE_KEY, WEBHOOKS_KEY, TWO_FACTOR_KEY,
+}
+
+// NewBackupRepo 实例化读取与写回用户数据的redis数据库操作对象
+func NewBackupRepo(data *Data) biz.BackupRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// NewBlobStore 依据服务配置实例化保存备份归档与导出归档的存储
+func NewBlobStore(c *conf.Server, data *Data) (biz.BlobStore, error) {
+	config := c.Backup
+	if config == nil || config.Type == "" || config.Type == "redis" {
+		return &redisBlobStore{client: data}, nil
+	}
+
+	switch config.Type {
+	case "file":
+		if config.Path == "" {
+			return nil, errors.New(500, "Config_Error", "file方式保存备份时需要配置保存备份的目录")
+		}
+		return &fileBlobStore{root: config.Path}, nil
+	default:
+		return nil, errors.Newf(500, "Config_Error", "不支持的备份存储方式:%s", config.Type)
+	}
+}
+
+// TenantExists 查询用户是否已注册
+func (r *RedisRepo) TenantExists(username string) (bool, error) {
+	exists, err := r.client.HExists(context.Background(), PSWS_KEY, username).Result()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"查询用户是否存在时发生了错误:%v", err)
+	}
+
+	return exists, nil
+}
+
+// SnapshotTenant 获得用户在各个hash中的字段，以及以"用户名:"为前缀的所有key经DUMP序列化后的值
+func (r *RedisRepo) SnapshotTenant(username string) (*biz.TenantSnapshot, error) {
+	snapshot := &biz.TenantSnapshot{
+		Fields: make(map[string]string),
+		Keys:   make(map[string][]byte),
+	}
+
+	for _, key := range tenantHashKeys {
+		value, err := r.client.HGet(context.Background(), key, username).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, errors.Newf(
+				500, "Repo_Error",
+				"获得用户数据时发生了错误:%v", err)
+		}
+		snapshot.Fields[key] = value
+	}
+
+	keys, err := r.scanKeys(tenantKeyPattern(username))
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得用户数据时发生了错误:%v", err)
+	}
+	for _, key := range keys {
+		value, err := r.client.Dump(context.Background(), key).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, errors.Newf(
+				500, "Repo_Error",
+				"获得用户数据时发生了错误:%v", err)
+		}
+		snapshot.Keys[key] = []byte(value)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreTenant 将快照中的数据写回数据库，已存在的字段与key被覆盖
+func (r *RedisRepo) RestoreTenant(username string, snapshot *biz.TenantSnapshot) error {
+	if snapshot == nil {
+		return nil
+	}
+
+	// 集群中的key可能位于不同的节点，因此逐个写回
+	for key, value := range snapshot.Keys {
+		err := r.client.RestoreReplace(context.Background(), key, 0, string(value)).Err()
+		if err != nil {
+			return errors.Newf(
+				500, "Repo_Error",
+				"写回用户数据时发生了错误:%v", err)
+		}
+	}
+	// 最后写回密码等字段，使用户在数据完整后才能登录
+	for _, key := range tenantHashKeys {
+		value, ok := snapshot.Fields[key]
+		if !ok {
+			continue
+		}
+		err := r.client.HSet(context.Background(), key, username, value).Err()
+		if err != nil {
+			return errors.Newf(
+				500, "Repo_Error",
+				"写回用户数据时发生了错误:%v", err)
+		}
+	}
+
+	return nil
+}
+
+// 匹配用户的key的pattern，用户相关的key以"用户名:"为前缀，需要包括分隔符，
+// 避免匹配到用户名以该用户名为前缀的其他用户的key，用户名中的glob特殊字符被转义。
+// 服务全局的带前缀的key位于GLOBAL_KEY_NAMESPACE中，不会被匹配
+func tenantKeyPattern(username string) string {
+	escaped := new(strings.Builder)
+	for _, r := range username {
+		if strings.ContainsRune(`*?[]\`, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String() + ":*"
+}
+
+// 在集群的所有master节点中查询匹配pattern的key
+func (r *RedisRepo) scanKeys(pattern string) ([]string, error) {
+	var (
+		mutex sync.Mutex
+		keys  []string
+	)
+	err := r.client.ForEachMaster(context.Background(), func(ctx context.Context, client *redis.Client) error {
+		iter := client.Scan(ctx, 0, pattern, 0).Iterator()
+		for iter.Next(ctx) {
+			mutex.Lock()
+			keys = append(keys, iter.Val())
+			mutex.Unlock()
+		}
+		return iter.Err()
+	})
+	return keys, err
+}
+
+// 以redis的key保存备份归档
+type redisBlobStore struct {
+	client *Data
+}
+
+func (s *redisBlobStore) PutBlob(key string, blob []byte) error {
+	err := s.client.Set(context.Background(), BLOB_KEY_PREFIX+key, blob, 0).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存备份时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+func (s *redisBlobStore) GetBlob(key string) ([]byte, error) {
+	blob, err := s.client.Get(context.Background(), BLOB_KEY_PREFIX+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"读取备份时发生了错误:%v", err)
+	}
+
+	return blob, nil
+}
+
+func (s *redisBlobStore) ListBlobs(prefix string) ([]string, error) {
+	keys, err := (&RedisRepo{client: s.client}).scanKeys(BLOB_KEY_PREFIX + prefix + "*")
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询备份时发生了错误:%v", err)
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, BLOB_KEY_PREFIX)
+	}
+	return keys, nil
+}
+
+func (s *redisBlobStore) CreateBlob(key string) (io.WriteCloser, error) {
+	// 先写入临时的list，关闭时再重命名，避免读取到写入了一半的数据
+	tmp := BLOB_CHUNKS_KEY_PREFIX + key + ".tmp"
+	if err := s.client.Del(context.Background(), tmp).Err(); err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	return &redisBlobWriter{client: s.client, key: BLOB_CHUNKS_KEY_PREFIX + key, tmp: tmp}, nil
+}
+
+func (s *redisBlobStore) OpenBlob(key string) (io.ReadCloser, error) {
+	key = BLOB_CHUNKS_KEY_PREFIX + key
+	chunks, err := s.client.LLen(context.Background(), key).Result()
+	if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "读取数据时发生了错误:%v", err)
+	}
+	if chunks == 0 {
+		return nil, nil
+	}
+	return &redisBlobReader{client: s.client, key: key, chunks: chunks}, nil
+}
+
+func (s *redisBlobStore) DeleteBlob(key string) error {
+	err := s.client.Del(context.Background(),
+		BLOB_KEY_PREFIX+key, BLOB_CHUNKS_KEY_PREFIX+key, BLOB_CHUNKS_KEY_PREFIX+key+".tmp").Err()
+	if err != nil {
+		return errors.Newf(500, "Repo_Error", "删除数据时发生了错误:%v", err)
+	}
+	return nil
+}
+
+// 将写入的数据按块追加到redis的list中
+type redisBlobWriter struct {
+	client *Data
+	key    string
+	tmp    string
+	buffer bytes.Buffer
+	// 是否已向list追加过数据块
+	pushed bool
+}
+
+func (w *redisBlobWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+	for w.buffer.Len() >= blobChunkSize {
+		if err := w.push(w.buffer.Next(blobChunkSize)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *redisBlobWriter) Close() error {
+	// 空的数据同样保存一个数据块，使其可以被读取
+	if w.buffer.Len() > 0 || !w.pushed {
+		if err := w.push(w.buffer.Bytes()); err != nil {
+			return err
+		}
+		w.buffer.Reset()
+	}
+	if err := w.client.Rename(context.Background(), w.tmp, w.key).Err(); err != nil {
+		return errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	return nil
+}
+
+func (w *redisBlobWriter) push(chunk []byte) error {
+	if err := w.client.RPush(context.Background(), w.tmp, chunk).Err(); err != nil {
+		return errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	w.pushed = true
+	return nil
+}
+
+// 依次读取redis的list中保存的数据块
+type redisBlobReader struct {
+	client *Data
+	key    string
+	chunks int64
+	next   int64
+	chunk  []byte
+}
+
+func (r *redisBlobReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		if r.next >= r.chunks {
+			return 0, io.EOF
+		}
+		chunk, err := r.client.LIndex(context.Background(), r.key, r.next).Bytes()
+		if err != nil {
+			return 0, errors.Newf(500, "Repo_Error", "读取数据时发生了错误:%v", err)
+		}
+		r.chunk = chunk
+		r.next++
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func (r *redisBlobReader) Close() error {
+	return nil
+}
+
+// 以文件保存备份归档，key中的/对应目录的层级
+type fileBlobStore struct {
+	root string
+}
+
+func (s *fileBlobStore) PutBlob(key string, blob []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Newf(500, "Repo_Error", "保存备份时发生了错误:%v", err)
+	}
+
+	// 先写入临时文件再重命名，避免读取到写入了一半的备份
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, blob, 0o600); err != nil {
+		return errors.Newf(500, "Repo_Error", "保存备份时发生了错误:%v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Newf(500, "Repo_Error", "保存备份时发生了错误:%v", err)
+	}
+	return nil
+}
+
+func (s *fileBlobStore) GetBlob(key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "读取备份时发生了错误:%v", err)
+	}
+	return blob, nil
+}
+
+func (s *fileBlobStore) ListBlobs(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "查询备份时发生了错误:%v", err)
+	}
+
+	return keys, nil
+}
+
+func (s *fileBlobStore) CreateBlob(key string) (io.WriteCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+
+	// 同样先写入临时文件，关闭时再重命名
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	return &fileBlobWriter{File: file, path: path}, nil
+}
+
+func (s *fileBlobStore) OpenBlob(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", "读取数据时发生了错误:%v", err)
+	}
+	return file, nil
+}
+
+func (s *fileBlobStore) DeleteBlob(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{path, path + ".tmp"} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return errors.Newf(500, "Repo_Error", "删除数据时发生了错误:%v", err)
+		}
+	}
+	return nil
+}
+
+// 写入临时文件，关闭时重命名为目标文件
+type fileBlobWriter struct {
+	*os.File
+	path string
+}
+
+func (w *fileBlobWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		return errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	if err := os.Rename(w.File.Name(), w.path); err != nil {
+		return errors.Newf(500, "Repo_Error", "写入数据时发生了错误:%v", err)
+	}
+	return nil
+}
+
+// 获得key对应的文件，不允许key指向保存备份的目录之外
+func (s *fileBlobStore) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(s.root, path); err != nil || strings.HasPrefix(rel, "..") {
+		return "", errors.BadRequest("Repo_Error", "无效的备份路径:"+key)
+	}
+	return path, nil
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"io"
+	"path"
+	"sort"
+	"testing"
+)
+
+func TestFileBlobStore(t *testing.T) {
+	store := &fileBlobStore{root: t.TempDir()}
+
+	if blob, err := store.GetBlob("backups/alice/v1.zip"); err != nil || blob != nil {
+		t.Fatalf("不存在的备份应返回nil:%v %v", blob, err)
+	}
+	for _, key := range []string{"backups/alice/v1.zip", "backups/alice/v1.json", "backups/bob/v1.zip"} {
+		if err := store.PutBlob(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blob, err := store.GetBlob("backups/alice/v1.zip")
+	if err != nil || string(blob) != "backups/alice/v1.zip" {
+		t.Fatalf("unexpected blob: %q %v", blob, err)
+	}
+
+	keys, err := store.ListBlobs("backups/alice/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "backups/alice/v1.json" || keys[1] != "backups/alice/v1.zip" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if err := store.PutBlob("../outside", nil); err == nil {
+		t.Fatal("不允许写入保存备份的目录之外")
+	}
+}
+
+func TestFileBlobStore_stream(t *testing.T) {
+	store := &fileBlobStore{root: t.TempDir()}
+
+	if blob, err := store.OpenBlob("exports/a.zip"); err != nil || blob != nil {
+		t.Fatalf("不存在的数据应返回nil:%v %v", blob, err)
+	}
+
+	w, err := store.CreateBlob("exports/a.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, "zip"); err != nil {
+		t.Fatal(err)
+	}
+	// 关闭前数据不可读取
+	if blob, _ := store.OpenBlob("exports/a.zip"); blob != nil {
+		t.Fatal("写入完成前不应读取到数据")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := store.OpenBlob("exports/a.zip")
+	if err != nil || r == nil {
+		t.Fatalf("应读取到写入的数据:%v", err)
+	}
+	content, _ := io.ReadAll(r)
+	r.Close()
+	if string(content) != "zip" {
+		t.Fatalf("unexpected blob: %q", content)
+	}
+
+	if err := store.DeleteBlob("exports/a.zip"); err != nil {
+		t.Fatal(err)
+	}
+	if blob, _ := store.OpenBlob("exports/a.zip"); blob != nil {
+		t.Fatal("删除后不应读取到数据")
+	}
+	if err := store.DeleteBlob("exports/a.zip"); err != nil {
+		t.Fatalf("删除不存在的数据不应返回错误:%v", err)
+	}
+}
+
+func Test_tenantKeyPattern(t *testing.T) {
+	// redis的glob与path.Match对以下key的匹配结果一致
+	pattern := tenantKeyPattern("bob")
+	for key, want := range map[string]bool{
+		"bob:config":     true,
+		"bob:state:1":    true,
+		"bob_2:config":   false,
+		"bobby:config":   false,
+		"alice:bob:conf": false,
+	} {
+		if got, _ := path.Match(pattern, key); got != want {
+			t.Errorf("%s 匹配 %s 的结果应为%v", pattern, key, want)
+		}
+	}
+
+	if pattern := tenantKeyPattern("a*"); pattern != `a\*:*` {
+		t.Fatalf("应转义用户名中的glob特殊字符:%s", pattern)
+	}
+}
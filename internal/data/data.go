@@ -11,7 +11,11 @@ import (
 )
 
 // ProviderSet is data providers.
-var ProviderSet = wire.NewSet(NewData, NewRedisRepo)
+var ProviderSet = wire.NewSet(NewData, NewRedisRepo, NewUpgradeRepo, NewUsageRepo,
+	NewEventOutbox, NewEventBus, NewWebhookRepo, NewVerificationRepo, NewMailSender,
+	NewPasswordResetRepo, NewNotifier, NewOrgRepo, NewTwoFactorRepo, NewOidcRepo, NewAuditRepo,
+	NewDeletionRepo, NewExportRepo, NewBackupRepo, NewBlobStore, NewTimeSeriesAccountRepo,
+	NewExternalInfluxdbRepo)
 
 // Data .
 type Data struct {
@@ -0,0 +1,114 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+// PENDING_DELETIONS_KEY 已注销用户的清理计划，以用户名为成员，清理时间的unix秒数为分数的zset
+const PENDING_DELETIONS_KEY = "pending_deletions"
+
+// PURGING_DELETIONS_KEY 已开始清理的用户的集合，其中的用户无法再取消清理计划
+const PURGING_DELETIONS_KEY = "purging_deletions"
+
+// 用户未开始清理时删除清理计划
+var cancelDeletionScript = redis.NewScript(`
+if redis.call('SISMEMBER', KEYS[2], ARGV[1]) == 1 then
+	return 0
+end
+return redis.call('ZREM', KEYS[1], ARGV[1])
+`)
+
+// 清理计划到期时推迟清理时间作为租约，并标记用户已开始清理
+var claimDeletionScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+redis.call('SADD', KEYS[2], ARGV[1])
+return 1
+`)
+
+// NewDeletionRepo 实例化保存注销用户清理计划的redis数据库操作对象
+func NewDeletionRepo(data *Data) biz.DeletionRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// ScheduleDeletion 记录用户已注销，并在purgeTime之后清理，已有的记录被覆盖
+func (r *RedisRepo) ScheduleDeletion(username string, purgeTime time.Time) error {
+	err := r.client.ZAdd(context.Background(), PENDING_DELETIONS_KEY, &redis.Z{
+		Score:  float64(purgeTime.Unix()),
+		Member: username,
+	}).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户的清理计划时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetDeletion 获得已注销用户的清理时间，用户未注销时返回零值
+func (r *RedisRepo) GetDeletion(username string) (time.Time, error) {
+	score, err := r.client.ZScore(context.Background(), PENDING_DELETIONS_KEY, username).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, errors.Newf(
+			500, "Repo_Error",
+			"查询用户的清理计划时发生了错误:%v", err)
+	}
+
+	return time.Unix(int64(score), 0), nil
+}
+
+// CancelDeletion 删除用户的清理计划，返回false时表示用户未注销、计划已被删除或已开始清理
+func (r *RedisRepo) CancelDeletion(username string) (bool, error) {
+	removed, err := cancelDeletionScript.Run(context.Background(), r.client,
+		[]string{PENDING_DELETIONS_KEY, PURGING_DELETIONS_KEY}, username).Int()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"删除用户的清理计划时发生了错误:%v", err)
+	}
+
+	return removed > 0, nil
+}
+
+// DueDeletions 获得清理时间不晚于now的所有用户
+func (r *RedisRepo) DueDeletions(now time.Time) ([]string, error) {
+	usernames, err := r.client.ZRangeByScore(context.Background(), PENDING_DELETIONS_KEY, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询到期的清理计划时发生了错误:%v", err)
+	}
+
+	return usernames, nil
+}
+
+// ClaimDeletion 在用户的清理时间不晚于now时领取清理任务，将清理时间推迟到leaseUntil并标记用户已开始清理，
+// 返回false时表示用户未到期或已被其他实例领取
+func (r *RedisRepo) ClaimDeletion(username string, now, leaseUntil time.Time) (bool, error) {
+	claimed, err := claimDeletionScript.Run(context.Background(), r.client,
+		[]string{PENDING_DELETIONS_KEY, PURGING_DELETIONS_KEY},
+		username, now.Unix(), leaseUntil.Unix()).Int()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"领取用户的清理任务时发生了错误:%v", err)
+	}
+
+	return claimed == 1, nil
+}
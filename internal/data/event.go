@@ -0,0 +1,128 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// EVENT_OUTBOX_KEY 待发送的租户生命周期事件list的key
+	EVENT_OUTBOX_KEY = "event_outbox"
+	// 未配置topic时使用的默认值
+	defaultEventTopic = "tenant-events"
+)
+
+// NewEventOutbox 实例化保存待发送事件的redis数据库操作对象
+func NewEventOutbox(data *Data) biz.EventOutbox {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// NewEventBus 依据配置实例化发布事件的消息总线，未配置消息总线时返回nil
+func NewEventBus(c *conf.Data, data *Data, logger log.Logger) (biz.EventBus, func(), error) {
+	cleanup := func() {}
+	config := c.EventBus
+	if config == nil || config.Type == "" {
+		return nil, cleanup, nil
+	}
+
+	topic := config.Topic
+	if topic == "" {
+		topic = defaultEventTopic
+	}
+
+	switch config.Type {
+	case "redis":
+		return &redisStreamBus{client: data, stream: topic, maxLen: config.MaxLen}, cleanup, nil
+	case "nats":
+		bus := newNatsBus(config.Address, config.Token, topic)
+		return bus, func() {
+			if err := bus.Close(); err != nil {
+				log.NewHelper(logger).Errorf("关闭nats连接时发生了错误:%v", err)
+			}
+		}, nil
+	case "kafka":
+		return newKafkaBus(config.Address, topic), cleanup, nil
+	default:
+		return nil, nil, errors.Newf(500, "Config_Error", "不支持的消息总线类型:%s", config.Type)
+	}
+}
+
+// AppendEvent 将事件追加到发件箱list的末尾
+func (r *RedisRepo) AppendEvent(event []byte) error {
+	err := r.client.RPush(context.Background(), EVENT_OUTBOX_KEY, event).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"将事件写入发件箱时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// PeekEvents 获得发件箱中最早的n个事件
+func (r *RedisRepo) PeekEvents(n int64) ([][]byte, error) {
+	result, err := r.client.LRange(context.Background(), EVENT_OUTBOX_KEY, 0, n-1).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"读取发件箱中的事件时发生了错误:%v", err)
+	}
+
+	events := make([][]byte, len(result))
+	for i, e := range result {
+		events[i] = []byte(e)
+	}
+	return events, nil
+}
+
+// 依次比较发件箱开头的事件与给定的事件，一致时删除，出现不一致时停止，返回删除的事件数量
+var removeEventsScript = redis.NewScript(`
+local removed = 0
+for _, event in ipairs(ARGV) do
+	if redis.call("LINDEX", KEYS[1], 0) ~= event then
+		break
+	end
+	redis.call("LPOP", KEYS[1])
+	removed = removed + 1
+end
+return removed
+`)
+
+// RemoveEvents 删除发件箱开头与events一致的事件，比较与删除在脚本中原子地执行，
+// 多个实例发送了同一批事件时，后删除的实例不会误删其余实例尚未发送的事件
+func (r *RedisRepo) RemoveEvents(events [][]byte) error {
+	args := make([]interface{}, len(events))
+	for i, e := range events {
+		args[i] = e
+	}
+	err := removeEventsScript.Run(context.Background(), r.client, []string{EVENT_OUTBOX_KEY}, args...).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除发件箱中的事件时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// 以redis stream作为消息总线，每个事件保存为stream中一条包含key与event字段的消息
+type redisStreamBus struct {
+	client *Data
+	stream string
+	maxLen int64
+}
+
+func (b *redisStreamBus) Publish(ctx context.Context, key string, event []byte) error {
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream,
+		MaxLen: b.maxLen,
+		Approx: b.maxLen > 0,
+		Values: map[string]interface{}{"key": key, "event": event},
+	}).Err()
+}
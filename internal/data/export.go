@@ -0,0 +1,85 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// EXPORT_JOB_KEY_PREFIX 导出任务状态的键前缀，位于全局的命名空间中，使任务在租户注销后的宽限期内仍可下载，
+// EXPORT_JOBS_KEY 所有导出任务id的集合，用于在任务状态过期后清理其归档
+const (
+	EXPORT_JOB_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "export_job:"
+	EXPORT_JOBS_KEY       = "export_jobs"
+)
+
+// NewExportRepo 实例化保存导出任务的redis数据库操作对象
+func NewExportRepo(data *Data) biz.ExportRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveExportJob 保存导出任务的状态，任务在ttl后自动删除
+func (r *RedisRepo) SaveExportJob(id string, job []byte, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.Set(context.Background(), EXPORT_JOB_KEY_PREFIX+id, job, ttl)
+		p.SAdd(context.Background(), EXPORT_JOBS_KEY, id)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存导出任务的状态时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetExportJob 获得导出任务的状态，不存在或已过期时返回nil
+func (r *RedisRepo) GetExportJob(id string) ([]byte, error) {
+	return r.getExport(EXPORT_JOB_KEY_PREFIX+id, "导出任务的状态")
+}
+
+// ListExportJobs 获得所有未被删除的导出任务的id，包括状态已过期的任务
+func (r *RedisRepo) ListExportJobs() ([]string, error) {
+	ids, err := r.client.SMembers(context.Background(), EXPORT_JOBS_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询导出任务时发生了错误:%v", err)
+	}
+
+	return ids, nil
+}
+
+// RemoveExportJob 删除导出任务的状态
+func (r *RedisRepo) RemoveExportJob(id string) error {
+	_, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.Del(context.Background(), EXPORT_JOB_KEY_PREFIX+id)
+		p.SRem(context.Background(), EXPORT_JOBS_KEY, id)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除导出任务时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+func (r *RedisRepo) getExport(key, what string) ([]byte, error) {
+	value, err := r.client.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询%s时发生了错误:%v", what, err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// EXTERNAL_INFLUXDB_KEY 用户自带的influxdb加密后的连接信息的hash，以用户名为键
+const EXTERNAL_INFLUXDB_KEY = "external_influxdb"
+
+// NewExternalInfluxdbRepo 实例化保存用户自带的influxdb连接信息的redis数据库操作对象
+func NewExternalInfluxdbRepo(data *Data) biz.ExternalInfluxdbRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveExternalInfluxdb 保存用户自带的influxdb加密后的连接信息
+func (r *RedisRepo) SaveExternalInfluxdb(username string, encrypted []byte) error {
+	err := r.client.HSet(context.Background(), EXTERNAL_INFLUXDB_KEY, username, encrypted).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户自带的influxdb的连接信息时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetExternalInfluxdb 获得用户自带的influxdb加密后的连接信息，不存在时返回nil
+func (r *RedisRepo) GetExternalInfluxdb(username string) ([]byte, error) {
+	encrypted, err := r.client.HGet(context.Background(), EXTERNAL_INFLUXDB_KEY, username).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询用户自带的influxdb的连接信息时发生了错误:%v", err)
+	}
+
+	return encrypted, nil
+}
+
+// RemoveExternalInfluxdb 删除用户自带的influxdb的连接信息
+func (r *RedisRepo) RemoveExternalInfluxdb(username string) error {
+	err := r.client.HDel(context.Background(), EXTERNAL_INFLUXDB_KEY, username).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户自带的influxdb的连接信息时发生了错误:%v", err)
+	}
+
+	return nil
+}
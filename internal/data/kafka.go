@@ -0,0 +1,61 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/imroc/req/v3"
+	"strings"
+	"time"
+)
+
+// 以kafka作为消息总线，通过kafka rest proxy的v2接口发布消息，接口参考:
+// https://docs.confluent.io/platform/current/kafka-rest/api.html#post--topics-(string-topic_name)
+type kafkaBus struct {
+	client *req.Client
+	topic  string
+}
+
+func newKafkaBus(address, topic string) *kafkaBus {
+	client := req.C().
+		SetBaseURL(strings.TrimSuffix(address, "/")).
+		SetTimeout(10 * time.Second)
+	return &kafkaBus{client: client, topic: topic}
+}
+
+func (b *kafkaBus) Publish(ctx context.Context, key string, event []byte) error {
+	body := map[string]interface{}{
+		"records": []map[string]interface{}{
+			{"key": key, "value": json.RawMessage(event)},
+		},
+	}
+
+	response, err := b.client.R().
+		SetContext(ctx).
+		SetPathParam("topic", b.topic).
+		SetBodyJsonMarshal(body).
+		// 需要在设置请求体之后覆盖其设置的Content-Type
+		SetContentType("application/vnd.kafka.json.v2+json").
+		Post("/topics/{topic}")
+	if err != nil {
+		return fmt.Errorf("向kafka发布消息时发生了错误: %w", err)
+	}
+	if response.IsError() {
+		return fmt.Errorf("向kafka发布消息时发生了错误: %s", response.String())
+	}
+
+	// rest proxy对每条消息分别返回结果，发布失败的消息包含error字段
+	result := &struct {
+		Offsets []struct {
+			Error string `json:"error"`
+		} `json:"offsets"`
+	}{}
+	if err := json.Unmarshal(response.Bytes(), result); err == nil {
+		for _, o := range result.Offsets {
+			if o.Error != "" {
+				return fmt.Errorf("向kafka发布消息时发生了错误: %s", o.Error)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"bytes"
+	"fmt"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"mime"
+	"net/smtp"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewMailSender 依据配置实例化发送邮件的对象，未配置时只在日志中输出邮件
+func NewMailSender(c *conf.Server, logger log.Logger) (biz.MailSender, error) {
+	config := c.Mail
+	if config == nil || config.Type == "" || config.Type == "log" {
+		return &logMailSender{logger: log.NewHelper(logger)}, nil
+	}
+
+	switch config.Type {
+	case "smtp":
+		if config.Host == "" || config.From == "" {
+			return nil, errors.New(500, "Config_Error", "smtp方式发送邮件时需要配置服务器地址以及发件人")
+		}
+		sender := &smtpMailSender{
+			address: fmt.Sprintf("%s:%d", config.Host, config.Port),
+			from:    config.From,
+		}
+		if config.Port == 0 {
+			sender.address = config.Host + ":25"
+		}
+		if config.Username != "" {
+			sender.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+		}
+		return sender, nil
+	case "file":
+		if config.Path == "" {
+			return nil, errors.New(500, "Config_Error", "file方式发送邮件时需要配置写入邮件的文件")
+		}
+		return &fileMailSender{path: config.Path, from: config.From}, nil
+	default:
+		return nil, errors.Newf(500, "Config_Error", "不支持的邮件发送方式:%s", config.Type)
+	}
+}
+
+// 通过smtp服务器发送邮件，服务器支持时自动使用STARTTLS
+type smtpMailSender struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+func (s *smtpMailSender) Send(to, subject, body string) error {
+	return smtp.SendMail(s.address, s.auth, s.from, []string{to}, mailMessage(s.from, to, subject, body))
+}
+
+// 将邮件追加写入文件，用于开发环境
+type fileMailSender struct {
+	mutex sync.Mutex
+	path  string
+	from  string
+}
+
+func (s *fileMailSender) Send(to, subject, body string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(mailMessage(s.from, to, subject, body), '\n'))
+	return err
+}
+
+// 只在日志中输出邮件，用于开发环境
+type logMailSender struct {
+	logger *log.Helper
+}
+
+func (s *logMailSender) Send(to, subject, body string) error {
+	s.logger.Infof("向 %s 发送邮件，主题:%s\n%s", to, subject, body)
+	return nil
+}
+
+// 生成纯文本格式的邮件内容
+func mailMessage(from, to, subject, body string) []byte {
+	buffer := new(bytes.Buffer)
+	fmt.Fprintf(buffer, "From: %s\r\n", from)
+	fmt.Fprintf(buffer, "To: %s\r\n", to)
+	fmt.Fprintf(buffer, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buffer.WriteString(body)
+	return buffer.Bytes()
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nats操作的超时时长
+const natsTimeout = 5 * time.Second
+
+// 以nats作为消息总线，直接实现了发布消息所需的nats客户端协议，协议参考:
+// https://docs.nats.io/reference/reference-protocols/nats-protocol
+// 发布消息后通过PING/PONG确认服务器已经处理了消息，连接出错时在下一次发布时重新建立
+type natsBus struct {
+	address string
+	token   string
+	subject string
+
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newNatsBus(address, token, subject string) *natsBus {
+	return &natsBus{address: address, token: token, subject: subject}
+}
+
+func (b *natsBus) Publish(ctx context.Context, key string, event []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.conn == nil {
+		if err := b.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := b.publish(event)
+	if err != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	return err
+}
+
+// Close 关闭与nats服务器的连接
+func (b *natsBus) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn = nil
+	return err
+}
+
+// 建立连接并完成握手，服务器在连接建立后首先发送INFO，客户端回复CONNECT
+func (b *natsBus) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: natsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", b.address)
+	if err != nil {
+		return fmt.Errorf("连接nats服务器时发生了错误: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(natsTimeout))
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("nats服务器的握手信息错误: %q %v", line, err)
+	}
+
+	options, _ := json.Marshal(map[string]interface{}{
+		"verbose":    false,
+		"pedantic":   false,
+		"auth_token": b.token,
+		"name":       "service-centre",
+		"lang":       "go",
+		"protocol":   0,
+	})
+	if _, err = fmt.Fprintf(conn, "CONNECT %s\r\n", options); err != nil {
+		conn.Close()
+		return fmt.Errorf("向nats服务器发送连接信息时发生了错误: %w", err)
+	}
+
+	b.conn, b.reader = conn, reader
+	return nil
+}
+
+// 发布消息，并发送PING等待PONG以确认消息已被服务器处理
+func (b *natsBus) publish(event []byte) error {
+	b.conn.SetDeadline(time.Now().Add(natsTimeout))
+
+	_, err := fmt.Fprintf(b.conn, "PUB %s %d\r\n%s\r\nPING\r\n", b.subject, len(event), event)
+	if err != nil {
+		return fmt.Errorf("向nats服务器发布消息时发生了错误: %w", err)
+	}
+
+	for {
+		line, err := b.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("读取nats服务器的响应时发生了错误: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err = b.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats服务器返回了错误: %s", line)
+		}
+	}
+}
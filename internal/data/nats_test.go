@@ -0,0 +1,59 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// 模拟nats服务器，记录收到的PUB消息
+func serveNats(listener net.Listener, published chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PUB "):
+			payload, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			published <- strings.TrimSpace(line) + " " + strings.TrimSpace(payload)
+		case strings.HasPrefix(line, "PING"):
+			conn.Write([]byte("PONG\r\n"))
+		}
+	}
+}
+
+func TestNatsBus_Publish(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	published := make(chan string, 1)
+	go serveNats(listener, published)
+
+	bus := newNatsBus(listener.Addr().String(), "", "tenant-events")
+	defer bus.Close()
+
+	err = bus.Publish(context.Background(), "test", []byte(`{"subject":"test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-published; msg != `PUB tenant-events 18 {"subject":"test"}` {
+		t.Fatalf("服务器收到的消息错误:%v", msg)
+	}
+}
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"gitee.com/moyusir/service-centre/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// NewNotifier 依据配置实例化发送重置令牌等用户通知的对象，默认通过邮件发送
+func NewNotifier(c *conf.Server, mail biz.MailSender, logger log.Logger) (biz.Notifier, error) {
+	var notifier string
+	if c.PasswordReset != nil {
+		notifier = c.PasswordReset.Notifier
+	}
+
+	switch notifier {
+	case "", "mail":
+		return &mailNotifier{mail: mail}, nil
+	case "log":
+		return &logNotifier{logger: log.NewHelper(logger)}, nil
+	default:
+		return nil, errors.Newf(500, "Config_Error", "不支持的通知方式:%s", notifier)
+	}
+}
+
+// 将通知发送到用户注册时填写的邮箱
+type mailNotifier struct {
+	mail biz.MailSender
+}
+
+func (n *mailNotifier) Notify(username, email, subject, body string) error {
+	if email == "" {
+		return errors.Newf(400, "Notify_Error", "用户 %s 注册时未填写邮箱，无法发送通知", username)
+	}
+	return n.mail.Send(email, subject, body)
+}
+
+// 只在日志中输出通知，用于开发环境
+type logNotifier struct {
+	logger *log.Helper
+}
+
+func (n *logNotifier) Notify(username, email, subject, body string) error {
+	n.logger.Infof("向用户 %s 发送通知，主题:%s\n%s", username, subject, body)
+	return nil
+}
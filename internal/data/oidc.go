@@ -0,0 +1,230 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const (
+	// OIDC_STATE_KEY_PREFIX OIDC登录请求的key前缀，完整的key为sc/oidc_state:<state>，依靠key的过期时间自动删除
+	OIDC_STATE_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "oidc_state:"
+	// OIDC_TICKET_KEY_PREFIX OIDC首次注册凭证的key前缀，完整的key为sc/oidc_ticket:<凭证>，依靠key的过期时间自动删除
+	OIDC_TICKET_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "oidc_ticket:"
+	// OIDC_SUBJECTS_KEY OIDC身份关联的租户hash的key，以subject为键
+	OIDC_SUBJECTS_KEY = "oidc_subjects"
+	// OIDC_USERS_KEY 租户关联的OIDC身份hash的key，以用户名为键，用于注销时删除关联
+	OIDC_USERS_KEY = "oidc_users"
+	// ADMIN_SESSION_KEY_PREFIX 管理令牌的key前缀，完整的key为sc/admin_session:<令牌的摘要>，依靠key的过期时间自动删除
+	ADMIN_SESSION_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "admin_session:"
+)
+
+// NewOidcRepo 实例化保存OIDC登录信息的redis数据库操作对象
+func NewOidcRepo(data *Data) biz.OidcRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveOidcState 保存登录请求的state对应的信息，在ttl后自动删除
+func (r *RedisRepo) SaveOidcState(state string, record []byte, ttl time.Duration) error {
+	err := r.client.Set(context.Background(), OIDC_STATE_KEY_PREFIX+state, record, ttl).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存OIDC登录请求时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// TakeOidcState 获得并删除state对应的信息，不存在或已过期时返回nil
+func (r *RedisRepo) TakeOidcState(state string) ([]byte, error) {
+	return r.take(OIDC_STATE_KEY_PREFIX+state, "获得OIDC登录请求时发生了错误:%v")
+}
+
+// SaveOidcTicket 保存首次注册凭证对应的身份，在ttl后自动删除
+func (r *RedisRepo) SaveOidcTicket(ticket string, identity []byte, ttl time.Duration) error {
+	err := r.client.Set(context.Background(), OIDC_TICKET_KEY_PREFIX+ticket, identity, ttl).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存OIDC注册凭证时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetOidcTicket 获得首次注册凭证对应的身份，不存在或已过期时返回nil
+func (r *RedisRepo) GetOidcTicket(ticket string) ([]byte, error) {
+	result, err := r.client.Get(context.Background(), OIDC_TICKET_KEY_PREFIX+ticket).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得OIDC注册凭证时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// TakeOidcTicket 获得并删除首次注册凭证对应的身份，不存在或已过期时返回nil
+func (r *RedisRepo) TakeOidcTicket(ticket string) ([]byte, error) {
+	return r.take(OIDC_TICKET_KEY_PREFIX+ticket, "获得OIDC注册凭证时发生了错误:%v")
+}
+
+// LinkOidcSubject 将身份提供方的subject关联到租户，subject已关联其他租户时返回错误
+func (r *RedisRepo) LinkOidcSubject(subject, username string) error {
+	ctx := context.Background()
+	linked, err := r.client.HSetNX(ctx, OIDC_SUBJECTS_KEY, subject, username).Result()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"关联OIDC身份时发生了错误:%v", err)
+	} else if !linked {
+		return errors.New(409, "Repo_Error", "OIDC身份已关联租户")
+	}
+
+	err = r.client.HSet(ctx, OIDC_USERS_KEY, username, subject).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"关联OIDC身份时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// 撤销subject与租户的关联，subject已关联到其他租户时不进行任何操作
+var unlinkOidcSubjectScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HDEL', KEYS[1], ARGV[1])
+if redis.call('HGET', KEYS[2], ARGV[2]) == ARGV[1] then
+	redis.call('HDEL', KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+// UnlinkOidcSubject 撤销subject与租户的关联，用于注册失败时撤销注册前建立的关联
+func (r *RedisRepo) UnlinkOidcSubject(subject, username string) error {
+	err := unlinkOidcSubjectScript.Run(context.Background(), r.client,
+		[]string{OIDC_SUBJECTS_KEY, OIDC_USERS_KEY}, subject, username).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"撤销OIDC身份的关联时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetOidcSubject 获得subject关联的租户，未关联时返回空字符串
+func (r *RedisRepo) GetOidcSubject(subject string) (string, error) {
+	username, err := r.client.HGet(context.Background(), OIDC_SUBJECTS_KEY, subject).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Newf(
+			500, "Repo_Error",
+			"查询OIDC身份关联的租户时发生了错误:%v", err)
+	}
+
+	return username, nil
+}
+
+// UsernameTaken 用户名是否已被注册的用户或组织的成员使用
+func (r *RedisRepo) UsernameTaken(username string) (bool, error) {
+	ctx := context.Background()
+	for _, key := range []string{PSWS_KEY, MEMBERS_KEY} {
+		taken, err := r.client.HExists(ctx, key, username).Result()
+		if err != nil {
+			return false, errors.Newf(
+				500, "Repo_Error",
+				"查询用户名是否已被使用时发生了错误:%v", err)
+		}
+		if taken {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetToken 获得已注册用户的token
+func (r *RedisRepo) GetToken(username string) (string, error) {
+	token, err := r.client.HGet(context.Background(), TOKENS_KEY, username).Result()
+	if err != nil {
+		return "", errors.Newf(
+			500, "Repo_Error",
+			"查询用户的token时发生了错误:%v", err)
+	}
+
+	return token, nil
+}
+
+// SaveAdminSession 保存管理令牌的摘要，在ttl后自动删除
+func (r *RedisRepo) SaveAdminSession(digest, subject string, ttl time.Duration) error {
+	err := r.client.Set(context.Background(), ADMIN_SESSION_KEY_PREFIX+digest, subject, ttl).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存管理令牌时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetAdminSession 获得管理令牌对应的管理员的subject，不存在或已过期时返回空字符串
+func (r *RedisRepo) GetAdminSession(digest string) (string, error) {
+	subject, err := r.client.Get(context.Background(), ADMIN_SESSION_KEY_PREFIX+digest).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Newf(
+			500, "Repo_Error",
+			"查询管理令牌时发生了错误:%v", err)
+	}
+
+	return subject, nil
+}
+
+// 获得并删除给定的key，不存在时返回nil
+func (r *RedisRepo) take(key, format string) ([]byte, error) {
+	ctx := context.Background()
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		get = p.Get(ctx, key)
+		p.Del(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(500, "Repo_Error", format, err)
+	}
+
+	return get.Bytes()
+}
+
+// 删除租户关联的OIDC身份，用于租户注销
+func (r *RedisRepo) unlinkOidcSubject(username string) error {
+	ctx := context.Background()
+	subject, err := r.client.HGet(ctx, OIDC_USERS_KEY, username).Result()
+	if err == redis.Nil {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HDel(ctx, OIDC_SUBJECTS_KEY, subject)
+		p.HDel(ctx, OIDC_USERS_KEY, username)
+		return nil
+	})
+	return err
+}
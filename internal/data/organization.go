@@ -0,0 +1,218 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const (
+	// MEMBERS_KEY 组织成员信息hash的key，以成员的账号为键
+	MEMBERS_KEY = "members"
+	// ORG_MEMBERS_KEY_PREFIX 组织成员账号set的key前缀，完整的key为sc/org_members:<组织的用户名>
+	ORG_MEMBERS_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "org_members:"
+	// INVITATION_KEY_PREFIX 邀请的key前缀，完整的key为sc/invitation:<邀请码>，邀请依靠key的过期时间自动删除
+	INVITATION_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "invitation:"
+	// ORG_ACL_KEY 已为服务启用acl插件的组织set的key，用于为引入组织之前注册的用户补充启用acl插件
+	ORG_ACL_KEY = "org_acl"
+)
+
+// NewOrgRepo 实例化保存组织成员的redis数据库操作对象
+func NewOrgRepo(data *Data) biz.OrgRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveInvitation 保存邀请，邀请在ttl后自动删除
+func (r *RedisRepo) SaveInvitation(code string, invitation []byte, ttl time.Duration) error {
+	err := r.client.Set(context.Background(), INVITATION_KEY_PREFIX+code, invitation, ttl).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存邀请时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetInvitation 获得邀请但不删除，不存在或已过期时返回nil
+func (r *RedisRepo) GetInvitation(code string) ([]byte, error) {
+	result, err := r.client.Get(context.Background(), INVITATION_KEY_PREFIX+code).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得邀请时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// TakeInvitation 获得并删除邀请，不存在或已过期时返回nil
+func (r *RedisRepo) TakeInvitation(code string) ([]byte, error) {
+	ctx := context.Background()
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		get = p.Get(ctx, INVITATION_KEY_PREFIX+code)
+		p.Del(ctx, INVITATION_KEY_PREFIX+code)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得邀请时发生了错误:%v", err)
+	}
+
+	return get.Bytes()
+}
+
+// AddMember 保存组织的成员，成员的账号与已注册的用户或已有的成员重复时返回错误
+func (r *RedisRepo) AddMember(org, member string, record []byte) error {
+	ctx := context.Background()
+	registered, err := r.client.HExists(ctx, PSWS_KEY, member).Result()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存组织的成员时发生了错误:%v", err)
+	} else if registered {
+		return errors.New(400, "Repo_Error", "用户账号已经存在")
+	}
+
+	cmders, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSetNX(ctx, MEMBERS_KEY, member, record)
+		p.SAdd(ctx, ORG_MEMBERS_KEY_PREFIX+org, member)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存组织的成员时发生了错误:%v", err)
+	}
+	if !cmders[0].(*redis.BoolCmd).Val() {
+		r.client.SRem(ctx, ORG_MEMBERS_KEY_PREFIX+org, member)
+		return errors.New(400, "Repo_Error", "用户账号已经存在")
+	}
+
+	return nil
+}
+
+// ListOrgACLEnabled 获得已为服务启用acl插件的所有组织
+func (r *RedisRepo) ListOrgACLEnabled() ([]string, error) {
+	orgs, err := r.client.SMembers(context.Background(), ORG_ACL_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询已启用acl插件的组织时发生了错误:%v", err)
+	}
+
+	return orgs, nil
+}
+
+// MarkOrgACLEnabled 记录组织已为服务启用acl插件
+func (r *RedisRepo) MarkOrgACLEnabled(org string) error {
+	err := r.client.SAdd(context.Background(), ORG_ACL_KEY, org).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"记录组织已启用acl插件时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetMember 获得成员的信息，不存在时返回nil
+func (r *RedisRepo) GetMember(member string) ([]byte, error) {
+	result, err := r.client.HGet(context.Background(), MEMBERS_KEY, member).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得成员的信息时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// UpdateMember 更新成员的信息
+func (r *RedisRepo) UpdateMember(member string, record []byte) error {
+	err := r.client.HSet(context.Background(), MEMBERS_KEY, member, record).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"更新成员的信息时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// RemoveMember 删除组织的成员
+func (r *RedisRepo) RemoveMember(org, member string) error {
+	_, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.HDel(context.Background(), MEMBERS_KEY, member)
+		p.HDel(context.Background(), TWO_FACTOR_KEY, member)
+		p.SRem(context.Background(), ORG_MEMBERS_KEY_PREFIX+org, member)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除组织的成员时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// ListMembers 获得组织所有成员的信息
+func (r *RedisRepo) ListMembers(org string) ([][]byte, error) {
+	ctx := context.Background()
+	members, err := r.client.SMembers(ctx, ORG_MEMBERS_KEY_PREFIX+org).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询组织的成员时发生了错误:%v", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	result, err := r.client.HMGet(ctx, MEMBERS_KEY, members...).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询组织的成员时发生了错误:%v", err)
+	}
+
+	records := make([][]byte, 0, len(result))
+	for _, m := range result {
+		if s, ok := m.(string); ok {
+			records = append(records, []byte(s))
+		}
+	}
+	return records, nil
+}
+
+// 删除组织的所有成员，用于组织注销
+func (r *RedisRepo) removeOrgMembers(org string) error {
+	ctx := context.Background()
+	members, err := r.client.SMembers(ctx, ORG_MEMBERS_KEY_PREFIX+org).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		if len(members) != 0 {
+			p.HDel(ctx, MEMBERS_KEY, members...)
+			p.HDel(ctx, TWO_FACTOR_KEY, members...)
+		}
+		p.Del(ctx, ORG_MEMBERS_KEY_PREFIX+org)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"encoding/hex"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const (
+	// PASSWORD_RESET_KEY_PREFIX 重置令牌摘要的key前缀，完整的key为password_reset:<用户名>
+	PASSWORD_RESET_KEY_PREFIX = "password_reset:"
+	// PASSWORD_RESET_COUNTER_KEY_PREFIX 限制重置次数的计数器的key前缀
+	PASSWORD_RESET_COUNTER_KEY_PREFIX = "password_reset_counter:"
+	// PASSWORD_RESET_AUDIT_KEY 重置密码审计记录list的key
+	PASSWORD_RESET_AUDIT_KEY = "password_reset_audit"
+	// 最多保留的审计记录数量
+	passwordResetAuditRetention = 10000
+)
+
+// NewPasswordResetRepo 实例化保存重置令牌的redis数据库操作对象
+func NewPasswordResetRepo(data *Data) biz.PasswordResetRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveResetToken 保存重置令牌的摘要，令牌在ttl后自动删除
+func (r *RedisRepo) SaveResetToken(username, digest string, ttl time.Duration) error {
+	err := r.client.Set(context.Background(), PASSWORD_RESET_KEY_PREFIX+username, digest, ttl).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存重置令牌时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetResetToken 获得重置令牌的摘要，不存在或已过期时返回空字符串
+func (r *RedisRepo) GetResetToken(username string) (string, error) {
+	digest, err := r.client.Get(context.Background(), PASSWORD_RESET_KEY_PREFIX+username).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Newf(
+			500, "Repo_Error",
+			"获得重置令牌时发生了错误:%v", err)
+	}
+
+	return digest, nil
+}
+
+// RemoveResetToken 删除重置令牌，返回false时表示令牌已被删除
+func (r *RedisRepo) RemoveResetToken(username string) (bool, error) {
+	deleted, err := r.client.Del(context.Background(), PASSWORD_RESET_KEY_PREFIX+username).Result()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"删除重置令牌时发生了错误:%v", err)
+	}
+
+	return deleted == 1, nil
+}
+
+// IncrResetCounter 对给定的计数器加一并返回加一后的值，计数器在首次计数window时长后清零
+func (r *RedisRepo) IncrResetCounter(key string, window time.Duration) (int64, error) {
+	ctx := context.Background()
+	key = PASSWORD_RESET_COUNTER_KEY_PREFIX + key
+	count, err := r.client.Incr(ctx, key).Result()
+	if err == nil && count == 1 {
+		err = r.client.Expire(ctx, key, window).Err()
+	}
+	if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"更新重置密码的计数器时发生了错误:%v", err)
+	}
+
+	return count, nil
+}
+
+// UpdatePassword 更新已注册用户的密码以及注册信息中的密码，利用事务保证两者一并更新
+func (r *RedisRepo) UpdatePassword(username, password string, registerInfo []byte) error {
+	ctx := context.Background()
+	registered, err := r.client.HExists(ctx, PSWS_KEY, username).Result()
+	if err == nil && registered {
+		_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.HSet(ctx, PSWS_KEY, username, password)
+			p.HSet(ctx, REGISTER_INFO_KEY, username, hex.EncodeToString(registerInfo))
+			return nil
+		})
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"更新用户密码时发生了错误:%v", err)
+	} else if !registered {
+		return errors.New(400, "Repo_Error", "用户账号不存在")
+	}
+
+	return nil
+}
+
+// UpdateToken 更新已注册用户的token
+func (r *RedisRepo) UpdateToken(username, token string) error {
+	return r.updateRegistered(TOKENS_KEY, username, token)
+}
+
+// AppendResetAudit 追加重置密码的审计记录，并淘汰超出保留数量的最早的记录
+func (r *RedisRepo) AppendResetAudit(record []byte) error {
+	_, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.LPush(context.Background(), PASSWORD_RESET_AUDIT_KEY, record)
+		p.LTrim(context.Background(), PASSWORD_RESET_AUDIT_KEY, 0, passwordResetAuditRetention-1)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存重置密码的审计记录时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// 更新hash中已注册用户对应的值，用户不存在时返回错误
+func (r *RedisRepo) updateRegistered(key, username, value string) error {
+	ctx := context.Background()
+	registered, err := r.client.HExists(ctx, key, username).Result()
+	if err == nil && registered {
+		err = r.client.HSet(ctx, key, username, value).Err()
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"更新用户信息时发生了错误:%v", err)
+	} else if !registered {
+		return errors.New(400, "Repo_Error", "用户账号不存在")
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"gitee.com/moyusir/service-centre/internal/biz"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-redis/redis/v8"
+	"time"
 )
 
 const (
@@ -17,6 +18,26 @@ const (
 	CLIENT_CODE_KEY = "client_code"
 	// REGISTER_INFO_KEY 用户注册信息hash的key
 	REGISTER_INFO_KEY = "register_info"
+	// UPGRADES_KEY 镜像升级任务状态hash的key
+	UPGRADES_KEY = "upgrades"
+	// ROLLOUTS_KEY 用户服务最近一次部署或升级结果hash的key
+	ROLLOUTS_KEY = "rollouts"
+	// PLACEMENTS_KEY 用户服务所在集群hash的key
+	PLACEMENTS_KEY = "placements"
+	// SERVICE_IMAGES_KEY 最近一次成功的升级任务的目标镜像的key，新注册的用户使用该镜像
+	SERVICE_IMAGES_KEY = "service_images"
+	// GLOBAL_KEY_NAMESPACE 服务全局的带前缀的key所在的命名空间，其中的'/'不允许出现在用户名中，
+	// 使清理与备份用户时以"<用户名>:"为前缀匹配用户的key不会匹配到服务全局的key
+	GLOBAL_KEY_NAMESPACE = "sc/"
+	// USAGE_KEY_PREFIX 用户每日资源使用量hash的key前缀，完整的key为sc/usage:<用户名>，
+	// 位于全局的命名空间中，使计费数据在用户注销后仍被保留
+	USAGE_KEY_PREFIX = GLOBAL_KEY_NAMESPACE + "usage:"
+	// USAGE_USERS_KEY 存在资源使用量记录的用户set的key
+	USAGE_USERS_KEY = "usage_users"
+	// REQUEST_COUNTERS_KEY 上一次采集时用户在网关中累计请求数量hash的key
+	REQUEST_COUNTERS_KEY = "request_counters"
+	// METERING_LOCK_KEY 负责采集资源使用量的实例持有的锁的key，值为实例的标识
+	METERING_LOCK_KEY = "metering_lock"
 )
 
 // RedisRepo redis数据库操作对象，可以理解为dao
@@ -31,6 +52,20 @@ func NewRedisRepo(data *Data) biz.UserRepo {
 	}
 }
 
+// NewUpgradeRepo 实例化保存升级任务状态的redis数据库操作对象
+func NewUpgradeRepo(data *Data) biz.UpgradeRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// NewUsageRepo 实例化保存资源使用量的redis数据库操作对象
+func NewUsageRepo(data *Data) biz.UsageRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
 // Login 验证用户账号密码，正确时返回用户token
 // 用户的密码以用户账号-用户密码键值对的形式存储在hash中，用户的token也同样
 func (r *RedisRepo) Login(username, password string) (token string, err error) {
@@ -53,6 +88,16 @@ func (r *RedisRepo) Login(username, password string) (token string, err error) {
 
 // Register 用户注册，并保存用户token
 func (r *RedisRepo) Register(username, password, token string, info []byte) error {
+	// 用户账号不能与组织成员的账号重复
+	member, err := r.client.HExists(context.Background(), MEMBERS_KEY, username).Result()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"将用户信息保存到redis时发生了错误:%v", err)
+	} else if member {
+		return errors.New(400, "Repo_Error", "用户账号已经存在")
+	}
+
 	// 保存用户密码以及token，利用事务保证一并执行
 	cmders, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
 		p.HSetNX(context.Background(), PSWS_KEY, username, password)
@@ -80,6 +125,11 @@ func (r *RedisRepo) Register(username, password, token string, info []byte) erro
 	return nil
 }
 
+// UpdateRegisterInfo 更新已注册用户的注册信息
+func (r *RedisRepo) UpdateRegisterInfo(username string, info []byte) error {
+	return r.updateRegistered(REGISTER_INFO_KEY, username, hex.EncodeToString(info))
+}
+
 // GetRegisterInfo 获取用户注册信息
 func (r *RedisRepo) GetRegisterInfo(username string) ([]byte, error) {
 	result, err := r.client.HGet(context.Background(), REGISTER_INFO_KEY, username).Result()
@@ -101,6 +151,28 @@ func (r *RedisRepo) GetRegisterInfo(username string) ([]byte, error) {
 
 // UnRegister 注销账户，清除用户相关的所有redis key
 func (r *RedisRepo) UnRegister(username string) error {
+	// 删除用户作为组织的所有成员
+	if err := r.removeOrgMembers(username); err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户信息时发生了错误:%v", err)
+	}
+	// 删除用户关联的OIDC身份
+	if err := r.unlinkOidcSubject(username); err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户信息时发生了错误:%v", err)
+	}
+
+	// 在事务之外查询用户相关的key，事务中的命令在提交前不会返回结果，
+	// 包括设备配置信息、状态信息、警告信息等
+	keys, err := r.scanKeys(tenantKeyPattern(username))
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户信息时发生了错误:%v", err)
+	}
+
 	// 利用事务保证全部删除完毕
 	cmders, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
 		// 删除密码和token、注册信息以及客户端代码
@@ -108,11 +180,18 @@ func (r *RedisRepo) UnRegister(username string) error {
 		p.HDel(context.Background(), TOKENS_KEY, username)
 		p.HDel(context.Background(), REGISTER_INFO_KEY, username)
 		p.HDel(context.Background(), CLIENT_CODE_KEY, username)
+		p.HDel(context.Background(), ROLLOUTS_KEY, username)
+		p.HDel(context.Background(), PLACEMENTS_KEY, username)
+		p.HDel(context.Background(), REQUEST_COUNTERS_KEY, username)
+		p.HDel(context.Background(), WEBHOOKS_KEY, username)
+		p.HDel(context.Background(), TWO_FACTOR_KEY, username)
+		p.ZRem(context.Background(), PENDING_DELETIONS_KEY, username)
+		p.SRem(context.Background(), PURGING_DELETIONS_KEY, username)
+		p.SRem(context.Background(), ORG_ACL_KEY, username)
 
-		// 获得然后删除和用户相关的键，包括设备配置信息、状态信息、警告信息等
-		keys := p.Keys(context.Background(), username+"*").Val()
-		if len(keys) != 0 {
-			p.Del(context.Background(), keys...)
+		// 删除和用户相关的键
+		for _, key := range keys {
+			p.Del(context.Background(), key)
 		}
 
 		return nil
@@ -152,3 +231,258 @@ func (r *RedisRepo) GetClientCode(username string) ([]byte, error) {
 
 	return ret, nil
 }
+
+// ListUsers 以保存用户密码的hash中的所有field作为已注册用户的用户名
+func (r *RedisRepo) ListUsers() ([]string, error) {
+	users, err := r.client.HKeys(context.Background(), PSWS_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询已注册用户时发生了错误:%v", err)
+	}
+
+	return users, nil
+}
+
+// SaveRolloutOutcome 以十六进制字符串的形式保存用户服务最近一次部署或升级的结果
+func (r *RedisRepo) SaveRolloutOutcome(username string, outcome []byte) error {
+	err := r.client.HSet(
+		context.Background(), ROLLOUTS_KEY, username, hex.EncodeToString(outcome)).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户服务的部署结果时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetRolloutOutcome 获得用户服务最近一次部署或升级的结果，不存在时返回nil
+func (r *RedisRepo) GetRolloutOutcome(username string) ([]byte, error) {
+	result, err := r.client.HGet(context.Background(), ROLLOUTS_KEY, username).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得用户服务的部署结果时发生了错误:%v", err)
+	}
+
+	decodeString, err := hex.DecodeString(result)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"解码用户服务的部署结果时发生了错误:%v", err)
+	}
+
+	return decodeString, nil
+}
+
+// SaveServiceImages 保存新注册的用户使用的镜像
+func (r *RedisRepo) SaveServiceImages(images []byte) error {
+	err := r.client.Set(context.Background(), SERVICE_IMAGES_KEY, hex.EncodeToString(images), 0).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户服务使用的镜像时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetServiceImages 获得新注册的用户使用的镜像，不存在时返回nil
+func (r *RedisRepo) GetServiceImages() ([]byte, error) {
+	result, err := r.client.Get(context.Background(), SERVICE_IMAGES_KEY).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得用户服务使用的镜像时发生了错误:%v", err)
+	}
+
+	decodeString, err := hex.DecodeString(result)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"解码用户服务使用的镜像时发生了错误:%v", err)
+	}
+
+	return decodeString, nil
+}
+
+// SavePlacement 保存用户服务所在的集群
+func (r *RedisRepo) SavePlacement(username, cluster string) error {
+	err := r.client.HSet(context.Background(), PLACEMENTS_KEY, username, cluster).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户服务所在的集群时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetPlacement 获得用户服务所在的集群，不存在记录时返回空字符串
+func (r *RedisRepo) GetPlacement(username string) (string, error) {
+	cluster, err := r.client.HGet(context.Background(), PLACEMENTS_KEY, username).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Newf(
+			500, "Repo_Error",
+			"获得用户服务所在的集群时发生了错误:%v", err)
+	}
+
+	return cluster, nil
+}
+
+// ListPlacements 获得所有用户服务所在的集群，以用户名为键
+func (r *RedisRepo) ListPlacements() (map[string]string, error) {
+	placements, err := r.client.HGetAll(context.Background(), PLACEMENTS_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询用户服务所在的集群时发生了错误:%v", err)
+	}
+
+	return placements, nil
+}
+
+// SaveUpgradeStatus 以十六进制字符串的形式保存升级任务的状态
+func (r *RedisRepo) SaveUpgradeStatus(id string, status []byte) error {
+	err := r.client.HSet(
+		context.Background(), UPGRADES_KEY, id, hex.EncodeToString(status)).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存升级任务状态时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetUpgradeStatus 获得升级任务的状态
+func (r *RedisRepo) GetUpgradeStatus(id string) ([]byte, error) {
+	result, err := r.client.HGet(context.Background(), UPGRADES_KEY, id).Result()
+	if err == redis.Nil {
+		return nil, errors.NotFound("Repo_Error", "升级任务不存在")
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得升级任务状态时发生了错误:%v", err)
+	}
+
+	decodeString, err := hex.DecodeString(result)
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"解码升级任务状态时发生了错误:%v", err)
+	}
+
+	return decodeString, nil
+}
+
+// SaveUsage 以十六进制字符串的形式保存用户在某一天的资源使用量
+func (r *RedisRepo) SaveUsage(username, date string, record []byte) error {
+	err := r.client.HSet(
+		context.Background(), USAGE_KEY_PREFIX+username, date, hex.EncodeToString(record)).Err()
+	if err == nil {
+		err = r.client.SAdd(context.Background(), USAGE_USERS_KEY, username).Err()
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户的资源使用量时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetUsage 获得用户在给定日期的资源使用量，没有记录的日期对应的元素为nil
+func (r *RedisRepo) GetUsage(username string, dates []string) ([][]byte, error) {
+	values, err := r.client.HMGet(context.Background(), USAGE_KEY_PREFIX+username, dates...).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得用户的资源使用量时发生了错误:%v", err)
+	}
+
+	result := make([][]byte, len(values))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		result[i], err = hex.DecodeString(s)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Repo_Error",
+				"解码用户的资源使用量时发生了错误:%v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// ListUsageUsers 获得所有存在资源使用量记录的用户
+func (r *RedisRepo) ListUsageUsers() ([]string, error) {
+	users, err := r.client.SMembers(context.Background(), USAGE_USERS_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询存在资源使用量记录的用户时发生了错误:%v", err)
+	}
+
+	return users, nil
+}
+
+// GetRequestCounter 获得上一次采集时用户在网关中累计的请求数量，不存在时exists为false
+func (r *RedisRepo) GetRequestCounter(username string) (counter int64, exists bool, err error) {
+	counter, err = r.client.HGet(context.Background(), REQUEST_COUNTERS_KEY, username).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, errors.Newf(
+			500, "Repo_Error",
+			"获得用户的网关请求数量时发生了错误:%v", err)
+	}
+
+	return counter, true, nil
+}
+
+// SaveRequestCounter 保存本次采集时用户在网关中累计的请求数量
+func (r *RedisRepo) SaveRequestCounter(username string, counter int64) error {
+	err := r.client.HSet(context.Background(), REQUEST_COUNTERS_KEY, username, counter).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户的网关请求数量时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// 锁由owner持有时延长其有效期，锁不存在时由owner获得，返回owner是否持有锁
+var acquireLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// AcquireMeteringLock 获得或者续期采集资源使用量的锁，返回owner是否持有锁
+func (r *RedisRepo) AcquireMeteringLock(owner string, ttl time.Duration) (bool, error) {
+	held, err := acquireLockScript.Run(context.Background(), r.client,
+		[]string{METERING_LOCK_KEY}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"获得采集资源使用量的锁时发生了错误:%v", err)
+	}
+
+	return held == 1, nil
+}
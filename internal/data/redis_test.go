@@ -1,13 +1,14 @@
 package data
 
 import (
+	"context"
 	"gitee.com/moyusir/service-centre/internal/conf"
 	"github.com/go-kratos/kratos/v2/log"
 	"testing"
 )
 
 func TestRedisRepo(t *testing.T) {
-	bootstrap, err := conf.LoadConfig("../../configs/config.yaml")
+	bootstrap, err := conf.LoadConfig("../../configs/config.yaml", log.DefaultLogger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -27,13 +28,13 @@ func TestRedisRepo(t *testing.T) {
 
 	// 测试注册
 	t.Run("Register", func(t *testing.T) {
-		err := redisRepo.Register(username, password, token)
+		err := redisRepo.Register(username, password, token, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// 测试利用相同账号重复注册
-		err = redisRepo.Register(username, password, token)
+		err = redisRepo.Register(username, password, token, nil)
 		if err == nil {
 			t.Fatal("允许了相同的账号注册")
 		}
@@ -64,4 +65,90 @@ func TestRedisRepo(t *testing.T) {
 			t.Fatal("注销功能失效")
 		}
 	})
+
+	// 测试用户名有相同前缀的用户的数据互不影响
+	t.Run("TenantKeys", func(t *testing.T) {
+		tenants := []string{"prefix", "prefix_2"}
+		for _, tenant := range tenants {
+			if err := redisRepo.Register(tenant, password, tenant, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := data.Set(context.Background(), tenant+":state", tenant, 0).Err(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Cleanup(func() {
+			for _, tenant := range tenants {
+				redisRepo.UnRegister(tenant)
+			}
+		})
+
+		snapshot, err := NewBackupRepo(data).SnapshotTenant("prefix")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := snapshot.Keys["prefix_2:state"]; ok || len(snapshot.Keys) != 1 {
+			t.Fatalf("快照中只应包括用户自身的key:%v", snapshot.Keys)
+		}
+
+		if err := redisRepo.UnRegister("prefix"); err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := data.Exists(context.Background(), "prefix:state").Result(); n != 0 {
+			t.Fatal("注销后应删除用户自身的key")
+		}
+		if n, _ := data.Exists(context.Background(), "prefix_2:state").Result(); n != 1 {
+			t.Fatal("注销不应删除用户名以其为前缀的其他用户的key")
+		}
+	})
+
+	// 测试用户名与服务全局的key的前缀相同时，注销不会删除服务全局的key
+	t.Run("ReservedPrefix", func(t *testing.T) {
+		if err := redisRepo.Register("usage", password, "usage", nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewUsageRepo(data).SaveUsage("other", "2026-10-19", []byte("usage")); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			redisRepo.UnRegister("usage")
+			data.Del(context.Background(), USAGE_KEY_PREFIX+"other")
+			data.SRem(context.Background(), USAGE_USERS_KEY, "other")
+		})
+
+		if err := redisRepo.UnRegister("usage"); err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := data.Exists(context.Background(), USAGE_KEY_PREFIX+"other").Result(); n != 1 {
+			t.Fatal("注销名为usage的用户不应删除其他用户的资源使用量")
+		}
+	})
+
+	// 测试多个实例发送同一批事件后重复删除
+	t.Run("RemoveEvents", func(t *testing.T) {
+		outbox := NewEventOutbox(data)
+		t.Cleanup(func() { data.Del(context.Background(), EVENT_OUTBOX_KEY) })
+		for _, e := range []string{"a", "b", "c"} {
+			if err := outbox.AppendEvent([]byte(e)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sent, err := outbox.PeekEvents(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := outbox.RemoveEvents(sent); err != nil {
+				t.Fatal(err)
+			}
+		}
+		remaining, err := outbox.PeekEvents(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(remaining) != 1 || string(remaining[0]) != "c" {
+			t.Fatalf("重复删除已发送的事件时不应删除其余的事件:%q", remaining)
+		}
+	})
 }
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+const (
+	// VM_ACCOUNTS_KEY 用户在victoriametrics集群中的租户id的hash，以用户名为键
+	VM_ACCOUNTS_KEY = "vm_accounts"
+	// VM_ACCOUNT_SEQUENCE_KEY 分配租户id的计数器，租户id由1开始分配
+	VM_ACCOUNT_SEQUENCE_KEY = "vm_account_sequence"
+	// VM_CREDENTIALS_KEY_PREFIX 用户经由vmauth访问其租户数据的凭证的hash的键前缀，以凭证id为键
+	VM_CREDENTIALS_KEY_PREFIX = "vm_credentials:"
+)
+
+// NewTimeSeriesAccountRepo 实例化保存victoriametrics租户id与凭证的redis数据库操作对象
+func NewTimeSeriesAccountRepo(data *Data) biz.TimeSeriesAccountRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// GetAccountID 获得用户的租户id，未分配时返回0
+func (r *RedisRepo) GetAccountID(username string) (uint32, error) {
+	id, err := r.client.HGet(context.Background(), VM_ACCOUNTS_KEY, username).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"查询用户的victoriametrics租户id时发生了错误:%v", err)
+	}
+
+	return uint32(id), nil
+}
+
+// AllocateAccountID 为用户分配租户id，已分配时返回已有的id，并发分配时以先写入的id为准
+func (r *RedisRepo) AllocateAccountID(username string) (uint32, error) {
+	id, err := r.GetAccountID(username)
+	if err != nil || id != 0 {
+		return id, err
+	}
+
+	next, err := r.client.Incr(context.Background(), VM_ACCOUNT_SEQUENCE_KEY).Result()
+	if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"分配victoriametrics租户id时发生了错误:%v", err)
+	}
+	if next > 1<<32-1 {
+		return 0, errors.New(500, "Repo_Error", "victoriametrics的租户id已经耗尽")
+	}
+	ok, err := r.client.HSetNX(context.Background(), VM_ACCOUNTS_KEY, username, next).Result()
+	if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"保存用户的victoriametrics租户id时发生了错误:%v", err)
+	}
+	if !ok {
+		return r.GetAccountID(username)
+	}
+
+	return uint32(next), nil
+}
+
+// DeleteAccountID 删除用户的租户id，已分配的id不会被重新分配
+func (r *RedisRepo) DeleteAccountID(username string) error {
+	err := r.client.HDel(context.Background(), VM_ACCOUNTS_KEY, username).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户的victoriametrics租户id时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// ListAccountIDs 获得所有已分配的租户id，以用户名为键
+func (r *RedisRepo) ListAccountIDs() (map[string]uint32, error) {
+	values, err := r.client.HGetAll(context.Background(), VM_ACCOUNTS_KEY).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询victoriametrics租户id时发生了错误:%v", err)
+	}
+
+	accounts := make(map[string]uint32, len(values))
+	for username, value := range values {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, errors.Newf(
+				500, "Repo_Error",
+				"用户 %v 的victoriametrics租户id %q 无效", username, value)
+		}
+		accounts[username] = uint32(id)
+	}
+	return accounts, nil
+}
+
+// SaveCredential 保存用户经由vmauth访问其租户数据的凭证
+func (r *RedisRepo) SaveCredential(username, id, token string) error {
+	err := r.client.HSet(context.Background(), VM_CREDENTIALS_KEY_PREFIX+username, id, token).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户的victoriametrics凭证时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// ListCredentials 获得用户所有的凭证，以凭证id为键
+func (r *RedisRepo) ListCredentials(username string) (map[string]string, error) {
+	credentials, err := r.client.HGetAll(context.Background(), VM_CREDENTIALS_KEY_PREFIX+username).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询用户的victoriametrics凭证时发生了错误:%v", err)
+	}
+
+	return credentials, nil
+}
+
+// DeleteCredentials 删除用户的指定凭证，ids为空时删除用户所有的凭证
+func (r *RedisRepo) DeleteCredentials(username string, ids ...string) error {
+	var err error
+	if len(ids) == 0 {
+		err = r.client.Del(context.Background(), VM_CREDENTIALS_KEY_PREFIX+username).Err()
+	} else {
+		err = r.client.HDel(context.Background(), VM_CREDENTIALS_KEY_PREFIX+username, ids...).Err()
+	}
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除用户的victoriametrics凭证时发生了错误:%v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const (
+	// TWO_FACTOR_KEY 账号二次验证信息hash的key，以用户或成员的账号为键
+	TWO_FACTOR_KEY = "two_factor"
+	// TWO_FACTOR_FAILURES_KEY_PREFIX 账号连续验证失败次数的key前缀，完整的key为two_factor_failures:<账号>
+	TWO_FACTOR_FAILURES_KEY_PREFIX = "two_factor_failures:"
+)
+
+// NewTwoFactorRepo 实例化保存二次验证信息的redis数据库操作对象
+func NewTwoFactorRepo(data *Data) biz.TwoFactorRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveTwoFactor 保存账号的二次验证信息
+func (r *RedisRepo) SaveTwoFactor(username string, record []byte) error {
+	err := r.client.HSet(context.Background(), TWO_FACTOR_KEY, username, record).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存账号的二次验证信息时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetTwoFactor 获得账号的二次验证信息，不存在时返回nil
+func (r *RedisRepo) GetTwoFactor(username string) ([]byte, error) {
+	record, err := r.client.HGet(context.Background(), TWO_FACTOR_KEY, username).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询账号的二次验证信息时发生了错误:%v", err)
+	}
+
+	return record, nil
+}
+
+// hash中的值仍为ARGV[2]时将其更新为ARGV[3]，返回是否更新
+var updateTwoFactorScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// UpdateTwoFactor 账号的二次验证信息仍为previous时将其更新为record，返回false时表示已被并发修改
+func (r *RedisRepo) UpdateTwoFactor(username string, previous, record []byte) (bool, error) {
+	updated, err := updateTwoFactorScript.Run(context.Background(), r.client,
+		[]string{TWO_FACTOR_KEY}, username, previous, record).Int()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"更新账号的二次验证信息时发生了错误:%v", err)
+	}
+
+	return updated == 1, nil
+}
+
+// RemoveTwoFactor 删除账号的二次验证信息
+func (r *RedisRepo) RemoveTwoFactor(username string) error {
+	_, err := r.client.TxPipelined(context.Background(), func(p redis.Pipeliner) error {
+		p.HDel(context.Background(), TWO_FACTOR_KEY, username)
+		p.Del(context.Background(), TWO_FACTOR_FAILURES_KEY_PREFIX+username)
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"删除账号的二次验证信息时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// IncrTwoFactorFailures 对账号连续验证失败的次数加一并返回加一后的值，计数在首次失败lockout时长后清零
+func (r *RedisRepo) IncrTwoFactorFailures(username string, lockout time.Duration) (int64, error) {
+	ctx := context.Background()
+	key := TWO_FACTOR_FAILURES_KEY_PREFIX + username
+	count, err := r.client.Incr(ctx, key).Result()
+	if err == nil && count == 1 {
+		err = r.client.Expire(ctx, key, lockout).Err()
+	}
+	if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"更新账号验证失败的次数时发生了错误:%v", err)
+	}
+
+	return count, nil
+}
+
+// GetTwoFactorFailures 获得账号连续验证失败的次数
+func (r *RedisRepo) GetTwoFactorFailures(username string) (int64, error) {
+	count, err := r.client.Get(context.Background(), TWO_FACTOR_FAILURES_KEY_PREFIX+username).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Newf(
+			500, "Repo_Error",
+			"查询账号验证失败的次数时发生了错误:%v", err)
+	}
+
+	return count, nil
+}
+
+// ResetTwoFactorFailures 验证通过后清零账号连续验证失败的次数
+func (r *RedisRepo) ResetTwoFactorFailures(username string) error {
+	err := r.client.Del(context.Background(), TWO_FACTOR_FAILURES_KEY_PREFIX+username).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"清零账号验证失败的次数时发生了错误:%v", err)
+	}
+
+	return nil
+}
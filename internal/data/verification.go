@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// PENDING_ACCOUNT_KEY_PREFIX 等待邮箱验证的账号的key前缀，完整的key为pending_account:<用户名>，
+// 账号依靠key的过期时间自动删除
+const PENDING_ACCOUNT_KEY_PREFIX = "pending_account:"
+
+// PENDING_ACCOUNT_CLAIM_KEY_PREFIX 正在为其创建服务的等待验证账号的key前缀，完整的key为pending_account_claim:<用户名>，
+// 保证同一账号的验证链接同时只被使用一次
+const PENDING_ACCOUNT_CLAIM_KEY_PREFIX = "pending_account_claim:"
+
+// NewVerificationRepo 实例化保存等待邮箱验证账号的redis数据库操作对象
+func NewVerificationRepo(data *Data) biz.VerificationRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SavePendingAccount 保存等待验证的账号，账号在ttl后自动删除
+func (r *RedisRepo) SavePendingAccount(username string, account []byte, ttl time.Duration) error {
+	ctx := context.Background()
+	registered, err := r.client.HExists(ctx, PSWS_KEY, username).Result()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存等待验证的账号时发生了错误:%v", err)
+	} else if registered {
+		return errors.New(400, "Repo_Error", "用户账号已经存在")
+	}
+
+	saved, err := r.client.SetNX(ctx, PENDING_ACCOUNT_KEY_PREFIX+username, account, ttl).Result()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存等待验证的账号时发生了错误:%v", err)
+	} else if !saved {
+		return errors.New(400, "Repo_Error", "用户账号正在等待邮箱验证")
+	}
+
+	return nil
+}
+
+// GetPendingAccount 获得等待验证的账号，不存在或已过期时返回nil
+func (r *RedisRepo) GetPendingAccount(username string) ([]byte, error) {
+	result, err := r.client.Get(context.Background(), PENDING_ACCOUNT_KEY_PREFIX+username).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得等待验证的账号时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// RemovePendingAccount 删除等待验证的账号及其认领记录，返回false时表示账号已被删除
+func (r *RedisRepo) RemovePendingAccount(username string) (bool, error) {
+	ctx := context.Background()
+	deleted, err := r.client.Del(ctx, PENDING_ACCOUNT_KEY_PREFIX+username).Result()
+	if err == nil {
+		err = r.client.Del(ctx, PENDING_ACCOUNT_CLAIM_KEY_PREFIX+username).Err()
+	}
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"删除等待验证的账号时发生了错误:%v", err)
+	}
+
+	return deleted == 1, nil
+}
+
+// ClaimPendingAccount 认领等待验证的账号，认领在ttl后自动失效，返回false时表示账号已被认领
+func (r *RedisRepo) ClaimPendingAccount(username string, ttl time.Duration) (bool, error) {
+	claimed, err := r.client.SetNX(context.Background(), PENDING_ACCOUNT_CLAIM_KEY_PREFIX+username, 1, ttl).Result()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"认领等待验证的账号时发生了错误:%v", err)
+	}
+
+	return claimed, nil
+}
+
+// ReleasePendingAccount 释放对等待验证的账号的认领
+func (r *RedisRepo) ReleasePendingAccount(username string) error {
+	err := r.client.Del(context.Background(), PENDING_ACCOUNT_CLAIM_KEY_PREFIX+username).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"释放等待验证的账号时发生了错误:%v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package data
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+const (
+	// WEBHOOKS_KEY 用户订阅的webhook hash的key
+	WEBHOOKS_KEY = "webhooks"
+	// WEBHOOK_DELIVERIES_KEY webhook投递记录hash的key
+	WEBHOOK_DELIVERIES_KEY = "webhook_deliveries"
+	// WEBHOOK_DELIVERY_LOG_KEY 以创建时间为分数的投递记录id zset的key，用于按时间顺序查询以及淘汰投递记录
+	WEBHOOK_DELIVERY_LOG_KEY = "webhook_delivery_log"
+	// WEBHOOK_QUEUE_KEY 以下一次投递时间为分数的待投递id zset的key
+	WEBHOOK_QUEUE_KEY = "webhook_queue"
+	// 最多保留的投递记录数量，超出时淘汰最早的记录
+	webhookDeliveryRetention = 10000
+)
+
+// NewWebhookRepo 实例化保存webhook订阅以及投递记录的redis数据库操作对象
+func NewWebhookRepo(data *Data) biz.WebhookRepo {
+	return &RedisRepo{
+		client: data,
+	}
+}
+
+// SaveWebhooks 保存用户订阅的webhook
+func (r *RedisRepo) SaveWebhooks(username string, webhooks []byte) error {
+	err := r.client.HSet(context.Background(), WEBHOOKS_KEY, username, webhooks).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存用户订阅的webhook时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// GetWebhooks 获得用户订阅的webhook，不存在时返回nil
+func (r *RedisRepo) GetWebhooks(username string) ([]byte, error) {
+	result, err := r.client.HGet(context.Background(), WEBHOOKS_KEY, username).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得用户订阅的webhook时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// SaveDelivery 保存投递记录，并淘汰超出保留数量的最早的记录
+func (r *RedisRepo) SaveDelivery(id string, createTime time.Time, delivery []byte) error {
+	ctx := context.Background()
+	_, err := r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, WEBHOOK_DELIVERIES_KEY, id, delivery)
+		p.ZAddNX(ctx, WEBHOOK_DELIVERY_LOG_KEY, &redis.Z{
+			Score:  float64(createTime.UnixNano()),
+			Member: id,
+		})
+		return nil
+	})
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"保存webhook的投递记录时发生了错误:%v", err)
+	}
+
+	expired, err := r.client.ZRange(ctx, WEBHOOK_DELIVERY_LOG_KEY, 0, -webhookDeliveryRetention-1).Result()
+	if err != nil || len(expired) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(expired))
+	for i, e := range expired {
+		members[i] = e
+	}
+	r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HDel(ctx, WEBHOOK_DELIVERIES_KEY, expired...)
+		p.ZRem(ctx, WEBHOOK_DELIVERY_LOG_KEY, members...)
+		p.ZRem(ctx, WEBHOOK_QUEUE_KEY, members...)
+		return nil
+	})
+
+	return nil
+}
+
+// GetDelivery 获得投递记录，不存在时返回nil
+func (r *RedisRepo) GetDelivery(id string) ([]byte, error) {
+	result, err := r.client.HGet(context.Background(), WEBHOOK_DELIVERIES_KEY, id).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"获得webhook的投递记录时发生了错误:%v", err)
+	}
+
+	return result, nil
+}
+
+// ListDeliveries 按创建时间倒序获得从offset开始的至多count条投递记录
+func (r *RedisRepo) ListDeliveries(offset, count int64) ([][]byte, error) {
+	ctx := context.Background()
+	ids, err := r.client.ZRevRange(ctx, WEBHOOK_DELIVERY_LOG_KEY, offset, offset+count-1).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询webhook的投递记录时发生了错误:%v", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	result, err := r.client.HMGet(ctx, WEBHOOK_DELIVERIES_KEY, ids...).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询webhook的投递记录时发生了错误:%v", err)
+	}
+
+	deliveries := make([][]byte, 0, len(result))
+	for _, d := range result {
+		// 记录可能在两次查询之间被淘汰
+		if s, ok := d.(string); ok {
+			deliveries = append(deliveries, []byte(s))
+		}
+	}
+	return deliveries, nil
+}
+
+// ScheduleDelivery 将投递加入待投递队列，在at之后投递
+func (r *RedisRepo) ScheduleDelivery(id string, at time.Time) error {
+	err := r.client.ZAdd(context.Background(), WEBHOOK_QUEUE_KEY, &redis.Z{
+		Score:  float64(at.Unix()),
+		Member: id,
+	}).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"将webhook投递加入待投递队列时发生了错误:%v", err)
+	}
+
+	return nil
+}
+
+// DueDeliveries 获得待投递队列中至多limit个到期的投递的id
+func (r *RedisRepo) DueDeliveries(now time.Time, limit int64) ([]string, error) {
+	ids, err := r.client.ZRangeByScore(context.Background(), WEBHOOK_QUEUE_KEY, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, errors.Newf(
+			500, "Repo_Error",
+			"查询到期的webhook投递时发生了错误:%v", err)
+	}
+
+	return ids, nil
+}
+
+// 投递的下一次投递时间不晚于ARGV[2]时，将其推迟到ARGV[3]，返回是否推迟
+var leaseDeliveryScript = redis.NewScript(`
+local at = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if at and tonumber(at) <= tonumber(ARGV[2]) then
+	redis.call("ZADD", KEYS[1], ARGV[3], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// LeaseDelivery 投递仍到期时，将其下一次投递时间推迟到until，返回false时表示投递已被其他实例取走
+func (r *RedisRepo) LeaseDelivery(id string, now, until time.Time) (bool, error) {
+	leased, err := leaseDeliveryScript.Run(context.Background(), r.client,
+		[]string{WEBHOOK_QUEUE_KEY}, id, now.Unix(), until.Unix()).Int()
+	if err != nil {
+		return false, errors.Newf(
+			500, "Repo_Error",
+			"从待投递队列中取出webhook投递时发生了错误:%v", err)
+	}
+
+	return leased == 1, nil
+}
+
+// CompleteDelivery 将投递从待投递队列中移除
+func (r *RedisRepo) CompleteDelivery(id string) error {
+	err := r.client.ZRem(context.Background(), WEBHOOK_QUEUE_KEY, id).Err()
+	if err != nil {
+		return errors.Newf(
+			500, "Repo_Error",
+			"将webhook投递移出待投递队列时发生了错误:%v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"gitee.com/moyusir/service-centre/internal/biz/audit"
+	"gitee.com/moyusir/service-centre/internal/service"
+	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 以静态管理令牌调用管理接口的操作者名称
+const adminActor = "admin"
+
+// 在ctx中保存管理员身份的key
+type auditActorKey struct{}
+
+// 由AdminAuth在通过校验后填写的管理员身份，Audit在外层执行，因此通过指针获得内层中间件写入的身份
+type auditActor struct {
+	name string
+}
+
+// Audit 为租户与管理员的每次请求保存审计记录的中间件，记录操作者、来源ip、操作以及结果，
+// 不记录请求的内容，需要在校验请求与AdminAuth之前执行，使被拒绝的请求同样留下审计记录
+func Audit(audits *biz.AuditUsecase, orgs *biz.OrganizationUsecase) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			ctx = context.WithValue(ctx, auditActorKey{}, new(auditActor))
+			reply, err = handler(ctx, req)
+
+			entry := &audit.Entry{
+				Time:    time.Now().UTC(),
+				IP:      service.ClientIP(ctx),
+				Action:  auditAction(tr.Operation()),
+				Outcome: audit.OutcomeSucceeded,
+			}
+			if err != nil {
+				entry.Outcome = audit.OutcomeFailed
+				entry.Message = err.Error()
+			}
+			entry.Actor, entry.Tenant = auditSubject(ctx, tr.Operation(), req, orgs)
+			if r, ok := req.(interface{ GetMemberId() string }); ok && r.GetMemberId() != "" {
+				entry.Targets = []string{r.GetMemberId()}
+			} else if r, ok := req.(interface{ GetId() string }); ok && r.GetId() != "" {
+				entry.Targets = []string{r.GetId()}
+			}
+			audits.Record(entry)
+
+			return reply, err
+		}
+	}
+}
+
+// 获得请求的操作者以及涉及的租户，管理员的身份由AdminAuth保存在ctx中，
+// 租户的身份来自请求中的账号或token
+func auditSubject(ctx context.Context, operation string, req interface{},
+	orgs *biz.OrganizationUsecase) (actor, tenant string) {
+	if a, ok := ctx.Value(auditActorKey{}).(*auditActor); ok {
+		actor = a.name
+	}
+
+	switch r := req.(type) {
+	case *utilApi.User:
+		tenant = r.GetId()
+	case *v1.UnregisterRequest:
+		tenant = r.GetId()
+	case *v1.RegisterRequest:
+		tenant = r.GetUser().GetId()
+	case *v1.AcceptInvitationRequest:
+		if actor == "" {
+			actor = r.GetUser().GetId()
+		}
+	case interface{ GetUsername() string }:
+		tenant = r.GetUsername()
+	}
+
+	// 邮箱验证的令牌不是api密钥，无法获得对应的成员
+	if t, ok := req.(interface{ GetToken() string }); ok && t.GetToken() != "" &&
+		operation != "/api.serviceCentre.v1.User/VerifyEmail" {
+		if member, org := orgs.Identify(t.GetToken()); member != "" {
+			if actor == "" {
+				actor = member
+			}
+			tenant = org
+		}
+	}
+
+	if actor == "" {
+		actor = tenant
+	}
+	return actor, tenant
+}
+
+// 将操作名称转换为审计记录中的操作，如/api.serviceCentre.v1.User/ConfirmPasswordReset
+// 转换为user.confirm_password_reset
+func auditAction(operation string) string {
+	operation = strings.TrimPrefix(operation, "/api.serviceCentre.v1.")
+	builder := new(strings.Builder)
+	for i, r := range operation {
+		switch {
+		case r == '/':
+			builder.WriteByte('.')
+		case unicode.IsUpper(r):
+			if i > 0 && operation[i-1] != '/' {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// AuditHandler 为不经过中间件的http处理函数(如推送日志的websocket)保存审计记录，
+// 操作者与租户由query参数中的token获得，请求的状态码不低于400时记录为失败
+func AuditHandler(audits *biz.AuditUsecase, orgs *biz.OrganizationUsecase,
+	action string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		start := time.Now().UTC()
+		handler(recorder, r)
+
+		entry := &audit.Entry{
+			Time:    start,
+			IP:      service.ClientIP(r.Context()),
+			Action:  action,
+			Outcome: audit.OutcomeSucceeded,
+		}
+		if recorder.status >= http.StatusBadRequest {
+			entry.Outcome = audit.OutcomeFailed
+			entry.Message = http.StatusText(recorder.status)
+		}
+		entry.Actor, entry.Tenant = orgs.Identify(r.URL.Query().Get("token"))
+		audits.Record(entry)
+	}
+}
+
+// 记录响应状态码的ResponseWriter，连接被websocket接管时视为成功
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter不支持接管连接")
+	}
+	if s.status == 0 {
+		s.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
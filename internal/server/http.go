@@ -2,6 +2,7 @@ package server
 
 import (
 	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz"
 	"gitee.com/moyusir/service-centre/internal/conf"
 	"gitee.com/moyusir/service-centre/internal/service"
 	"github.com/go-kratos/kratos/v2/log"
@@ -13,20 +14,44 @@ import (
 )
 
 // NewHTTPServer new a HTTP server.
-func NewHTTPServer(c *conf.Server, us *service.UserService, logger log.Logger) *http.Server {
+func NewHTTPServer(c *conf.Server, us *service.UserService, as *service.AdminService,
+	oidc *biz.OidcUsecase, audits *biz.AuditUsecase, orgs *biz.OrganizationUsecase, logger log.Logger) (*http.Server, error) {
+	var adminToken string
+	if c.Admin != nil {
+		adminToken = c.Admin.Token
+	}
+	trusted, err := service.ParseTrustedProxies(c.Http.GetTrustedProxies())
+	if err != nil {
+		return nil, err
+	}
+
 	var opts = []http.ServerOption{
 		http.Middleware(
 			recovery.Recovery(
 				recovery.WithLogger(logger),
 			),
 			logging.Server(logger),
+			// 记录每次请求的审计记录，需要在校验请求与管理令牌之前，使被拒绝的请求同样被记录
+			Audit(audits, orgs),
+			// 以OIDC身份注册时，在校验前填充用户信息
+			selector.Server(
+				OidcRegistration(oidc)).
+				Path("/api.serviceCentre.v1.User/Register").
+				Build(),
 			validate.Validator(),
 			// 添加单独校验注册信息的中间件
 			selector.Server(
 				RegisterValidator()).
 				Path("/api.serviceCentre.v1.User/Register").
 				Build(),
+			// 添加校验管理令牌的中间件
+			selector.Server(
+				AdminAuth(adminToken, oidc)).
+				Prefix("/api.serviceCentre.v1.Admin/").
+				Build(),
 		),
+		// 在所有路由之前获得客户端ip，websocket的处理函数同样需要
+		http.Filter(service.ClientIPFilter(trusted)),
 		http.ResponseEncoder(MyResponseEncoder),
 	}
 	if c.Http.Network != "" {
@@ -41,5 +66,11 @@ func NewHTTPServer(c *conf.Server, us *service.UserService, logger log.Logger) *
 	srv := http.NewServer(opts...)
 
 	v1.RegisterUserHTTPServer(srv, us)
-	return srv
+	v1.RegisterAdminHTTPServer(srv, as)
+	// 用户服务的日志以websocket的形式推送，无法通过proto定义，因此单独注册
+	srv.HandleFunc("/users/logs", AuditHandler(audits, orgs, "user.stream_logs", us.StreamLogs))
+	srv.HandleFunc("/users/export/{id}", AuditHandler(audits, orgs, "user.download_export", us.DownloadExport))
+	// vmauth以-auth.config定期拉取鉴权配置，不记录审计日志
+	srv.HandleFunc("/timeseries/vmauth/config", us.VMAuthConfig)
+	return srv, nil
 }
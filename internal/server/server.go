@@ -2,17 +2,20 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"gitee.com/moyusir/service-centre/internal/biz"
 	utilApi "gitee.com/moyusir/util/api/util/v1"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
 	h "github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/google/wire"
 	"net/http"
 )
 
 // ProviderSet is server providers.
-var ProviderSet = wire.NewSet(NewHTTPServer)
+var ProviderSet = wire.NewSet(NewHTTPServer, NewWorkerServer)
 
 // MyResponseEncoder 用于处理文件响应的响应编码器
 func MyResponseEncoder(respWriter http.ResponseWriter, req *http.Request, v interface{}) error {
@@ -117,3 +120,51 @@ func RegisterValidator() middleware.Middleware {
 		}
 	}
 }
+
+// OidcRegistration 以OIDC注册凭证对应的身份填充注册请求中的用户信息的中间件，
+// 需要在校验请求的中间件之前执行，使以OIDC身份注册时不需要填写用户的密码
+func OidcRegistration(oidc *biz.OidcUsecase) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			if registerReq, ok := req.(*v1.RegisterRequest); ok && registerReq.OidcTicket != "" {
+				if err := oidc.PrepareRegister(registerReq); err != nil {
+					return nil, err
+				}
+			}
+
+			return handler(ctx, req)
+		}
+	}
+}
+
+// AdminAuth 用于验证管理接口调用者的中间件，要求请求在X-Admin-Token请求头中携带配置的管理令牌，
+// 或管理员通过OIDC登录获得的管理令牌，未配置管理令牌且未开启OIDC登录时拒绝所有管理接口的调用
+func AdminAuth(token string, oidc *biz.OidcUsecase) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return nil, errors.Unauthorized("missing transport", "")
+			}
+
+			adminToken := tr.RequestHeader().Get("X-Admin-Token")
+			valid := token != "" && subtle.ConstantTimeCompare([]byte(adminToken), []byte(token)) == 1
+			actor := adminActor
+			if !valid {
+				subject, ok := oidc.AdminSession(adminToken)
+				if !ok {
+					return nil, errors.Unauthorized(
+						"invalid admin token",
+						"The X-Admin-Token header is missing or invalid")
+				}
+				actor = adminActor + ":" + subject
+			}
+
+			// 记录管理员的身份，供审计中间件使用
+			if a, ok := ctx.Value(auditActorKey{}).(*auditActor); ok {
+				a.name = actor
+			}
+			return handler(ctx, req)
+		}
+	}
+}
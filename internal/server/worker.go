@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+	"sync"
+)
+
+// Worker 在后台持续运行的任务，ctx被取消时需要退出
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// WorkerServer 将后台任务包装为kratos的transport.Server，使其随应用一同启动与停止
+type WorkerServer struct {
+	workers []Worker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewWorkerServer new a worker server.
+func NewWorkerServer(
+	metering *biz.MeteringUsecase, events *biz.EventPublisher, webhooks *biz.WebhookUsecase,
+	deletion *biz.DeletionUsecase, orgs *biz.OrganizationUsecase, exports *biz.ExportUsecase) *WorkerServer {
+	return &WorkerServer{workers: []Worker{metering, events, webhooks, deletion, orgs, exports}}
+}
+
+// Start 启动所有后台任务
+func (s *WorkerServer) Start(ctx context.Context) error {
+	// 后台任务的生命周期由Stop控制，因此不沿用启动时的ctx
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, w := range s.workers {
+		s.wg.Add(1)
+		go func(w Worker) {
+			defer s.wg.Done()
+			w.Run(runCtx)
+		}(w)
+	}
+	return nil
+}
+
+// Stop 停止所有后台任务，并等待其退出
+func (s *WorkerServer) Stop(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
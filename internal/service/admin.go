@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz"
+
+	pb "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AdminService struct {
+	pb.UnimplementedAdminServer
+	upgrade   *biz.UpgradeUsecase
+	metering  *biz.MeteringUsecase
+	webhooks  *biz.WebhookUsecase
+	audits    *biz.AuditUsecase
+	retention *biz.RetentionUsecase
+	backups   *biz.BackupUsecase
+	tokens    *biz.TenantTokenUsecase
+}
+
+func NewAdminService(upgrade *biz.UpgradeUsecase, metering *biz.MeteringUsecase,
+	webhooks *biz.WebhookUsecase, audits *biz.AuditUsecase, retention *biz.RetentionUsecase,
+	backups *biz.BackupUsecase, tokens *biz.TenantTokenUsecase) *AdminService {
+	return &AdminService{
+		upgrade: upgrade, metering: metering, webhooks: webhooks, audits: audits, retention: retention,
+		backups: backups, tokens: tokens,
+	}
+}
+
+func (s *AdminService) UpgradeFleet(ctx context.Context, req *pb.UpgradeFleetRequest) (*pb.UpgradeFleetReply, error) {
+	id, err := s.upgrade.UpgradeFleet(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpgradeFleetReply{Id: id}, nil
+}
+
+func (s *AdminService) GetUpgradeStatus(ctx context.Context, req *pb.GetUpgradeStatusRequest) (*pb.UpgradeStatus, error) {
+	return s.upgrade.GetUpgradeStatus(req.Id)
+}
+
+func (s *AdminService) ExportUsage(ctx context.Context, req *pb.ExportUsageRequest) (*pb.File, error) {
+	return s.metering.ExportUsage(req.Username, req.StartDate, req.EndDate, req.Format)
+}
+
+func (s *AdminService) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesReply, error) {
+	deliveries, err := s.webhooks.ListDeliveries(req.Username, req.State, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListWebhookDeliveriesReply{Deliveries: deliveries}, nil
+}
+
+func (s *AdminService) RedeliverWebhook(ctx context.Context, req *pb.RedeliverWebhookRequest) (*pb.WebhookDelivery, error) {
+	return s.webhooks.Redeliver(req.Id)
+}
+
+func (s *AdminService) ListAuditEntries(ctx context.Context, req *pb.ListAuditEntriesRequest) (*pb.ListAuditEntriesReply, error) {
+	filter, err := biz.ParseAuditFilter(req.Username, req.Action, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, nextCursor, err := s.audits.List(filter, req.Limit, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListAuditEntriesReply{Entries: entries, NextCursor: nextCursor}, nil
+}
+
+func (s *AdminService) ExportAuditEntries(ctx context.Context, req *pb.ExportAuditEntriesRequest) (*pb.File, error) {
+	filter, err := biz.ParseAuditFilter(req.Username, req.Action, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.audits.Export(filter)
+}
+
+func (s *AdminService) UpdateRetention(ctx context.Context, req *pb.UpdateRetentionRequest) (*pb.UpdateRetentionReply, error) {
+	return s.retention.UpdateRetention(req)
+}
+
+func (s *AdminService) CreateBackup(ctx context.Context, req *pb.CreateBackupRequest) (*pb.Backup, error) {
+	return s.backups.Create(req.Username)
+}
+
+func (s *AdminService) ListBackups(ctx context.Context, req *pb.ListBackupsRequest) (*pb.ListBackupsReply, error) {
+	backups, err := s.backups.List(req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListBackupsReply{Backups: backups}, nil
+}
+
+func (s *AdminService) RestoreBackup(ctx context.Context, req *pb.RestoreBackupRequest) (*pb.RestoreBackupReply, error) {
+	points, err := s.backups.Restore(req.Username, req.Version, req.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RestoreBackupReply{Success: true, Points: points}, nil
+}
+
+func (s *AdminService) RotateInfluxdbToken(ctx context.Context, req *pb.RotateInfluxdbTokenRequest) (*pb.RotateInfluxdbTokenReply, error) {
+	rotateTime, err := s.tokens.Rotate(req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RotateInfluxdbTokenReply{Success: true, RotateTime: timestamppb.New(rotateTime)}, nil
+}
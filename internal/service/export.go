@@ -0,0 +1,40 @@
+package service
+
+import (
+	pb "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
+	"github.com/go-kratos/kratos/v2/errors"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// DownloadExport 以流的形式下载导出任务完成后生成的归档，避免在内存中保存完整的归档，
+// 路径为GET /users/export/{id}，token通过query传递，需要admin及以上的角色，
+// 租户注销后的宽限期内同样可以下载
+func (s *UserService) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		khttp.DefaultErrorEncoder(w, r, errors.New(http.StatusMethodNotAllowed, "Export_Error", "只支持GET请求"))
+		return
+	}
+
+	org, _, _, err := s.orgs.AuthorizePending(r.URL.Query().Get("token"), pb.Role_ADMIN)
+	if err != nil {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+	name, size, archive, err := s.exports.Open(org, path.Base(r.URL.Path))
+	if err != nil {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	// 响应头已经发送，客户端中断下载时无法再返回错误
+	io.Copy(w, archive)
+}
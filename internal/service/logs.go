@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"gitee.com/moyusir/service-centre/internal/biz/kubecontroller"
+	"github.com/go-kratos/kratos/v2/errors"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 日志流连接的websocket升级器，浏览器经网关发起连接，因此不限制来源
+var logsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// StreamLogs 以websocket的形式推送用户服务的日志，每条消息为一行日志的json，
+// 请求的query参数包括:
+//
+//	token: 用户的token，由于浏览器发起ws连接时无法添加请求头，因此通过query传递
+//	service: dc或dp，为空时推送两个服务的日志
+//	container: app或build，为空时推送应用容器与编译初始容器的日志
+//	follow: 是否持续推送新产生的日志，默认为true
+//	since: 只推送最近一段时间内的日志，如10m
+//	tail: 只推送每个容器最后若干行日志
+func (s *UserService) StreamLogs(w http.ResponseWriter, r *http.Request) {
+	option, err := parseLogStreamOption(r)
+	if err != nil {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+
+	// 日志流的持续时间不应受http服务超时配置的限制，因此不使用请求的context，
+	// 而是在连接关闭时取消
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 在升级连接前完成鉴权，使错误能以普通的http响应返回
+	lines, err := s.uc.StreamLogs(ctx, r.URL.Query().Get("token"), option)
+	if err != nil {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+
+	conn, err := logsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// 客户端不会发送消息，持续读取只是为了感知连接的关闭
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for line := range lines {
+		if err := conn.WriteJSON(line); err != nil {
+			cancel()
+			return
+		}
+	}
+	conn.WriteMessage(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+// 由请求的query参数解析获取日志流的配置
+func parseLogStreamOption(r *http.Request) (*kubecontroller.LogStreamOption, error) {
+	query := r.URL.Query()
+	option := &kubecontroller.LogStreamOption{
+		Service:   query.Get("service"),
+		Container: query.Get("container"),
+		Follow:    true,
+	}
+
+	if follow := query.Get("follow"); follow != "" {
+		f, err := strconv.ParseBool(follow)
+		if err != nil {
+			return nil, errors.BadRequest("Stream_Logs_Error", "follow参数的格式错误")
+		}
+		option.Follow = f
+	}
+	if since := query.Get("since"); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return nil, errors.BadRequest("Stream_Logs_Error", "since参数的格式错误")
+		}
+		seconds := int64(d.Seconds())
+		if seconds == 0 {
+			seconds = 1
+		}
+		option.SinceSeconds = &seconds
+	}
+	if tail := query.Get("tail"); tail != "" {
+		t, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil || t < 0 {
+			return nil, errors.BadRequest("Stream_Logs_Error", "tail参数的格式错误")
+		}
+		option.TailLines = &t
+	}
+
+	return option, nil
+}
@@ -1,6 +1,120 @@
 package service
 
-import "github.com/google/wire"
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/google/wire"
+	"net"
+	nethttp "net/http"
+	"strings"
+)
 
 // ProviderSet is service providers.
-var ProviderSet = wire.NewSet(NewUserService)
+var ProviderSet = wire.NewSet(NewUserService, NewAdminService)
+
+// 在请求的ctx中保存客户端ip的key
+type clientIPKey struct{}
+
+// ParseTrustedProxies 解析受信任的反向代理，每一项可以为ip地址或CIDR
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Newf(500, "Config_Error", "受信任的代理 %s 不是合法的ip地址或CIDR:%v", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// ClientIPFilter 获得发起请求的客户端ip并保存在请求的ctx中，websocket等不经过中间件的处理函数同样适用。
+// 只有直接连接的地址属于受信任的代理时才使用X-Forwarded-For请求头，从右向左跳过受信任的代理，
+// 第一个不受信任的地址即为客户端ip，避免客户端伪造请求头冒充其他ip
+func ClientIPFilter(trusted []*net.IPNet) http.FilterFunc {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+			ip := clientIP(req, trusted)
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), clientIPKey{}, ip)))
+		})
+	}
+}
+
+func clientIP(req *nethttp.Request, trusted []*net.IPNet) string {
+	ip := remoteIP(req.RemoteAddr)
+	if !isTrusted(ip, trusted) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		for _, addr := range strings.Split(header, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				forwarded = append(forwarded, addr)
+			}
+		}
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip = forwarded[i]
+		if !isTrusted(ip, trusted) {
+			break
+		}
+	}
+	return ip
+}
+
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 获得发起请求的客户端ip，优先使用ClientIPFilter保存在ctx中的地址，
+// 否则使用直接连接的地址
+func ClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ht, ok := tr.(*http.Transport); ok {
+		return remoteIP(ht.Request().RemoteAddr)
+	}
+	return ""
+}
+
+// 获得请求头中携带的二次验证的验证码或恢复码
+func twoFactorCode(ctx context.Context) string {
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(tr.RequestHeader().Get("X-Two-Factor-Code"))
+}
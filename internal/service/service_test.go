@@ -0,0 +1,38 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_clientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name, remoteAddr, forwarded, want string
+	}{
+		{"不受信任的连接忽略请求头", "1.2.3.4:1234", "5.6.7.8", "1.2.3.4"},
+		{"受信任的代理使用请求头", "10.0.0.1:1234", "5.6.7.8", "5.6.7.8"},
+		{"跳过受信任的代理", "10.0.0.1:1234", "5.6.7.8, 192.168.1.1", "5.6.7.8"},
+		{"伪造的请求头只取最后一个不受信任的地址", "10.0.0.1:1234", "9.9.9.9, 5.6.7.8", "5.6.7.8"},
+		{"缺少请求头时使用代理的地址", "10.0.0.1:1234", "", "10.0.0.1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := &http.Request{RemoteAddr: c.remoteAddr, Header: make(http.Header)}
+			if c.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", c.forwarded)
+			}
+			if ip := clientIP(req, trusted); ip != c.want {
+				t.Fatalf("客户端ip应为%v，实际为:%v", c.want, ip)
+			}
+		})
+	}
+
+	if _, err := ParseTrustedProxies([]string{"proxy"}); err == nil {
+		t.Fatal("不合法的代理地址应返回错误")
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/go-kratos/kratos/v2/errors"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"net/http"
+)
+
+// VMAuthConfig 供vmauth以-auth.config定期获取的鉴权配置，其中包括所有租户的凭证，
+// 路径为GET /timeseries/vmauth/config，服务配置中的密钥通过query中的key传递
+func (s *UserService) VMAuthConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		khttp.DefaultErrorEncoder(w, r, errors.New(http.StatusMethodNotAllowed, "TimeSeries_Auth_Error", "只支持GET请求"))
+		return
+	}
+
+	config, err := s.uc.TimeSeriesAuthConfig(r.URL.Query().Get("key"))
+	if err != nil {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(config)
+}
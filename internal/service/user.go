@@ -6,18 +6,57 @@ import (
 
 	pb "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
 	utilApi "gitee.com/moyusir/util/api/util/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserService struct {
 	pb.UnimplementedUserServer
-	uc *biz.UserUsecase
+	uc           *biz.UserUsecase
+	metering     *biz.MeteringUsecase
+	verification *biz.VerificationUsecase
+	reset        *biz.PasswordResetUsecase
+	orgs         *biz.OrganizationUsecase
+	twoFactor    *biz.TwoFactorUsecase
+	oidc         *biz.OidcUsecase
+	exports      *biz.ExportUsecase
 }
 
-func NewUserService(uc *biz.UserUsecase) *UserService {
-	return &UserService{uc: uc}
+func NewUserService(uc *biz.UserUsecase, metering *biz.MeteringUsecase, verification *biz.VerificationUsecase,
+	reset *biz.PasswordResetUsecase, orgs *biz.OrganizationUsecase, twoFactor *biz.TwoFactorUsecase,
+	oidc *biz.OidcUsecase, exports *biz.ExportUsecase) *UserService {
+	return &UserService{
+		uc: uc, metering: metering, verification: verification, reset: reset, orgs: orgs, twoFactor: twoFactor,
+		oidc: oidc, exports: exports,
+	}
 }
 
 func (s *UserService) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterReply, error) {
+	// 以OIDC身份注册时，用户信息已由注册凭证填充
+	if req.OidcTicket != "" {
+		token, err := s.oidc.Register(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.RegisterReply{
+			Success: true,
+			Token:   token,
+		}, nil
+	}
+
+	// 开启邮箱验证时，只保存等待验证的账号，验证通过后再创建服务
+	if s.verification.Enabled() {
+		err := s.verification.Register(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.RegisterReply{
+			Success: true,
+			Pending: true,
+		}, nil
+	}
+
 	token, err := s.uc.Register(req)
 	if err != nil {
 		return nil, err
@@ -40,7 +79,7 @@ func (s *UserService) GetRegisterInfo(ctx context.Context, req *pb.GetRegisterIn
 }
 
 func (s *UserService) Login(ctx context.Context, req *utilApi.User) (*pb.LoginReply, error) {
-	token, err := s.uc.Login(req.Id, req.Password)
+	token, err := s.orgs.Login(req.Id, req.Password, twoFactorCode(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -50,22 +89,200 @@ func (s *UserService) Login(ctx context.Context, req *utilApi.User) (*pb.LoginRe
 		Token:   token,
 	}, nil
 }
-func (s *UserService) Unregister(ctx context.Context, req *utilApi.User) (*pb.UnregisterReply, error) {
-	err := s.uc.Unregister(req.Id, req.Password)
+func (s *UserService) Unregister(ctx context.Context, req *pb.UnregisterRequest) (*pb.UnregisterReply, error) {
+	purgeTime, err := s.uc.Unregister(req.Id, req.Password, twoFactorCode(ctx))
 	if err != nil {
 		return nil, err
 	}
+	reply := &pb.UnregisterReply{
+		Success:   true,
+		PurgeTime: timestamppb.New(purgeTime),
+	}
 
-	return &pb.UnregisterReply{
-		Success: true,
-	}, nil
+	// 注销后的宽限期内数据仍然保留，此时开始导出不会与清理冲突，
+	// 账号此时已经注销，因此导出任务创建失败时仍返回成功，并携带失败的原因
+	if req.ExportFirst {
+		job, err := s.exports.Start(req.Id, req.ExportFormat)
+		if err != nil {
+			reply.ExportError = err.Error()
+		} else {
+			reply.ExportId = job.Id
+		}
+	}
+
+	return reply, nil
+}
+func (s *UserService) Restore(ctx context.Context, req *utilApi.User) (*pb.RestoreReply, error) {
+	err := s.uc.Restore(req.Id, req.Password, twoFactorCode(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RestoreReply{Success: true}, nil
+}
+
+func (s *UserService) CreateExport(ctx context.Context, req *pb.CreateExportRequest) (*pb.ExportJob, error) {
+	org, _, _, err := s.orgs.AuthorizePending(req.Token, pb.Role_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.exports.Start(org, req.Format)
+}
+func (s *UserService) GetExportStatus(ctx context.Context, req *pb.GetExportRequest) (*pb.ExportJob, error) {
+	org, _, _, err := s.orgs.AuthorizePending(req.Token, pb.Role_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.exports.Status(org, req.Id)
 }
 
 func (s *UserService) DownloadClientCode(ctx context.Context, req *pb.DownloadClientCodeRequest) (*pb.File, error) {
-	code, err := s.uc.GetClientCode(req.Username)
+	code, err := s.orgs.GetClientCode(req.Token, req.Username)
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.File{Content: code, Name: "client_code.zip"}, nil
 }
+
+func (s *UserService) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusReply, error) {
+	return s.uc.GetStatus(req.Token)
+}
+
+func (s *UserService) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.GetUsageReply, error) {
+	records, err := s.metering.GetUsage(req.Token, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUsageReply{Records: records}, nil
+}
+
+func (s *UserService) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.RegisterReply, error) {
+	token, err := s.verification.Verify(req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RegisterReply{
+		Success: true,
+		Token:   token,
+	}, nil
+}
+
+func (s *UserService) RequestPasswordReset(ctx context.Context, req *pb.PasswordResetRequest) (*pb.PasswordResetReply, error) {
+	err := s.reset.Request(req.Username, ClientIP(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PasswordResetReply{Success: true}, nil
+}
+
+func (s *UserService) ConfirmPasswordReset(ctx context.Context, req *pb.ConfirmPasswordResetRequest) (*pb.ConfirmPasswordResetReply, error) {
+	token, err := s.reset.Confirm(req, ClientIP(ctx), twoFactorCode(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmPasswordResetReply{
+		Success: true,
+		Token:   token,
+	}, nil
+}
+
+func (s *UserService) CreateInvitation(ctx context.Context, req *pb.CreateInvitationRequest) (*pb.Invitation, error) {
+	return s.orgs.CreateInvitation(req.Token, req.Role)
+}
+
+func (s *UserService) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (*pb.LoginReply, error) {
+	token, err := s.orgs.AcceptInvitation(req.Code, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginReply{
+		Success: true,
+		Token:   token,
+	}, nil
+}
+
+func (s *UserService) ListMembers(ctx context.Context, req *pb.ListMembersRequest) (*pb.ListMembersReply, error) {
+	org, members, err := s.orgs.ListMembers(req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListMembersReply{Org: org, Members: members}, nil
+}
+
+func (s *UserService) UpdateMemberRole(ctx context.Context, req *pb.UpdateMemberRoleRequest) (*pb.Member, error) {
+	return s.orgs.UpdateMemberRole(req.Token, req.MemberId, req.Role)
+}
+
+func (s *UserService) RemoveMember(ctx context.Context, req *pb.RemoveMemberRequest) (*pb.RemoveMemberReply, error) {
+	err := s.orgs.RemoveMember(req.Token, req.MemberId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RemoveMemberReply{Success: true}, nil
+}
+
+func (s *UserService) EnrollTwoFactor(ctx context.Context, req *pb.EnrollTwoFactorRequest) (*pb.EnrollTwoFactorReply, error) {
+	_, account, _, err := s.orgs.Authorize(req.Token, pb.Role_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+	secret, otpauthUrl, err := s.twoFactor.Enroll(account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EnrollTwoFactorReply{Secret: secret, OtpauthUrl: otpauthUrl}, nil
+}
+
+func (s *UserService) VerifyTwoFactor(ctx context.Context, req *pb.VerifyTwoFactorRequest) (*pb.VerifyTwoFactorReply, error) {
+	_, account, _, err := s.orgs.Authorize(req.Token, pb.Role_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.twoFactor.Verify(account, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.VerifyTwoFactorReply{RecoveryCodes: codes}, nil
+}
+
+func (s *UserService) DisableTwoFactor(ctx context.Context, req *pb.DisableTwoFactorRequest) (*pb.DisableTwoFactorReply, error) {
+	_, account, _, err := s.orgs.Authorize(req.Token, pb.Role_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+	err = s.twoFactor.Disable(account, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DisableTwoFactorReply{Success: true}, nil
+}
+
+func (s *UserService) OidcLogin(ctx context.Context, req *pb.OidcLoginRequest) (*pb.OidcLoginReply, error) {
+	authorizationUrl, state, err := s.oidc.Login(req.Admin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.OidcLoginReply{AuthorizationUrl: authorizationUrl, State: state}, nil
+}
+
+func (s *UserService) OidcCallback(ctx context.Context, req *pb.OidcCallbackRequest) (*pb.OidcCallbackReply, error) {
+	return s.oidc.Callback(req.Code, req.State, twoFactorCode(ctx))
+}
+
+func (s *UserService) OidcTwoFactor(ctx context.Context, req *pb.OidcTwoFactorRequest) (*pb.OidcCallbackReply, error) {
+	return s.oidc.CompleteTwoFactor(req.Ticket, twoFactorCode(ctx))
+}
@@ -139,7 +139,7 @@ func TestUserUsecase(t *testing.T) {
 		t.Fatal(err)
 	}
 	t.Cleanup(func() {
-		_, err := userHTTPClient.Unregister(context.Background(), &utilApi.User{
+		_, err := userHTTPClient.Unregister(context.Background(), &v1.UnregisterRequest{
 			Id:       username,
 			Password: username,
 		})
@@ -231,7 +231,7 @@ func TestUser_Register(t *testing.T) {
 	userHTTPClient := StartServiceCenterServer(t)
 	username := "testreg"
 	t.Cleanup(func() {
-		userHTTPClient.Unregister(context.Background(), &utilApi.User{
+		userHTTPClient.Unregister(context.Background(), &v1.UnregisterRequest{
 			Id:       username,
 			Password: username,
 		})
@@ -4,6 +4,7 @@ import (
 	"context"
 	v1 "gitee.com/moyusir/service-centre/api/serviceCenter/v1"
 	"gitee.com/moyusir/service-centre/internal/conf"
+	"gitee.com/moyusir/service-centre/internal/server"
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/transport/http"
@@ -12,7 +13,7 @@ import (
 	"time"
 )
 
-func newApp(logger log.Logger, hs *http.Server) *kratos.App {
+func newApp(logger log.Logger, hs *http.Server, ws *server.WorkerServer) *kratos.App {
 	// go build -ldflags "-X main.Version=x.y.z"
 	var (
 		// Name is the name of the compiled software.
@@ -29,6 +30,7 @@ func newApp(logger log.Logger, hs *http.Server) *kratos.App {
 		kratos.Logger(logger),
 		kratos.Server(
 			hs,
+			ws,
 		),
 	)
 }